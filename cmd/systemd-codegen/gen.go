@@ -0,0 +1,500 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+const (
+	systemdPath = "routerd.net/go-systemd"
+	marker      = "// Code generated by systemd-codegen. DO NOT EDIT.\n"
+)
+
+// generatedMarker matches the marker of generated files, see https://golang.org/s/generatedcode.
+var generatedMarker = regexp.MustCompile(`(?m)^// Code generated .* DO NOT EDIT\.\n\n?`)
+
+// pkg holds the struct types declared in a package.
+type pkg struct {
+	Name    string
+	Structs map[string]*structType
+}
+
+type structType struct {
+	Name   string
+	Fields []*ast.Field
+	// Header holds the comments in front of the package clause of the declaring file.
+	Header string
+	// Systemd is the name of the go-systemd package in the declaring file.
+	Systemd string
+}
+
+// parsePackage reads the struct types declared in the Go files in dir.
+// Test files and files generated by systemd-codegen are skipped.
+func parsePackage(dir string) (*pkg, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+
+	p := &pkg{Structs: map[string]*structType{}}
+	fset := token.NewFileSet()
+	for _, filename := range files {
+		if strings.HasSuffix(filename, "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(fset, filename, nil, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+		if isGenerated(file) {
+			continue
+		}
+		if p.Name == "" {
+			p.Name = file.Name.Name
+		}
+
+		header := ""
+		for _, c := range file.Comments {
+			if c.End() < file.Package && c != file.Doc {
+				header += commentSource(c) + "\n\n"
+			}
+		}
+		systemdName := ""
+		for _, imp := range file.Imports {
+			if path, _ := strconv.Unquote(imp.Path.Value); path == systemdPath {
+				systemdName = "systemd"
+				if imp.Name != nil {
+					systemdName = imp.Name.Name
+				}
+			}
+		}
+
+		for _, decl := range file.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				spec := spec.(*ast.TypeSpec)
+				st, ok := spec.Type.(*ast.StructType)
+				if !ok {
+					continue
+				}
+				p.Structs[spec.Name.Name] = &structType{
+					Name:    spec.Name.Name,
+					Fields:  st.Fields.List,
+					Header:  header,
+					Systemd: systemdName,
+				}
+			}
+		}
+	}
+	if p.Name == "" {
+		return nil, fmt.Errorf("%s: no Go files", dir)
+	}
+	return p, nil
+}
+
+func isGenerated(file *ast.File) bool {
+	for _, c := range file.Comments {
+		if c.Pos() > file.Package {
+			break
+		}
+		if strings.HasPrefix(c.Text(), strings.TrimPrefix(marker, "// ")) {
+			return true
+		}
+	}
+	return false
+}
+
+// commentSource returns the comment group as written in the source.
+func commentSource(c *ast.CommentGroup) string {
+	var lines []string
+	for _, line := range c.List {
+		lines = append(lines, line.Text)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// fileModel describes the file struct.
+type fileModel struct {
+	Type        string
+	Sections    []sectionField
+	SectionList bool // unknown sections are stored in an embedded SectionList
+}
+
+type sectionField struct {
+	Field string // name of the struct field
+	Name  string // name of the section
+	Type  string // name of the section struct
+	Kind  string // "*", "[]" or "" for pointers, slices or values
+}
+
+// sectionModel describes a section struct.
+type sectionModel struct {
+	Type        string
+	Keys        []keyField
+	Comment     bool // the section comment is stored in a Comment field
+	KeyList     bool // unknown keys are stored in an embedded KeyList
+	KeyComments bool // key comments are stored in an embedded KeyComments
+}
+
+type keyField struct {
+	Field  string // name of the struct field
+	Name   string // name of the key
+	Method string // SectionDecoder and SectionEncoder method handling the field type
+	Option string // argument passed after the value, if any
+}
+
+// methods maps the field types with a dedicated method to the methods handling them.
+// Other field types and fields with an assignment mode are handled by reflection
+// with the Value methods.
+var methods = map[string]string{
+	"string":   "String",
+	"bool":     "Bool",
+	"uint":     "Uint",
+	"*string":  "StringPtr",
+	"*bool":    "BoolPtr",
+	"*uint":    "UintPtr",
+	"[]string": "Strings",
+}
+
+// tag holds the parts of a systemd tag relevant for the generated code.
+type tag struct {
+	Name      string
+	Omitempty bool
+	WSlist    bool
+	Mode      string // assignment mode, if any
+}
+
+// options returns the tag options relevant for the Value methods.
+func (t tag) options() string {
+	var opts string
+	if t.Omitempty {
+		opts += ",omitempty"
+	}
+	if t.WSlist {
+		opts += ",wslist"
+	}
+	if t.Mode != "" {
+		opts += "," + t.Mode
+	}
+	return opts
+}
+
+// parseTag parses the systemd tag of a field,
+// ok is false if the field is ignored.
+func parseTag(field *ast.Field) (t tag, ok bool, err error) {
+	if field.Tag == nil {
+		return t, true, nil
+	}
+	value, err := strconv.Unquote(field.Tag.Value)
+	if err != nil {
+		return t, false, err
+	}
+	s := reflect.StructTag(value).Get("systemd")
+	if s == "-" {
+		return t, false, nil
+	}
+
+	opts := strings.Split(s, ",")
+	t.Name = opts[0]
+	for _, opt := range opts[1:] {
+		switch {
+		case opt == "omitempty":
+			t.Omitempty = true
+		case opt == "wslist":
+			t.WSlist = true
+		case opt == "append", opt == "reset", opt == "lastwins", opt == "first":
+			t.Mode = opt
+		case opt == "unordered",
+			strings.HasPrefix(opt, "since="),
+			strings.HasPrefix(opt, "deprecated="),
+			strings.HasPrefix(opt, "legacy="):
+			// only used by reflection for comparison and older versions
+		default:
+			return t, false, fmt.Errorf("tag option %q is not supported", opt)
+		}
+	}
+	return t, true, nil
+}
+
+// embedded returns the type name of an embedded field, or "" for named fields.
+func embedded(field *ast.Field, systemdName string) string {
+	if len(field.Names) != 0 {
+		return ""
+	}
+	name := types.ExprString(field.Type)
+	if systemdName != "" && strings.HasPrefix(name, systemdName+".") {
+		return "systemd." + strings.TrimPrefix(name, systemdName+".")
+	}
+	return name
+}
+
+func newFileModel(p *pkg, typeName string) (*fileModel, error) {
+	st := p.Structs[typeName]
+	if st == nil {
+		return nil, fmt.Errorf("struct type %s not found", typeName)
+	}
+
+	m := &fileModel{Type: typeName}
+	names := map[string]bool{}
+	for _, field := range st.Fields {
+		switch embedded(field, st.Systemd) {
+		case "":
+		case "systemd.SectionList":
+			m.SectionList = true
+			continue
+		default:
+			return nil, fmt.Errorf("%s: embedded field %s is not supported", typeName, types.ExprString(field.Type))
+		}
+
+		t, ok, err := parseTag(field)
+		if err != nil {
+			return nil, fmt.Errorf("%s.%s: %w", typeName, field.Names[0].Name, err)
+		}
+		if !ok {
+			continue
+		}
+		typ := types.ExprString(field.Type)
+		kind := typ[:len(typ)-len(strings.TrimLeft(typ, "*[]"))]
+		elem := typ[len(kind):]
+		if (kind != "" && kind != "*" && kind != "[]") || p.Structs[elem] == nil {
+			return nil, fmt.Errorf("%s.%s: type %s is not a section struct", typeName, field.Names[0].Name, typ)
+		}
+
+		for _, name := range field.Names {
+			if !name.IsExported() {
+				continue
+			}
+			f := sectionField{Field: name.Name, Name: t.Name, Type: elem, Kind: kind}
+			if f.Name == "" {
+				f.Name = name.Name
+			}
+			if names[f.Name] {
+				return nil, fmt.Errorf("%s: duplicate section %s", typeName, f.Name)
+			}
+			names[f.Name] = true
+			m.Sections = append(m.Sections, f)
+		}
+	}
+	return m, nil
+}
+
+func newSectionModel(p *pkg, typeName string) (*sectionModel, error) {
+	st := p.Structs[typeName]
+	m := &sectionModel{Type: typeName}
+	names := map[string]bool{}
+	for _, field := range st.Fields {
+		switch embedded(field, st.Systemd) {
+		case "":
+		case "systemd.KeyList":
+			m.KeyList = true
+			continue
+		case "systemd.KeyComments":
+			m.KeyComments = true
+			continue
+		default:
+			return nil, fmt.Errorf("%s: embedded field %s is not supported", typeName, types.ExprString(field.Type))
+		}
+
+		typ := types.ExprString(field.Type)
+		if len(field.Names) == 1 && field.Names[0].Name == "Comment" && typ == "string" {
+			m.Comment = true
+			continue
+		}
+		t, ok, err := parseTag(field)
+		if err != nil {
+			return nil, fmt.Errorf("%s.%s: %w", typeName, field.Names[0].Name, err)
+		}
+		if !ok {
+			continue
+		}
+		method, ok := methods[typ]
+		if !ok || t.Mode != "" {
+			method = "Value"
+		}
+
+		for _, name := range field.Names {
+			if !name.IsExported() {
+				continue
+			}
+			k := keyField{Field: name.Name, Name: t.Name, Method: method}
+			if k.Name == "" {
+				k.Name = name.Name
+			}
+			if names[k.Name] {
+				return nil, fmt.Errorf("%s: duplicate key %s", typeName, k.Name)
+			}
+			names[k.Name] = true
+
+			switch method {
+			case "Value":
+				k.Option = strconv.Quote(t.options())
+			case "Strings":
+				k.Option = strconv.FormatBool(t.WSlist)
+			default:
+				k.Option = strconv.FormatBool(t.Omitempty)
+			}
+			m.Keys = append(m.Keys, k)
+		}
+	}
+	return m, nil
+}
+
+// generate returns the source of the methods of the file struct typeName
+// and its section structs.
+func generate(p *pkg, typeName string) ([]byte, error) {
+	file, err := newFileModel(p, typeName)
+	if err != nil {
+		return nil, err
+	}
+	var sections []*sectionModel
+	seen := map[string]bool{}
+	for _, f := range file.Sections {
+		if seen[f.Type] {
+			continue
+		}
+		seen[f.Type] = true
+		s, err := newSectionModel(p, f.Type)
+		if err != nil {
+			return nil, err
+		}
+		sections = append(sections, s)
+	}
+	sort.Slice(sections, func(i, j int) bool {
+		return sections[i].Type < sections[j].Type
+	})
+
+	var buf bytes.Buffer
+	header := generatedMarker.ReplaceAllString(p.Structs[typeName].Header, "")
+	buf.WriteString(header + marker + "\n")
+	fmt.Fprintf(&buf, "package %s\n\nimport %q\n\n", p.Name, systemdPath)
+	writeFile(&buf, file)
+	for _, s := range sections {
+		writeSection(&buf, s)
+	}
+
+	out, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("formatting generated code: %w", err)
+	}
+	return out, nil
+}
+
+// receiver returns the receiver name of the methods of the file struct,
+// avoiding the names of local variables.
+func receiver(typeName string) string {
+	r := string(unicode.ToLower([]rune(typeName)[0]))
+	if r == "s" || r == "i" {
+		return "x"
+	}
+	return r
+}
+
+func writeFile(buf *bytes.Buffer, m *fileModel) {
+	r := receiver(m.Type)
+
+	fmt.Fprintf(buf, "// MarshalSystemd implements systemd.Marshaler.\n")
+	fmt.Fprintf(buf, "func (%s *%s) MarshalSystemd() ([]systemd.Section, error) {\n", r, m.Type)
+	fmt.Fprintf(buf, "var (\nsections []systemd.Section\nsection systemd.Section\nerr error\n)\n")
+	marshal := func(field string, f sectionField) {
+		fmt.Fprintf(buf, "if section, err = %s.marshalSystemd(%q); err != nil {\nreturn nil, err\n}\n", field, f.Name)
+		fmt.Fprintf(buf, "sections = append(sections, section)\n")
+	}
+	for _, f := range m.Sections {
+		switch f.Kind {
+		case "*":
+			fmt.Fprintf(buf, "if %s.%s != nil {\n", r, f.Field)
+			marshal(r+"."+f.Field, f)
+			fmt.Fprintf(buf, "}\n")
+		case "[]":
+			fmt.Fprintf(buf, "for i := range %s.%s {\n", r, f.Field)
+			marshal(r+"."+f.Field+"[i]", f)
+			fmt.Fprintf(buf, "}\n")
+		default:
+			marshal(r+"."+f.Field, f)
+		}
+	}
+	if m.SectionList {
+		fmt.Fprintf(buf, "sections = append(sections, %s.SectionList...)\n", r)
+	}
+	fmt.Fprintf(buf, "return sections, nil\n}\n\n")
+
+	fmt.Fprintf(buf, "// UnmarshalSystemd implements systemd.Unmarshaler.\n")
+	fmt.Fprintf(buf, "func (%s *%s) UnmarshalSystemd(file *systemd.File) error {\n", r, m.Type)
+	fmt.Fprintf(buf, "for i := range file.Sections {\n")
+	fmt.Fprintf(buf, "section := &file.Sections[i]\n")
+	fmt.Fprintf(buf, "switch section.Name {\n")
+	for _, f := range m.Sections {
+		fmt.Fprintf(buf, "case %q:\n", f.Name)
+		if f.Kind == "*" {
+			fmt.Fprintf(buf, "s := &%s{}\n", f.Type)
+		} else {
+			fmt.Fprintf(buf, "s := %s{}\n", f.Type)
+		}
+		fmt.Fprintf(buf, "if err := s.unmarshalSystemd(section); err != nil {\nreturn err\n}\n")
+		if f.Kind == "[]" {
+			fmt.Fprintf(buf, "%s.%s = append(%[1]s.%[2]s, s)\n", r, f.Field)
+		} else {
+			fmt.Fprintf(buf, "%s.%s = s\n", r, f.Field)
+		}
+	}
+	if m.SectionList {
+		fmt.Fprintf(buf, "default:\n%s.AddSection(*section)\n", r)
+	}
+	fmt.Fprintf(buf, "}\n}\nreturn nil\n}\n\n")
+}
+
+func writeSection(buf *bytes.Buffer, m *sectionModel) {
+	comment, getComment, addComment, addKey, keyList := `""`, "nil", "nil", "nil", "nil"
+	if m.Comment {
+		comment = "s.Comment"
+	}
+	if m.KeyComments {
+		getComment, addComment = "s.GetKeyComment", "s.AddKeyComment"
+	}
+	if m.KeyList {
+		addKey, keyList = "s.AddKey", "s.KeyList"
+	}
+
+	fmt.Fprintf(buf, "func (s *%s) marshalSystemd(name string) (systemd.Section, error) {\n", m.Type)
+	fmt.Fprintf(buf, "e := systemd.NewSectionEncoder(name, %s, %s)\n", comment, getComment)
+	for _, k := range m.Keys {
+		switch k.Method {
+		case "Value":
+			fmt.Fprintf(buf, "e.Value(%q, &s.%s, %s)\n", k.Name, k.Field, k.Option)
+		default:
+			fmt.Fprintf(buf, "e.%s(%q, s.%s, %s)\n", k.Method, k.Name, k.Field, k.Option)
+		}
+	}
+	fmt.Fprintf(buf, "return e.Section(%s), e.Err()\n}\n\n", keyList)
+
+	fmt.Fprintf(buf, "func (s *%s) unmarshalSystemd(section *systemd.Section) error {\n", m.Type)
+	if m.Comment {
+		fmt.Fprintf(buf, "s.Comment = section.Comment\n")
+	}
+	fmt.Fprintf(buf, "d := systemd.NewSectionDecoder(section, %s)\n", addComment)
+	for _, k := range m.Keys {
+		switch k.Method {
+		case "StringPtr", "Strings", "Value":
+			fmt.Fprintf(buf, "d.%s(%q, &s.%s, %s)\n", k.Method, k.Name, k.Field, k.Option)
+		default:
+			fmt.Fprintf(buf, "d.%s(%q, &s.%s)\n", k.Method, k.Name, k.Field)
+		}
+	}
+	fmt.Fprintf(buf, "d.Unknown(%s)\n", addKey)
+	fmt.Fprintf(buf, "return d.Err()\n}\n\n")
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerate(t *testing.T) {
+	p, err := parsePackage("testdata/example")
+	require.NoError(t, err)
+	assert.Equal(t, "example", p.Name)
+
+	out, err := generate(p, "Example")
+	require.NoError(t, err)
+	golden, err := ioutil.ReadFile("testdata/example.golden")
+	require.NoError(t, err)
+	assert.Equal(t, string(golden), string(out))
+
+	_, err = generate(p, "Missing")
+	assert.EqualError(t, err, "struct type Missing not found")
+}
+
+func TestGenerateErrors(t *testing.T) {
+	tests := []struct {
+		Name string
+		Src  string
+		Err  string
+	}{
+		{
+			Name: "unsupported tag option",
+			Src:  "type File struct{ Main *Main }\ntype Main struct{ DNS []string `systemd:\",prepend\"` }",
+			Err:  `Main.DNS: tag option "prepend" is not supported`,
+		},
+		{
+			Name: "not a section struct",
+			Src:  "type File struct{ Main map[string]string }",
+			Err:  "File.Main: type map[string]string is not a section struct",
+		},
+		{
+			Name: "embedded struct",
+			Src:  "type File struct{ Shared }\ntype Shared struct{ Main *Main }\ntype Main struct{}",
+			Err:  "File: embedded field Shared is not supported",
+		},
+		{
+			Name: "duplicate key",
+			Src:  "type File struct{ Main *Main }\ntype Main struct{ A, B string `systemd:\"Name\"` }",
+			Err:  "Main: duplicate key Name",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "systemd-codegen")
+			require.NoError(t, err)
+			defer os.RemoveAll(dir)
+			src := "package test\n\n" + test.Src + "\n"
+			require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "test.go"), []byte(src), 0666))
+
+			p, err := parsePackage(dir)
+			require.NoError(t, err)
+			_, err = generate(p, "File")
+			assert.EqualError(t, err, test.Err)
+		})
+	}
+}
+
+// The generated files of the packages in this module are up to date.
+func TestGeneratedFiles(t *testing.T) {
+	for pkg, typeName := range map[string]string{
+		"network": "Network",
+		"netdev":  "NetDev",
+		"link":    "Link",
+	} {
+		t.Run(pkg, func(t *testing.T) {
+			dir := filepath.Join("..", "..", pkg)
+			p, err := parsePackage(dir)
+			require.NoError(t, err)
+			out, err := generate(p, typeName)
+			require.NoError(t, err)
+
+			existing, err := ioutil.ReadFile(filepath.Join(dir, pkg+"_systemd.go"))
+			require.NoError(t, err)
+			assert.Equal(t, string(existing), string(out), "run go generate in %s", pkg)
+		})
+	}
+}
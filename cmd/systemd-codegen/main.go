@@ -0,0 +1,68 @@
+// Command systemd-codegen generates MarshalSystemd and UnmarshalSystemd methods
+// for a file struct and its section structs, which Marshal and Unmarshal
+// prefer over reflection.
+//
+// Usage:
+//
+//	systemd-codegen -type name [-o file] [dir]
+//
+// The struct types are read from the Go files of the package in dir,
+// the current directory by default. The generated code follows the rules
+// of the reflective encoder. Keys of type string, bool, uint, pointers to them
+// and []string are handled by dedicated code, keys of other types or with an
+// assignment mode like append fall back to reflection for the single field.
+// Unknown tag options are reported as errors.
+//
+// It is meant to be run by go generate after the structs have changed:
+//
+//	//go:generate go run ../cmd/systemd-codegen -type Network -o network_systemd.go
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+)
+
+var (
+	typeName = flag.String("type", "", "`name` of the struct of the file")
+	output   = flag.String("o", "", "write output to `file` instead of stdout")
+)
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: systemd-codegen -type name [-o file] [dir]\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() > 1 || *typeName == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	dir := "."
+	if flag.NArg() == 1 {
+		dir = flag.Arg(0)
+	}
+	if err := run(dir); err != nil {
+		fmt.Fprintln(os.Stderr, "systemd-codegen:", err)
+		os.Exit(1)
+	}
+}
+
+func run(dir string) error {
+	p, err := parsePackage(dir)
+	if err != nil {
+		return err
+	}
+	out, err := generate(p, *typeName)
+	if err != nil {
+		return err
+	}
+	if *output == "" {
+		_, err = os.Stdout.Write(out)
+		return err
+	}
+	return ioutil.WriteFile(*output, out, 0666)
+}
@@ -0,0 +1,46 @@
+// Copyright header of the example.
+
+package example
+
+import (
+	"time"
+
+	sd "routerd.net/go-systemd"
+)
+
+type Example struct {
+	sd.SectionList
+
+	Main     MainSection
+	Match    *MatchSection
+	Routes   []RouteSection `systemd:"Route,since=240"`
+	Internal string         `systemd:"-"`
+}
+
+type MainSection struct {
+	Comment string
+	sd.KeyComments
+
+	Name    string
+	Enabled bool    `systemd:",omitempty"`
+	Count   uint    `systemd:",omitempty"`
+	Label   *string `systemd:"Description"`
+	Ignored string  `systemd:"-"`
+	private string
+}
+
+type MatchSection struct {
+	sd.KeyList
+
+	Names []string `systemd:"Name,omitempty,wslist,unordered"`
+	Up    *bool    `systemd:",omitempty"`
+}
+
+type RouteSection struct {
+	Gateway  []string
+	Metric   *uint         `systemd:",omitempty"`
+	Priority int           `systemd:",omitempty"`
+	Timeout  time.Duration `systemd:",omitempty"`
+	DNS      []string      `systemd:",append"`
+	Source   string        `systemd:",omitempty,first"`
+}
@@ -0,0 +1,137 @@
+package main
+
+// lineOp is a single line of a diff.
+type lineOp struct {
+	kind byte // ' ', '-' or '+'
+	line string
+}
+
+// diffLines returns the line operations turning a into b,
+// based on their longest common subsequence.
+// It returns nil if a and b are equal.
+func diffLines(a, b []string) []lineOp {
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			switch {
+			case a[i] == b[j]:
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			case lcs[i+1][j] >= lcs[i][j+1]:
+				lcs[i][j] = lcs[i+1][j]
+			default:
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var (
+		ops     []lineOp
+		changed bool
+		i, j    int
+	)
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			ops = append(ops, lineOp{' ', a[i]})
+			i++
+			j++
+		case j == len(b) || i < len(a) && lcs[i+1][j] >= lcs[i][j+1]:
+			ops = append(ops, lineOp{'-', a[i]})
+			changed = true
+			i++
+		default:
+			ops = append(ops, lineOp{'+', b[j]})
+			changed = true
+			j++
+		}
+	}
+	if !changed {
+		return nil
+	}
+	return ops
+}
+
+// hunk is a group of changes with surrounding context.
+type hunk struct {
+	aStart, aLen int
+	bStart, bLen int
+	ops          []lineOp
+}
+
+// hunks groups ops into hunks with up to context unchanged lines around changes.
+func hunks(ops []lineOp, context int) []hunk {
+	var (
+		out    []hunk
+		cur    *hunk
+		aLine  int
+		bLine  int
+		last   = -1 // index of the last change
+		starts = make([][2]int, len(ops))
+	)
+	for k, op := range ops {
+		starts[k] = [2]int{aLine, bLine}
+		if op.kind != '+' {
+			aLine++
+		}
+		if op.kind != '-' {
+			bLine++
+		}
+	}
+
+	// trailing adds the context after the last change to the current hunk
+	trailing := func() {
+		end := last + 1 + context
+		if end > len(ops) {
+			end = len(ops)
+		}
+		for _, c := range ops[last+1 : end] {
+			if c.kind != ' ' {
+				break
+			}
+			cur.add(c)
+		}
+	}
+	for k, op := range ops {
+		if op.kind == ' ' {
+			continue
+		}
+		first := k - context
+		if first < 0 {
+			first = 0
+		}
+		if cur == nil || first > last+context {
+			if cur != nil {
+				trailing()
+			}
+			out = append(out, hunk{aStart: starts[first][0], bStart: starts[first][1]})
+			cur = &out[len(out)-1]
+			for _, c := range ops[first:k] {
+				cur.add(c)
+			}
+		} else {
+			for _, c := range ops[last+1 : k] {
+				cur.add(c)
+			}
+		}
+		cur.add(op)
+		last = k
+	}
+	if cur != nil {
+		trailing()
+	}
+	return out
+}
+
+func (h *hunk) add(op lineOp) {
+	h.ops = append(h.ops, op)
+	if op.kind != '+' {
+		h.aLen++
+	}
+	if op.kind != '-' {
+		h.bLen++
+	}
+}
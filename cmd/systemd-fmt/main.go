@@ -0,0 +1,285 @@
+// Command systemd-fmt formats systemd unit and network files.
+//
+// Usage:
+//
+//	systemd-fmt [flags] [path ...]
+//
+// Without paths, standard input is formatted to standard output.
+// Directories are processed recursively, formatting all files
+// with a known systemd extension.
+//
+// The canonical format writes keys as Name=Value without spacing around =
+// or trailing whitespace, comments with a common prefix
+// and blank lines between sections and in front of commented keys.
+// Values continued by a trailing backslash keep their line layout,
+// only trailing whitespace is removed. Comment lines within such a value
+// stay in place and are written with the comment prefix.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"routerd.net/go-systemd"
+	"routerd.net/go-systemd/internal/parser"
+)
+
+var (
+	list  = flag.Bool("l", false, "list files whose formatting differs from systemd-fmt's")
+	diff  = flag.Bool("d", false, "display diffs instead of rewriting files")
+	write = flag.Bool("w", false, "write result to (source) file instead of stdout")
+
+	indent        = flag.String("indent", "", "indentation of keys")
+	commentPrefix = flag.String("comment-prefix", "# ", "prefix of comment lines")
+	blankLines    = flag.String("blank-lines", "default", "blank lines between `default|sections|none`")
+)
+
+// extensions lists the file extensions formatted when walking directories.
+var extensions = map[string]bool{
+	".network": true, ".netdev": true, ".link": true, ".conf": true,
+	".service": true, ".socket": true, ".timer": true, ".target": true,
+	".mount": true, ".automount": true, ".swap": true, ".path": true,
+	".slice": true, ".scope": true, ".device": true,
+}
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: systemd-fmt [flags] [path ...]\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	opts := systemd.EncodeOptions{
+		Indent:        *indent,
+		CommentPrefix: *commentPrefix,
+	}
+	switch *blankLines {
+	case "default":
+		opts.BlankLines = systemd.BlankLinesDefault
+	case "sections":
+		opts.BlankLines = systemd.BlankLinesBetweenSections
+	case "none":
+		opts.BlankLines = systemd.BlankLinesNone
+	default:
+		fmt.Fprintf(os.Stderr, "systemd-fmt: invalid -blank-lines %q\n", *blankLines)
+		os.Exit(2)
+	}
+
+	f := &formatter{
+		opts:  opts,
+		list:  *list,
+		diff:  *diff,
+		write: *write,
+		out:   os.Stdout,
+	}
+	if flag.NArg() == 0 {
+		if *write {
+			fmt.Fprintln(os.Stderr, "systemd-fmt: cannot use -w with standard input")
+			os.Exit(2)
+		}
+		f.processFile("<standard input>", os.Stdin, false)
+	}
+	for _, path := range flag.Args() {
+		info, err := os.Stat(path)
+		if err != nil {
+			f.report(err)
+			continue
+		}
+		if info.IsDir() {
+			f.walkDir(path)
+			continue
+		}
+		f.processPath(path)
+	}
+	if f.failed {
+		os.Exit(2)
+	}
+}
+
+// formatter formats files according to the command line flags.
+type formatter struct {
+	opts              systemd.EncodeOptions
+	list, diff, write bool
+
+	out    io.Writer
+	failed bool
+}
+
+func (f *formatter) report(err error) {
+	fmt.Fprintln(os.Stderr, err)
+	f.failed = true
+}
+
+func (f *formatter) walkDir(root string) {
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			f.report(err)
+			return nil
+		}
+		if info.IsDir() || !extensions[filepath.Ext(path)] {
+			return nil
+		}
+		f.processPath(path)
+		return nil
+	})
+	if err != nil {
+		f.report(err)
+	}
+}
+
+func (f *formatter) processPath(path string) {
+	file, err := os.Open(path)
+	if err != nil {
+		f.report(err)
+		return
+	}
+	defer file.Close()
+	f.processFile(path, file, true)
+}
+
+// processFile formats the contents of in, read from the named file.
+func (f *formatter) processFile(name string, in io.Reader, isFile bool) {
+	src, err := ioutil.ReadAll(in)
+	if err != nil {
+		f.report(err)
+		return
+	}
+	res, err := format(name, src, f.opts)
+	if err != nil {
+		f.report(err)
+		return
+	}
+
+	if bytes.Equal(src, res) {
+		if !f.list && !f.diff && !f.write {
+			f.out.Write(res)
+		}
+		return
+	}
+	if f.list {
+		fmt.Fprintln(f.out, name)
+	}
+	if f.diff {
+		io.WriteString(f.out, unifiedDiff(name, string(src), string(res)))
+	}
+	if f.write && isFile {
+		info, err := os.Stat(name)
+		if err != nil {
+			f.report(err)
+			return
+		}
+		if err := ioutil.WriteFile(name, res, info.Mode().Perm()); err != nil {
+			f.report(err)
+		}
+		return
+	}
+	if !f.list && !f.diff {
+		f.out.Write(res)
+	}
+}
+
+// format returns src in the canonical format.
+func format(name string, src []byte, opts systemd.EncodeOptions) ([]byte, error) {
+	file, err := systemd.DecodeOptions{Filename: name}.Decode(src)
+	if err != nil {
+		return nil, err
+	}
+	keepContinuations(file, splitLines(string(src)), opts)
+	var buf bytes.Buffer
+	if err := opts.Encode(&buf, file); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// keepContinuations restores the line layout of the values of file
+// continued over several lines, given the lines of the source.
+// The decoder joins these lines and moves the comment lines within them
+// to the comment of the key.
+func keepContinuations(file *systemd.File, lines []string, opts systemd.EncodeOptions) {
+	prefix := opts.CommentPrefix
+	if prefix == "" {
+		prefix = "# "
+	}
+	for i := range file.Sections {
+		for j := range file.Sections[i].Keys {
+			key := &file.Sections[i].Keys[j]
+			n := key.Pos.Line - 1
+			if n < 0 || n >= len(lines) || !parser.EndsWithContinuation(strings.TrimSuffix(lines[n], "\n")) {
+				continue
+			}
+
+			first := lines[n][strings.IndexByte(lines[n], '=')+1:]
+			value := []string{strings.TrimLeft(strings.TrimRight(first, "\r\n"), " \t")}
+			comments, last := 0, 0 // comment lines in front of the last value line
+			for continued := true; continued && n+1 < len(lines); {
+				n++
+				line := strings.TrimRight(lines[n], "\r\n")
+				switch trimmed := strings.TrimSpace(line); {
+				case trimmed == "":
+					continued = false
+				case trimmed[0] == '#' || trimmed[0] == ';':
+					value = append(value, opts.Indent+prefix+strings.TrimSpace(trimmed[1:]))
+				default:
+					comments += len(value) - 1 - last
+					last = len(value)
+					value = append(value, line)
+					continued = parser.EndsWithContinuation(line)
+				}
+			}
+			trailing := len(value) - 1 - last
+
+			// a continuation ended by an empty line or the end of the file is dropped,
+			// as the next key would continue the value otherwise
+			value = value[:last+1]
+			value[last] = strings.TrimRight(parser.TrimContinuation(value[last]), " \t")
+			key.Value = strings.Join(value, "\n")
+
+			// the decoder appends the comment lines within the value to the comment of the key,
+			// the ones following the last value line stay there
+			comment := strings.Split(key.Comment, "\n")
+			if end := len(comment) - trailing; comments+trailing < len(comment) {
+				comment = append(comment[:end-comments], comment[end:]...)
+			} else {
+				comment = comment[len(comment)-trailing:]
+			}
+			key.Comment = strings.Join(comment, "\n")
+		}
+	}
+}
+
+// unifiedDiff returns the differences between a and b in unified diff format.
+func unifiedDiff(name, a, b string) string {
+	ops := diffLines(splitLines(a), splitLines(b))
+	if len(ops) == 0 {
+		return ""
+	}
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "--- %s.orig\n+++ %s\n", name, name)
+	for _, h := range hunks(ops, 3) {
+		fmt.Fprintf(&out, "@@ -%d,%d +%d,%d @@\n", h.aStart+1, h.aLen, h.bStart+1, h.bLen)
+		for _, op := range h.ops {
+			out.WriteByte(op.kind)
+			out.WriteString(op.line)
+			if !strings.HasSuffix(op.line, "\n") {
+				out.WriteString("\n\\ No newline at end of file\n")
+			}
+		}
+	}
+	return out.String()
+}
+
+func splitLines(s string) []string {
+	lines := strings.SplitAfter(s, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
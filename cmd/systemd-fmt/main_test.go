@@ -0,0 +1,227 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"routerd.net/go-systemd"
+)
+
+const unformatted = `;  leading comment
+[Match]
+Name = eth0   
+
+[Network]
+Address=10.0.0.2/24
+#comment
+Gateway =10.0.0.1
+[Route]
+Gateway=10.0.0.1
+`
+
+const formatted = `# leading comment
+[Match]
+Name=eth0
+
+[Network]
+Address=10.0.0.2/24
+
+# comment
+Gateway=10.0.0.1
+
+[Route]
+Gateway=10.0.0.1
+`
+
+const continued = `[Service]
+ExecStart = /usr/bin/daemon \
+    --verbose \
+ ; embedded comment
+    --config=/etc/daemon.conf   
+Environment=A=1
+`
+
+const continuedFormatted = `[Service]
+ExecStart=/usr/bin/daemon \
+    --verbose \
+# embedded comment
+    --config=/etc/daemon.conf
+Environment=A=1
+`
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		Name string
+		Opts systemd.EncodeOptions
+		In   string
+		Out  string
+	}{
+		{
+			Name: "default",
+			In:   unformatted,
+			Out:  formatted,
+		},
+		{
+			Name: "formatted",
+			In:   formatted,
+			Out:  formatted,
+		},
+		{
+			Name: "options",
+			Opts: systemd.EncodeOptions{
+				CommentPrefix: ";",
+				BlankLines:    systemd.BlankLinesNone,
+			},
+			In: unformatted,
+			Out: `;leading comment
+[Match]
+Name=eth0
+[Network]
+Address=10.0.0.2/24
+;comment
+Gateway=10.0.0.1
+[Route]
+Gateway=10.0.0.1
+`,
+		},
+		{
+			Name: "continuation",
+			In:   continued,
+			Out:  continuedFormatted,
+		},
+		{
+			Name: "continuation formatted",
+			In:   continuedFormatted,
+			Out:  continuedFormatted,
+		},
+		{
+			Name: "continuation comments",
+			Opts: systemd.EncodeOptions{Indent: "  ", CommentPrefix: ";"},
+			In:   "[Service]\n# leading\nExecStart=a \\\n# first\n#second\n  b \\\n# trailing\n\nType=simple\n",
+			Out:  "[Service]\n  ;leading\n  ;trailing\n  ExecStart=a \\\n  ;first\n  ;second\n  b\n  Type=simple\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			out, err := format("test.network", []byte(test.In), test.Opts)
+			require.NoError(t, err)
+			assert.Equal(t, test.Out, string(out))
+		})
+	}
+
+	// the layout of continued values does not change their value
+	before, err := systemd.Decode([]byte(continued))
+	require.NoError(t, err)
+	after, err := systemd.Decode([]byte(continuedFormatted))
+	require.NoError(t, err)
+	assert.Equal(t, before.Sections[0].Keys[0].Value, after.Sections[0].Keys[0].Value)
+
+	_, err = format("test.network", []byte("Name=eth0\n"), systemd.EncodeOptions{})
+	assert.Error(t, err)
+}
+
+func TestFormatter(t *testing.T) {
+	root := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(root, name)
+		require.NoError(t, ioutil.WriteFile(path, []byte(content), 0644))
+		return path
+	}
+	bad := write("10-bad.network", unformatted)
+	good := write("20-good.network", formatted)
+	other := write("README", unformatted)
+
+	t.Run("list", func(t *testing.T) {
+		var out bytes.Buffer
+		f := &formatter{list: true, out: &out}
+		f.walkDir(root)
+		assert.False(t, f.failed)
+		assert.Equal(t, bad+"\n", out.String())
+	})
+
+	t.Run("diff", func(t *testing.T) {
+		var out bytes.Buffer
+		f := &formatter{diff: true, out: &out}
+		f.processPath(bad)
+		assert.Equal(t, `--- `+bad+`.orig
++++ `+bad+`
+@@ -1,10 +1,12 @@
+-;  leading comment
++# leading comment
+ [Match]
+-Name = eth0   
++Name=eth0
+ 
+ [Network]
+ Address=10.0.0.2/24
+-#comment
+-Gateway =10.0.0.1
++
++# comment
++Gateway=10.0.0.1
++
+ [Route]
+ Gateway=10.0.0.1
+`, out.String())
+	})
+
+	t.Run("write", func(t *testing.T) {
+		var out bytes.Buffer
+		f := &formatter{opts: systemd.EncodeOptions{CommentPrefix: "# "}, write: true, out: &out}
+		f.walkDir(root)
+		assert.False(t, f.failed)
+		assert.Empty(t, out.String())
+
+		for _, path := range []string{bad, good} {
+			content, err := ioutil.ReadFile(path)
+			require.NoError(t, err)
+			assert.Equal(t, formatted, string(content), path)
+		}
+		content, err := ioutil.ReadFile(other)
+		require.NoError(t, err)
+		assert.Equal(t, unformatted, string(content))
+	})
+
+	t.Run("stdout", func(t *testing.T) {
+		var out bytes.Buffer
+		f := &formatter{out: &out}
+		f.processFile("<standard input>", strings.NewReader(unformatted), false)
+		assert.Equal(t, formatted, out.String())
+	})
+
+	t.Run("error", func(t *testing.T) {
+		f := &formatter{out: ioutil.Discard}
+		f.processPath(filepath.Join(root, "missing.network"))
+		assert.True(t, f.failed)
+	})
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	a := "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n"
+	b := "1\n2\nthree\n4\n5\n6\n7\n8\n9\n10\n11\n12\n13"
+	assert.Equal(t, `--- f.orig
++++ f
+@@ -1,6 +1,6 @@
+ 1
+ 2
+-3
++three
+ 4
+ 5
+ 6
+@@ -10,3 +10,4 @@
+ 10
+ 11
+ 12
++13
+\ No newline at end of file
+`, unifiedDiff("f", a, b))
+	assert.Empty(t, unifiedDiff("f", a, a))
+}
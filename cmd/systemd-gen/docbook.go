@@ -0,0 +1,440 @@
+package main
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// element is a node of a docbook document.
+// Children are either strings or *element.
+type element struct {
+	Name     xml.Name
+	Attrs    []xml.Attr
+	Children []interface{}
+}
+
+const xincludeSpace = "http://www.w3.org/2001/XInclude"
+
+func (e *element) attr(name string) string {
+	for _, a := range e.Attrs {
+		if a.Name.Local == name {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+// elements returns the child elements named name.
+func (e *element) elements(name string) []*element {
+	var out []*element
+	for _, c := range e.Children {
+		if c, ok := c.(*element); ok && c.Name.Local == name {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// text returns the concatenated character data of e and its descendants.
+func (e *element) text() string {
+	var b strings.Builder
+	for _, c := range e.Children {
+		switch c := c.(type) {
+		case string:
+			b.WriteString(c)
+		case *element:
+			b.WriteString(c.text())
+		}
+	}
+	return b.String()
+}
+
+// find returns the first element with the id in e and its descendants.
+func (e *element) find(id string) *element {
+	if e.attr("id") == id {
+		return e
+	}
+	for _, c := range e.Children {
+		if c, ok := c.(*element); ok {
+			if found := c.find(id); found != nil {
+				return found
+			}
+		}
+	}
+	return nil
+}
+
+// parseDocbook reads a docbook document.
+// Entities not defined by HTML, like the ones of systemd's custom.entities, are kept as is.
+func parseDocbook(r io.Reader) (*element, error) {
+	d := xml.NewDecoder(r)
+	d.Strict = false
+	d.Entity = xml.HTMLEntity
+
+	root := &element{}
+	stack := []*element{root}
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		top := stack[len(stack)-1]
+		switch tok := tok.(type) {
+		case xml.StartElement:
+			e := &element{Name: tok.Name, Attrs: tok.Attr}
+			top.Children = append(top.Children, e)
+			stack = append(stack, e)
+		case xml.EndElement:
+			if len(stack) > 1 {
+				stack = stack[:len(stack)-1]
+			}
+		case xml.CharData:
+			top.Children = append(top.Children, string(tok))
+		}
+	}
+	return root, nil
+}
+
+// includer resolves XInclude elements referencing elements of other pages by id.
+type includer struct {
+	dir   string
+	pages map[string]*element
+}
+
+// versionInclude matches the xpointer of includes of version-info.xml.
+var versionInclude = regexp.MustCompile(`^v(\d+)$`)
+
+// resolve replaces the includes in e by the included elements.
+// Includes of version-info.xml are replaced by version elements holding the number of the release.
+func (inc *includer) resolve(e *element) error {
+	for i, c := range e.Children {
+		c, ok := c.(*element)
+		if !ok {
+			continue
+		}
+		if c.Name.Space != xincludeSpace || c.Name.Local != "include" {
+			if err := inc.resolve(c); err != nil {
+				return err
+			}
+			continue
+		}
+
+		href, xpointer := c.attr("href"), c.attr("xpointer")
+		if m := versionInclude.FindStringSubmatch(xpointer); m != nil && filepath.Base(href) == "version-info.xml" {
+			e.Children[i] = &element{Name: xml.Name{Local: "version"}, Children: []interface{}{m[1]}}
+			continue
+		}
+		page, err := inc.page(href)
+		if err != nil {
+			return err
+		}
+		included := page.find(xpointer)
+		if included == nil {
+			return fmt.Errorf("%s: no element with id %q", href, xpointer)
+		}
+		included = copyElement(included)
+		if err := inc.resolve(included); err != nil {
+			return err
+		}
+		e.Children[i] = included
+	}
+	return nil
+}
+
+// page returns the parsed page name in the directory of the includer.
+func (inc *includer) page(name string) (*element, error) {
+	if page, ok := inc.pages[name]; ok {
+		return page, nil
+	}
+	f, err := os.Open(filepath.Join(inc.dir, name))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	page, err := parseDocbook(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	inc.pages[name] = page
+	return page, nil
+}
+
+func copyElement(e *element) *element {
+	c := *e
+	c.Children = make([]interface{}, len(e.Children))
+	for i, child := range e.Children {
+		if child, ok := child.(*element); ok {
+			c.Children[i] = copyElement(child)
+			continue
+		}
+		c.Children[i] = child
+	}
+	return &c
+}
+
+// page is the documentation of the sections of a configuration file.
+type page struct {
+	Name     string // name of the page, like "systemd.network"
+	Purpose  string
+	Sections []*section
+}
+
+type section struct {
+	Name string
+	Doc  []string // paragraphs
+	// Repeated is set if the documentation says the section may appear more than once.
+	Repeated bool
+	Keys     []*key
+}
+
+type key struct {
+	Name string
+	Doc  []string // paragraphs
+	// Since is the systemd version adding the key, or 0 if unknown.
+	Since int
+}
+
+func (p *page) section(name string) *section {
+	for _, s := range p.Sections {
+		if s.Name == name {
+			return s
+		}
+	}
+	return nil
+}
+
+func (s *section) key(name string) *key {
+	for _, k := range s.Keys {
+		if k.Name == name {
+			return k
+		}
+	}
+	return nil
+}
+
+var (
+	sectionTitle    = regexp.MustCompile(`^\[([^\]]+)\] Section Options$`)
+	repeatedSection = regexp.MustCompile(`(?i)more than once|multiple times|several \[`)
+)
+
+// readPage reads the sections documented in the page named filename.
+// Elements included from other pages are looked up in the directory dir.
+func readPage(dir, filename string) (*page, error) {
+	inc := &includer{dir: dir, pages: map[string]*element{}}
+	root, err := inc.page(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &page{Name: strings.TrimSuffix(filepath.Base(filename), ".xml")}
+	for _, refentry := range root.elements("refentry") {
+		for _, namediv := range refentry.elements("refnamediv") {
+			for _, purpose := range namediv.elements("refpurpose") {
+				p.Purpose = collapse(purpose.text())
+			}
+		}
+
+		for _, refsect := range refentry.elements("refsect1") {
+			var title string
+			for _, t := range refsect.elements("title") {
+				title = collapse(t.text())
+			}
+			m := sectionTitle.FindStringSubmatch(title)
+			if m == nil {
+				continue
+			}
+			if err := inc.resolve(refsect); err != nil {
+				return nil, fmt.Errorf("%s: %w", filename, err)
+			}
+			p.addSection(m[1], refsect)
+		}
+	}
+	return p, nil
+}
+
+// addSection adds the keys documented in refsect to the section name.
+// Sections documented in several places are merged.
+func (p *page) addSection(name string, refsect *element) {
+	var s *section
+	for _, existing := range p.Sections {
+		if existing.Name == name {
+			s = existing
+		}
+	}
+	if s == nil {
+		s = &section{Name: name}
+		p.Sections = append(p.Sections, s)
+	}
+
+	for _, para := range refsect.elements("para") {
+		lines := render(para)
+		s.Doc = append(s.Doc, lines...)
+		for _, line := range lines {
+			if repeatedSection.MatchString(line) {
+				s.Repeated = true
+			}
+		}
+	}
+
+	for _, list := range refsect.elements("variablelist") {
+		for _, entry := range list.elements("varlistentry") {
+			s.addEntry(entry)
+		}
+	}
+}
+
+// addEntry adds the keys of a varlistentry, all sharing its documentation.
+func (s *section) addEntry(entry *element) {
+	var (
+		doc   []string
+		since int
+	)
+	for _, item := range entry.elements("listitem") {
+		doc = append(doc, render(item)...)
+		since = version(item)
+	}
+
+	for _, term := range entry.elements("term") {
+		for _, name := range term.elements("varname") {
+			k := &key{
+				Name:  strings.TrimSuffix(strings.TrimSpace(name.text()), "="),
+				Doc:   doc,
+				Since: since,
+			}
+			if k.Name == "" || s.key(k.Name) != nil {
+				continue
+			}
+			s.Keys = append(s.Keys, k)
+		}
+	}
+}
+
+// version returns the version of the first version element in e, or 0.
+func version(e *element) int {
+	for _, c := range e.Children {
+		c, ok := c.(*element)
+		if !ok {
+			continue
+		}
+		if c.Name.Local == "version" {
+			v, _ := strconv.Atoi(c.text())
+			return v
+		}
+		if v := version(c); v != 0 {
+			return v
+		}
+	}
+	return 0
+}
+
+// blockElements start a new line of documentation.
+var blockElements = map[string]bool{
+	"para": true, "simpara": true, "formalpara": true, "title": true,
+	"listitem": true, "itemizedlist": true, "orderedlist": true, "simplelist": true, "member": true,
+	"variablelist": true, "varlistentry": true, "term": true,
+	"example": true, "informalexample": true, "note": true, "warning": true, "tip": true,
+	"table": true, "informaltable": true, "tgroup": true, "thead": true, "tbody": true, "row": true,
+	"refsect2": true, "refsect3": true,
+}
+
+// verbatimElements keep their lines.
+var verbatimElements = map[string]bool{
+	"programlisting": true, "screen": true, "literallayout": true, "synopsis": true,
+}
+
+// render returns the documentation paragraphs of e.
+// Verbatim paragraphs, like program listings, keep their lines.
+func render(e *element) []string {
+	var r renderer
+	r.children(e)
+	r.flush()
+	return r.lines
+}
+
+type renderer struct {
+	lines []string
+	cur   strings.Builder
+}
+
+func (r *renderer) flush() {
+	if text := collapse(r.cur.String()); text != "" {
+		r.lines = append(r.lines, text)
+	}
+	r.cur.Reset()
+}
+
+func (r *renderer) children(e *element) {
+	for _, c := range e.Children {
+		switch c := c.(type) {
+		case string:
+			r.cur.WriteString(c)
+		case *element:
+			r.element(c)
+		}
+	}
+}
+
+func (r *renderer) element(e *element) {
+	switch name := e.Name.Local; {
+	case name == "version" || name == "indexterm" || name == "remark":
+		// not part of the text
+
+	case verbatimElements[name]:
+		r.flush()
+		var lines []string
+		for _, line := range strings.Split(e.text(), "\n") {
+			if line = strings.TrimSpace(line); line != "" {
+				lines = append(lines, line)
+			}
+		}
+		if len(lines) > 0 {
+			r.lines = append(r.lines, strings.Join(lines, "\n"))
+		}
+
+	case blockElements[name]:
+		r.flush()
+		r.children(e)
+		r.flush()
+
+	case name == "entry":
+		r.children(e)
+		r.cur.WriteByte(' ')
+
+	case name == "literal":
+		r.cur.WriteString(`"` + e.text() + `"`)
+
+	case name == "citerefentry":
+		var title, volume string
+		for _, t := range e.elements("refentrytitle") {
+			title = t.text()
+		}
+		for _, v := range e.elements("manvolnum") {
+			volume = v.text()
+		}
+		r.cur.WriteString(title + "(" + volume + ")")
+
+	case name == "ulink":
+		if text := collapse(e.text()); text != "" {
+			r.cur.WriteString(text)
+		} else {
+			r.cur.WriteString(e.attr("url"))
+		}
+
+	default:
+		r.children(e)
+	}
+}
+
+// collapse replaces runs of white space with a single space.
+func collapse(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
@@ -0,0 +1,456 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// config controls the generated code.
+type config struct {
+	Package string
+	Type    string // name of the file struct
+	// BoolType is the Go type of new keys taking a boolean.
+	BoolType string
+}
+
+// existingFile holds the declarations of a previously generated or hand written file.
+// Names and types of existing sections and keys are kept, so regenerating does not break the API.
+type existingFile struct {
+	Header string // comments in front of the package clause
+	Types  map[string]*existingType
+	Order  []string // type names in source order
+}
+
+type existingType struct {
+	Doc    string
+	Fields []*existingField
+	Source string // declaration including the doc comment
+}
+
+type existingField struct {
+	Name string
+	Type string
+	Tag  string // value of the systemd tag
+	Doc  string
+	// other tags than systemd, kept as is
+	OtherTags string
+}
+
+// systemdName returns the name of the section or key of the field.
+func (f *existingField) systemdName() string {
+	if name := strings.Split(f.Tag, ",")[0]; name != "" {
+		return name
+	}
+	return f.Name
+}
+
+// elemType returns the name of the section type of a file struct field.
+func (f *existingField) elemType() string {
+	return strings.TrimLeft(f.Type, "*[]")
+}
+
+func (e *existingFile) field(typeName, systemdName string) *existingField {
+	if e == nil || e.Types[typeName] == nil {
+		return nil
+	}
+	for _, f := range e.Types[typeName].Fields {
+		if f.systemdName() == systemdName {
+			return f
+		}
+	}
+	return nil
+}
+
+// parseExisting reads the type declarations of the Go source src.
+func parseExisting(filename string, src []byte) (*existingFile, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filename, src, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+
+	e := &existingFile{
+		Header: string(src[:fset.Position(file.Package).Offset]),
+		Types:  map[string]*existingType{},
+	}
+	source := func(from, to token.Pos) string {
+		return string(src[fset.Position(from).Offset:fset.Position(to).Offset])
+	}
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			spec := spec.(*ast.TypeSpec)
+			doc := spec.Doc
+			if doc == nil && len(gen.Specs) == 1 {
+				doc = gen.Doc
+			}
+			t := &existingType{
+				Doc:    strings.TrimSpace(doc.Text()),
+				Source: source(gen.Pos(), gen.End()),
+			}
+			if gen.Doc != nil {
+				t.Source = source(gen.Doc.Pos(), gen.End())
+			}
+			e.Types[spec.Name.Name] = t
+			e.Order = append(e.Order, spec.Name.Name)
+
+			st, ok := spec.Type.(*ast.StructType)
+			if !ok {
+				continue
+			}
+			for _, field := range st.Fields.List {
+				if len(field.Names) == 0 {
+					// embedded containers are regenerated
+					continue
+				}
+				f := existingField{
+					Type: source(field.Type.Pos(), field.Type.End()),
+					Doc:  strings.TrimSpace(field.Doc.Text()),
+				}
+				if field.Tag != nil {
+					tag, err := strconv.Unquote(field.Tag.Value)
+					if err != nil {
+						return nil, err
+					}
+					f.Tag = reflect.StructTag(tag).Get("systemd")
+					f.OtherTags = otherTags(tag)
+				}
+				// fields declared together, like "A, B string", are written one per line
+				for _, name := range field.Names {
+					if name.Name == "Comment" && f.Type == "string" {
+						continue
+					}
+					named := f
+					named.Name = name.Name
+					t.Fields = append(t.Fields, &named)
+				}
+			}
+		}
+	}
+	return e, nil
+}
+
+var structTag = regexp.MustCompile(`(\w+):"((?:[^"\\]|\\.)*)"`)
+
+// otherTags returns the struct tags in tag, except the systemd tag.
+func otherTags(tag string) string {
+	var out []string
+	for _, m := range structTag.FindAllStringSubmatch(tag, -1) {
+		if m[1] != "systemd" {
+			out = append(out, m[0])
+		}
+	}
+	return strings.Join(out, " ")
+}
+
+var (
+	takesBoolean = regexp.MustCompile(`^Takes a boolean( argument)?\.`)
+	repeatedKey  = regexp.MustCompile(`(?i)(may|can) be specified (more than once|multiple times)`)
+	listKey      = regexp.MustCompile(`(?i)^A (white)?space-separated list`)
+	nonIdent     = regexp.MustCompile(`[^A-Za-z0-9_]`)
+)
+
+// generate returns the Go source of the structs of the sections in p.
+// Sections and keys of existing, which may be nil, keep their names and types;
+// the ones not documented by p are kept in their place, see withUndocumented.
+func generate(p *page, c config, existing *existingFile) ([]byte, error) {
+	var (
+		buf       bytes.Buffer
+		fileDoc   = []string{capitalize(p.Purpose), "https://www.freedesktop.org/software/systemd/man/" + p.Name + ".html"}
+		fields    []string
+		sections  []string
+		typeNames = map[string]bool{c.Type: true}
+	)
+	marker := "// Code generated by systemd-gen from " + p.Name + ".xml. DO NOT EDIT.\n"
+	if existing != nil {
+		buf.WriteString(generatedMarker.ReplaceAllString(existing.Header, ""))
+		if !strings.HasSuffix(existing.Header, "\n\n") && existing.Header != "" {
+			buf.WriteString("\n")
+		}
+	}
+	buf.WriteString(marker + "\n")
+	fmt.Fprintf(&buf, "package %s\n\nimport %q\n\n", c.Package, "routerd.net/go-systemd")
+
+	var documented, undocumented []declaration
+	for _, s := range p.Sections {
+		f := existing.field(c.Type, s.Name)
+		if f == nil {
+			f = newSectionField(s)
+		}
+		typeName := f.elemType()
+		typeNames[typeName] = true
+
+		var doc []string
+		if len(s.Doc) > 0 {
+			doc = s.Doc
+		} else if t := existing.typ(typeName); t != nil && t.Doc != "" {
+			doc = []string{t.Doc}
+		}
+		documented = append(documented, declaration{
+			name:    s.Name,
+			field:   fieldSource(f),
+			section: sectionSource(typeName, doc, c, s, existing),
+		})
+	}
+
+	// sections not documented anymore
+	var existingNames []string
+	if t := existing.typ(c.Type); t != nil {
+		for _, f := range t.Fields {
+			existingNames = append(existingNames, f.systemdName())
+			if p.section(f.systemdName()) != nil {
+				continue
+			}
+			typeName := f.elemType()
+			typeNames[typeName] = true
+			d := declaration{name: f.systemdName(), field: fieldSource(f)}
+			if et := existing.typ(typeName); et != nil {
+				d.section = sectionSource(typeName, []string{et.Doc}, c, &section{}, existing)
+			}
+			undocumented = append(undocumented, d)
+		}
+	}
+	for _, d := range withUndocumented(documented, undocumented, existingNames) {
+		fields = append(fields, d.field)
+		if d.section != "" {
+			sections = append(sections, d.section)
+		}
+	}
+
+	writeDoc(&buf, "", fileDoc, false)
+	fmt.Fprintf(&buf, "type %s struct {\n\tsystemd.SectionList // SectionList to store unknown sections\n\n", c.Type)
+	buf.WriteString(strings.Join(fields, ""))
+	buf.WriteString("}\n")
+	for _, s := range sections {
+		buf.WriteString("\n" + s)
+	}
+
+	// other declarations of the existing file
+	if existing != nil {
+		for _, name := range existing.Order {
+			if !typeNames[name] {
+				buf.WriteString("\n" + existing.Types[name].Source + "\n")
+			}
+		}
+	}
+
+	out, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("formatting generated code: %w\n%s", err, buf.Bytes())
+	}
+	return out, nil
+}
+
+var generatedMarker = regexp.MustCompile(`(?m)^// Code generated .* DO NOT EDIT\.\n\n?`)
+
+func (e *existingFile) typ(name string) *existingType {
+	if e == nil {
+		return nil
+	}
+	return e.Types[name]
+}
+
+// newSectionField returns the file struct field of a new section.
+// Repeated sections are stored in slices named in plural.
+func newSectionField(s *section) *existingField {
+	name := identifier(s.Name)
+	f := &existingField{Name: name, Type: "*" + name + "Section"}
+	if s.Repeated {
+		f.Name = plural(name)
+		f.Type = "[]" + name + "Section"
+	}
+	if f.Name != s.Name {
+		f.Tag = s.Name
+	}
+	return f
+}
+
+// newKeyField returns the section struct field of a new key,
+// with its type guessed from the documentation.
+func newKeyField(k *key, c config) *existingField {
+	f := &existingField{Name: identifier(k.Name), Type: "string", Tag: ",omitempty"}
+	if f.Name != k.Name {
+		f.Tag = k.Name + f.Tag
+	}
+	if len(k.Doc) > 0 {
+		switch doc := strings.Join(k.Doc, " "); {
+		case takesBoolean.MatchString(doc):
+			f.Type = c.BoolType
+		case listKey.MatchString(doc):
+			f.Type = "[]string"
+			f.Tag += ",wslist"
+		case repeatedKey.MatchString(doc):
+			f.Type = "[]string"
+		}
+	}
+	return f
+}
+
+// sectionSource returns the declaration of the section struct typeName.
+func sectionSource(typeName string, doc []string, c config, s *section, existing *existingFile) string {
+	var buf bytes.Buffer
+	writeDoc(&buf, "", doc, true)
+	fmt.Fprintf(&buf, "type %s struct {\n", typeName)
+	buf.WriteString("\tsystemd.KeyList // KeyList to store unknown keys\n")
+	buf.WriteString("\tComment string // Section Comment\n")
+	buf.WriteString("\tsystemd.KeyComments\n")
+
+	var documented, undocumented []declaration
+	for _, k := range s.Keys {
+		f := existing.field(typeName, k.Name)
+		if f == nil {
+			f = newKeyField(k, c)
+		}
+		f.Tag = withSince(f.Tag, k.Since)
+		var field bytes.Buffer
+		writeDoc(&field, "\t", k.Doc, false)
+		field.WriteString("\t" + fieldSource(f))
+		documented = append(documented, declaration{name: k.Name, field: field.String()})
+	}
+
+	// keys not documented anymore
+	var existingNames []string
+	if t := existing.typ(typeName); t != nil {
+		for _, f := range t.Fields {
+			existingNames = append(existingNames, f.systemdName())
+			if s.key(f.systemdName()) != nil {
+				continue
+			}
+			var field bytes.Buffer
+			writeDoc(&field, "\t", []string{f.Doc}, false)
+			field.WriteString("\t" + fieldSource(f))
+			undocumented = append(undocumented, declaration{name: f.systemdName(), field: field.String()})
+		}
+	}
+	for _, d := range withUndocumented(documented, undocumented, existingNames) {
+		buf.WriteString("\n" + d.field)
+	}
+	buf.WriteString("}\n")
+	return buf.String()
+}
+
+// A declaration is the source of the field of a section or key,
+// and the source of the struct of a section.
+type declaration struct {
+	name    string // name of the section or key
+	field   string
+	section string
+}
+
+// withUndocumented returns the documented declarations, in the order of the page,
+// with the undocumented ones inserted after the declaration preceding them
+// in the existing struct, whose fields are named by existing.
+// This keeps the place of sections and keys missing from the page,
+// like the ones added by hand for newer systemd versions.
+func withUndocumented(documented, undocumented []declaration, existing []string) []declaration {
+	out := append([]declaration(nil), documented...)
+	pos := 0 // insertion index of the next undocumented declaration
+	for _, name := range existing {
+		if d, ok := findDeclaration(undocumented, name); ok {
+			out = append(out[:pos], append([]declaration{d}, out[pos:]...)...)
+			pos++
+			continue
+		}
+		for i, d := range out {
+			if d.name == name {
+				pos = i + 1
+				break
+			}
+		}
+	}
+	return out
+}
+
+func findDeclaration(decls []declaration, name string) (declaration, bool) {
+	for _, d := range decls {
+		if d.name == name {
+			return d, true
+		}
+	}
+	return declaration{}, false
+}
+
+// withSince sets the since option of the systemd tag to version, if it is known.
+func withSince(tag string, version int) string {
+	if version == 0 {
+		return tag
+	}
+	opts := strings.Split(tag, ",")
+	out := opts[:1]
+	for _, opt := range opts[1:] {
+		if !strings.HasPrefix(opt, "since=") {
+			out = append(out, opt)
+		}
+	}
+	return strings.Join(append(out, "since="+strconv.Itoa(version)), ",")
+}
+
+func fieldSource(f *existingField) string {
+	var tags []string
+	if f.Tag != "" {
+		tags = append(tags, "systemd:"+strconv.Quote(f.Tag))
+	}
+	if f.OtherTags != "" {
+		tags = append(tags, f.OtherTags)
+	}
+	if len(tags) == 0 {
+		return f.Name + " " + f.Type + "\n"
+	}
+	return f.Name + " " + f.Type + " `" + strings.Join(tags, " ") + "`\n"
+}
+
+// writeDoc writes the paragraphs as a comment, separated by empty comment lines if blank is set.
+func writeDoc(buf *bytes.Buffer, indent string, paragraphs []string, blank bool) {
+	first := true
+	for _, p := range paragraphs {
+		if p == "" {
+			continue
+		}
+		if blank && !first {
+			buf.WriteString(indent + "//\n")
+		}
+		first = false
+		for _, line := range strings.Split(p, "\n") {
+			if line == "" {
+				buf.WriteString(indent + "//\n")
+				continue
+			}
+			buf.WriteString(indent + "// " + line + "\n")
+		}
+	}
+}
+
+// identifier returns the Go name of a section or key.
+func identifier(name string) string {
+	return nonIdent.ReplaceAllString(name, "")
+}
+
+// plural returns the plural of the field name.
+func plural(name string) string {
+	switch {
+	case strings.HasSuffix(name, "s"), strings.HasSuffix(name, "x"),
+		strings.HasSuffix(name, "ch"), strings.HasSuffix(name, "sh"):
+		return name + "es"
+	case strings.HasSuffix(name, "y") && len(name) > 1 && !strings.ContainsAny(name[len(name)-2:len(name)-1], "aeiou"):
+		return name[:len(name)-1] + "ies"
+	}
+	return name + "s"
+}
+
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
@@ -0,0 +1,227 @@
+package main
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadPage(t *testing.T) {
+	p, err := readPage("testdata/man", "systemd.network.xml")
+	require.NoError(t, err)
+
+	assert.Equal(t, "systemd.network", p.Name)
+	assert.Equal(t, "Network configuration", p.Purpose)
+	var names []string
+	for _, s := range p.Sections {
+		names = append(names, s.Name)
+	}
+	assert.Equal(t, []string{"Match", "Network", "IPoIB", "DHCPServerStaticLease"}, names)
+
+	match := p.Sections[0]
+	assert.False(t, match.Repeated)
+	assert.Equal(t, []string{"The network file contains a [Match] section, which determines if a given network file may be applied to a given device."}, match.Doc)
+	// included from systemd.link.xml
+	assert.Equal(t, &key{
+		Name: "MACAddress",
+		Doc: []string{
+			"A whitespace-separated list of hardware addresses. See the example below.",
+			"Example:",
+			"MACAddress=01:23:45:67:89:ab 00-11-22-33-44-55 AABB.CCDD.EEFF",
+		},
+		Since: 211,
+	}, match.Keys[0])
+	assert.Equal(t, []string{`A whitespace-separated list of shell-style globs matching the device name, as exposed by the udev property "INTERFACE".`}, match.Keys[1].Doc)
+
+	// keys sharing a varlistentry
+	network := p.Sections[1]
+	require.Len(t, network.Keys, 3)
+	assert.Equal(t, "IPoIB", network.Keys[1].Name)
+	assert.Equal(t, "KeepMaster", network.Keys[2].Name)
+	assert.Equal(t, []string{"Takes a boolean. Example:", "[Network]\nKeepMaster=yes"}, network.Keys[2].Doc)
+	assert.Equal(t, 254, network.Keys[2].Since)
+	assert.Equal(t, 0, network.Keys[0].Since)
+
+	assert.True(t, p.Sections[3].Repeated)
+
+	_, err = readPage("testdata/man", "systemd.netdev.xml")
+	assert.Error(t, err)
+}
+
+func TestGenerate(t *testing.T) {
+	p, err := readPage("testdata/man", "systemd.network.xml")
+	require.NoError(t, err)
+
+	t.Run("new", func(t *testing.T) {
+		out, err := generate(p, config{Package: "network", Type: "Network", BoolType: "*bool"}, nil)
+		require.NoError(t, err)
+		assertGolden(t, "testdata/network.golden", out)
+	})
+
+	t.Run("existing", func(t *testing.T) {
+		src, err := ioutil.ReadFile("testdata/existing.go")
+		require.NoError(t, err)
+		existing, err := parseExisting("existing.go", src)
+		require.NoError(t, err)
+
+		c := config{Package: "network", Type: "Network", BoolType: "string"}
+		out, err := generate(p, c, existing)
+		require.NoError(t, err)
+		assertGolden(t, "testdata/existing.golden", out)
+
+		// regenerating keeps the file
+		existing, err = parseExisting("existing.go", out)
+		require.NoError(t, err)
+		again, err := generate(p, c, existing)
+		require.NoError(t, err)
+		assert.Equal(t, string(out), string(again))
+	})
+}
+
+// TestRegenerate checks that regenerating the structs of the network package
+// keeps all of their fields, which are used by the generated methods,
+// including the sections added by hand for systemd versions newer than the page,
+// with their tags and their place.
+func TestRegenerate(t *testing.T) {
+	p, err := readPage("testdata/man", "systemd.network.xml")
+	require.NoError(t, err)
+	src, err := ioutil.ReadFile("../../network/network.go")
+	require.NoError(t, err)
+	existing, err := parseExisting("network.go", src)
+	require.NoError(t, err)
+
+	out, err := generate(p, config{Package: "network", Type: "Network", BoolType: "*bool"}, existing)
+	require.NoError(t, err)
+	fields, regenerated := structFields(t, src), structFields(t, out)
+	for name, field := range fields {
+		if !assert.Contains(t, regenerated, name) {
+			continue
+		}
+		assert.Equal(t, field.Type, regenerated[name].Type, name)
+		assert.Equal(t, field.Tag, regenerated[name].Tag, name)
+	}
+
+	// sections not documented by the page stay behind the same section
+	assert.Equal(t, fields["Network.DHCPv6"].Index+1, fields["Network.DHCPPrefixDelegation"].Index)
+	assert.Equal(t, regenerated["Network.DHCPv6"].Index+1, regenerated["Network.DHCPPrefixDelegation"].Index)
+}
+
+type structField struct {
+	Type  string
+	Tag   string // systemd tag, without the since option, which is taken from the page
+	Index int    // position in the struct
+}
+
+// structFields returns the fields of all structs declared in src,
+// by "Type.Field".
+func structFields(t *testing.T, src []byte) map[string]structField {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", src, 0)
+	require.NoError(t, err)
+
+	fields := map[string]structField{}
+	ast.Inspect(file, func(n ast.Node) bool {
+		spec, ok := n.(*ast.TypeSpec)
+		if !ok {
+			return true
+		}
+		if st, ok := spec.Type.(*ast.StructType); ok {
+			for i, field := range st.Fields.List {
+				f := structField{
+					Type:  string(src[fset.Position(field.Type.Pos()).Offset:fset.Position(field.Type.End()).Offset]),
+					Index: i,
+				}
+				if field.Tag != nil {
+					tag, err := strconv.Unquote(field.Tag.Value)
+					require.NoError(t, err)
+					f.Tag = withoutSince(reflect.StructTag(tag).Get("systemd"))
+				}
+				for _, name := range field.Names {
+					fields[spec.Name.Name+"."+name.Name] = f
+				}
+			}
+		}
+		return false
+	})
+	return fields
+}
+
+// withoutSince removes the since option from the systemd tag.
+func withoutSince(tag string) string {
+	var opts []string
+	for _, opt := range strings.Split(tag, ",") {
+		if !strings.HasPrefix(opt, "since=") {
+			opts = append(opts, opt)
+		}
+	}
+	return strings.Join(opts, ",")
+}
+
+func assertGolden(t *testing.T, filename string, out []byte) {
+	t.Helper()
+	want, err := ioutil.ReadFile(filepath.FromSlash(filename))
+	require.NoError(t, err)
+	assert.Equal(t, string(want), string(out))
+}
+
+func TestNewKeyField(t *testing.T) {
+	tests := []struct {
+		Key  key
+		Want existingField
+	}{
+		{
+			Key:  key{Name: "Description", Doc: []string{"A free-form description."}},
+			Want: existingField{Name: "Description", Type: "string", Tag: ",omitempty"},
+		},
+		{
+			Key:  key{Name: "ARP", Doc: []string{"Takes a boolean. Enables ARP."}},
+			Want: existingField{Name: "ARP", Type: "*bool", Tag: ",omitempty"},
+		},
+		{
+			Key:  key{Name: "Name", Doc: []string{"A whitespace-separated list of globs."}},
+			Want: existingField{Name: "Name", Type: "[]string", Tag: ",omitempty,wslist"},
+		},
+		{
+			Key:  key{Name: "DNS", Doc: []string{"A DNS server address. This option may be specified more than once."}},
+			Want: existingField{Name: "DNS", Type: "[]string", Tag: ",omitempty"},
+		},
+		{
+			Key:  key{Name: "IPv6-Token"},
+			Want: existingField{Name: "IPv6Token", Type: "string", Tag: "IPv6-Token,omitempty"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Key.Name, func(t *testing.T) {
+			assert.Equal(t, &test.Want, newKeyField(&test.Key, config{BoolType: "*bool"}))
+		})
+	}
+}
+
+func TestPlural(t *testing.T) {
+	for name, want := range map[string]string{
+		"Address":           "Addresses",
+		"Route":             "Routes",
+		"IPv6Prefix":        "IPv6Prefixes",
+		"BridgeVLAN":        "BridgeVLANs",
+		"Policy":            "Policies",
+		"Key":               "Keys",
+		"HeavyHitterFilter": "HeavyHitterFilters",
+	} {
+		assert.Equal(t, want, plural(name), name)
+	}
+}
+
+func TestWithSince(t *testing.T) {
+	assert.Equal(t, ",omitempty", withSince(",omitempty", 0))
+	assert.Equal(t, ",omitempty,since=245", withSince(",omitempty", 245))
+	assert.Equal(t, "Name,omitempty,wslist,since=250", withSince("Name,omitempty,since=240,wslist", 250))
+}
@@ -0,0 +1,103 @@
+// Command systemd-gen generates the section structs of a configuration file
+// from the docbook sources of its systemd man page.
+//
+// Usage:
+//
+//	systemd-gen -man dir -pkg name -type name [flags] page.xml
+//
+// The page is read from the man directory of a systemd source checkout,
+// where pages included by it are looked up as well. Every "[Name] Section Options"
+// part of the page becomes a section struct holding the documented keys,
+// with the documentation copied into the doc comments and the version
+// adding a key in the since option of its tag.
+//
+// If the output file exists, the names, types and tags of its sections
+// and keys are kept, so regenerating does not break users of the package.
+// Sections and keys which are not documented by the page are kept as well,
+// behind the section or key preceding them in the output file. This covers
+// sections and keys added by hand before the page of a newer systemd
+// version is available.
+// The types of new keys are guessed from their documentation.
+//
+// It is meant to be run by go generate with the checkout in $SYSTEMD_SRC:
+//
+//	//go:generate go run ../cmd/systemd-gen -pkg network -type Network -o network.go systemd.network.xml
+//
+// Without -man, the man directory of $SYSTEMD_SRC is read. If SYSTEMD_SRC
+// is not set either, nothing is generated, so go generate can run the
+// following directives without a systemd checkout.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+var (
+	manDir   = flag.String("man", "", "man `dir` of the systemd sources, defaults to $SYSTEMD_SRC/man")
+	pkg      = flag.String("pkg", "", "package `name` of the generated code")
+	typeName = flag.String("type", "", "`name` of the struct of the file")
+	boolType = flag.String("bool-type", "*bool", "Go `type` of new keys taking a boolean")
+	output   = flag.String("o", "", "write output to `file` instead of stdout, keeping its declarations")
+)
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: systemd-gen -man dir -pkg name -type name [flags] page.xml\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 1 || *pkg == "" || *typeName == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if *manDir == "" {
+		src := os.Getenv("SYSTEMD_SRC")
+		if src == "" {
+			fmt.Fprintln(os.Stderr, "systemd-gen: SYSTEMD_SRC is not set, skipping", flag.Arg(0))
+			return
+		}
+		*manDir = filepath.Join(src, "man")
+	}
+
+	if err := run(flag.Arg(0)); err != nil {
+		fmt.Fprintln(os.Stderr, "systemd-gen:", err)
+		os.Exit(1)
+	}
+}
+
+func run(pageName string) error {
+	p, err := readPage(*manDir, pageName)
+	if err != nil {
+		return err
+	}
+	if len(p.Sections) == 0 {
+		return fmt.Errorf("%s: no sections documented", pageName)
+	}
+
+	var existing *existingFile
+	if *output != "" {
+		src, err := ioutil.ReadFile(*output)
+		switch {
+		case err == nil:
+			if existing, err = parseExisting(*output, src); err != nil {
+				return err
+			}
+		case !os.IsNotExist(err):
+			return err
+		}
+	}
+
+	out, err := generate(p, config{Package: *pkg, Type: *typeName, BoolType: *boolType}, existing)
+	if err != nil {
+		return err
+	}
+	if *output == "" {
+		_, err = os.Stdout.Write(out)
+		return err
+	}
+	return ioutil.WriteFile(*output, out, 0666)
+}
@@ -0,0 +1,51 @@
+/*
+Copyright 2020 The routerd Authors.
+*/
+
+package network
+
+import "routerd.net/go-systemd"
+
+// Network configuration
+type Network struct {
+	systemd.SectionList // SectionList to store unknown sections
+
+	Match                  *MatchSection
+	Network                *NetworkSection
+	DHCPv6PrefixDelegation *DHCPv6PrefixDelegationSection
+}
+
+type MatchSection struct {
+	systemd.KeyList        // KeyList to store unknown keys
+	Comment         string // Section Comment
+	systemd.KeyComments
+
+	// Outdated documentation.
+	MACAddresses []string `systemd:"MACAddress,omitempty,wslist" json:"macAddresses"`
+
+	// Not documented anymore.
+	Driver string `systemd:",omitempty"`
+
+	// Declared together.
+	Host, Virtualization string `systemd:",omitempty"`
+}
+
+type NetworkSection struct {
+	systemd.KeyList        // KeyList to store unknown keys
+	Comment         string // Section Comment
+	systemd.KeyComments
+
+	DHCP string `systemd:",omitempty,since=200"`
+}
+
+// Renamed upstream.
+type DHCPv6PrefixDelegationSection struct {
+	systemd.KeyList        // KeyList to store unknown keys
+	Comment         string // Section Comment
+	systemd.KeyComments
+
+	SubnetId string `systemd:",omitempty"`
+}
+
+// Mode is not a section.
+type Mode string
@@ -0,0 +1,310 @@
+package main
+
+import (
+	"errors"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"routerd.net/go-systemd"
+	"routerd.net/go-systemd/internal/encoding"
+	"routerd.net/go-systemd/link"
+	"routerd.net/go-systemd/netdev"
+	"routerd.net/go-systemd/network"
+)
+
+// Severity of a Diagnostic.
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+// A Diagnostic describes a single problem found in a file.
+type Diagnostic struct {
+	Pos      systemd.Position
+	Rule     string
+	Severity Severity
+	Message  string
+}
+
+func (d Diagnostic) String() string {
+	return d.Pos.String() + ": " + d.Message + " (" + d.Rule + ")"
+}
+
+// Rules reported by the linter.
+const (
+	ruleSyntax       = "syntax"
+	ruleUnknown      = "unknown-key"
+	ruleInvalidValue = "invalid-value"
+	ruleInvalidBool  = "invalid-bool"
+	ruleConflict     = "conflict"
+	ruleDeprecated   = "deprecated"
+)
+
+// ruleDescriptions describes all rules, used for SARIF output.
+var ruleDescriptions = map[string]string{
+	ruleSyntax:       "The file cannot be parsed.",
+	ruleUnknown:      "The section or key is not known and ignored by systemd.",
+	ruleInvalidValue: "The value cannot be parsed and is ignored by systemd.",
+	ruleInvalidBool:  "The value is not a valid boolean and is ignored by systemd.",
+	ruleConflict:     "The option conflicts with other options.",
+	ruleDeprecated:   "The section, key or value is deprecated.",
+}
+
+// fileKind describes a supported type of configuration file.
+type fileKind struct {
+	name       string
+	new        func() interface{} // returns a pointer to the file struct
+	values     map[string]map[string]valueRule
+	deprecated []deprecation
+	conflicts  []func(file *systemd.File) []Diagnostic
+}
+
+// kinds maps file extensions to the supported kinds of files.
+var kinds = map[string]*fileKind{
+	".network": {
+		name:       "network",
+		new:        func() interface{} { return &network.Network{} },
+		values:     networkValues,
+		deprecated: networkDeprecations,
+		conflicts:  networkConflicts,
+	},
+	".netdev": {
+		name:       "netdev",
+		new:        func() interface{} { return &netdev.NetDev{} },
+		values:     netdevValues,
+		deprecated: netdevDeprecations,
+		conflicts:  netdevConflicts,
+	},
+	".link": {
+		name:      "link",
+		new:       func() interface{} { return &link.Link{} },
+		values:    linkValues,
+		conflicts: linkConflicts,
+	},
+}
+
+// kindOf returns the kind of the named file, or nil if it is not supported.
+func kindOf(name string) *fileKind {
+	return kinds[filepath.Ext(name)]
+}
+
+// lint checks src, the contents of the named file, and returns all problems found,
+// ordered by position. The kind of file is determined by its extension.
+func lint(name string, src []byte) []Diagnostic {
+	kind := kindOf(name)
+	if kind == nil {
+		return []Diagnostic{{
+			Pos:      systemd.Position{Filename: name},
+			Rule:     ruleSyntax,
+			Severity: SeverityError,
+			Message:  "unsupported file type " + filepath.Ext(name),
+		}}
+	}
+
+	file, err := systemd.DecodeOptions{Filename: name}.Decode(src)
+	if err != nil {
+		d := Diagnostic{
+			Pos:      systemd.Position{Filename: name},
+			Rule:     ruleSyntax,
+			Severity: SeverityError,
+			Message:  err.Error(),
+		}
+		var syntaxErr *systemd.SyntaxError
+		if errors.As(err, &syntaxErr) {
+			d.Pos, d.Message = syntaxErr.Pos, syntaxErr.Msg
+		}
+		return []Diagnostic{d}
+	}
+
+	var diags []Diagnostic
+	deprecated := map[systemd.Position]bool{}
+	for _, d := range kind.checkDeprecated(file) {
+		deprecated[d.Pos] = true
+		diags = append(diags, d)
+	}
+	diags = append(diags, kind.checkValues(file)...)
+	for _, d := range kind.unmarshal(file) {
+		if d.Rule == ruleUnknown && deprecated[d.Pos] {
+			// already reported with a replacement
+			continue
+		}
+		diags = append(diags, d)
+	}
+	for _, check := range kind.conflicts {
+		diags = append(diags, check(file)...)
+	}
+
+	sort.SliceStable(diags, func(i, j int) bool {
+		a, b := diags[i].Pos, diags[j].Pos
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Column < b.Column
+	})
+	return diags
+}
+
+// unmarshal unmarshals file into the struct of the file kind,
+// reporting values that cannot be unmarshaled as well as unknown sections and keys.
+func (k *fileKind) unmarshal(file *systemd.File) []Diagnostic {
+	var diags []Diagnostic
+	file = copyFile(file)
+	for {
+		err := file.UnmarshalStrict(k.new())
+		var (
+			typeErr    *systemd.UnmarshalTypeError
+			unknownErr *systemd.UnknownFieldsError
+		)
+		switch {
+		case errors.As(err, &typeErr):
+			diags = append(diags, Diagnostic{
+				Pos:      typeErr.Pos,
+				Rule:     ruleInvalidValue,
+				Severity: SeverityError,
+				Message:  "invalid value " + quote(typeErr.Value) + " for " + typeErr.Key + ": " + typeErr.Err.Error(),
+			})
+			if !deleteKey(file, typeErr.Pos) {
+				return diags
+			}
+			// unmarshal stops at the first invalid value, try again without it
+			continue
+
+		case errors.As(err, &unknownErr):
+			for _, e := range unknownErr.Errors {
+				diags = append(diags, Diagnostic{
+					Pos:      e.Pos,
+					Rule:     ruleUnknown,
+					Severity: SeverityError,
+					Message:  strings.TrimPrefix(e.Error(), e.Pos.String()+": "),
+				})
+			}
+
+		case err != nil:
+			diags = append(diags, Diagnostic{
+				Pos:      systemd.Position{Filename: file.Name},
+				Rule:     ruleSyntax,
+				Severity: SeverityError,
+				Message:  err.Error(),
+			})
+		}
+		return diags
+	}
+}
+
+// checkValues validates the values of keys with a value rule
+// or a boolean struct field, which are silently ignored by Unmarshal if invalid.
+func (k *fileKind) checkValues(file *systemd.File) []Diagnostic {
+	var (
+		diags []Diagnostic
+		types = fieldTypes(reflect.TypeOf(k.new()).Elem())
+	)
+	for _, section := range file.Sections {
+		for _, key := range section.Keys {
+			if key.Value == "" {
+				// resets the key to its default
+				continue
+			}
+			rule, ok := k.values[section.Name][key.Name]
+			if !ok {
+				keys, ok := types[section.Name]
+				if !ok {
+					keys = types[""]
+				}
+				t, known := keys[key.Name]
+				if !known || !isBool(t) {
+					continue
+				}
+				rule = valueRule{Kind: kindBool}
+			}
+			if d, ok := rule.check(key); !ok {
+				diags = append(diags, d)
+			}
+		}
+	}
+	return diags
+}
+
+// checkDeprecated reports all deprecated sections, keys and values.
+func (k *fileKind) checkDeprecated(file *systemd.File) []Diagnostic {
+	var diags []Diagnostic
+	for _, section := range file.Sections {
+		for _, dep := range k.deprecated {
+			if dep.Section != section.Name {
+				continue
+			}
+			if dep.Key == "" {
+				diags = append(diags, dep.diagnostic(section.Pos))
+				continue
+			}
+			for _, key := range section.Keys {
+				if key.Name == dep.Key && (dep.Value == "" || key.Value == dep.Value) {
+					diags = append(diags, dep.diagnostic(key.Pos))
+				}
+			}
+		}
+	}
+	return diags
+}
+
+// fieldTypes returns the types of all key fields of the file struct type t,
+// by section and key name, resolved like Unmarshal does.
+// The keys of sections stored in a map are stored by the empty section name.
+func fieldTypes(t reflect.Type) map[string]map[string]reflect.Type {
+	types := map[string]map[string]reflect.Type{}
+	info := encoding.TypeInfo(t)
+	sections := info.Fields
+	if info.Map != nil {
+		m := *info.Map
+		m.Name = ""
+		sections = append(sections[:len(sections):len(sections)], m)
+	}
+	for _, section := range sections {
+		st := section.Section()
+		if st == nil {
+			continue
+		}
+		keys := map[string]reflect.Type{}
+		for _, key := range encoding.TypeInfo(st).Fields {
+			keys[key.Name] = key.Type
+		}
+		types[section.Name] = keys
+	}
+	return types
+}
+
+// isBool returns true for boolean types and pointers or slices of them.
+func isBool(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.Bool
+}
+
+// deleteKey removes the key at pos from file and reports whether it was found.
+func deleteKey(file *systemd.File, pos systemd.Position) bool {
+	for i := range file.Sections {
+		section := &file.Sections[i]
+		for j := range section.Keys {
+			if section.Keys[j].Pos == pos {
+				section.DeleteKey(j)
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// copyFile returns a copy of file that can be modified without affecting file.
+func copyFile(file *systemd.File) *systemd.File {
+	c := *file
+	c.Sections = make([]systemd.Section, len(file.Sections))
+	for i, section := range file.Sections {
+		section.Keys = append([]systemd.Key(nil), section.Keys...)
+		c.Sections[i] = section
+	}
+	return &c
+}
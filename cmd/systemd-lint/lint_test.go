@@ -0,0 +1,289 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"routerd.net/go-systemd"
+)
+
+func TestLint(t *testing.T) {
+	tests := []struct {
+		Name  string
+		File  string
+		Input string
+		Diags []string
+	}{
+		{
+			Name:  "valid",
+			File:  "10-eth0.network",
+			Input: "[Match]\nName=eth0\n\n[Network]\nAddress=10.0.0.2/24\nGateway=10.0.0.1\nDHCP=yes\n",
+		},
+		{
+			Name: "network",
+			File: "10-eth0.network",
+			Input: `[Match]
+MACAddress=00:11:22:33:44:55 zz
+
+[Link]
+ARP=maybe
+Unmanaged=no
+
+[Network]
+Address=10.0.0.300/24
+Gateway=_dhcp4
+Bond=bond0
+Bridge=br0
+IPv4LL=yes
+DHCP=both
+Adress=10.0.0.1
+
+[DHCP]
+UseDNS=yes
+
+[Route]
+Type=blackhole
+Gateway=10.0.0.1
+`,
+			Diags: []string{
+				`10-eth0.network:2:1: invalid MAC address "zz" for MACAddress, the key is ignored (invalid-value)`,
+				`10-eth0.network:5:1: invalid boolean "maybe" for ARP, the key is ignored (invalid-bool)`,
+				`10-eth0.network:9:1: invalid IP prefix "10.0.0.300/24" for Address, the key is ignored (invalid-value)`,
+				`10-eth0.network:12:1: Bridge= conflicts with Bond=, an interface can only have one master (conflict)`,
+				`10-eth0.network:13:1: key IPv4LL in section [Network] is deprecated, use LinkLocalAddressing= instead (deprecated)`,
+				`10-eth0.network:14:1: DHCP=both is deprecated, use DHCP=yes instead (deprecated)`,
+				`10-eth0.network:15:1: unknown key Adress in section [Network], did you mean Address? (unknown-key)`,
+				`10-eth0.network:17:1: section [DHCP] is deprecated, use [DHCPv4] instead (deprecated)`,
+				`10-eth0.network:22:1: Gateway= conflicts with Type=blackhole (conflict)`,
+			},
+		},
+		{
+			Name:  "renamed section",
+			File:  "10-eth0.network",
+			Input: "[Network]\nDHCPPrefixDelegation=yes\n\n[DHCPv6PrefixDelegation]\nSubnetId=1\n",
+			Diags: []string{
+				`10-eth0.network:4:1: section [DHCPv6PrefixDelegation] is deprecated, use [DHCPPrefixDelegation] instead (deprecated)`,
+			},
+		},
+		{
+			Name:  "unmanaged",
+			File:  "10-eth0.network",
+			Input: "[Match]\nName=eth0\n[Link]\nUnmanaged=yes\n[Network]\nDHCP=yes\n",
+			Diags: []string{
+				`10-eth0.network:5:1: section [Network] is ignored, because the link is unmanaged (conflict)`,
+			},
+		},
+		{
+			Name: "netdev",
+			File: "vxlan.netdev",
+			Input: `[NetDev]
+Name=vxlan1
+Kind=vxlan
+MACAddress=00:11:22:33:44:55
+
+[VXLAN]
+Id=1
+Remote=10.0.0.256
+MacLearning=sometimes
+FDBAgeingSec=5 minutes
+
+[VLAN]
+Id=1
+
+[Bond]
+MIIMonitorSec=soon
+`,
+			Diags: []string{
+				`vxlan.netdev:7:1: key Id in section [VXLAN] is deprecated, use VNI= instead (deprecated)`,
+				`vxlan.netdev:8:1: invalid IP address "10.0.0.256" for Remote, the key is ignored (invalid-value)`,
+				`vxlan.netdev:9:1: invalid boolean "sometimes" for MacLearning, the key is ignored (invalid-bool)`,
+				`vxlan.netdev:12:1: section [VLAN] conflicts with Kind=vxlan (conflict)`,
+				`vxlan.netdev:15:1: section [Bond] conflicts with Kind=vxlan (conflict)`,
+				`vxlan.netdev:16:1: invalid time span "soon" for MIIMonitorSec, the key is ignored (invalid-value)`,
+			},
+		},
+		{
+			Name: "link",
+			File: "10-wlan.link",
+			Input: `[Match]
+MACAddress=00:11:22:33:44:55
+
+[Link]
+MACAddressPolicy=random
+MACAddress=00:11:22:33:44:66
+TransmitQueues=many
+ReceiveQueues=lots
+AutoNegotiation=maybe
+`,
+			Diags: []string{
+				`10-wlan.link:6:1: MACAddress= is ignored, because of MACAddressPolicy=random (conflict)`,
+				`10-wlan.link:7:1: invalid value "many" for TransmitQueues: invalid syntax (invalid-value)`,
+				`10-wlan.link:8:1: invalid value "lots" for ReceiveQueues: invalid syntax (invalid-value)`,
+				`10-wlan.link:9:1: invalid boolean "maybe" for AutoNegotiation, the key is ignored (invalid-bool)`,
+			},
+		},
+		{
+			Name:  "syntax error",
+			File:  "10-eth0.network",
+			Input: "Name=eth0\n",
+			Diags: []string{
+				`10-eth0.network:1:1: key started outside of section "Name" (syntax)`,
+			},
+		},
+		{
+			Name:  "unsupported",
+			File:  "foo.service",
+			Input: "[Unit]\n",
+			Diags: []string{
+				`foo.service: unsupported file type .service (syntax)`,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			var diags []string
+			for _, d := range lint(test.File, []byte(test.Input)) {
+				diags = append(diags, d.String())
+			}
+			assert.Equal(t, test.Diags, diags)
+		})
+	}
+}
+
+func TestValueRules(t *testing.T) {
+	// all value rules refer to string fields, booleans are checked by type
+	for ext, kind := range kinds {
+		types := fieldTypes(reflect.TypeOf(kind.new()).Elem())
+		for section, keys := range kind.values {
+			for key := range keys {
+				ft, ok := types[section][key]
+				if assert.True(t, ok, "%s: [%s] %s", ext, section, key) {
+					assert.False(t, isBool(ft), "%s: [%s] %s", ext, section, key)
+				}
+			}
+		}
+	}
+}
+
+func TestFieldTypes(t *testing.T) {
+	type shared struct {
+		MTUBytes string
+	}
+	type section struct {
+		shared
+		Enabled  *bool  `systemd:"Enable"`
+		Internal string `systemd:"-"`
+	}
+	type Sections struct {
+		Other *section
+	}
+	type file struct {
+		systemd.SectionList
+		*Sections
+		Main  *section `systemd:"Section"`
+		Extra map[string]*section
+	}
+
+	keys := map[string]reflect.Type{
+		"MTUBytes": reflect.TypeOf(""),
+		"Enable":   reflect.TypeOf((*bool)(nil)),
+	}
+	assert.Equal(t, map[string]map[string]reflect.Type{
+		"Section": keys,
+		"Other":   keys,
+		"":        keys,
+	}, fieldTypes(reflect.TypeOf(file{})))
+}
+
+func TestLintPaths(t *testing.T) {
+	root := t.TempDir()
+	for name, content := range map[string]string{
+		"a/10-eth0.network": "[Network]\nDHCP=both\n",
+		"a/README":          "not a network file",
+		"b.netdev":          "[NetDev]\nName=br0\nKind=bridge\n",
+	} {
+		path := filepath.Join(root, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, ioutil.WriteFile(path, []byte(content), 0644))
+	}
+
+	diags, err := lintPaths([]string{filepath.Join(root, "a"), filepath.Join(root, "b.netdev")})
+	require.NoError(t, err)
+	require.Len(t, diags, 1)
+	assert.Equal(t, filepath.Join(root, "a/10-eth0.network"), diags[0].Pos.Filename)
+	assert.Equal(t, ruleDeprecated, diags[0].Rule)
+
+	_, err = lintPaths([]string{filepath.Join(root, "missing")})
+	assert.True(t, os.IsNotExist(err))
+}
+
+var testDiags = []Diagnostic{
+	{
+		Pos:      systemd.Position{Filename: "10-eth0.network", Line: 5, Column: 1},
+		Rule:     ruleInvalidBool,
+		Severity: SeverityError,
+		Message:  `invalid boolean "maybe" for ARP, the key is ignored`,
+	},
+	{
+		Pos:      systemd.Position{Filename: "foo.service"},
+		Rule:     ruleSyntax,
+		Severity: SeverityError,
+		Message:  "unsupported file type .service",
+	},
+}
+
+func TestWriteJSON(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, writeJSON(&buf, testDiags))
+	assert.JSONEq(t, `[
+		{"file": "10-eth0.network", "line": 5, "column": 1, "rule": "invalid-bool", "severity": "error",
+		 "message": "invalid boolean \"maybe\" for ARP, the key is ignored"},
+		{"file": "foo.service", "rule": "syntax", "severity": "error",
+		 "message": "unsupported file type .service"}
+	]`, buf.String())
+
+	buf.Reset()
+	require.NoError(t, writeJSON(&buf, nil))
+	assert.Equal(t, "[]\n", buf.String())
+}
+
+func TestWriteSARIF(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, writeSARIF(&buf, testDiags))
+
+	var log sarifLog
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &log))
+	assert.Equal(t, "2.1.0", log.Version)
+	require.Len(t, log.Runs, 1)
+	run := log.Runs[0]
+	assert.Equal(t, "systemd-lint", run.Tool.Driver.Name)
+	assert.Len(t, run.Tool.Driver.Rules, len(ruleDescriptions))
+	assert.Equal(t, []sarifResult{
+		{
+			RuleID:  ruleInvalidBool,
+			Level:   "error",
+			Message: sarifMessage{Text: `invalid boolean "maybe" for ARP, the key is ignored`},
+			Locations: []sarifLocation{{PhysicalLocation: sarifPhysicalLocation{
+				ArtifactLocation: sarifArtifactLocation{URI: "10-eth0.network"},
+				Region:           &sarifRegion{StartLine: 5, StartColumn: 1},
+			}}},
+		},
+		{
+			RuleID:  ruleSyntax,
+			Level:   "error",
+			Message: sarifMessage{Text: "unsupported file type .service"},
+			Locations: []sarifLocation{{PhysicalLocation: sarifPhysicalLocation{
+				ArtifactLocation: sarifArtifactLocation{URI: "foo.service"},
+			}}},
+		},
+	}, run.Results)
+}
@@ -0,0 +1,136 @@
+// Command systemd-lint checks systemd-networkd .network, .netdev and .link files.
+//
+// Usage:
+//
+//	systemd-lint [flags] path ...
+//
+// Directories are processed recursively, checking all supported files.
+// Files are unmarshaled into the structs of the network, netdev and link packages
+// and checked for unknown sections and keys, invalid values, which systemd ignores,
+// conflicting options and deprecated settings.
+//
+// Problems are written in a human readable form, as JSON or SARIF, see -format.
+// The exit code is 1 if problems were found and 2 if files could not be read.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+var format = flag.String("format", "text", "output `format`: text, json or sarif")
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: systemd-lint [flags] path ...\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	var write func(io.Writer, []Diagnostic) error
+	switch *format {
+	case "text":
+		write = writeText
+	case "json":
+		write = writeJSON
+	case "sarif":
+		write = writeSARIF
+	default:
+		fmt.Fprintf(os.Stderr, "systemd-lint: invalid -format %q\n", *format)
+		os.Exit(2)
+	}
+
+	diags, err := lintPaths(flag.Args())
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	if err := write(os.Stdout, diags); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	if len(diags) != 0 {
+		os.Exit(1)
+	}
+}
+
+// lintPaths checks all named files and supported files in the named directories.
+func lintPaths(paths []string) ([]Diagnostic, error) {
+	var diags []Diagnostic
+	lintFile := func(path string) error {
+		src, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		diags = append(diags, lint(path, src)...)
+		return nil
+	}
+
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			if err := lintFile(path); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		err = filepath.Walk(path, func(path string, info os.FileInfo, err error) error {
+			if err != nil || info.IsDir() || kindOf(path) == nil {
+				return err
+			}
+			return lintFile(path)
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return diags, nil
+}
+
+func writeText(w io.Writer, diags []Diagnostic) error {
+	for _, d := range diags {
+		if _, err := fmt.Fprintln(w, d); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// jsonDiagnostic is the JSON representation of a Diagnostic.
+type jsonDiagnostic struct {
+	File     string   `json:"file"`
+	Line     int      `json:"line,omitempty"`
+	Column   int      `json:"column,omitempty"`
+	Rule     string   `json:"rule"`
+	Severity Severity `json:"severity"`
+	Message  string   `json:"message"`
+}
+
+func writeJSON(w io.Writer, diags []Diagnostic) error {
+	out := make([]jsonDiagnostic, len(diags))
+	for i, d := range diags {
+		out[i] = jsonDiagnostic{
+			File:     d.Pos.Filename,
+			Line:     d.Pos.Line,
+			Column:   d.Pos.Column,
+			Rule:     d.Rule,
+			Severity: d.Severity,
+			Message:  d.Message,
+		}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
@@ -0,0 +1,476 @@
+package main
+
+import (
+	"net"
+	"strconv"
+	"strings"
+
+	"routerd.net/go-systemd"
+)
+
+// valueKind is the type of value expected by a key.
+type valueKind int
+
+const (
+	kindBool     valueKind = iota + 1
+	kindAddress            // IP address
+	kindPrefix             // IP address with optional prefix length
+	kindMAC                // hardware address
+	kindTimespan           // systemd time span
+)
+
+func (k valueKind) String() string {
+	switch k {
+	case kindBool:
+		return "boolean"
+	case kindAddress:
+		return "IP address"
+	case kindPrefix:
+		return "IP prefix"
+	case kindMAC:
+		return "MAC address"
+	case kindTimespan:
+		return "time span"
+	}
+	return "valueKind(" + strconv.Itoa(int(k)) + ")"
+}
+
+// valueRule describes the values accepted by a key.
+type valueRule struct {
+	Kind    valueKind
+	Sep     string   // separator of list values, " " for whitespace, empty for single values
+	Special []string // special values accepted in addition to Kind
+}
+
+// check validates the value of key and returns a diagnostic if it is invalid.
+func (r valueRule) check(key systemd.Key) (Diagnostic, bool) {
+	values := []string{key.Value}
+	switch r.Sep {
+	case "":
+	case " ":
+		values = strings.Fields(key.Value)
+	default:
+		values = strings.Split(key.Value, r.Sep)
+	}
+
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if contains(r.Special, value) || r.valid(value) {
+			continue
+		}
+		rule := ruleInvalidValue
+		if r.Kind == kindBool {
+			rule = ruleInvalidBool
+		}
+		return Diagnostic{
+			Pos:      key.Pos,
+			Rule:     rule,
+			Severity: SeverityError,
+			Message:  "invalid " + r.Kind.String() + " " + quote(value) + " for " + key.Name + ", the key is ignored",
+		}, false
+	}
+	return Diagnostic{}, true
+}
+
+func (r valueRule) valid(value string) bool {
+	switch r.Kind {
+	case kindBool:
+		return systemd.StrToBool(value) != nil
+	case kindAddress:
+		return net.ParseIP(value) != nil
+	case kindPrefix:
+		if _, _, err := net.ParseCIDR(value); err == nil {
+			return true
+		}
+		return net.ParseIP(value) != nil
+	case kindMAC:
+		_, err := net.ParseMAC(value)
+		return err == nil
+	case kindTimespan:
+		_, err := systemd.ParseTimespan(value)
+		return err == nil
+	}
+	return true
+}
+
+var (
+	boolValue     = valueRule{Kind: kindBool}
+	addressValue  = valueRule{Kind: kindAddress}
+	prefixValue   = valueRule{Kind: kindPrefix}
+	macValue      = valueRule{Kind: kindMAC}
+	macList       = valueRule{Kind: kindMAC, Sep: " "}
+	timespanValue = valueRule{Kind: kindTimespan}
+	gatewayValue  = valueRule{Kind: kindAddress, Special: []string{"_dhcp4", "_ipv6ra"}}
+)
+
+// Value rules of keys stored as strings.
+// Boolean struct fields are checked without an entry.
+var (
+	networkValues = map[string]map[string]valueRule{
+		"Match": {
+			"MACAddress":          macList,
+			"PermanentMACAddress": macList,
+			"BSSID":               macList,
+		},
+		"Link": {
+			"MACAddress":   macValue,
+			"ARP":          boolValue,
+			"Multicast":    boolValue,
+			"AllMulticast": boolValue,
+			"Unmanaged":    boolValue,
+		},
+		"Network": {
+			"Address":                 prefixValue,
+			"Gateway":                 gatewayValue,
+			"ConfigureWithoutCarrier": boolValue,
+			"IgnoreCarrierLoss":       boolValue,
+			"IPv6AcceptRA":            boolValue,
+			"IPv4ProxyARP":            boolValue,
+			"IPv6ProxyNDP":            boolValue,
+			"IPv6ProxyNDPAddress":     addressValue,
+			"DNSDefaultRoute":         boolValue,
+			"ActiveSlave":             boolValue,
+			"PrimarySlave":            boolValue,
+		},
+		"Address": {
+			"Address":                prefixValue,
+			"Peer":                   prefixValue,
+			"Broadcast":              addressValue,
+			"HomeAddress":            boolValue,
+			"ManageTemporaryAddress": boolValue,
+			"AddPrefixRoute":         boolValue,
+			"AutoJoin":               boolValue,
+		},
+		"Route": {
+			"Gateway":         gatewayValue,
+			"Destination":     prefixValue,
+			"Source":          prefixValue,
+			"PreferredSource": addressValue,
+			"GatewayOnLink":   boolValue,
+			"QuickAck":        boolValue,
+			"TTLPropagate":    boolValue,
+		},
+		"Neighbor": {
+			"Address": addressValue,
+		},
+		"NextHop": {
+			"Gateway": addressValue,
+		},
+		"RoutingPolicyRule": {
+			"From":       prefixValue,
+			"To":         prefixValue,
+			"InvertRule": boolValue,
+		},
+		"DHCPv4": {
+			"UseDNS":           boolValue,
+			"UseNTP":           boolValue,
+			"UseMTU":           boolValue,
+			"UseHostname":      boolValue,
+			"UseRoutes":        boolValue,
+			"SendHostname":     boolValue,
+			"SendRelease":      boolValue,
+			"RequestBroadcast": boolValue,
+		},
+		"DHCPServer": {
+			"DefaultLeaseTimeSec": timespanValue,
+			"MaxLeaseTimeSec":     timespanValue,
+			"EmitDNS":             boolValue,
+			"EmitNTP":             boolValue,
+			"EmitRouter":          boolValue,
+			"EmitTimezone":        boolValue,
+		},
+		"IPv6Prefix": {
+			"Prefix":                   prefixValue,
+			"OnLink":                   boolValue,
+			"AddressAutoconfiguration": boolValue,
+			"PreferredLifetimeSec":     timespanValue,
+			"ValidLifetimeSec":         timespanValue,
+		},
+		"Bridge": {
+			"UnicastFlood":        boolValue,
+			"MulticastFlood":      boolValue,
+			"MulticastToUnicast":  boolValue,
+			"NeighborSuppression": boolValue,
+			"Learning":            boolValue,
+			"HairPin":             boolValue,
+			"UseBPDU":             boolValue,
+			"FastLeave":           boolValue,
+			"AllowPortToBeRoot":   boolValue,
+		},
+		"BridgeFDB": {
+			"MACAddress":  macValue,
+			"Destination": addressValue,
+		},
+	}
+
+	netdevValues = map[string]map[string]valueRule{
+		"NetDev": {
+			"MACAddress": macValue,
+		},
+		"Bridge": {
+			"HelloTimeSec":    timespanValue,
+			"MaxAgeSec":       timespanValue,
+			"ForwardDelaySec": timespanValue,
+			"AgeingTimeSec":   timespanValue,
+		},
+		"VXLAN": {
+			"Remote":       addressValue,
+			"Local":        addressValue,
+			"Group":        addressValue,
+			"FDBAgeingSec": timespanValue,
+		},
+		"GENEVE": {
+			"Remote": addressValue,
+		},
+		"L2TP": {
+			"Local":  valueRule{Kind: kindAddress, Special: []string{"auto", "static", "dynamic"}},
+			"Remote": addressValue,
+		},
+		"Tunnel": {
+			"Local":  valueRule{Kind: kindAddress, Special: []string{"any"}},
+			"Remote": valueRule{Kind: kindAddress, Special: []string{"any"}},
+		},
+		"Peer": {
+			"MACAddress": macValue,
+		},
+		"WireGuardPeer": {
+			"AllowedIPs": valueRule{Kind: kindPrefix, Sep: ","},
+		},
+		"Bond": {
+			"MIIMonitorSec":          timespanValue,
+			"UpDelaySec":             timespanValue,
+			"DownDelaySec":           timespanValue,
+			"ARPIntervalSec":         timespanValue,
+			"LearnPacketIntervalSec": timespanValue,
+			"ARPIPTargets":           valueRule{Kind: kindAddress, Sep: " "},
+		},
+	}
+
+	linkValues = map[string]map[string]valueRule{
+		"Match": {
+			"MACAddress":          macList,
+			"PermanentMACAddress": macList,
+		},
+		"Link": {
+			"MACAddress": macValue,
+		},
+	}
+)
+
+// deprecation describes a deprecated section, key or value.
+type deprecation struct {
+	Section     string
+	Key         string // empty for the whole section
+	Value       string // empty for all values
+	Replacement string
+}
+
+func (d deprecation) diagnostic(pos systemd.Position) Diagnostic {
+	msg := "section [" + d.Section + "] is deprecated"
+	switch {
+	case d.Value != "":
+		msg = d.Key + "=" + d.Value + " is deprecated"
+	case d.Key != "":
+		msg = "key " + d.Key + " in section [" + d.Section + "] is deprecated"
+	}
+	if d.Replacement != "" {
+		msg += ", use " + d.Replacement + " instead"
+	}
+	return Diagnostic{
+		Pos:      pos,
+		Rule:     ruleDeprecated,
+		Severity: SeverityWarning,
+		Message:  msg,
+	}
+}
+
+var (
+	networkDeprecations = []deprecation{
+		{Section: "DHCP", Replacement: "[DHCPv4]"},
+		{Section: "DHCPv6PrefixDelegation", Replacement: "[DHCPPrefixDelegation]"},
+		{Section: "Network", Key: "IPv4LL", Replacement: "LinkLocalAddressing="},
+		{Section: "Network", Key: "CriticalConnection", Replacement: "KeepConfiguration="},
+		{Section: "DHCPv4", Key: "CriticalConnection", Replacement: "KeepConfiguration= in [Network]"},
+		{Section: "Network", Key: "DHCP", Value: "both", Replacement: "DHCP=yes"},
+		{Section: "Network", Key: "DHCP", Value: "v4", Replacement: "DHCP=ipv4"},
+		{Section: "Network", Key: "DHCP", Value: "v6", Replacement: "DHCP=ipv6"},
+	}
+
+	netdevDeprecations = []deprecation{
+		{Section: "VXLAN", Key: "Id", Replacement: "VNI="},
+		{Section: "VXLAN", Key: "ARPProxy", Replacement: "ReduceARPProxy="},
+		{Section: "VXLAN", Key: "UDPCheckSum", Replacement: "UDPChecksum="},
+		{Section: "VXLAN", Key: "UDP6ZeroCheckSumTx", Replacement: "UDP6ZeroChecksumTx="},
+		{Section: "VXLAN", Key: "UDP6ZeroCheckSumRx", Replacement: "UDP6ZeroChecksumRx="},
+	}
+)
+
+var (
+	networkConflicts = []func(*systemd.File) []Diagnostic{
+		conflictingMasters,
+		unmanagedLink,
+		routeTypeGateway,
+	}
+	netdevConflicts = []func(*systemd.File) []Diagnostic{
+		netdevKindSections,
+	}
+	linkConflicts = []func(*systemd.File) []Diagnostic{
+		macAddressPolicy,
+	}
+)
+
+func conflict(pos systemd.Position, msg string) Diagnostic {
+	return Diagnostic{
+		Pos:      pos,
+		Rule:     ruleConflict,
+		Severity: SeverityWarning,
+		Message:  msg,
+	}
+}
+
+// conflictingMasters reports interfaces that are added to more than one master device.
+func conflictingMasters(file *systemd.File) []Diagnostic {
+	var diags []Diagnostic
+	for _, section := range file.SectionsByName("Network") {
+		var first *systemd.Key
+		for i, key := range section.Keys {
+			if key.Value == "" || !contains([]string{"Bond", "Bridge", "VRF"}, key.Name) {
+				continue
+			}
+			if first == nil {
+				first = &section.Keys[i]
+				continue
+			}
+			if key.Name != first.Name {
+				diags = append(diags, conflict(key.Pos,
+					key.Name+"= conflicts with "+first.Name+"=, an interface can only have one master"))
+			}
+		}
+	}
+	return diags
+}
+
+// unmanagedLink reports sections that are ignored because of Unmanaged=yes.
+func unmanagedLink(file *systemd.File) []Diagnostic {
+	link := file.Section("Link")
+	if link == nil {
+		return nil
+	}
+	if value, _ := link.Get("Unmanaged"); !isTrue(value) {
+		return nil
+	}
+
+	var diags []Diagnostic
+	for _, section := range file.Sections {
+		if section.Name == "Match" || section.Name == "Link" {
+			continue
+		}
+		diags = append(diags, conflict(section.Pos,
+			"section ["+section.Name+"] is ignored, because the link is unmanaged"))
+	}
+	return diags
+}
+
+// routeTypeGateway reports routes with a gateway and a type that cannot have one.
+func routeTypeGateway(file *systemd.File) []Diagnostic {
+	var diags []Diagnostic
+	for _, section := range file.SectionsByName("Route") {
+		routeType, _ := section.Get("Type")
+		if !contains([]string{"blackhole", "unreachable", "prohibit", "throw"}, routeType) {
+			continue
+		}
+		if gateway := section.Key("Gateway"); gateway != nil && gateway.Value != "" {
+			diags = append(diags, conflict(gateway.Pos, "Gateway= conflicts with Type="+routeType))
+		}
+	}
+	return diags
+}
+
+// kindSections maps the kind specific sections of netdev files to their kinds.
+var kindSections = map[string][]string{
+	"Bridge":                    {"bridge"},
+	"VLAN":                      {"vlan"},
+	"MACVLAN":                   {"macvlan"},
+	"MACVTAP":                   {"macvtap"},
+	"IPVLAN":                    {"ipvlan"},
+	"IPVTAP":                    {"ipvtap"},
+	"VXLAN":                     {"vxlan"},
+	"GENEVE":                    {"geneve"},
+	"L2TP":                      {"l2tp"},
+	"L2TPSession":               {"l2tp"},
+	"MACsec":                    {"macsec"},
+	"MACsecReceiveChannel":      {"macsec"},
+	"MACsecTransmitAssociation": {"macsec"},
+	"MACsecReceiveAssociation":  {"macsec"},
+	"Tunnel": {
+		"ipip", "sit", "gre", "gretap", "ip6gre", "ip6gretap",
+		"vti", "vti6", "ip6tnl", "erspan",
+	},
+	"FooOverUDP":     {"fou"},
+	"Peer":           {"veth"},
+	"VXCAN":          {"vxcan"},
+	"Tun":            {"tun"},
+	"Tap":            {"tap"},
+	"WireGuard":      {"wireguard"},
+	"WireGuardPeer":  {"wireguard"},
+	"Bond":           {"bond"},
+	"Xfrm":           {"xfrm"},
+	"VRF":            {"vrf"},
+	"BatmanAdvanced": {"batadv"},
+	"IPoIB":          {"ipoib"},
+}
+
+// netdevKindSections reports kind specific sections not matching the kind of the netdev.
+func netdevKindSections(file *systemd.File) []Diagnostic {
+	netDev := file.Section("NetDev")
+	if netDev == nil {
+		return nil
+	}
+	kind, ok := netDev.Get("Kind")
+	if !ok {
+		return nil
+	}
+
+	var diags []Diagnostic
+	for _, section := range file.Sections {
+		if kinds, ok := kindSections[section.Name]; ok && !contains(kinds, kind) {
+			diags = append(diags, conflict(section.Pos,
+				"section ["+section.Name+"] conflicts with Kind="+kind))
+		}
+	}
+	return diags
+}
+
+// macAddressPolicy reports MAC addresses that are ignored because of MACAddressPolicy=.
+func macAddressPolicy(file *systemd.File) []Diagnostic {
+	var diags []Diagnostic
+	for _, section := range file.SectionsByName("Link") {
+		policy, _ := section.Get("MACAddressPolicy")
+		if policy != "persistent" && policy != "random" {
+			continue
+		}
+		if mac := section.Key("MACAddress"); mac != nil && mac.Value != "" {
+			diags = append(diags, conflict(mac.Pos,
+				"MACAddress= is ignored, because of MACAddressPolicy="+policy))
+		}
+	}
+	return diags
+}
+
+func isTrue(value string) bool {
+	b := systemd.StrToBool(value)
+	return b != nil && *b
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func quote(s string) string {
+	return strconv.Quote(s)
+}
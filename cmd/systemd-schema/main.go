@@ -0,0 +1,171 @@
+// Command systemd-schema generates JSON Schemas of .network, .netdev and .link files.
+//
+// Usage:
+//
+//	systemd-schema [flags] kind ...
+//
+// The kind is one of network, netdev or link. The schemas describe the
+// JSON representation written by MarshalJSON of the structs in the
+// network, netdev and link packages. Descriptions and enum values are
+// taken from the doc comments of the structs, which are read from the
+// package sources in the directory given by -src.
+//
+// By default a JSON Schema of a single kind is written. With -openapi,
+// an OpenAPI document holding the schemas of all named kinds in its
+// components is written instead.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+
+	"routerd.net/go-systemd/link"
+	"routerd.net/go-systemd/netdev"
+	"routerd.net/go-systemd/network"
+)
+
+var (
+	openAPIFlag = flag.Bool("openapi", false, "write an OpenAPI document with the schemas in its components")
+	output      = flag.String("o", "", "write output to `file` instead of stdout")
+	src         = flag.String("src", ".", "module root `dir` containing the sources of the network, netdev and link packages")
+)
+
+const (
+	jsonSchemaVersion = "https://json-schema.org/draft/2020-12/schema"
+	openAPIVersion    = "3.1.0"
+)
+
+// kinds maps the supported kinds of files to their structs.
+var kinds = map[string]reflect.Type{
+	"network": reflect.TypeOf(network.Network{}),
+	"netdev":  reflect.TypeOf(netdev.NetDev{}),
+	"link":    reflect.TypeOf(link.Link{}),
+}
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: systemd-schema [flags] kind ...\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() == 0 || !*openAPIFlag && flag.NArg() > 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	var (
+		doc interface{}
+		err error
+	)
+	if *openAPIFlag {
+		doc, err = openAPIDocument(*src, flag.Args())
+	} else {
+		doc, err = jsonSchema(*src, flag.Arg(0))
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "systemd-schema:", err)
+		os.Exit(1)
+	}
+
+	out, err := marshal(doc)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "systemd-schema:", err)
+		os.Exit(1)
+	}
+	if *output == "" {
+		_, err = os.Stdout.Write(out)
+	} else {
+		err = ioutil.WriteFile(*output, out, 0666)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "systemd-schema:", err)
+		os.Exit(1)
+	}
+}
+
+// jsonSchema returns the JSON Schema of kind, reading the doc comments from the module root dir.
+func jsonSchema(dir, kind string) (*Schema, error) {
+	g := newGenerator("#/$defs/")
+	s, err := addKind(g, dir, kind)
+	if err != nil {
+		return nil, err
+	}
+	s.Schema = jsonSchemaVersion
+	s.Title = kind
+	s.Defs = g.defs
+	return s, nil
+}
+
+// openAPI is the subset of an OpenAPI document written by the generator.
+// https://spec.openapis.org/oas/v3.1.0
+type openAPI struct {
+	OpenAPI    string            `json:"openapi"`
+	Info       openAPIInfo       `json:"info"`
+	Components openAPIComponents `json:"components"`
+}
+
+type openAPIInfo struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+type openAPIComponents struct {
+	Schemas map[string]*Schema `json:"schemas"`
+}
+
+// openAPIDocument returns an OpenAPI document with the schemas of kinds as components,
+// reading the doc comments from the module root dir.
+// The file schemas are named after their structs, like "network.Network".
+func openAPIDocument(dir string, kinds []string) (*openAPI, error) {
+	g := newGenerator("#/components/schemas/")
+	for _, kind := range kinds {
+		s, err := addKind(g, dir, kind)
+		if err != nil {
+			return nil, err
+		}
+		s.Title = kind
+		t := fileType(kind)
+		g.defs[packageName(t)+"."+t.Name()] = s
+	}
+	return &openAPI{
+		OpenAPI:    openAPIVersion,
+		Info:       openAPIInfo{Title: "systemd-networkd configuration", Version: "1"},
+		Components: openAPIComponents{Schemas: g.defs},
+	}, nil
+}
+
+// addKind returns the schema of the file struct of kind and adds its sections to g.
+func addKind(g *generator, dir, kind string) (*Schema, error) {
+	t := fileType(kind)
+	if t == nil {
+		return nil, fmt.Errorf("unknown kind %q", kind)
+	}
+	d, err := parseDocs(filepath.Join(dir, packageName(t)))
+	if err != nil {
+		return nil, err
+	}
+	return g.fileSchema(t, d), nil
+}
+
+func fileType(kind string) reflect.Type {
+	return kinds[kind]
+}
+
+// marshal returns the indented JSON encoding of v, without escaping HTML characters
+// which are common in the descriptions.
+func marshal(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,398 @@
+package main
+
+import (
+	"encoding"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"net"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+
+	systemd "routerd.net/go-systemd"
+	sdencoding "routerd.net/go-systemd/internal/encoding"
+)
+
+// Schema is the subset of JSON Schema written by the generator.
+// https://json-schema.org/draft/2020-12/json-schema-core.html
+type Schema struct {
+	Schema               string             `json:"$schema,omitempty"`
+	Ref                  string             `json:"$ref,omitempty"`
+	AllOf                []*Schema          `json:"allOf,omitempty"`
+	Title                string             `json:"title,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Minimum              *int               `json:"minimum,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	AdditionalProperties interface{}        `json:"additionalProperties,omitempty"`
+	Deprecated           bool               `json:"deprecated,omitempty"`
+	// Repeated marks keys and sections that may appear more than once.
+	Repeated bool `json:"x-systemd-repeated,omitempty"`
+	// systemd versions adding and deprecating keys and sections
+	Since        int                `json:"x-systemd-since,omitempty"`
+	DeprecatedIn int                `json:"x-systemd-deprecated,omitempty"`
+	Defs         map[string]*Schema `json:"$defs,omitempty"`
+}
+
+// Names of the definitions for unknown sections and keys.
+const (
+	sectionDef = "systemd.Section"
+	keyDef     = "systemd.Key"
+)
+
+var (
+	durationType       = reflect.TypeOf(time.Duration(0))
+	hardwareAddrType   = reflect.TypeOf(net.HardwareAddr(nil))
+	textMarshalerType  = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	valueMarshalerType = reflect.TypeOf((*systemd.ValueMarshaler)(nil)).Elem()
+)
+
+// generator builds the schemas of file structs.
+// Definitions of sections are collected in defs and referenced with refPrefix.
+type generator struct {
+	refPrefix string
+	defs      map[string]*Schema
+	docs      map[string]docs // by package name
+}
+
+func newGenerator(refPrefix string) *generator {
+	return &generator{
+		refPrefix: refPrefix,
+		defs: map[string]*Schema{
+			sectionDef: {
+				Description: "A section that is not known to the file struct.",
+				Type:        "object",
+				Properties: map[string]*Schema{
+					"name":    {Type: "string"},
+					"comment": {Type: "string"},
+					"keys":    {Type: "array", Items: &Schema{Ref: refPrefix + keyDef}},
+				},
+				AdditionalProperties: false,
+			},
+			keyDef: {
+				Description: "A key that is not known to the section struct.",
+				Type:        "object",
+				Properties: map[string]*Schema{
+					"name":    {Type: "string"},
+					"value":   {Type: "string"},
+					"comment": {Type: "string"},
+				},
+				AdditionalProperties: false,
+			},
+		},
+		docs: map[string]docs{},
+	}
+}
+
+// ref returns a reference to the definition name.
+func (g *generator) ref(name string) *Schema {
+	return &Schema{Ref: g.refPrefix + name}
+}
+
+// fileSchema returns the schema of the file struct t, documented by d,
+// in the JSON representation of MarshalJSON.
+// Sections are resolved like Marshal does, see sdencoding.TypeInfo.
+func (g *generator) fileSchema(t reflect.Type, d docs) *Schema {
+	pkg := packageName(t)
+	g.docs[pkg] = d
+
+	s := &Schema{
+		Description:          d[t.Name()],
+		Type:                 "object",
+		Properties:           map[string]*Schema{},
+		AdditionalProperties: false,
+	}
+	info := sdencoding.TypeInfo(t)
+	if info.SectionList != nil {
+		s.Properties["sections"] = &Schema{
+			Description: "Sections that are not known to the file struct.",
+			Type:        "array",
+			Items:       g.ref(sectionDef),
+		}
+	}
+	for _, field := range info.Fields {
+		st := field.Section()
+		if st == nil {
+			continue
+		}
+		prop := g.ref(g.sectionSchema(st))
+		if field.Type.Kind() == reflect.Slice {
+			prop = &Schema{Type: "array", Items: prop, Repeated: true}
+		}
+		s.Properties[field.Name] = withVersions(prop, field)
+	}
+	if info.Map != nil {
+		// sections without a field of their own are members named after the section
+		s.AdditionalProperties = g.ref(g.sectionSchema(info.Map.Section()))
+	}
+	return s
+}
+
+// sectionSchema adds the definition of the section struct t and returns its name.
+func (g *generator) sectionSchema(t reflect.Type) string {
+	name := packageName(t) + "." + t.Name()
+	if _, ok := g.defs[name]; ok {
+		return name
+	}
+	d := g.docs[packageName(t)]
+
+	s := &Schema{
+		Description:          d[t.Name()],
+		Type:                 "object",
+		Properties:           map[string]*Schema{},
+		AdditionalProperties: false,
+	}
+	g.defs[name] = s
+	info := sdencoding.TypeInfo(t)
+	if info.Comment != nil {
+		s.Properties["comment"] = &Schema{Type: "string"}
+	}
+	if info.KeyList != nil {
+		s.Properties["keys"] = &Schema{
+			Description: "Keys that are not known to the section struct.",
+			Type:        "array",
+			Items:       g.ref(keyDef),
+		}
+	}
+	if info.KeyComments != nil {
+		s.Properties["keyComments"] = &Schema{
+			Description:          "Comments of the keys by key name.",
+			Type:                 "object",
+			AdditionalProperties: &Schema{Type: "string"},
+		}
+	}
+	for _, field := range info.Fields {
+		if key := keySchema(field, d[docName(t, field.Index)]); key != nil {
+			s.Properties[field.Name] = withVersions(key, field)
+		}
+	}
+	if info.Map != nil {
+		// keys without a field of their own are members named after the key
+		s.AdditionalProperties = scalarSchema(info.Map.Type.Elem())
+		if info.Map.Type.Elem().Kind() == reflect.Slice {
+			s.AdditionalProperties = &Schema{Type: "array", Items: &Schema{Type: "string"}, Repeated: true}
+		}
+	}
+	return name
+}
+
+// docName returns the name of the field at index in the struct t as used by docs,
+// "Type.Field" with the struct type declaring the possibly embedded field.
+func docName(t reflect.Type, index []int) string {
+	for _, i := range index[:len(index)-1] {
+		t = t.Field(i).Type
+		if t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+	}
+	return t.Name() + "." + t.Field(index[len(index)-1]).Name
+}
+
+// keySchema returns the schema of the key field with the documentation doc,
+// or nil if the type of the field is not supported by MarshalJSON.
+func keySchema(field sdencoding.FieldInfo, doc string) *Schema {
+	t := field.Type
+	if reflect.PtrTo(t).Implements(valueMarshalerType) {
+		return &Schema{
+			Description: doc,
+			Type:        "array",
+			Items:       &Schema{Type: "string"},
+			Repeated:    true,
+		}
+	}
+
+	var s *Schema
+	switch {
+	case t.Kind() == reflect.Slice && t != hardwareAddrType:
+		item := scalarSchema(t.Elem())
+		if item == nil {
+			return nil
+		}
+		if item.Type == "string" {
+			item.Enum = enumValues(doc, t.Elem())
+		}
+		s = &Schema{Type: "array", Items: item}
+		// white space lists and single assignments are written in a single assignment
+		s.Repeated = !field.WSlist && field.Mode != "lastwins" && field.Mode != "first"
+	case t.Kind() == reflect.Ptr:
+		s = scalarSchema(t.Elem())
+	default:
+		s = scalarSchema(t)
+	}
+	if s == nil {
+		return nil
+	}
+	if s.Type == "string" && !isList(doc) {
+		s.Enum = enumValues(doc, t)
+	}
+	s.Description = doc
+	return s
+}
+
+// scalarSchema returns the schema of the scalar type t or nil if it is not supported.
+// Booleans and numbers are kept, everything else is written as a string.
+func scalarSchema(t reflect.Type) *Schema {
+	if t == durationType || t == hardwareAddrType || reflect.PtrTo(t).Implements(textMarshalerType) {
+		return &Schema{Type: "string"}
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return &Schema{Type: "integer"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		zero := 0
+		return &Schema{Type: "integer", Minimum: &zero}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	}
+	return nil
+}
+
+// withVersions annotates s with the systemd versions in the tag of field.
+// References can not have siblings in OpenAPI, so they are wrapped with allOf.
+func withVersions(s *Schema, field sdencoding.FieldInfo) *Schema {
+	if field.Since == 0 && field.Deprecated == 0 {
+		return s
+	}
+	if s.Ref != "" {
+		s = &Schema{AllOf: []*Schema{s}}
+	}
+	s.Since = field.Since
+	s.DeprecatedIn = field.Deprecated
+	s.Deprecated = field.Deprecated != 0
+	return s
+}
+
+// packageName returns the name of the package declaring the named type t.
+func packageName(t reflect.Type) string {
+	return t.PkgPath()[strings.LastIndex(t.PkgPath(), "/")+1:]
+}
+
+// docs maps the names of types and their fields, as "Type.Field", to their doc comments.
+type docs map[string]string
+
+// parseDocs reads the doc comments of the struct types declared in the Go files in dir.
+func parseDocs(dir string) (docs, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+
+	d := docs{}
+	fset := token.NewFileSet()
+	for _, filename := range files {
+		if strings.HasSuffix(filename, "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(fset, filename, nil, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+		for _, decl := range file.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				spec := spec.(*ast.TypeSpec)
+				doc := spec.Doc
+				if doc == nil && len(gen.Specs) == 1 {
+					doc = gen.Doc
+				}
+				d[spec.Name.Name] = docText(doc)
+
+				st, ok := spec.Type.(*ast.StructType)
+				if !ok {
+					continue
+				}
+				for _, field := range st.Fields.List {
+					for _, name := range field.Names {
+						d[spec.Name.Name+"."+name.Name] = docText(field.Doc)
+					}
+				}
+			}
+		}
+	}
+	return d, nil
+}
+
+func docText(doc *ast.CommentGroup) string {
+	return strings.TrimSpace(doc.Text())
+}
+
+var (
+	// sentence ends at a period followed by white space or the end of the text
+	sentenceEnd = regexp.MustCompile(`\.(\s+|$)`)
+	// phrases starting a sentence which lists the possible values of a key
+	enumIntro = regexp.MustCompile(`^(Takes|(Possible|Supported|Valid|Accepted|Allowed) values are|One of)\b`)
+	quoted    = regexp.MustCompile(`"([^"]*)"`)
+	// words which show that values other than the quoted ones are accepted
+	openEnded = regexp.MustCompile(`(?i)\b(number|integer|identifier|range|between|means|address|name|string|path|time|timespan|size|prefix|list|percentage|suffixed)\b`)
+	// whitespace-separated lists can not be described by an enum
+	listIntro = regexp.MustCompile(`(?i)\b(whitespace|space)-separated list\b`)
+)
+
+// boolValues are the values accepted for booleans, see StrToBool.
+var boolValues = []string{"yes", "no", "true", "false", "on", "off", "1", "0"}
+
+// enumValues returns the values allowed for a string key, taken from its documentation,
+// or nil if it does not restrict the values.
+//
+// The documentation is searched for the first sentence listing the quoted values,
+// like `Takes one of "a", "b" or "c".` or `Takes a boolean or "c".`,
+// and not mentioning other kinds of values.
+func enumValues(doc string, t reflect.Type) []string {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.String {
+		return nil
+	}
+
+	for _, sentence := range sentenceEnd.Split(doc, -1) {
+		sentence = strings.TrimSpace(sentence)
+		if !enumIntro.MatchString(sentence) {
+			continue
+		}
+		rest := quoted.ReplaceAllString(sentence, "")
+		if openEnded.MatchString(rest) {
+			continue
+		}
+
+		var values []string
+		if strings.Contains(strings.ToLower(rest), "boolean") {
+			values = append(values, boolValues...)
+		}
+		for _, m := range quoted.FindAllStringSubmatch(sentence, -1) {
+			if m[1] != "" && !contains(values, m[1]) {
+				values = append(values, m[1])
+			}
+		}
+		if len(values) > 0 {
+			return values
+		}
+	}
+	return nil
+}
+
+// isList returns true if doc describes a whitespace-separated list.
+func isList(doc string) bool {
+	return listIntro.MatchString(doc)
+}
+
+func contains(list []string, s string) bool {
+	for _, l := range list {
+		if l == s {
+			return true
+		}
+	}
+	return false
+}
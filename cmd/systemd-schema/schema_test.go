@@ -0,0 +1,320 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"routerd.net/go-systemd"
+	"routerd.net/go-systemd/internal/encoding"
+	"routerd.net/go-systemd/netdev"
+	"routerd.net/go-systemd/network"
+)
+
+func TestEnumValues(t *testing.T) {
+	tests := []struct {
+		Name   string
+		Doc    string
+		Values []string
+	}{
+		{
+			Name:   "takes one of",
+			Doc:    `Specifies the mode. Takes one of "slow", "fast" or "auto". Defaults to "slow".`,
+			Values: []string{"slow", "fast", "auto"},
+		},
+		{
+			Name:   "possible values",
+			Doc:    `Possible values are "layer2", "layer3+4" and "encap2+3".`,
+			Values: []string{"layer2", "layer3+4", "encap2+3"},
+		},
+		{
+			Name:   "boolean",
+			Doc:    `Takes a boolean. Defaults to "no".`,
+			Values: boolValues,
+		},
+		{
+			Name:   "boolean or value",
+			Doc:    `Takes a boolean or "resolve". When true, enables full support.`,
+			Values: append(append([]string{}, boolValues...), "resolve"),
+		},
+		{
+			Name: "open ended",
+			Doc:  `Takes one of "root", "clsact" or a class identifier.`,
+		},
+		{
+			Name: "not at the start of a sentence",
+			Doc:  `EmitDNS= takes a boolean. The type takes one of "uint8" or "string".`,
+		},
+		{
+			Name: "unquoted",
+			Doc:  `Takes an IPv4 address.`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			assert.Equal(t, test.Values, enumValues(test.Doc, reflect.TypeOf("")))
+		})
+	}
+
+	t.Run("not a string", func(t *testing.T) {
+		assert.Nil(t, enumValues(`Takes a boolean.`, reflect.TypeOf(true)))
+	})
+}
+
+func TestKeySchema(t *testing.T) {
+	zero := 0
+	type section struct {
+		String   string
+		Bool     *bool
+		Uint     uint
+		Repeated []string
+		List     []string `systemd:"Names,wslist"`
+		Enum     string   `systemd:"Mode"`
+		Items    []string `systemd:"Kind"`
+		Single   []string `systemd:",lastwins"`
+		Func     func()
+	}
+	docs := map[string]string{
+		"Mode":  `Takes one of "a" or "b".`,
+		"Names": `A whitespace-separated list of names. Takes one of "a" or "b".`,
+		"Kind":  `Takes one of "a" or "b".`,
+	}
+	tests := map[string]*Schema{
+		"String":   {Type: "string"},
+		"Bool":     {Type: "boolean"},
+		"Uint":     {Type: "integer", Minimum: &zero},
+		"Repeated": {Type: "array", Items: &Schema{Type: "string"}, Repeated: true},
+		"List":     {Description: docs["Names"], Type: "array", Items: &Schema{Type: "string", Enum: []string{"a", "b"}}},
+		"Enum":     {Description: docs["Mode"], Type: "string", Enum: []string{"a", "b"}},
+		"Items":    {Description: docs["Kind"], Type: "array", Items: &Schema{Type: "string", Enum: []string{"a", "b"}}, Repeated: true},
+		"Single":   {Type: "array", Items: &Schema{Type: "string"}},
+		"Func":     nil,
+	}
+	fields := encoding.TypeInfo(reflect.TypeOf(section{})).Fields
+	require.Len(t, fields, len(tests))
+	for i, name := range []string{"String", "Bool", "Uint", "Repeated", "List", "Enum", "Items", "Single", "Func"} {
+		field := fields[i]
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tests[name], keySchema(field, docs[field.Name]))
+		})
+	}
+}
+
+// Shared is embedded by the structs of TestStructFields.
+type Shared struct {
+	// Shared key.
+	MTUBytes string
+}
+
+func TestStructFields(t *testing.T) {
+	type section struct {
+		Comment string
+		systemd.KeyList
+		Shared
+		Internal string `systemd:"-"`
+		Extra    map[string]string
+	}
+	type file struct {
+		systemd.SectionList
+		Main  *section `systemd:"Section"`
+		Other map[string]section
+	}
+
+	g := newGenerator("#/$defs/")
+	s := g.fileSchema(reflect.TypeOf(file{}), docs{"Shared.MTUBytes": "Shared key."})
+	assert.Equal(t, &Schema{Ref: "#/$defs/systemd-schema.section"}, s.Properties["Section"])
+	assert.Contains(t, s.Properties, "sections")
+	assert.Equal(t, &Schema{Ref: "#/$defs/systemd-schema.section"}, s.AdditionalProperties)
+
+	def := g.defs["systemd-schema.section"]
+	require.NotNil(t, def)
+	assert.Equal(t, &Schema{Description: "Shared key.", Type: "string"}, def.Properties["MTUBytes"])
+	assert.NotContains(t, def.Properties, "Internal")
+	assert.NotContains(t, def.Properties, "-")
+	assert.NotContains(t, def.Properties, "keyComments")
+	assert.Contains(t, def.Properties, "comment")
+	assert.Contains(t, def.Properties, "keys")
+	assert.Equal(t, &Schema{Type: "string"}, def.AdditionalProperties)
+
+	data, err := systemd.MarshalJSON(&file{
+		Main:  &section{Shared: Shared{MTUBytes: "1500"}, Extra: map[string]string{"Foo": "bar"}},
+		Other: map[string]section{"Other": {Comment: "other"}},
+	})
+	require.NoError(t, err)
+	assert.NoError(t, validate(s, g.defs, "#/$defs/", data))
+}
+
+func TestJSONSchema(t *testing.T) {
+	s, err := jsonSchema("../..", "network")
+	require.NoError(t, err)
+
+	assert.Equal(t, jsonSchemaVersion, s.Schema)
+	assert.Equal(t, &Schema{Ref: "#/$defs/network.MatchSection"}, s.Properties["Match"])
+	assert.Equal(t, &Schema{
+		Type:     "array",
+		Items:    &Schema{Ref: "#/$defs/network.RouteSection"},
+		Repeated: true,
+	}, s.Properties["Route"])
+
+	assert.Equal(t, 246, s.Properties["SR-IOV"].Since)
+	assert.Equal(t, &Schema{
+		AllOf:        []*Schema{{Ref: "#/$defs/network.DHCPv6PrefixDelegationSection"}},
+		Deprecated:   true,
+		Since:        247,
+		DeprecatedIn: 250,
+	}, s.Properties["DHCPv6PrefixDelegation"])
+
+	route := s.Defs["network.RouteSection"]
+	require.NotNil(t, route)
+	assert.Equal(t, boolValues, route.Properties["GatewayOnLink"].Enum)
+	assert.Contains(t, route.Properties["Type"].Enum, "blackhole")
+	assert.Contains(t, route.Properties["Gateway"].Description, "gateway address")
+
+	n := network.Network{
+		Match: &network.MatchSection{Name: []string{"eth0"}, Comment: "match"},
+		Network: &network.NetworkSection{
+			Address: []string{"10.0.0.2/24"},
+			DHCP:    "yes",
+		},
+		Link:   &network.LinkSection{ARP: "yes"},
+		Routes: []network.RouteSection{{Gateway: "10.0.0.1", Type: "unicast"}},
+	}
+	n.Match.KeyList.AddKey(systemd.Key{Name: "Unknown", Value: "value"})
+	n.SectionList.AddSection(systemd.Section{Name: "Unknown"})
+	data, err := n.MarshalJSON()
+	require.NoError(t, err)
+	assert.NoError(t, validate(s, s.Defs, "#/$defs/", data))
+
+	t.Run("invalid", func(t *testing.T) {
+		for _, data := range []string{
+			`{"Unknown": {}}`,
+			`{"Match": {"Unknown": "eth0"}}`,
+			`{"Link": {"ARP": true}}`,
+			`{"Route": {"Gateway": "10.0.0.1"}}`,
+			`{"Route": [{"Type": "invalid"}]}`,
+		} {
+			assert.Error(t, validate(s, s.Defs, "#/$defs/", []byte(data)), data)
+		}
+	})
+}
+
+func TestOpenAPIDocument(t *testing.T) {
+	doc, err := openAPIDocument("../..", []string{"network", "netdev"})
+	require.NoError(t, err)
+
+	schemas := doc.Components.Schemas
+	assert.Equal(t, openAPIVersion, doc.OpenAPI)
+	require.Contains(t, schemas, "network.Network")
+	require.Contains(t, schemas, "netdev.NetDev")
+	assert.Contains(t, schemas, "network.MatchSection")
+	assert.Contains(t, schemas, "netdev.MatchSection")
+	assert.Equal(t, &Schema{Ref: "#/components/schemas/netdev.NetDevSection"}, schemas["netdev.NetDev"].Properties["NetDev"])
+	assert.Contains(t, schemas["netdev.BondSection"].Properties["Mode"].Enum, "802.3ad")
+
+	nd := netdev.NetDev{NetDev: netdev.NetDevSection{Name: "bond0", Kind: "bond"}}
+	data, err := nd.MarshalJSON()
+	require.NoError(t, err)
+	assert.NoError(t, validate(schemas["netdev.NetDev"], schemas, "#/components/schemas/", data))
+
+	_, err = openAPIDocument("../..", []string{"unit"})
+	assert.EqualError(t, err, `unknown kind "unit"`)
+}
+
+// TestGenerated checks that the schemas in the schema package are up to date.
+func TestGenerated(t *testing.T) {
+	for kind := range kinds {
+		s, err := jsonSchema("../..", kind)
+		require.NoError(t, err)
+		want, err := marshal(s)
+		require.NoError(t, err)
+		got, err := ioutil.ReadFile(filepath.Join("../../schema", kind+".schema.json"))
+		require.NoError(t, err)
+		assert.Equal(t, string(want), string(got), "%s.schema.json is out of date, run go generate ./schema", kind)
+	}
+
+	doc, err := openAPIDocument("../..", []string{"network", "netdev", "link"})
+	require.NoError(t, err)
+	want, err := marshal(doc)
+	require.NoError(t, err)
+	got, err := ioutil.ReadFile("../../schema/openapi.json")
+	require.NoError(t, err)
+	assert.Equal(t, string(want), string(got), "openapi.json is out of date, run go generate ./schema")
+}
+
+// validate checks the JSON data against the subset of JSON Schema written by the generator.
+func validate(s *Schema, defs map[string]*Schema, refPrefix string, data []byte) error {
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	return validateValue(s, defs, refPrefix, v, "")
+}
+
+func validateValue(s *Schema, defs map[string]*Schema, refPrefix string, v interface{}, path string) error {
+	if s.Ref != "" {
+		def, ok := defs[s.Ref[len(refPrefix):]]
+		if !ok {
+			return fmt.Errorf("%s: unknown reference %s", path, s.Ref)
+		}
+		return validateValue(def, defs, refPrefix, v, path)
+	}
+	for _, s := range s.AllOf {
+		if err := validateValue(s, defs, refPrefix, v, path); err != nil {
+			return err
+		}
+	}
+	if len(s.AllOf) > 0 {
+		return nil
+	}
+
+	switch v := v.(type) {
+	case map[string]interface{}:
+		if s.Type != "object" {
+			return fmt.Errorf("%s: object instead of %s", path, s.Type)
+		}
+		for name, value := range v {
+			prop, ok := s.Properties[name]
+			if !ok {
+				additional, ok := s.AdditionalProperties.(*Schema)
+				if !ok {
+					return fmt.Errorf("%s: unknown property %s", path, name)
+				}
+				prop = additional
+			}
+			if err := validateValue(prop, defs, refPrefix, value, path+"/"+name); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		if s.Type != "array" {
+			return fmt.Errorf("%s: array instead of %s", path, s.Type)
+		}
+		for i, value := range v {
+			if err := validateValue(s.Items, defs, refPrefix, value, fmt.Sprintf("%s/%d", path, i)); err != nil {
+				return err
+			}
+		}
+	case string:
+		if s.Type != "string" {
+			return fmt.Errorf("%s: string instead of %s", path, s.Type)
+		}
+		if len(s.Enum) > 0 && !contains(s.Enum, v) {
+			return fmt.Errorf("%s: %q is not one of %q", path, v, s.Enum)
+		}
+	case bool:
+		if s.Type != "boolean" {
+			return fmt.Errorf("%s: boolean instead of %s", path, s.Type)
+		}
+	case float64:
+		if s.Type != "integer" && s.Type != "number" {
+			return fmt.Errorf("%s: number instead of %s", path, s.Type)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,219 @@
+// Package envfile reads and writes environment files in the dialect of systemd,
+// like the files referenced by EnvironmentFile= in units, /etc/default files
+// and /etc/os-release.
+//
+// A file is an ordered map of variables. Quotes, escapes, line continuations,
+// comments and the "export" prefix of shell scripts are understood when reading,
+// values are quoted as needed when writing.
+package envfile
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+
+	"routerd.net/go-systemd/internal/parser"
+)
+
+// A Var is a variable assignment of an env file.
+type Var struct {
+	Name  string
+	Value string
+	// Export is written as "export" prefix, so the file can be sourced by shell scripts.
+	Export bool
+	// Comment holds the comment lines in front of the assignment, without "#".
+	Comment string
+}
+
+// File holds the variables of an env file, in order of their first assignment.
+// The zero value is an empty file ready to use.
+type File struct {
+	vars  []Var
+	index map[string]int // index of the variables in vars by name
+
+	// TrailingComment holds the comment lines after the last variable.
+	TrailingComment string
+}
+
+// SyntaxError describes an invalid line of an env file.
+type SyntaxError = parser.EnvError
+
+// Position describes a location in an env file.
+type Position = parser.Position
+
+// Parse parses the contents of an env file.
+// A variable assigned more than once takes the last value, but keeps its position.
+// Lines without "=" are skipped, like systemd does,
+// invalid variable names and values are reported as *SyntaxError.
+func Parse(data []byte) (*File, error) {
+	return parse(data, "")
+}
+
+// Read parses the env file read from r, see Parse.
+func Read(r io.Reader) (*File, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(data)
+}
+
+// ReadFile parses the env file with the given name, see Parse.
+// Positions of syntax errors include the file name.
+func ReadFile(name string) (*File, error) {
+	data, err := ioutil.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	return parse(data, name)
+}
+
+func parse(data []byte, filename string) (*File, error) {
+	assignments, trailing, err := parser.ScanEnv(data, filename)
+	if err != nil {
+		return nil, err
+	}
+	f := &File{TrailingComment: trailing}
+	for _, a := range assignments {
+		v := f.add(a.Name)
+		v.Value, v.Export = a.Value, a.Export
+		if a.Comment != "" {
+			v.Comment = a.Comment
+		}
+	}
+	return f, nil
+}
+
+// add returns the variable with the name, appending it if it does not exist yet.
+func (f *File) add(name string) *Var {
+	if i, ok := f.index[name]; ok {
+		return &f.vars[i]
+	}
+	if f.index == nil {
+		f.index = map[string]int{}
+	}
+	f.index[name] = len(f.vars)
+	f.vars = append(f.vars, Var{Name: name})
+	return &f.vars[len(f.vars)-1]
+}
+
+// Len returns the number of variables.
+func (f *File) Len() int {
+	return len(f.vars)
+}
+
+// Get returns the value of the variable and whether it is set.
+func (f *File) Get(name string) (string, bool) {
+	if i, ok := f.index[name]; ok {
+		return f.vars[i].Value, true
+	}
+	return "", false
+}
+
+// Lookup returns the variable with the name and whether it is set.
+func (f *File) Lookup(name string) (Var, bool) {
+	if i, ok := f.index[name]; ok {
+		return f.vars[i], true
+	}
+	return Var{}, false
+}
+
+// Set sets the value of the variable, appending it if it is not set yet.
+func (f *File) Set(name, value string) {
+	f.add(name).Value = value
+}
+
+// SetVar replaces the variable with the name of v, appending it if it is not set yet.
+func (f *File) SetVar(v Var) {
+	*f.add(v.Name) = v
+}
+
+// Delete removes the variable, if it is set.
+func (f *File) Delete(name string) {
+	i, ok := f.index[name]
+	if !ok {
+		return
+	}
+	f.vars = append(f.vars[:i], f.vars[i+1:]...)
+	delete(f.index, name)
+	for j := i; j < len(f.vars); j++ {
+		f.index[f.vars[j].Name] = j
+	}
+}
+
+// Names returns the names of the variables in order.
+func (f *File) Names() []string {
+	names := make([]string, len(f.vars))
+	for i, v := range f.vars {
+		names[i] = v.Name
+	}
+	return names
+}
+
+// Vars returns a copy of the variables in order.
+func (f *File) Vars() []Var {
+	return append([]Var(nil), f.vars...)
+}
+
+// Map returns the variables as a map from names to values.
+func (f *File) Map() map[string]string {
+	m := make(map[string]string, len(f.vars))
+	for _, v := range f.vars {
+		m[v.Name] = v.Value
+	}
+	return m
+}
+
+// Environ returns the variables in the "name=value" form of os.Environ, in order.
+func (f *File) Environ() []string {
+	env := make([]string, len(f.vars))
+	for i, v := range f.vars {
+		env[i] = v.Name + "=" + v.Value
+	}
+	return env
+}
+
+// Encode writes the variables to w, one assignment per line with comments in front.
+// Values are quoted as needed, so Parse returns the same variables.
+// Invalid variable names are reported as error, before anything is written.
+func (f *File) Encode(w io.Writer) error {
+	b, err := f.Marshal()
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(b)
+	return err
+}
+
+// Marshal returns the env file, see Encode.
+func (f *File) Marshal() ([]byte, error) {
+	var b bytes.Buffer
+	for _, v := range f.vars {
+		if !parser.IsEnvName(v.Name) {
+			return nil, fmt.Errorf("envfile: invalid variable name %q", v.Name)
+		}
+		writeComment(&b, v.Comment)
+		if v.Export {
+			b.WriteString("export ")
+		}
+		b.WriteString(v.Name + "=" + parser.QuoteEnv(v.Value) + "\n")
+	}
+	writeComment(&b, f.TrailingComment)
+	return b.Bytes(), nil
+}
+
+func writeComment(b *bytes.Buffer, comment string) {
+	if comment == "" {
+		return
+	}
+	for _, line := range strings.Split(comment, "\n") {
+		if line == "" {
+			b.WriteString("#\n")
+			continue
+		}
+		// a trailing backslash would continue the comment on the next line
+		b.WriteString("# " + strings.TrimRight(line, " \t\\") + "\n")
+	}
+}
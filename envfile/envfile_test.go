@@ -0,0 +1,144 @@
+package envfile
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const osRelease = `PRETTY_NAME="Debian GNU/Linux 12 (bookworm)"
+NAME="Debian GNU/Linux"
+VERSION_ID="12"
+ID=debian
+HOME_URL="https://www.debian.org/"
+`
+
+const defaults = `# Defaults for the DHCP relay, sourced by
+# dhcp-relay.service and the init script.
+export SERVERS="10.0.0.1 10.0.0.2"
+INTERFACES='eth0 eth1'
+OPTIONS=-a \
+  -m replace
+; overridden below
+SERVERS=10.0.0.3
+EMPTY=
+# end
+`
+
+func TestParse(t *testing.T) {
+	f, err := Parse([]byte(osRelease))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"PRETTY_NAME", "NAME", "VERSION_ID", "ID", "HOME_URL"}, f.Names())
+	assert.Equal(t, map[string]string{
+		"PRETTY_NAME": "Debian GNU/Linux 12 (bookworm)",
+		"NAME":        "Debian GNU/Linux",
+		"VERSION_ID":  "12",
+		"ID":          "debian",
+		"HOME_URL":    "https://www.debian.org/",
+	}, f.Map())
+
+	f, err = Read(bytes.NewBufferString(defaults))
+	require.NoError(t, err)
+	assert.Equal(t, []Var{
+		{Name: "SERVERS", Value: "10.0.0.3", Export: false, Comment: "overridden below"},
+		{Name: "INTERFACES", Value: "eth0 eth1"},
+		{Name: "OPTIONS", Value: "-a   -m replace"},
+		{Name: "EMPTY"},
+	}, f.Vars())
+	assert.Equal(t, "end", f.TrailingComment)
+	assert.Equal(t, []string{"SERVERS=10.0.0.3", "INTERFACES=eth0 eth1", "OPTIONS=-a   -m replace", "EMPTY="}, f.Environ())
+
+	// CRLF line endings, also within continuations and quotes
+	f, err = Parse(bytes.ReplaceAll([]byte(defaults), []byte("\n"), []byte("\r\n")))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"SERVERS=10.0.0.3", "INTERFACES=eth0 eth1", "OPTIONS=-a   -m replace", "EMPTY="}, f.Environ())
+	f, err = Parse([]byte("A=\"con\\\r\ntinued\"\r\nB=\"two\r\nlines\"\r\n"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"A=continued", "B=two\nlines"}, f.Environ())
+
+	// lines without "=" are skipped
+	f, err = Parse([]byte("A=1\nB\n"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"A=1"}, f.Environ())
+}
+
+func TestReadFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "envfile")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	name := filepath.Join(dir, "defaults")
+	require.NoError(t, ioutil.WriteFile(name, []byte(defaults), 0666))
+	f, err := ReadFile(name)
+	require.NoError(t, err)
+	assert.Equal(t, 4, f.Len())
+
+	require.NoError(t, ioutil.WriteFile(name, []byte("1A=x\n"), 0666))
+	_, err = ReadFile(name)
+	assert.EqualError(t, err, name+":1:1: invalid variable name 1A")
+
+	_, err = ReadFile(filepath.Join(dir, "missing"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestFile(t *testing.T) {
+	f := &File{}
+	_, ok := f.Get("A")
+	assert.False(t, ok)
+	f.Delete("A")
+
+	f.Set("A", "1")
+	f.Set("B", "two words")
+	f.SetVar(Var{Name: "C", Value: `"$HOME"`, Export: true, Comment: "quoted\n\nends in \\"})
+	f.Set("A", "one")
+	value, ok := f.Get("A")
+	assert.True(t, ok)
+	assert.Equal(t, "one", value)
+	v, ok := f.Lookup("C")
+	assert.True(t, ok)
+	assert.True(t, v.Export)
+	assert.Equal(t, []string{"A", "B", "C"}, f.Names())
+
+	f.Delete("A")
+	assert.Equal(t, []string{"B", "C"}, f.Names())
+	f.Set("A", "again")
+	assert.Equal(t, []string{"B", "C", "A"}, f.Names())
+	f.TrailingComment = "end"
+
+	var out bytes.Buffer
+	require.NoError(t, f.Encode(&out))
+	assert.Equal(t, `B="two words"
+# quoted
+#
+# ends in
+export C="\"\$HOME\""
+A=again
+# end
+`, out.String())
+
+	parsed, err := Parse(out.Bytes())
+	require.NoError(t, err)
+	assert.Equal(t, f.Map(), parsed.Map())
+
+	// unchanged files are written as they are read, except for quotes
+	parsed, err = Parse([]byte(defaults))
+	require.NoError(t, err)
+	b, err := parsed.Marshal()
+	require.NoError(t, err)
+	assert.Equal(t, `# overridden below
+SERVERS=10.0.0.3
+INTERFACES="eth0 eth1"
+OPTIONS="-a   -m replace"
+EMPTY=
+# end
+`, string(b))
+
+	f.Set("NOT VALID", "x")
+	_, err = f.Marshal()
+	assert.EqualError(t, err, `envfile: invalid variable name "NOT VALID"`)
+}
@@ -0,0 +1,373 @@
+package encoding
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Marshaler is implemented by file structs that marshal themselves,
+// usually by code generated with cmd/systemd-codegen.
+// Marshal and Encoder.Encode prefer it over reflection,
+// unless a systemd version is targeted, see MarshalForVersion.
+type Marshaler interface {
+	MarshalSystemd() ([]Section, error)
+}
+
+// Unmarshaler is implemented by file structs that unmarshal themselves,
+// usually by code generated with cmd/systemd-codegen.
+// Unmarshal and File.Unmarshal prefer it over reflection.
+type Unmarshaler interface {
+	UnmarshalSystemd(file *File) error
+}
+
+var (
+	uintType    = reflect.TypeOf(uint(0))
+	uintPtrType = reflect.TypeOf((*uint)(nil))
+)
+
+// A SectionDecoder stores the keys of a section in the fields of a section struct
+// for generated UnmarshalSystemd methods, following the rules of Unmarshal.
+// Every method handles the assignments of one key.
+// After the first error, all methods do nothing and Err returns the error.
+type SectionDecoder struct {
+	section    *Section
+	addComment func(key, comment string)
+	known      []bool // keys handled by a field
+	keys       []Key  // buffer of the assignments of the current key
+	err        error
+}
+
+// NewSectionDecoder returns a decoder for section.
+// Comments of the keys are passed to addComment, if it is not nil.
+func NewSectionDecoder(section *Section, addComment func(key, comment string)) *SectionDecoder {
+	return &SectionDecoder{
+		section:    section,
+		addComment: addComment,
+		known:      make([]bool, len(section.Keys)),
+	}
+}
+
+// Err returns the first error of the decoder.
+func (d *SectionDecoder) Err() error {
+	return d.err
+}
+
+// keysByName marks the key as known and returns its assignments.
+func (d *SectionDecoder) keysByName(name string) []Key {
+	d.keys = d.keys[:0]
+	if d.err != nil {
+		return nil
+	}
+	for i, key := range d.section.Keys {
+		if key.Name == name {
+			d.known[i] = true
+			d.keys = append(d.keys, key)
+		}
+	}
+	return d.keys
+}
+
+func (d *SectionDecoder) comment(name, comment string) {
+	if comment != "" && d.addComment != nil {
+		d.addComment(name, comment)
+	}
+}
+
+func (d *SectionDecoder) typeError(key Key, t reflect.Type, err error) {
+	d.err = newUnmarshalTypeError(d.section, key, t, err)
+}
+
+// String stores the last assignment of the key in v.
+func (d *SectionDecoder) String(name string, v *string) {
+	keys := d.keysByName(name)
+	if len(keys) == 0 {
+		return
+	}
+	key := keys[len(keys)-1]
+	*v = key.Value
+	d.comment(name, key.Comment)
+}
+
+// Bool stores the last assignment of the key in v, invalid booleans are ignored.
+func (d *SectionDecoder) Bool(name string, v *bool) {
+	keys := d.keysByName(name)
+	if len(keys) == 0 {
+		return
+	}
+	key := keys[len(keys)-1]
+	b := StrToBool(key.Value)
+	if b == nil {
+		return
+	}
+	*v = *b
+	d.comment(name, key.Comment)
+}
+
+// Uint stores the last assignment of the key in v, an empty assignment resets it to 0.
+func (d *SectionDecoder) Uint(name string, v *uint) {
+	keys := d.keysByName(name)
+	if len(keys) == 0 {
+		return
+	}
+	key := keys[len(keys)-1]
+	if key.Value == "" {
+		*v = 0
+		d.comment(name, key.Comment)
+		return
+	}
+	u, err := strconv.ParseUint(key.Value, 10, strconv.IntSize)
+	if err != nil {
+		d.typeError(key, uintType, err.(*strconv.NumError).Err)
+		return
+	}
+	*v = uint(u)
+	d.comment(name, key.Comment)
+}
+
+// StringPtr stores the last assignment of the key in v.
+// With omitempty, an empty assignment is ignored.
+func (d *SectionDecoder) StringPtr(name string, v **string, omitempty bool) {
+	keys := d.keysByName(name)
+	if len(keys) == 0 {
+		return
+	}
+	key := keys[len(keys)-1]
+	if key.Value == "" && omitempty {
+		return
+	}
+	*v = StringPtr(key.Value)
+	d.comment(name, key.Comment)
+}
+
+// BoolPtr stores the last assignment of the key in v,
+// empty assignments and invalid booleans are ignored.
+func (d *SectionDecoder) BoolPtr(name string, v **bool) {
+	keys := d.keysByName(name)
+	if len(keys) == 0 {
+		return
+	}
+	key := keys[len(keys)-1]
+	b := StrToBool(key.Value)
+	if b == nil {
+		return
+	}
+	*v = b
+	d.comment(name, key.Comment)
+}
+
+// UintPtr stores the last assignment of the key in v, empty assignments are ignored.
+func (d *SectionDecoder) UintPtr(name string, v **uint) {
+	keys := d.keysByName(name)
+	if len(keys) == 0 {
+		return
+	}
+	key := keys[len(keys)-1]
+	if key.Value == "" {
+		return
+	}
+	u, err := strconv.ParseUint(key.Value, 10, strconv.IntSize)
+	if err != nil {
+		d.typeError(key, uintPtrType, err.(*strconv.NumError).Err)
+		return
+	}
+	n := uint(u)
+	*v = &n
+	d.comment(name, key.Comment)
+}
+
+// Strings stores the values of all assignments of the key in v,
+// an empty assignment resets the list.
+// With wslist, every assignment holds a whitespace separated list of values.
+func (d *SectionDecoder) Strings(name string, v *[]string, wslist bool) {
+	keys := d.keysByName(name)
+	if len(keys) == 0 {
+		return
+	}
+	var (
+		values  []string
+		comment string
+	)
+	for _, key := range keys {
+		if comment != "" {
+			comment += "\n"
+		}
+		comment += key.Comment
+
+		if key.Value == "" {
+			values, comment = nil, ""
+			continue
+		}
+		if wslist {
+			values = append(values, filterEmpty(strings.Split(key.Value, " "))...)
+			continue
+		}
+		values = append(values, key.Value)
+	}
+	*v = values
+	d.comment(name, comment)
+}
+
+// Value stores the assignments of the key in the field pointed to by v by reflection,
+// as Unmarshal does for a field with the systemd tag options opts, like ",append".
+// It handles the field types and options not supported by the other methods.
+func (d *SectionDecoder) Value(name string, v interface{}, opts string) {
+	keys := d.keysByName(name)
+	if len(keys) == 0 {
+		return
+	}
+	config := configForTag(name, opts)
+	keys = config.Mode.effectiveKeys(keys)
+	if len(keys) == 0 {
+		return
+	}
+	comment, err := unmarshalField(d.section, keys, reflect.ValueOf(v).Elem(), config)
+	if err != nil {
+		d.err = err
+		return
+	}
+	d.comment(name, comment)
+}
+
+// Unknown passes the keys not handled by any other method to add, if it is not nil.
+func (d *SectionDecoder) Unknown(add func(key Key)) {
+	if d.err != nil || add == nil {
+		return
+	}
+	for i, key := range d.section.Keys {
+		if !d.known[i] {
+			add(key)
+		}
+	}
+}
+
+// A SectionEncoder builds a section from the fields of a section struct
+// for generated MarshalSystemd methods, following the rules of Marshal.
+// Every method appends the assignments of one key.
+// Err returns the first error of a value marshaler.
+type SectionEncoder struct {
+	section    Section
+	getComment func(key string) string
+	err        error
+}
+
+// NewSectionEncoder returns an encoder for the section name with the section comment.
+// Comments of the keys are looked up by getComment, if it is not nil.
+func NewSectionEncoder(name, comment string, getComment func(key string) string) *SectionEncoder {
+	return &SectionEncoder{
+		section:    Section{Name: name, Comment: comment},
+		getComment: getComment,
+	}
+}
+
+// Err returns the first error of the encoder.
+func (e *SectionEncoder) Err() error {
+	return e.err
+}
+
+func (e *SectionEncoder) comment(name string) string {
+	if e.getComment == nil {
+		return ""
+	}
+	return e.getComment(name)
+}
+
+func (e *SectionEncoder) add(name, value string) {
+	key := Key{Name: name, Value: value, Comment: e.comment(name)}
+	e.section.Keys = append(e.section.Keys, key)
+}
+
+// String appends the key, unless v is empty and omitempty is set.
+func (e *SectionEncoder) String(name string, v string, omitempty bool) {
+	if v == "" && omitempty {
+		return
+	}
+	e.add(name, v)
+}
+
+// Bool appends the key, unless v is false and omitempty is set.
+func (e *SectionEncoder) Bool(name string, v bool, omitempty bool) {
+	if !v && omitempty {
+		return
+	}
+	e.add(name, BoolToStr(v))
+}
+
+// Uint appends the key, unless v is 0 and omitempty is set.
+func (e *SectionEncoder) Uint(name string, v uint, omitempty bool) {
+	if v == 0 && omitempty {
+		return
+	}
+	e.add(name, strconv.FormatUint(uint64(v), 10))
+}
+
+// StringPtr appends the key, unless v is nil and omitempty is set.
+// A nil v is written as an empty assignment.
+func (e *SectionEncoder) StringPtr(name string, v *string, omitempty bool) {
+	switch {
+	case v != nil:
+		e.add(name, *v)
+	case !omitempty:
+		e.add(name, "")
+	}
+}
+
+// BoolPtr appends the key, unless v is nil and omitempty is set.
+// A nil v is written as an empty assignment.
+func (e *SectionEncoder) BoolPtr(name string, v *bool, omitempty bool) {
+	switch {
+	case v != nil:
+		e.add(name, BoolToStr(*v))
+	case !omitempty:
+		e.add(name, "")
+	}
+}
+
+// UintPtr appends the key, unless v is nil and omitempty is set.
+// A nil v is written as an empty assignment.
+func (e *SectionEncoder) UintPtr(name string, v *uint, omitempty bool) {
+	switch {
+	case v != nil:
+		e.add(name, strconv.FormatUint(uint64(*v), 10))
+	case !omitempty:
+		e.add(name, "")
+	}
+}
+
+// Strings appends an assignment for every non-empty value,
+// or a single assignment of the whitespace separated values with wslist.
+// The key comment is attached to the first assignment.
+func (e *SectionEncoder) Strings(name string, v []string, wslist bool) {
+	if wslist {
+		if value := strings.Join(v, " "); value != "" {
+			e.add(name, value)
+		}
+		return
+	}
+	for i, value := range v {
+		if value == "" {
+			continue
+		}
+		if i == 0 {
+			e.add(name, value)
+			continue
+		}
+		e.section.Keys = append(e.section.Keys, Key{Name: name, Value: value})
+	}
+}
+
+// Value appends the assignments of the field pointed to by v by reflection,
+// as Marshal does for a field with the systemd tag options opts, like ",append".
+// It handles the field types and options not supported by the other methods.
+func (e *SectionEncoder) Value(name string, v interface{}, opts string) {
+	if e.err != nil {
+		return
+	}
+	e.err = marshalKey(&e.section, reflect.ValueOf(v).Elem(), configForTag(name, opts), e.comment(name))
+}
+
+// Section returns the section, with the unknown keys appended.
+func (e *SectionEncoder) Section(unknown []Key) Section {
+	e.section.Keys = append(e.section.Keys, unknown...)
+	return e.section
+}
@@ -0,0 +1,245 @@
+package encoding
+
+import (
+	"errors"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type codegenFile struct {
+	SectionList
+	Main *codegenSection `systemd:",since=250"`
+	err  error           // returned by MarshalSystemd
+}
+
+type codegenSection struct {
+	KeyList
+	Comment string
+	KeyComments
+
+	Name    string
+	Enabled bool `systemd:",omitempty"`
+	Count   uint `systemd:",omitempty"`
+	Label   *string
+	Alias   *string  `systemd:",omitempty"`
+	Up      *bool    `systemd:",omitempty"`
+	Limit   *uint    `systemd:",omitempty"`
+	Names   []string `systemd:",wslist"`
+	Values  []string
+
+	// handled by reflection
+	Priority int           `systemd:",omitempty"`
+	Weight   float64       `systemd:",omitempty"`
+	Timeout  time.Duration `systemd:",omitempty"`
+	Gateway  net.IP        `systemd:",omitempty"`
+	DNS      []string      `systemd:",append"`
+	Servers  []string      `systemd:",reset"`
+	Kind     string        `systemd:",omitempty,first"`
+	Ports    []uint        `systemd:",lastwins"`
+}
+
+// reflectFile has the fields of codegenFile without its methods.
+type reflectFile codegenFile
+
+func (f *codegenFile) MarshalSystemd() ([]Section, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	var sections []Section
+	if s := f.Main; s != nil {
+		e := NewSectionEncoder("Main", s.Comment, s.GetKeyComment)
+		e.String("Name", s.Name, false)
+		e.Bool("Enabled", s.Enabled, true)
+		e.Uint("Count", s.Count, true)
+		e.StringPtr("Label", s.Label, false)
+		e.StringPtr("Alias", s.Alias, true)
+		e.BoolPtr("Up", s.Up, true)
+		e.UintPtr("Limit", s.Limit, true)
+		e.Strings("Names", s.Names, true)
+		e.Strings("Values", s.Values, false)
+		e.Value("Priority", &s.Priority, ",omitempty")
+		e.Value("Weight", &s.Weight, ",omitempty")
+		e.Value("Timeout", &s.Timeout, ",omitempty")
+		e.Value("Gateway", &s.Gateway, ",omitempty")
+		e.Value("DNS", &s.DNS, ",append")
+		e.Value("Servers", &s.Servers, ",reset")
+		e.Value("Kind", &s.Kind, ",omitempty,first")
+		e.Value("Ports", &s.Ports, ",lastwins")
+		if err := e.Err(); err != nil {
+			return nil, err
+		}
+		sections = append(sections, e.Section(s.KeyList))
+	}
+	return append(sections, f.SectionList...), nil
+}
+
+func (f *codegenFile) UnmarshalSystemd(file *File) error {
+	for i := range file.Sections {
+		section := &file.Sections[i]
+		if section.Name != "Main" {
+			f.AddSection(*section)
+			continue
+		}
+		s := &codegenSection{Comment: section.Comment}
+		d := NewSectionDecoder(section, s.AddKeyComment)
+		d.String("Name", &s.Name)
+		d.Bool("Enabled", &s.Enabled)
+		d.Uint("Count", &s.Count)
+		d.StringPtr("Label", &s.Label, false)
+		d.StringPtr("Alias", &s.Alias, true)
+		d.BoolPtr("Up", &s.Up)
+		d.UintPtr("Limit", &s.Limit)
+		d.Strings("Names", &s.Names, true)
+		d.Strings("Values", &s.Values, false)
+		d.Value("Priority", &s.Priority, ",omitempty")
+		d.Value("Weight", &s.Weight, ",omitempty")
+		d.Value("Timeout", &s.Timeout, ",omitempty")
+		d.Value("Gateway", &s.Gateway, ",omitempty")
+		d.Value("DNS", &s.DNS, ",append")
+		d.Value("Servers", &s.Servers, ",reset")
+		d.Value("Kind", &s.Kind, ",omitempty,first")
+		d.Value("Ports", &s.Ports, ",lastwins")
+		d.Unknown(s.AddKey)
+		if err := d.Err(); err != nil {
+			return err
+		}
+		f.Main = s
+	}
+	return nil
+}
+
+func TestSectionCodegen(t *testing.T) {
+	tests := []struct {
+		Name string
+		File string
+	}{
+		{Name: "empty", File: "[Main]\n"},
+		{Name: "all keys", File: `# main
+[Main]
+Name=test
+Enabled=yes
+Count=16
+Label=label
+Alias=alias
+Up=no
+Limit=100
+# names
+Names=a  b
+Names=c
+Values=1
+Values=2
+Priority=-5
+Weight=0.5
+Timeout=1min 30s
+Gateway=192.168.0.1
+DNS=1.1.1.1
+DNS=
+# second server
+DNS=8.8.8.8
+Servers=a
+Servers=
+Servers=b c
+Kind=first
+Kind=second
+Ports=1 2
+Ports=3
+Other=x
+
+[Other]
+Foo=bar
+`},
+		{Name: "empty assignments", File: `[Main]
+Name=test
+Name=
+Enabled=
+Count=3
+Count=
+Label=
+Alias=
+Up=
+Limit=
+Names=a
+# reset
+Names=
+Values=1
+Values=
+Priority=
+Timeout=
+Gateway=
+Servers=a
+# reset
+Servers=
+`},
+		{Name: "invalid booleans", File: "[Main]\nEnabled=maybe\nUp=maybe\n"},
+		{Name: "comments", File: `[Main]
+# one
+Values=1
+Values=2
+# three
+Values=3
+# name
+Name=a
+Name=b
+`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			generated := &codegenFile{}
+			require.NoError(t, Unmarshal([]byte(test.File), generated))
+			reflected := &reflectFile{}
+			require.NoError(t, Unmarshal([]byte(test.File), reflected))
+			assert.Equal(t, (*codegenFile)(reflected), generated)
+
+			b, err := Marshal(generated)
+			require.NoError(t, err)
+			expected, err := Marshal(reflected)
+			require.NoError(t, err)
+			assert.Equal(t, string(expected), string(b))
+		})
+	}
+
+	for _, in := range []string{
+		"[Main]\nCount=many\n", "[Main]\nLimit=-1\n", "[Main]\nPriority=high\n",
+		// numbers are decimal only
+		"[Main]\nCount=0x10\n", "[Main]\nLimit=1_000\n",
+	} {
+		generatedErr := Unmarshal([]byte(in), &codegenFile{})
+		require.Error(t, generatedErr)
+		assert.Equal(t, Unmarshal([]byte(in), &reflectFile{}), generatedErr)
+		assert.True(t, errors.Is(generatedErr, strconv.ErrSyntax))
+	}
+}
+
+func TestCodegenInterfaces(t *testing.T) {
+	in := &codegenFile{Main: &codegenSection{Name: "test"}}
+	b, err := Marshal(in)
+	require.NoError(t, err)
+	assert.Equal(t, "[Main]\nName=test\nLabel=\n", string(b))
+
+	// versions are handled by reflection only
+	_, err = MarshalForVersion(in, 249)
+	assert.Equal(t, &VersionError{Section: "Main", Since: 250, Version: 249}, err)
+
+	// errors of value marshalers are wrapped like other errors of MarshalSystemd
+	in.Main.Gateway = net.IP{1, 2}
+	_, generatedErr := Marshal(in)
+	require.Error(t, generatedErr)
+	_, err = Marshal((*reflectFile)(in))
+	require.Error(t, err)
+	assert.Equal(t, err, errors.Unwrap(generatedErr))
+	in.Main.Gateway = nil
+
+	in.err = errors.New("broken")
+	_, err = Marshal(in)
+	assert.EqualError(t, err, "systemd: error calling marshaler for type *encoding.codegenFile: broken")
+
+	_, err = Marshal((*codegenFile)(nil))
+	assert.IsType(t, &InvalidUnmarshalError{}, err)
+	assert.IsType(t, &InvalidUnmarshalError{}, Unmarshal([]byte("[Main]\n"), (*codegenFile)(nil)))
+}
@@ -1,71 +1,277 @@
 package encoding
 
 import (
+	"bytes"
 	"fmt"
+	"io"
+	"os"
 	"strings"
 
 	"routerd.net/go-systemd/internal/parser"
 )
 
 // Decode takes a systemd configuration file and returns a data container to access and manipulate it.
+// Comments after the last key are kept, so Encode writes them back.
 func Decode(data []byte) (*File, error) {
-	var d decodeState
-	d.init(data)
-	f, err := d.decode()
+	return DecodeOptions{}.Decode(data)
+}
+
+// ReadFile reads and decodes the named file.
+// The file name is recorded in the positions of all sections and keys.
+func ReadFile(filename string) (*File, error) {
+	return DecodeOptions{}.ReadFile(filename)
+}
+
+// DecodeLossless works like Decode, but additionally records the original
+// formatting of every section and key. Encoding the returned File reproduces
+// the input byte-for-byte; modified sections and keys are written in the
+// canonical format with the line ending of the first line,
+// while all unmodified lines are kept as they are.
+func DecodeLossless(data []byte) (*File, error) {
+	return DecodeOptions{Lossless: true}.Decode(data)
+}
+
+// DecodeOptions configures how systemd configuration files are decoded.
+// The zero value decodes strictly and without limits.
+type DecodeOptions struct {
+	// Filename is recorded in the positions of all sections and keys.
+	Filename string
+
+	// Lossless records the original formatting, see DecodeLossless.
+	Lossless bool
+
+	// Lenient mode ignores illegal characters and skips
+	// lines that systemd would ignore with a warning,
+	// like keys without = or keys outside of sections.
+	// In strict mode these are reported as *SyntaxError.
+	Lenient bool
+
+	// AllowKeysOutsideSections collects keys in front of the
+	// first section into a section with an empty name.
+	AllowKeysOutsideSections bool
+
+	// MaxFileSize limits the size of the input in bytes, 0 means no limit.
+	MaxFileSize int
+
+	// MaxLineLength limits the length of each line in bytes, 0 means no limit.
+	MaxLineLength int
+}
+
+// Decode takes a systemd configuration file and returns a data container to access and manipulate it.
+func (o DecodeOptions) Decode(data []byte) (*File, error) {
+	return o.decodeReader(bytes.NewReader(data))
+}
+
+// ReadFile reads and decodes the named file.
+// The file name is recorded in the positions of all sections and keys.
+func (o DecodeOptions) ReadFile(filename string) (*File, error) {
+	f, err := os.Open(filename)
 	if err != nil {
 		return nil, err
 	}
+	defer f.Close()
+
+	o.Filename = filename
+	return o.decodeReader(f)
+}
+
+// decodeReader decodes the file read from r.
+// The input is scanned incrementally, only lossless decoding keeps a copy of it.
+func (o DecodeOptions) decodeReader(r io.Reader) (*File, error) {
+	if o.MaxFileSize > 0 || o.MaxLineLength > 0 {
+		r = &limitReader{r: r, opts: o, line: 1}
+	}
+	var src *bytes.Buffer
+	if o.Lossless {
+		src = &bytes.Buffer{}
+		r = io.TeeReader(r, src)
+	}
+
+	var d decodeState
+	d.init(r)
+	d.opts = o
+	d.file.Name = o.Filename
+	d.src = src
+	return d.decode()
+}
+
+// limitReader enforces MaxFileSize and MaxLineLength while reading.
+type limitReader struct {
+	r    io.Reader
+	opts DecodeOptions
+	size int // bytes read so far
+	line int // current line
+	col  int // length of the current line
+}
+
+func (l *limitReader) Read(p []byte) (int, error) {
+	n, err := l.r.Read(p)
+	l.size += n
+	if l.opts.MaxFileSize > 0 && l.size > l.opts.MaxFileSize {
+		return 0, &SyntaxError{
+			Msg: fmt.Sprintf("file size exceeds limit of %d bytes", l.opts.MaxFileSize),
+			Pos: Position{Filename: l.opts.Filename},
+		}
+	}
+	if l.opts.MaxLineLength <= 0 {
+		return n, err
+	}
+	for _, b := range p[:n] {
+		if b == '\n' {
+			l.line++
+			l.col = 0
+			continue
+		}
+		l.col++
+		if l.col > l.opts.MaxLineLength {
+			return 0, &SyntaxError{
+				Msg: fmt.Sprintf("line length exceeds limit of %d bytes", l.opts.MaxLineLength),
+				Pos: Position{Filename: l.opts.Filename, Line: l.line},
+			}
+		}
+	}
+	return n, err
+}
+
+// A Decoder reads and decodes systemd configuration from an input stream.
+type Decoder struct {
+	r    io.Reader
+	opts DecodeOptions
+	done bool
+
+	disallowUnknownFields bool
+}
+
+// NewDecoder returns a new decoder that reads from r.
+func NewDecoder(r io.Reader) *Decoder {
+	return DecodeOptions{}.NewDecoder(r)
+}
+
+// NewDecoder returns a new decoder that reads from r using the options of o.
+func (o DecodeOptions) NewDecoder(r io.Reader) *Decoder {
+	return &Decoder{r: r, opts: o}
+}
+
+// DisallowUnknownFields causes the Decoder to return an error when the input
+// contains sections or keys without a matching struct field, see UnmarshalStrict.
+func (dec *Decoder) DisallowUnknownFields() {
+	dec.disallowUnknownFields = true
+}
+
+// Decode reads the whole input and stores it in the value pointed to by v.
+// If v is a *File, it receives the decoded file,
+// otherwise the file is unmarshaled into v as with Unmarshal.
+// Decode returns io.EOF once the input has been consumed.
+func (dec *Decoder) Decode(v interface{}) error {
+	if dec.done {
+		return io.EOF
+	}
+	dec.done = true
 
-	return f, nil
+	file, err := dec.opts.decodeReader(dec.r)
+	if err != nil {
+		return err
+	}
+	if f, ok := v.(*File); ok && f != nil {
+		*f = *file
+		return nil
+	}
+	if dec.disallowUnknownFields {
+		return file.UnmarshalStrict(v)
+	}
+	return file.Unmarshal(v)
 }
 
 // decodeState stores the current state of a decode operation.
 type decodeState struct {
 	scanner parser.Scanner
+	opts    DecodeOptions
+	err     error // first error reported by the scanner
 	// comments belonging to the next section
 	// or the next/current key
 	comment string
 	section *Section // active section
 	key     *Key     // active key
+	discard bool     // the active key is ignored
 	file    *File
+
+	// source of the input, only set when decoding lossless
+	src      *bytes.Buffer
+	lines    []string
+	resolve  []func() // assign source text once all lines are read
+	lastLine int      // last line consumed by a section or key
+	keyLine  int      // first line of the active key
 }
 
-func (d *decodeState) init(src []byte) *decodeState {
-	d.scanner.Init(src, nil)
+func (d *decodeState) init(r io.Reader) *decodeState {
+	d.scanner.InitReader(r, d.scanError)
+	d.opts = DecodeOptions{}
+	d.err = nil
 	d.comment = ""
 	d.section = nil
 	d.key = nil
+	d.discard = false
 	d.file = &File{}
+	d.src = nil
+	d.lines = nil
+	d.resolve = nil
+	d.lastLine = 0
+	d.keyLine = 0
 	return d
 }
 
+// scanError is the parser.ErrorHandler of the scanner.
+func (d *decodeState) scanError(pos parser.Position, msg string) {
+	if d.err != nil || d.opts.Lenient {
+		return
+	}
+	pos.Filename = d.file.Name
+	d.err = &SyntaxError{Msg: msg, Pos: pos}
+}
+
 func (d *decodeState) decode() (*File, error) {
+	var prevTok parser.Token
 decode:
 	for {
-		pos, tok, lit := d.scanner.Scan()
+		pos, tok, lit := d.scan()
+		if err := d.scanner.Err(); err != nil {
+			return nil, err
+		}
+		if d.err != nil {
+			return nil, d.err
+		}
 		switch tok {
 		case parser.COMMENT:
 			d.addComment(pos, tok, lit)
 
-		case parser.ASSIGN:
-			if d.key != nil {
-				// ignore ASSIGN tokens
-				// when scanning a value
-				d.key.Value += "="
-			}
-
 		case parser.EOF:
 			// force close
-			d.closeKey()
+			d.closeKey(pos)
+			if d.opts.Lossless {
+				d.lines = splitLines(d.src.Bytes())
+				for _, resolve := range d.resolve {
+					resolve()
+				}
+				d.file.trailing = strings.Join(d.lines[d.lastLine:], "")
+				if len(d.lines) > 0 && strings.HasSuffix(d.lines[0], "\r\n") {
+					d.file.lineEnding = "\r\n"
+				}
+			} else {
+				d.file.trailingComment = d.comment
+			}
 			break decode
 
 		case parser.NEWLINE:
-			if d.key != nil &&
-				!strings.HasSuffix(d.key.Value, "\\") {
-				// stop scanning value, but continue scanning on \
-				// for multi line strings
-				d.closeKey()
+			if d.key == nil || prevTok == parser.COMMENT {
+				// comment lines don't end multi line values
+				break
 			}
+			if parser.EndsWithContinuation(d.key.Value) {
+				// continue scanning on \ for multi line values
+				d.key.Value = parser.TrimContinuation(d.key.Value)
+				break
+			}
+			d.closeKey(pos)
 
 		case parser.STRING:
 			if err := d.addString(pos, tok, lit); err != nil {
@@ -77,6 +283,7 @@ decode:
 				return nil, err
 			}
 		}
+		prevTok = tok
 	}
 	return d.file, nil
 }
@@ -84,10 +291,10 @@ decode:
 func (d *decodeState) addSection(pos parser.Position, tok parser.Token, lit string) error {
 	// validate section name
 	if !strings.HasPrefix(lit, "[") {
-		return fmt.Errorf("%s: section needs to start with [, is: %q", pos, lit)
+		return &SyntaxError{Msg: fmt.Sprintf("section needs to start with [, is: %q", lit), Pos: pos}
 	}
 	if !strings.HasSuffix(lit, "]") {
-		return fmt.Errorf("%s: section needs to end with ], is: %q", pos, lit)
+		return &SyntaxError{Msg: fmt.Sprintf("section needs to end with ], is: %q", lit), Pos: pos}
 	}
 
 	d.file.Sections = append(d.file.Sections, Section{
@@ -95,34 +302,77 @@ func (d *decodeState) addSection(pos parser.Position, tok parser.Token, lit stri
 		Comment: d.comment,
 	})
 	d.section = &d.file.Sections[len(d.file.Sections)-1]
+	d.section.Pos = pos
 	d.comment = ""
+
+	if d.opts.Lossless {
+		src := &sectionSource{
+			name:    d.section.Name,
+			comment: d.section.Comment,
+		}
+		d.section.src = src
+		first, line := d.lastLine+1, pos.Line
+		d.resolve = append(d.resolve, func() {
+			src.leading = d.source(first, line-1)
+			src.header = d.source(line, line)
+		})
+		d.lastLine = pos.Line
+	}
 	return nil
 }
 
 func (d *decodeState) addString(pos parser.Position, tok parser.Token, lit string) error {
-	if d.section == nil {
-		// We want to be in a section before encountering any STRING
-		return fmt.Errorf("%s: key started outside of section %q", pos, lit)
+	// Value
+	if d.key != nil {
+		d.key.Value += lit
+		return nil
 	}
 
 	// KEY
-	if d.key == nil {
-		if pos, tok, lit := d.scanner.Scan(); tok != parser.ASSIGN {
-			return fmt.Errorf("%s: key not followed by = (ASSIGN), token found: %s %q", pos, tok, lit)
+	if apos, atok, alit := d.scan(); atok != parser.ASSIGN {
+		if d.opts.Lenient {
+			// ignore line, the key is followed by NEWLINE or EOF
+			return nil
 		}
+		return &SyntaxError{Msg: fmt.Sprintf("key not followed by = (ASSIGN), token found: %s %q", atok, alit), Pos: apos}
+	}
 
-		d.section.Keys = append(d.section.Keys, Key{
-			Name: strings.TrimSpace(lit),
-		})
-		d.key = &d.section.Keys[len(d.section.Keys)-1]
-		return nil
+	key := Key{
+		Name: strings.TrimSpace(lit),
+		Pos:  pos,
 	}
+	d.keyLine = pos.Line
+	if d.section == nil {
+		switch {
+		case d.opts.AllowKeysOutsideSections:
+			d.addGlobalSection(pos)
 
-	// Value
-	d.key.Value += strings.TrimSpace(lit)
+		case d.opts.Lenient:
+			// scan and drop the key
+			d.key = &key
+			d.discard = true
+			return nil
+
+		default:
+			// We want to be in a section before encountering any STRING
+			return &SyntaxError{Msg: fmt.Sprintf("key started outside of section %q", lit), Pos: pos}
+		}
+	}
+
+	d.section.Keys = append(d.section.Keys, key)
+	d.key = &d.section.Keys[len(d.section.Keys)-1]
 	return nil
 }
 
+// addGlobalSection adds the nameless section holding keys in front of the first section.
+func (d *decodeState) addGlobalSection(pos parser.Position) {
+	d.file.Sections = append(d.file.Sections, Section{Pos: pos})
+	d.section = &d.file.Sections[len(d.file.Sections)-1]
+	if d.opts.Lossless {
+		d.section.src = &sectionSource{}
+	}
+}
+
 func (d *decodeState) addComment(pos parser.Position, tok parser.Token, lit string) {
 	if d.comment != "" {
 		d.comment += "\n"
@@ -130,12 +380,72 @@ func (d *decodeState) addComment(pos parser.Position, tok parser.Token, lit stri
 	d.comment += strings.TrimSpace(lit[1:]) // strip # or ;
 }
 
-func (d *decodeState) closeKey() {
+// scan returns the next token, with the file name added to its position.
+func (d *decodeState) scan() (parser.Position, parser.Token, string) {
+	pos, tok, lit := d.scanner.Scan()
+	pos.Filename = d.file.Name
+	return pos, tok, lit
+}
+
+// closeKey finishes the active key, pos is the position of the NEWLINE or EOF token ending it.
+func (d *decodeState) closeKey(pos parser.Position) {
 	if d.key == nil {
 		return
 	}
+	if d.discard {
+		// the lines of ignored keys are
+		// kept as leading lines of the next key
+		d.key = nil
+		d.discard = false
+		return
+	}
 	d.key.Comment = d.comment
-	d.key.Value = strings.ReplaceAll(d.key.Value, "\\", " ")
+	d.key.Value = strings.TrimSpace(d.key.Value)
+
+	if d.opts.Lossless {
+		src := &keySource{
+			name:    d.key.Name,
+			value:   d.key.Value,
+			comment: d.key.Comment,
+		}
+		d.key.src = src
+		first, keyLine, last := d.lastLine+1, d.keyLine, pos.Line
+		d.resolve = append(d.resolve, func() {
+			src.leading = d.source(first, keyLine-1)
+			src.text = d.source(keyLine, last)
+		})
+		d.lastLine = pos.Line
+	}
 	d.key = nil
 	d.comment = ""
 }
+
+// source returns the verbatim source of the lines from first to last (inclusive, 1-based).
+func (d *decodeState) source(first, last int) string {
+	if last > len(d.lines) {
+		last = len(d.lines)
+	}
+	if first > last {
+		return ""
+	}
+	return strings.Join(d.lines[first-1:last], "")
+}
+
+// splitLines splits data after each newline.
+func splitLines(data []byte) []string {
+	lines := strings.SplitAfter(string(data), "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// A SyntaxError describes malformed systemd configuration.
+type SyntaxError struct {
+	Msg string   // description of error
+	Pos Position // position of the error
+}
+
+func (e *SyntaxError) Error() string {
+	return e.Pos.String() + ": " + e.Msg
+}
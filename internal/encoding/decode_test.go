@@ -1,7 +1,14 @@
 package encoding
 
 import (
+	"bytes"
+	"errors"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
 	"testing"
+	"testing/iotest"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -32,29 +39,35 @@ Address=10.1.10.11/24
 			{
 				Comment: "network comment",
 				Name:    "Network",
+				Pos:     Position{Line: 2, Column: 1},
 				Keys: []Key{
 					{
 						Comment: "start desc\nin the middle",
 						Name:    "Description",
-						Value:   "test1  test2  test3",
+						Value:   "test1  \ttest2  \ttest3",
+						Pos:     Position{Line: 4, Column: 1},
 					},
 					{
 						Comment: "address 1",
 						Name:    "Address",
 						Value:   "10.1.10.9/24",
+						Pos:     Position{Line: 9, Column: 1},
 					},
 					{
 						Name:  "Address",
 						Value: "",
+						Pos:   Position{Line: 10, Column: 1},
 					},
 					{
 						Name:  "Gateway",
 						Value: "10.1.10.1",
+						Pos:   Position{Line: 11, Column: 1},
 					},
 					{
 						Comment: "address 2\nsomething else",
 						Name:    "Address",
 						Value:   "10.1.10.11/24",
+						Pos:     Position{Line: 14, Column: 1},
 					},
 				},
 			},
@@ -81,28 +94,34 @@ Destination=20.0.0.0/8`
 			{
 				Comment: "route1000\nalso important",
 				Name:    "Route",
+				Pos:     Position{Line: 3, Column: 1},
 				Keys: []Key{
 					{
 						Name:  "Gateway",
 						Value: "192.168.0.11",
+						Pos:   Position{Line: 4, Column: 1},
 					},
 					{
 						Name:  "Destination",
 						Value: "10.0.0.0/8",
+						Pos:   Position{Line: 5, Column: 1},
 					},
 				},
 			},
 			{
 				Comment: "route2000\nthis is very important!",
 				Name:    "Route",
+				Pos:     Position{Line: 9, Column: 1},
 				Keys: []Key{
 					{
 						Name:  "Gateway",
 						Value: "192.168.0.12",
+						Pos:   Position{Line: 10, Column: 1},
 					},
 					{
 						Name:  "Destination",
 						Value: "20.0.0.0/8",
+						Pos:   Position{Line: 11, Column: 1},
 					},
 				},
 			},
@@ -121,18 +140,54 @@ Environment=ETCD_KEY_FILE=/path/to/server.key`
 		Sections: []Section{
 			{
 				Name: "Service",
+				Pos:  Position{Line: 1, Column: 1},
 				Keys: []Key{
 					{
 						Name:  "Environment",
 						Value: "ETCD_CA_FILE=/path/to/CA.pem",
+						Pos:   Position{Line: 2, Column: 1},
 					},
 					{
 						Name:  "Environment",
 						Value: "ETCD_CERT_FILE=/path/to/server.crt",
+						Pos:   Position{Line: 3, Column: 1},
 					},
 					{
 						Name:  "Environment",
 						Value: "ETCD_KEY_FILE=/path/to/server.key",
+						Pos:   Position{Line: 4, Column: 1},
+					},
+				},
+			},
+		},
+	}
+
+	// ----------------------
+	// quoting and escaping
+	// ----------------------
+	const quoting = `[Service]
+ExecStart=/bin/sh -c "echo \"a = b\"" \x20 \
+	# skipped
+	--flag=1
+Name=eth0 # not a comment
+`
+
+	var quotingFile = &File{
+		Sections: []Section{
+			{
+				Name: "Service",
+				Pos:  Position{Line: 1, Column: 1},
+				Keys: []Key{
+					{
+						Comment: "skipped",
+						Name:    "ExecStart",
+						Value:   "/bin/sh -c \"echo \\\"a = b\\\"\" \\x20  \t--flag=1",
+						Pos:     Position{Line: 2, Column: 1},
+					},
+					{
+						Name:  "Name",
+						Value: "eth0 # not a comment",
+						Pos:   Position{Line: 5, Column: 1},
 					},
 				},
 			},
@@ -159,6 +214,11 @@ Environment=ETCD_KEY_FILE=/path/to/server.key`
 			Input: nestedAssign,
 			File:  nestedAssignFile,
 		},
+		{
+			Name:  "quoting",
+			Input: quoting,
+			File:  quotingFile,
+		},
 	}
 
 	for _, test := range tests {
@@ -169,3 +229,175 @@ Environment=ETCD_KEY_FILE=/path/to/server.key`
 		})
 	}
 }
+
+func TestDecodeErrors(t *testing.T) {
+	tests := []struct {
+		Name  string
+		Input string
+		Err   string
+	}{
+		{
+			Name:  "unterminated section",
+			Input: "[Network\nName=eth0\n",
+			Err:   `1:1: section needs to end with ], is: "[Network"`,
+		},
+		{
+			Name:  "key outside of section",
+			Input: "# comment\nName=eth0\n",
+			Err:   `2:1: key started outside of section "Name"`,
+		},
+		{
+			Name:  "missing assign",
+			Input: "[Match]\n  Name\n",
+			Err:   `2:7: key not followed by = (ASSIGN), token found: NEWLINE ""`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			_, err := Decode([]byte(test.Input))
+			var syntaxErr *SyntaxError
+			require.True(t, errors.As(err, &syntaxErr))
+			assert.EqualError(t, err, test.Err)
+		})
+	}
+}
+
+func TestReadFile(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "foo.network")
+	require.NoError(t, ioutil.WriteFile(filename, []byte(`[Match]
+Name=eth0
+
+[Route]
+  Metric=abc
+`), 0644))
+
+	f, err := ReadFile(filename)
+	require.NoError(t, err)
+	assert.Equal(t, filename, f.Name)
+	assert.Equal(t, Position{Filename: filename, Line: 4, Column: 1}, f.Sections[1].Pos)
+	assert.Equal(t, Position{Filename: filename, Line: 5, Column: 3}, f.Sections[1].Keys[0].Pos)
+
+	var v struct {
+		Route struct {
+			Metric uint
+		}
+	}
+	err = f.Unmarshal(&v)
+	var typeErr *UnmarshalTypeError
+	require.True(t, errors.As(err, &typeErr))
+	assert.Equal(t, "Route", typeErr.Section)
+	assert.Equal(t, "Metric", typeErr.Key)
+	assert.EqualError(t, err, filename+`:5:3: cannot unmarshal "abc" of key [Route] Metric into Go value of type uint: invalid syntax`)
+}
+
+func TestDecodeOptions(t *testing.T) {
+	t.Run("illegal characters", func(t *testing.T) {
+		inputs := map[string]string{
+			"[Match]\nName=eth\x000\n": "2:9: illegal character NUL",
+			"[Match]\nName=eth\xff0\n": "2:9: illegal UTF-8 encoding",
+		}
+		for input, msg := range inputs {
+			_, err := Decode([]byte(input))
+			var syntaxErr *SyntaxError
+			require.True(t, errors.As(err, &syntaxErr))
+			assert.EqualError(t, err, msg)
+
+			_, err = DecodeOptions{Lenient: true}.Decode([]byte(input))
+			assert.NoError(t, err)
+		}
+	})
+
+	t.Run("lenient", func(t *testing.T) {
+		const input = `Ignored=yes
+[Match]
+Name
+Name=eth0
+`
+		f, err := DecodeOptions{Lenient: true}.Decode([]byte(input))
+		require.NoError(t, err)
+		require.Len(t, f.Sections, 1)
+		assert.Equal(t, []Key{
+			{Name: "Name", Value: "eth0", Pos: Position{Line: 4, Column: 1}},
+		}, f.Sections[0].Keys)
+
+		f, err = DecodeOptions{Lenient: true, Lossless: true}.Decode([]byte(input))
+		require.NoError(t, err)
+		var buf bytes.Buffer
+		require.NoError(t, Encode(&buf, f))
+		assert.Equal(t, input, buf.String())
+	})
+
+	t.Run("keys outside of sections", func(t *testing.T) {
+		const input = `Global=yes
+
+[Match]
+Name=eth0
+`
+		f, err := DecodeOptions{AllowKeysOutsideSections: true}.Decode([]byte(input))
+		require.NoError(t, err)
+		require.Len(t, f.Sections, 2)
+		assert.Equal(t, "", f.Sections[0].Name)
+		assert.Equal(t, "Global", f.Sections[0].Keys[0].Name)
+
+		var buf bytes.Buffer
+		require.NoError(t, Encode(&buf, f))
+		assert.Equal(t, input, buf.String())
+	})
+
+	t.Run("limits", func(t *testing.T) {
+		const input = "[Match]\nName=eth0\n"
+		_, err := DecodeOptions{MaxFileSize: 10, Filename: "foo.network"}.Decode([]byte(input))
+		assert.EqualError(t, err, "foo.network: file size exceeds limit of 10 bytes")
+
+		_, err = DecodeOptions{MaxLineLength: 8}.Decode([]byte(input))
+		assert.EqualError(t, err, "2: line length exceeds limit of 8 bytes")
+
+		_, err = DecodeOptions{MaxFileSize: 18, MaxLineLength: 9}.Decode([]byte(input))
+		assert.NoError(t, err)
+	})
+}
+
+func TestDecoder(t *testing.T) {
+	const input = `[Match]
+Name=eth0
+
+[Route]
+Gateway=10.0.0.1
+
+[Route]
+Gateway=10.0.0.2
+Destination=10.1.0.0/16
+`
+
+	t.Run("file", func(t *testing.T) {
+		dec := DecodeOptions{Lossless: true}.NewDecoder(iotest.OneByteReader(strings.NewReader(input)))
+		var f File
+		require.NoError(t, dec.Decode(&f))
+		assert.Len(t, f.Sections, 3)
+		assert.Equal(t, Position{Line: 9, Column: 1}, f.Sections[2].Keys[1].Pos)
+
+		var buf bytes.Buffer
+		require.NoError(t, Encode(&buf, &f))
+		assert.Equal(t, input, buf.String())
+
+		assert.Equal(t, io.EOF, dec.Decode(&f))
+	})
+
+	t.Run("struct", func(t *testing.T) {
+		var f testFile
+		require.NoError(t, NewDecoder(strings.NewReader(input)).Decode(&f))
+		assert.Equal(t, "eth0", f.Match.Name)
+		require.Len(t, f.Routes, 2)
+		assert.Equal(t, "10.1.0.0/16", f.Routes[1].Destination)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		var f File
+		err := NewDecoder(iotest.TimeoutReader(strings.NewReader(input))).Decode(&f)
+		assert.Equal(t, iotest.ErrTimeout, err)
+
+		err = DecodeOptions{MaxLineLength: 16}.NewDecoder(strings.NewReader(input)).Decode(&f)
+		assert.EqualError(t, err, "9: line length exceeds limit of 16 bytes")
+	})
+}
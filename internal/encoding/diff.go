@@ -0,0 +1,434 @@
+package encoding
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DefaultSectionIdentity lists the keys identifying repeated sections,
+// used to match them between two files.
+var DefaultSectionIdentity = map[string][]string{
+	"Address":       {"Address"},
+	"Route":         {"Destination", "Source", "Table", "Metric"},
+	"WireGuardPeer": {"PublicKey"},
+	"Neighbor":      {"Address"},
+	"NextHop":       {"Id"},
+	"IPv6Prefix":    {"Prefix"},
+}
+
+// Diff compares two files and returns the changes turning a into b.
+func Diff(a, b *File) []Change {
+	return DiffOptions{}.Diff(a, b)
+}
+
+// ThreeWayMerge applies the changes between base and upstream to local.
+// Changes that contradict local modifications are not applied, but reported as conflicts.
+func ThreeWayMerge(base, local, upstream *File) (*File, []Conflict) {
+	return DiffOptions{}.ThreeWayMerge(base, local, upstream)
+}
+
+// DiffOptions configures how files are compared.
+type DiffOptions struct {
+	// Identity lists the keys identifying sections, by section name.
+	// Sections with identity keys only match sections with the same values.
+	// Sections without are matched by the number of keys they share.
+	// Defaults to DefaultSectionIdentity.
+	Identity map[string][]string
+}
+
+// ChangeType describes the kind of a Change.
+type ChangeType int
+
+const (
+	SectionAdded ChangeType = iota + 1
+	SectionRemoved
+	KeyAdded
+	KeyChanged
+	KeyRemoved
+)
+
+func (t ChangeType) String() string {
+	switch t {
+	case SectionAdded:
+		return "section added"
+	case SectionRemoved:
+		return "section removed"
+	case KeyAdded:
+		return "key added"
+	case KeyChanged:
+		return "key changed"
+	case KeyRemoved:
+		return "key removed"
+	}
+	return fmt.Sprintf("ChangeType(%d)", int(t))
+}
+
+// A Change describes a single difference between two files a and b.
+type Change struct {
+	Type    ChangeType
+	Section string // name of the section
+	A, B    int    // index of the section in a and b, -1 if it does not exist there
+	Key     string // name of the key, empty for section changes
+	Old     string // value in a, for changed and removed keys
+	New     string // value in b, for changed and added keys
+}
+
+func (c Change) String() string {
+	switch c.Type {
+	case SectionAdded:
+		return "+[" + c.Section + "]"
+	case SectionRemoved:
+		return "-[" + c.Section + "]"
+	case KeyAdded:
+		return "[" + c.Section + "] +" + c.Key + "=" + c.New
+	case KeyChanged:
+		return "[" + c.Section + "] " + c.Key + "=" + c.Old + " -> " + c.New
+	case KeyRemoved:
+		return "[" + c.Section + "] -" + c.Key + "=" + c.Old
+	}
+	return c.Type.String()
+}
+
+// A Conflict describes an upstream change that could not be applied,
+// because the affected section or key was modified locally as well.
+type Conflict struct {
+	Change Change   // upstream change, indices refer to base and upstream
+	Local  []string // local values of the key, nil for section conflicts
+	Reason string
+}
+
+func (c Conflict) String() string {
+	return c.Change.String() + ": " + c.Reason
+}
+
+// Diff compares two files and returns the changes turning a into b.
+// Changes are ordered by the sections of a, followed by sections only found in b.
+func (o DiffOptions) Diff(a, b *File) []Change {
+	var changes []Change
+	matches := o.matchSections(a, b)
+	matched := make([]bool, len(b.Sections))
+	for i, j := range matches {
+		section := &a.Sections[i]
+		if j == -1 {
+			changes = append(changes, Change{
+				Type: SectionRemoved, Section: section.Name, A: i, B: -1,
+			})
+			continue
+		}
+		matched[j] = true
+		for _, c := range diffKeys(section, &b.Sections[j]) {
+			c.A, c.B = i, j
+			changes = append(changes, c)
+		}
+	}
+	for j := range b.Sections {
+		if !matched[j] {
+			changes = append(changes, Change{
+				Type: SectionAdded, Section: b.Sections[j].Name, A: -1, B: j,
+			})
+		}
+	}
+	return changes
+}
+
+// matchSections pairs the sections of a and b.
+// The returned slice holds the index of the matching section in b
+// for every section of a, or -1 if there is none.
+func (o DiffOptions) matchSections(a, b *File) []int {
+	identity := o.Identity
+	if identity == nil {
+		identity = DefaultSectionIdentity
+	}
+
+	matches := make([]int, len(a.Sections))
+	used := make([]bool, len(b.Sections))
+	for i := range a.Sections {
+		matches[i] = -1
+
+		keys, hasIdentity := identity[a.Sections[i].Name]
+		best, bestScore := -1, -1
+		for j := range b.Sections {
+			if used[j] || a.Sections[i].Name != b.Sections[j].Name {
+				continue
+			}
+			if hasIdentity {
+				if sectionIdentity(&a.Sections[i], keys) == sectionIdentity(&b.Sections[j], keys) {
+					best = j
+					break
+				}
+				continue
+			}
+			if score := commonKeys(&a.Sections[i], &b.Sections[j]); score > bestScore {
+				best, bestScore = j, score
+			}
+		}
+		if best != -1 {
+			matches[i] = best
+			used[best] = true
+		}
+	}
+	return matches
+}
+
+// sectionIdentity returns the values of the identity keys of section.
+func sectionIdentity(section *Section, keys []string) string {
+	values := make([]string, len(keys))
+	for i, key := range keys {
+		values[i], _ = section.Get(key)
+	}
+	return strings.Join(values, "\x00")
+}
+
+// commonKeys returns the number of keys with the same name and value in a and b.
+func commonKeys(a, b *Section) int {
+	count := map[Key]int{}
+	for _, key := range b.Keys {
+		count[Key{Name: key.Name, Value: key.Value}]++
+	}
+	n := 0
+	for _, key := range a.Keys {
+		k := Key{Name: key.Name, Value: key.Value}
+		if count[k] > 0 {
+			count[k]--
+			n++
+		}
+	}
+	return n
+}
+
+// diffKeys returns the key changes turning section a into b.
+// Repeated keys are compared by their effective values in order,
+// an empty assignment resets the values assigned before, see effectiveValues.
+func diffKeys(a, b *Section) []Change {
+	var changes []Change
+	for _, name := range keyNames(a.Keys, b.Keys) {
+		removed, added := diffValues(
+			effectiveValues(a.KeysByName(name)),
+			effectiveValues(b.KeysByName(name)),
+		)
+		if len(removed) == 1 && len(added) == 1 && removed[0] != added[0] {
+			changes = append(changes, Change{
+				Type: KeyChanged, Section: a.Name, Key: name, Old: removed[0], New: added[0],
+			})
+			continue
+		}
+		for _, value := range removed {
+			changes = append(changes, Change{
+				Type: KeyRemoved, Section: a.Name, Key: name, Old: value,
+			})
+		}
+		for _, value := range added {
+			changes = append(changes, Change{
+				Type: KeyAdded, Section: a.Name, Key: name, New: value,
+			})
+		}
+	}
+	return changes
+}
+
+// keyValues returns the values of keys.
+func keyValues(keys []Key) []string {
+	out := make([]string, len(keys))
+	for i, key := range keys {
+		out[i] = key.Value
+	}
+	return out
+}
+
+// effectiveValues returns the values of the assignments keys of a key
+// that are in effect: the ones after the last empty assignment.
+// The last reset itself is kept in front, as it still resets
+// the values of the files a drop-in is merged with.
+func effectiveValues(keys []Key) []string {
+	values := keyValues(keys)
+	for i := len(values) - 1; i >= 0; i-- {
+		if values[i] == "" {
+			return values[i:]
+		}
+	}
+	return values
+}
+
+// diffValues returns the values of a and b that are not part of
+// their longest common subsequence, so the order of the values matters.
+func diffValues(a, b []string) (removed, added []string) {
+	// common[i][j] is the length of the longest common subsequence of a[i:] and b[j:]
+	common := make([][]int, len(a)+1)
+	for i := range common {
+		common[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			switch {
+			case a[i] == b[j]:
+				common[i][j] = common[i+1][j+1] + 1
+			case common[i+1][j] >= common[i][j+1]:
+				common[i][j] = common[i+1][j]
+			default:
+				common[i][j] = common[i][j+1]
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			i++
+			j++
+		case common[i+1][j] >= common[i][j+1]:
+			removed = append(removed, a[i])
+			i++
+		default:
+			added = append(added, b[j])
+			j++
+		}
+	}
+	return append(removed, a[i:]...), append(added, b[j:]...)
+}
+
+// ThreeWayMerge applies the changes between base and upstream to local,
+// keeping local modifications and the formatting of local.
+// Upstream changes that contradict local modifications are not applied,
+// but reported as conflicts.
+func (o DiffOptions) ThreeWayMerge(base, local, upstream *File) (*File, []Conflict) {
+	var (
+		conflicts []Conflict
+		merged    = copyFile(local)
+		toLocal   = o.matchSections(base, local) // base index to local index
+		deleted   = make([]bool, len(merged.Sections))
+		added     = map[int][]Section{} // sections to insert after a local index, -1 for the end
+	)
+	conflict := func(c Change, local []string, format string, args ...interface{}) {
+		conflicts = append(conflicts, Conflict{Change: c, Local: local, Reason: fmt.Sprintf(format, args...)})
+	}
+
+	// local sections without counterpart in base
+	localOnly := make([]bool, len(merged.Sections))
+	for i := range localOnly {
+		localOnly[i] = true
+	}
+	for _, l := range toLocal {
+		if l != -1 {
+			localOnly[l] = false
+		}
+	}
+
+	for _, c := range o.Diff(base, upstream) {
+		switch c.Type {
+		case SectionAdded:
+			section := &upstream.Sections[c.B]
+			if l := o.findSection(merged, localOnly, section); l != -1 {
+				if commonKeys(section, &merged.Sections[l]) != len(section.Keys) ||
+					len(section.Keys) != len(merged.Sections[l].Keys) {
+					conflict(c, nil, "added locally with different keys")
+				}
+				localOnly[l] = false
+				continue
+			}
+			anchor := -1
+			for l := range merged.Sections {
+				if merged.Sections[l].Name == section.Name {
+					anchor = l
+				}
+			}
+			added[anchor] = append(added[anchor], copySection(*section))
+
+		case SectionRemoved:
+			l := toLocal[c.A]
+			if l == -1 {
+				// removed locally as well
+				continue
+			}
+			if len(diffKeys(&base.Sections[c.A], &merged.Sections[l])) != 0 {
+				conflict(c, nil, "modified locally")
+				continue
+			}
+			deleted[l] = true
+
+		default:
+			l := toLocal[c.A]
+			if l == -1 {
+				conflict(c, nil, "section removed locally")
+				continue
+			}
+			section := &merged.Sections[l]
+			localValues := keyValues(section.KeysByName(c.Key))
+			baseValues := keyValues(base.Sections[c.A].KeysByName(c.Key))
+			switch c.Type {
+			case KeyChanged:
+				if i := keyIndex(section, c.Key, c.Old); i != -1 {
+					section.Keys[i].Value = c.New
+				} else if keyIndex(section, c.Key, c.New) == -1 {
+					conflict(c, localValues, "changed locally to %s", strings.Join(localValues, ", "))
+				}
+
+			case KeyAdded:
+				switch {
+				case keyIndex(section, c.Key, c.New) != -1:
+					// added locally as well
+				case len(baseValues) != 0 || len(localValues) == 0:
+					section.Add(c.Key, c.New)
+				default:
+					conflict(c, localValues, "added locally as %s", strings.Join(localValues, ", "))
+				}
+
+			case KeyRemoved:
+				switch i := keyIndex(section, c.Key, c.Old); {
+				case i != -1:
+					section.DeleteKey(i)
+				case len(localValues) != 0:
+					conflict(c, localValues, "changed locally to %s", strings.Join(localValues, ", "))
+				}
+			}
+		}
+	}
+
+	var sections []Section
+	for l, section := range merged.Sections {
+		if !deleted[l] {
+			sections = append(sections, section)
+		}
+		sections = append(sections, added[l]...)
+	}
+	merged.Sections = append(sections, added[-1]...)
+	return merged, conflicts
+}
+
+// findSection returns the index of a section of f matching section,
+// only considering candidates, or -1 if there is none.
+func (o DiffOptions) findSection(f *File, candidates []bool, section *Section) int {
+	identity := o.Identity
+	if identity == nil {
+		identity = DefaultSectionIdentity
+	}
+	keys := identity[section.Name]
+	for i := range f.Sections {
+		if candidates[i] && f.Sections[i].Name == section.Name &&
+			sectionIdentity(&f.Sections[i], keys) == sectionIdentity(section, keys) {
+			return i
+		}
+	}
+	return -1
+}
+
+// keyIndex returns the index of the first key with the given name and value, or -1.
+func keyIndex(section *Section, name, value string) int {
+	for i, key := range section.Keys {
+		if key.Name == name && key.Value == value {
+			return i
+		}
+	}
+	return -1
+}
+
+// copyFile returns a deep copy of f, keeping the original formatting.
+func copyFile(f *File) *File {
+	c := *f
+	c.Sections = make([]Section, len(f.Sections))
+	for i, section := range f.Sections {
+		section.Keys = append([]Key(nil), section.Keys...)
+		c.Sections[i] = section
+	}
+	return &c
+}
@@ -0,0 +1,207 @@
+package encoding
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func mustDecode(t *testing.T, input string) *File {
+	t.Helper()
+	f, err := DecodeLossless([]byte(input))
+	require.NoError(t, err)
+	return f
+}
+
+func TestDiff(t *testing.T) {
+	a := mustDecode(t, `[Match]
+Name=eth0
+
+[Network]
+Address=10.0.0.2/24
+DNS=1.1.1.1
+DNS=8.8.8.8
+Gateway=10.0.0.1
+
+[Route]
+Destination=10.1.0.0/16
+Gateway=10.0.0.1
+
+[Route]
+Destination=10.2.0.0/16
+Gateway=10.0.0.1
+
+[WireGuardPeer]
+PublicKey=abc
+Endpoint=1.2.3.4:51820
+`)
+	b := mustDecode(t, `[Match]
+Name=eth0
+
+[Network]
+Address=10.0.0.2/24
+DNS=8.8.8.8
+DNS=9.9.9.9
+DNS=1.0.0.1
+Gateway=10.0.0.254
+
+[Route]
+Destination=10.2.0.0/16
+Gateway=10.0.0.254
+
+[Route]
+Destination=10.3.0.0/16
+Gateway=10.0.0.1
+
+[WireGuardPeer]
+PublicKey=abc
+Endpoint=5.6.7.8:51820
+`)
+
+	changes := Diff(a, b)
+	var out []string
+	for _, c := range changes {
+		out = append(out, c.String())
+	}
+	assert.Equal(t, []string{
+		"[Network] -DNS=1.1.1.1",
+		"[Network] +DNS=9.9.9.9",
+		"[Network] +DNS=1.0.0.1",
+		"[Network] Gateway=10.0.0.1 -> 10.0.0.254",
+		"-[Route]",
+		"[Route] Gateway=10.0.0.1 -> 10.0.0.254",
+		"[WireGuardPeer] Endpoint=1.2.3.4:51820 -> 5.6.7.8:51820",
+		"+[Route]",
+	}, out)
+
+	// indices refer to the sections of a and b
+	assert.Equal(t, Change{Type: SectionRemoved, Section: "Route", A: 2, B: -1}, changes[4])
+	assert.Equal(t, 3, changes[5].A)
+	assert.Equal(t, 2, changes[5].B)
+	assert.Equal(t, Change{Type: SectionAdded, Section: "Route", A: -1, B: 3}, changes[7])
+
+	assert.Empty(t, Diff(a, a))
+
+	// without identity keys, repeated sections match by their common keys
+	changes = DiffOptions{Identity: map[string][]string{}}.Diff(a, b)
+	assert.Equal(t, Change{
+		Type: KeyChanged, Section: "Route", A: 2, B: 3, Key: "Destination", Old: "10.1.0.0/16", New: "10.3.0.0/16",
+	}, changes[4])
+}
+
+func TestDiffResets(t *testing.T) {
+	diff := func(a, b string) []string {
+		var out []string
+		for _, c := range Diff(mustDecode(t, a), mustDecode(t, b)) {
+			out = append(out, c.String())
+		}
+		return out
+	}
+
+	// the reset clears the value assigned before it, but not the one after it
+	assert.Equal(t, []string{"[Network] +DNS=1.1.1.1"},
+		diff("[Network]\nDNS=1.1.1.1\nDNS=\n", "[Network]\nDNS=\nDNS=1.1.1.1\n"))
+	assert.Equal(t, []string{"[Network] -DNS=1.1.1.1"},
+		diff("[Network]\nDNS=\nDNS=1.1.1.1\n", "[Network]\nDNS=1.1.1.1\nDNS=\n"))
+
+	// values cleared by a reset are not in effect
+	assert.Empty(t, diff("[Network]\nDNS=8.8.8.8\nDNS=\nDNS=1.1.1.1\n", "[Network]\nDNS=\nDNS=1.1.1.1\n"))
+	assert.Equal(t, []string{"[Network] +DNS="},
+		diff("[Network]\nDNS=1.1.1.1\n", "[Network]\nDNS=\nDNS=1.1.1.1\n"))
+
+	// the order of the values matters
+	assert.Equal(t, []string{"[Network] -DNS=1.1.1.1", "[Network] +DNS=1.1.1.1"},
+		diff("[Network]\nDNS=1.1.1.1\nDNS=8.8.8.8\n", "[Network]\nDNS=8.8.8.8\nDNS=1.1.1.1\n"))
+}
+
+func TestThreeWayMergeResets(t *testing.T) {
+	base := mustDecode(t, "[Network]\nDNS=1.1.1.1\n\n[Route]\nGateway=10.0.0.1\nGateway=\n")
+	local := mustDecode(t, "[Network]\nDNS=1.1.1.1\n\n[Route]\nGateway=\nGateway=10.0.0.1\n")
+	upstream := mustDecode(t, "[Network]\nDNS=1.1.1.1\n")
+
+	// reordering the reset is a local modification
+	_, conflicts := ThreeWayMerge(base, local, upstream)
+	require.Len(t, conflicts, 1)
+	assert.Equal(t, "-[Route]: modified locally", conflicts[0].String())
+}
+
+func TestThreeWayMerge(t *testing.T) {
+	base := mustDecode(t, `[Network]
+Address=10.0.0.2/24
+Gateway=10.0.0.1
+DNS=1.1.1.1
+
+[Route]
+Destination=10.1.0.0/16
+Gateway=10.0.0.1
+
+[Route]
+Destination=10.2.0.0/16
+Gateway=10.0.0.1
+`)
+	local := mustDecode(t, `[Network]
+# local address
+Address=10.0.0.3/24
+Gateway=10.0.0.1
+DNS=1.1.1.1
+
+[Route]
+Destination=10.1.0.0/16
+Gateway=10.0.0.1
+
+[Route]
+Destination=10.2.0.0/16
+Gateway=10.0.0.2
+
+[Route]
+Destination=10.9.0.0/16
+`)
+	upstream := mustDecode(t, `[Network]
+Address=10.0.0.4/24
+Gateway=10.0.0.254
+DNS=1.1.1.1
+DNS=8.8.8.8
+
+[Route]
+Destination=10.3.0.0/16
+Gateway=10.0.0.1
+`)
+
+	merged, conflicts := ThreeWayMerge(base, local, upstream)
+
+	var out []string
+	for _, c := range conflicts {
+		out = append(out, c.String())
+	}
+	assert.Equal(t, []string{
+		"[Network] Address=10.0.0.2/24 -> 10.0.0.4/24: changed locally to 10.0.0.3/24",
+		"-[Route]: modified locally",
+	}, out)
+	assert.Equal(t, []string{"10.0.0.3/24"}, conflicts[0].Local)
+
+	var buf bytes.Buffer
+	require.NoError(t, Encode(&buf, merged))
+	assert.Equal(t, `[Network]
+# local address
+Address=10.0.0.3/24
+Gateway=10.0.0.254
+DNS=1.1.1.1
+DNS=8.8.8.8
+
+[Route]
+Destination=10.2.0.0/16
+Gateway=10.0.0.2
+
+[Route]
+Destination=10.9.0.0/16
+
+[Route]
+Destination=10.3.0.0/16
+Gateway=10.0.0.1
+`, buf.String())
+
+	// local is not modified
+	assert.Len(t, local.Sections, 4)
+}
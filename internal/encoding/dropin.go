@@ -0,0 +1,571 @@
+package encoding
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// NetworkSearchPath lists the directories searched by systemd-networkd and systemd-udevd
+// for .network, .netdev and .link files, highest priority first.
+var NetworkSearchPath = []string{
+	"/etc/systemd/network",
+	"/run/systemd/network",
+	"/usr/local/lib/systemd/network",
+	"/usr/lib/systemd/network",
+}
+
+// Load finds the named file and its drop-ins in NetworkSearchPath,
+// merges them and stores the result in the value pointed to by v.
+func Load(name string, v interface{}) error {
+	return DropInOptions{}.LoadInto(name, v)
+}
+
+// DropInOptions configures how a file and its drop-ins are found and merged.
+type DropInOptions struct {
+	// DecodeOptions are used to decode the main file and all drop-ins.
+	// The Filename is set for every file read.
+	DecodeOptions
+
+	// SearchPath lists the directories to search, highest priority first.
+	// Defaults to NetworkSearchPath, the directories used by systemd-networkd.
+	SearchPath []string
+
+	// MergeRules control how drop-ins are merged into the main file.
+	MergeRules
+}
+
+// Find returns the path of the named main file and its drop-ins.
+//
+// The main file is taken from the first directory of the search path that contains it.
+// Drop-ins are the *.conf files of all <name>.d directories in the search path,
+// sorted by their file name. A drop-in hides drop-ins with the same file name
+// in directories of lower priority, so linking it to /dev/null masks them.
+func (o DropInOptions) Find(name string) (string, []string, error) {
+	searchPath := o.SearchPath
+	if searchPath == nil {
+		searchPath = NetworkSearchPath
+	}
+
+	var main string
+	for _, dir := range searchPath {
+		path := filepath.Join(dir, name)
+		_, err := os.Stat(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return "", nil, err
+		}
+		main = path
+		break
+	}
+	if main == "" {
+		return "", nil, &os.PathError{Op: "find", Path: name, Err: os.ErrNotExist}
+	}
+
+	dropIns := map[string]string{}
+	for _, dir := range searchPath {
+		entries, err := ioutil.ReadDir(filepath.Join(dir, name+".d"))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return "", nil, err
+		}
+		for _, entry := range entries {
+			if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".conf") {
+				continue
+			}
+			if _, ok := dropIns[entry.Name()]; ok {
+				// hidden by a directory of higher priority
+				continue
+			}
+			dropIns[entry.Name()] = filepath.Join(dir, name+".d", entry.Name())
+		}
+	}
+
+	names := make([]string, 0, len(dropIns))
+	for dropIn := range dropIns {
+		names = append(names, dropIn)
+	}
+	sort.Strings(names)
+	paths := make([]string, len(names))
+	for i, dropIn := range names {
+		paths[i] = dropIns[dropIn]
+	}
+	return main, paths, nil
+}
+
+// ErrMasked is returned by Load for a main file linked to /dev/null,
+// which masks the file of the same name in directories of lower priority.
+var ErrMasked = errors.New("file is masked")
+
+// Load finds, reads and merges the named file and its drop-ins.
+// If the main file is masked, an *os.PathError wrapping ErrMasked is returned
+// and its drop-ins are not read.
+func (o DropInOptions) Load(name string) (*File, error) {
+	main, dropIns, err := o.Find(name)
+	if err != nil {
+		return nil, err
+	}
+	if isMasked(main) {
+		return nil, &os.PathError{Op: "load", Path: main, Err: ErrMasked}
+	}
+
+	files := make([]*File, 0, 1+len(dropIns))
+	for _, path := range append([]string{main}, dropIns...) {
+		f, err := o.DecodeOptions.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, f)
+	}
+	return o.MergeRules.Merge(files...), nil
+}
+
+// isMasked reports whether path is a symbolic link to /dev/null.
+func isMasked(path string) bool {
+	target, err := filepath.EvalSymlinks(path)
+	return err == nil && target == os.DevNull
+}
+
+// LoadInto works like Load and stores the merged file in the value pointed to by v.
+// If no MergeRules are set, the rules are derived from v, see MergeRulesFor.
+func (o DropInOptions) LoadInto(name string, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return &InvalidUnmarshalError{reflect.TypeOf(v)}
+	}
+	if o.RepeatedSection == nil && o.KeyMode == nil {
+		o.MergeRules = MergeRulesFor(v)
+	}
+
+	file, err := o.Load(name)
+	if err != nil {
+		return err
+	}
+	return file.Unmarshal(v)
+}
+
+// MergeRules describe how systemd merges the sections and keys of multiple files.
+// The zero value merges all sections with the same name and keeps all keys.
+type MergeRules struct {
+	// RepeatedSection reports whether a section may occur multiple times, like [Route].
+	// Every repeated section is kept on its own,
+	// all other sections are merged into the first section with the same name.
+	RepeatedSection func(section string) bool
+
+	// KeyMode returns how the assignments of a key are merged.
+	// If nil, all keys are kept.
+	KeyMode func(section, key string) MergeMode
+}
+
+// MergeMode describes how the assignments of a key are merged.
+type MergeMode int
+
+const (
+	// MergeKeepAll keeps all assignments.
+	MergeKeepAll MergeMode = iota
+	// MergeLastWins only keeps the last assignment.
+	MergeLastWins
+	// MergeResetOnEmpty keeps all assignments following the last empty assignment.
+	MergeResetOnEmpty
+)
+
+// MergeRulesFor derives the merge rules from the file struct pointed to by v.
+// Sections stored in slices are repeated, keys follow the assignment
+// semantics of their field, see Unmarshal.
+// Unknown sections and keys are kept as they are.
+func MergeRulesFor(v interface{}) MergeRules {
+	t := reflect.TypeOf(v)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return MergeRules{}
+	}
+
+	repeated := map[string]bool{}
+	modes := map[string]*sectionModes{}
+	fields := cachedFields(t)
+	for _, f := range fields.list {
+		if f.typ.Kind() == reflect.Slice {
+			repeated[f.Name] = true
+		}
+		if st := sectionType(f.typ); st != nil {
+			modes[f.Name] = keyModes(st)
+		}
+	}
+	var mapModes *sectionModes // modes of the sections stored in a map
+	if m := fields.mapField; m != nil && isSectionMap(m.typ) {
+		mapModes = keyModes(sectionType(m.typ))
+	}
+
+	return MergeRules{
+		RepeatedSection: func(section string) bool {
+			return repeated[section]
+		},
+		KeyMode: func(section, key string) MergeMode {
+			m, ok := modes[section]
+			if !ok {
+				m = mapModes
+			}
+			if m == nil {
+				// unknown sections keep all keys
+				return MergeKeepAll
+			}
+			return m.mode(key)
+		},
+	}
+}
+
+// sectionModes holds the merge modes of the keys of a section struct.
+type sectionModes struct {
+	keys map[string]MergeMode
+	// mode of the keys stored in a map, MergeKeepAll for unknown keys
+	other MergeMode
+}
+
+func (m *sectionModes) mode(key string) MergeMode {
+	if mode, ok := m.keys[key]; ok {
+		return mode
+	}
+	return m.other
+}
+
+// keyModes returns the merge mode of every key of the section struct t.
+func keyModes(t reflect.Type) *sectionModes {
+	fields := cachedFields(t)
+	modes := &sectionModes{keys: map[string]MergeMode{}}
+	if m := fields.mapField; m != nil && isKeyMap(m.typ) {
+		// string values take the last assignment, slices are reset by an empty one
+		modes.other = MergeLastWins
+		if m.typ.Elem().Kind() == reflect.Slice {
+			modes.other = MergeResetOnEmpty
+		}
+	}
+	for _, f := range fields.list {
+		ft := f.typ
+		fieldConfig := f.fieldConfig
+		switch fieldConfig.Mode {
+		case assignAppend, assignFirst:
+			modes.keys[fieldConfig.Name] = MergeKeepAll
+			continue
+		case assignLastWins:
+			modes.keys[fieldConfig.Name] = MergeLastWins
+			continue
+		}
+
+		switch {
+		case ft.Implements(valueUnmarshalerType) ||
+			reflect.PtrTo(ft).Implements(valueUnmarshalerType):
+			// resets are up to the implementation
+			modes.keys[fieldConfig.Name] = MergeKeepAll
+		case isScalar(ft),
+			ft.Kind() == reflect.Ptr && isScalar(ft.Elem()):
+			modes.keys[fieldConfig.Name] = MergeLastWins
+		case ft.Kind() == reflect.Slice:
+			modes.keys[fieldConfig.Name] = MergeResetOnEmpty
+		default:
+			modes.keys[fieldConfig.Name] = MergeKeepAll
+		}
+	}
+	return modes
+}
+
+// Merge merges files in the given order, like systemd merges a file and its drop-ins.
+// Keys are merged according to KeyMode, so later assignments of single value keys
+// override earlier ones, list keys are appended and an empty assignment resets the list.
+// The merged file does not keep the original formatting of the input files.
+func (r MergeRules) Merge(files ...*File) *File {
+	merged := &File{}
+	if len(files) > 0 {
+		merged.Name = files[0].Name
+	}
+
+	sections := map[string]int{} // index of merged sections by name
+	for _, file := range files {
+		for _, section := range file.Sections {
+			if r.RepeatedSection != nil && r.RepeatedSection(section.Name) {
+				merged.Sections = append(merged.Sections, copySection(section))
+				continue
+			}
+			i, ok := sections[section.Name]
+			if !ok {
+				sections[section.Name] = len(merged.Sections)
+				merged.Sections = append(merged.Sections, copySection(section))
+				continue
+			}
+			for _, key := range section.Keys {
+				key.src = nil
+				merged.Sections[i].Keys = append(merged.Sections[i].Keys, key)
+			}
+		}
+	}
+
+	for i := range merged.Sections {
+		merged.Sections[i].Keys = r.mergeKeys(merged.Sections[i].Name, merged.Sections[i].Keys)
+	}
+	return merged
+}
+
+// mergeKeys drops all keys overridden or reset by a later key.
+func (r MergeRules) mergeKeys(section string, keys []Key) []Key {
+	if r.KeyMode == nil {
+		return keys
+	}
+
+	keep := make([]bool, len(keys))
+	done := map[string]bool{} // no earlier key with this name applies
+	for i := len(keys) - 1; i >= 0; i-- {
+		key := keys[i]
+		if done[key.Name] {
+			continue
+		}
+		keep[i] = true
+		switch r.KeyMode(section, key.Name) {
+		case MergeLastWins:
+			done[key.Name] = true
+		case MergeResetOnEmpty:
+			done[key.Name] = key.Value == ""
+		}
+	}
+
+	var out []Key
+	for i, key := range keys {
+		if keep[i] {
+			out = append(out, key)
+		}
+	}
+	return out
+}
+
+// copySection returns a copy of section without its source formatting.
+func copySection(section Section) Section {
+	section.src = nil
+	keys := make([]Key, len(section.Keys))
+	for i, key := range section.Keys {
+		key.src = nil
+		keys[i] = key
+	}
+	section.Keys = keys
+	return section
+}
+
+// DropIn returns a drop-in, that turns original into desired when merged according to r.
+// Keys are only assigned if their effective value changes, list keys that keep their
+// values and only gain new ones are extended, all others are reset first.
+// If KeyMode is nil, all keys are treated as lists that are reset by an empty assignment.
+//
+// Drop-ins can only add repeated sections and only extend keys with MergeKeepAll,
+// all other changes to them are reported as *DropInError.
+func (r MergeRules) DropIn(original, desired *File) (*File, error) {
+	var (
+		dropIn        = &File{}
+		inexpressible []Change
+	)
+	keyMode := r.KeyMode
+	if keyMode == nil {
+		keyMode = func(section, key string) MergeMode { return MergeResetOnEmpty }
+	}
+	repeated := func(section string) bool {
+		return r.RepeatedSection != nil && r.RepeatedSection(section)
+	}
+
+	// repeated sections can only be added
+	used := make([]bool, len(original.Sections))
+	for j := range desired.Sections {
+		section := &desired.Sections[j]
+		if !repeated(section.Name) {
+			continue
+		}
+		found := false
+		for i := range original.Sections {
+			if !used[i] && original.Sections[i].Name == section.Name && sameKeys(&original.Sections[i], section) {
+				used[i], found = true, true
+				break
+			}
+		}
+		if !found {
+			dropIn.Sections = append(dropIn.Sections, copySection(*section))
+		}
+	}
+	for i := range original.Sections {
+		if !used[i] && repeated(original.Sections[i].Name) {
+			inexpressible = append(inexpressible, Change{
+				Type: SectionRemoved, Section: original.Sections[i].Name, A: i, B: -1,
+			})
+		}
+	}
+
+	// all other sections are merged
+	rules := MergeRules{RepeatedSection: repeated, KeyMode: keyMode}
+	from, to := rules.Merge(original), rules.Merge(desired)
+	var unique []Section
+	for _, section := range to.Sections {
+		if !repeated(section.Name) {
+			unique = append(unique, section)
+		}
+	}
+	for _, section := range from.Sections {
+		if !repeated(section.Name) && to.Section(section.Name) == nil {
+			// removed sections are reset
+			unique = append(unique, Section{Name: section.Name})
+		}
+	}
+
+	var sections []Section
+	for _, want := range unique {
+		have := from.Section(want.Name)
+		if have == nil {
+			have = &Section{Name: want.Name}
+		}
+		change := Change{
+			Section: want.Name,
+			A:       original.SectionIndex(want.Name),
+			B:       desired.SectionIndex(want.Name),
+		}
+
+		section := Section{Name: want.Name, Comment: want.Comment}
+		for _, name := range keyNames(want.Keys, have.Keys) {
+			keys, err := dropInKeys(keyMode(want.Name, name), have.KeysByName(name), want.KeysByName(name))
+			if err != nil {
+				change.Type, change.Key = KeyChanged, name
+				change.Old = strings.Join(keyValues(have.KeysByName(name)), " ")
+				change.New = strings.Join(keyValues(want.KeysByName(name)), " ")
+				inexpressible = append(inexpressible, change)
+				continue
+			}
+			section.Keys = append(section.Keys, keys...)
+		}
+		if len(section.Keys) != 0 {
+			sections = append(sections, section)
+		}
+	}
+	dropIn.Sections = append(sections, dropIn.Sections...)
+
+	if len(inexpressible) != 0 {
+		return nil, &DropInError{Changes: inexpressible}
+	}
+	return dropIn, nil
+}
+
+// dropInKeys returns the keys turning the assignments have into want.
+// It returns errInexpressible if the change cannot be expressed.
+func dropInKeys(mode MergeMode, have, want []Key) ([]Key, error) {
+	if mode == MergeResetOnEmpty {
+		// a reset in front of values makes no difference
+		have, want = trimReset(have), trimReset(want)
+	}
+	haveValues, wantValues := keyValues(have), keyValues(want)
+	if equalStrings(haveValues, wantValues) {
+		return nil, nil
+	}
+	var name string
+	if len(want) > 0 {
+		name = want[0].Name
+	} else {
+		name = have[0].Name
+	}
+
+	if mode != MergeLastWins && len(wantValues) > len(haveValues) &&
+		equalStrings(haveValues, wantValues[:len(haveValues)]) {
+		// only new values
+		return newKeys(want[len(have):]), nil
+	}
+
+	switch mode {
+	case MergeLastWins:
+		if len(want) == 0 {
+			return []Key{{Name: name}}, nil
+		}
+		return newKeys(want[len(want)-1:]), nil
+
+	case MergeResetOnEmpty:
+		keys := newKeys(want)
+		if len(keys) == 0 || keys[0].Value != "" {
+			keys = append([]Key{{Name: name}}, keys...)
+		}
+		return keys, nil
+	}
+	return nil, errInexpressible
+}
+
+// trimReset removes a leading empty assignment followed by further assignments.
+func trimReset(keys []Key) []Key {
+	if len(keys) > 1 && keys[0].Value == "" {
+		return keys[1:]
+	}
+	return keys
+}
+
+var errInexpressible = errors.New("change cannot be expressed as drop-in")
+
+// A DropInError lists the changes that cannot be expressed by a drop-in.
+type DropInError struct {
+	Changes []Change
+}
+
+func (e *DropInError) Error() string {
+	msgs := make([]string, len(e.Changes))
+	for i, c := range e.Changes {
+		msgs[i] = c.String()
+	}
+	return "systemd: changes cannot be expressed as drop-in: " + strings.Join(msgs, ", ")
+}
+
+// keyNames returns the distinct names of all keys, in order of appearance.
+func keyNames(keyLists ...[]Key) []string {
+	var (
+		names []string
+		seen  = map[string]bool{}
+	)
+	for _, keys := range keyLists {
+		for _, key := range keys {
+			if !seen[key.Name] {
+				seen[key.Name] = true
+				names = append(names, key.Name)
+			}
+		}
+	}
+	return names
+}
+
+// newKeys returns copies of keys without position and original formatting.
+func newKeys(keys []Key) []Key {
+	out := make([]Key, len(keys))
+	for i, key := range keys {
+		out[i] = Key{Name: key.Name, Value: key.Value, Comment: key.Comment}
+	}
+	return out
+}
+
+// sameKeys reports whether a and b contain the same keys in the same order.
+func sameKeys(a, b *Section) bool {
+	if len(a.Keys) != len(b.Keys) {
+		return false
+	}
+	for i := range a.Keys {
+		if a.Keys[i].Name != b.Keys[i].Name || a.Keys[i].Value != b.Keys[i].Value {
+			return false
+		}
+	}
+	return true
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,302 @@
+package encoding
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFiles(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, ioutil.WriteFile(path, []byte(content), 0644))
+	}
+}
+
+func TestDropInOptionsFind(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"run/10-eth0.network":            "",
+		"lib/10-eth0.network":            "",
+		"lib/10-eth0.network.d/a.conf":   "",
+		"lib/10-eth0.network.d/c.conf":   "",
+		"run/10-eth0.network.d/b.conf":   "",
+		"run/10-eth0.network.d/c.conf":   "",
+		"run/10-eth0.network.d/d.ignore": "",
+	})
+	opts := DropInOptions{SearchPath: []string{
+		filepath.Join(root, "etc"),
+		filepath.Join(root, "run"),
+		filepath.Join(root, "lib"),
+	}}
+
+	main, dropIns, err := opts.Find("10-eth0.network")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(root, "run/10-eth0.network"), main)
+	assert.Equal(t, []string{
+		filepath.Join(root, "lib/10-eth0.network.d/a.conf"),
+		filepath.Join(root, "run/10-eth0.network.d/b.conf"),
+		filepath.Join(root, "run/10-eth0.network.d/c.conf"),
+	}, dropIns)
+
+	_, _, err = opts.Find("20-eth1.network")
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestDropInOptionsLoad(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"lib/10-eth0.network": `[Match]
+Name=eth0
+
+[Network]
+Address=10.0.0.2/24
+Gateway=10.0.0.1
+
+[Route]
+Gateway=10.0.0.1
+Destination=10.1.0.0/16
+`,
+		"lib/10-eth0.network.d/10-address.conf": `[Network]
+Address=
+Address=10.0.0.3/24
+`,
+		"etc/10-eth0.network.d/20-name.conf": `[Match]
+Name=eth1
+
+[Route]
+Gateway=10.0.0.1
+Destination=10.2.0.0/16
+`,
+	})
+	opts := DropInOptions{SearchPath: []string{
+		filepath.Join(root, "etc"),
+		filepath.Join(root, "lib"),
+	}}
+
+	t.Run("file", func(t *testing.T) {
+		f, err := opts.Load("10-eth0.network")
+		require.NoError(t, err)
+		assert.Equal(t, filepath.Join(root, "lib/10-eth0.network"), f.Name)
+
+		// without rules all keys are kept
+		require.Len(t, f.Sections, 3)
+		assert.Equal(t, []string{"eth0", "eth1"}, keyValues(f.Sections[0].Keys))
+		assert.Equal(t, "etc/10-eth0.network.d/20-name.conf:2:1",
+			mustRel(t, root, f.Sections[0].Keys[1].Pos.String()))
+		assert.Len(t, f.Sections[2].Keys, 4)
+	})
+
+	t.Run("rules", func(t *testing.T) {
+		opts := opts
+		opts.MergeRules = MergeRulesFor(&testFile{})
+		f, err := opts.Load("10-eth0.network")
+		require.NoError(t, err)
+
+		require.Len(t, f.Sections, 4)
+		assert.Equal(t, "Match", f.Sections[0].Name)
+		assert.Equal(t, []string{"eth1"}, keyValues(f.Sections[0].Keys))
+		assert.Equal(t, "Network", f.Sections[1].Name)
+		assert.Equal(t, []string{"10.0.0.1", "", "10.0.0.3/24"}, keyValues(f.Sections[1].Keys))
+		assert.Equal(t, "Route", f.Sections[2].Name)
+		assert.Equal(t, "Route", f.Sections[3].Name)
+	})
+
+	t.Run("unmarshal", func(t *testing.T) {
+		var f testFile
+		require.NoError(t, opts.LoadInto("10-eth0.network", &f))
+		assert.Equal(t, "eth1", f.Match.Name)
+		assert.Equal(t, []string{"10.0.0.3/24"}, f.Network.Addresses)
+		assert.Equal(t, []string{"10.0.0.1"}, f.Network.Gateways)
+		require.Len(t, f.Routes, 2)
+		assert.Equal(t, "10.2.0.0/16", f.Routes[1].Destination)
+	})
+
+	t.Run("masked", func(t *testing.T) {
+		require.NoError(t, os.MkdirAll(filepath.Join(root, "etc"), 0755))
+		masked := filepath.Join(root, "etc/10-eth0.network")
+		require.NoError(t, os.Symlink(os.DevNull, masked))
+		defer os.Remove(masked)
+
+		_, err := opts.Load("10-eth0.network")
+		assert.True(t, errors.Is(err, ErrMasked))
+		assert.EqualError(t, err, "load "+masked+": file is masked")
+
+		var f testFile
+		assert.True(t, errors.Is(opts.LoadInto("10-eth0.network", &f), ErrMasked))
+		assert.Nil(t, f.Match)
+	})
+}
+
+func TestMergeRules(t *testing.T) {
+	rules := MergeRulesFor(&testFile{})
+	assert.True(t, rules.RepeatedSection("Route"))
+	assert.False(t, rules.RepeatedSection("Match"))
+	assert.Equal(t, MergeLastWins, rules.KeyMode("Match", "Name"))
+	assert.Equal(t, MergeResetOnEmpty, rules.KeyMode("Match", "MACAddress"))
+	assert.Equal(t, MergeLastWins, rules.KeyMode("Route", "Enable"))
+	assert.Equal(t, MergeKeepAll, rules.KeyMode("Route", "Unknown"))
+	assert.Equal(t, MergeKeepAll, rules.KeyMode("Unknown", "Name"))
+
+	modes := MergeRulesFor(&struct {
+		Section assignSection
+	}{})
+	assert.Equal(t, MergeKeepAll, modes.KeyMode("Section", "Append"))
+	assert.Equal(t, MergeResetOnEmpty, modes.KeyMode("Section", "Reset"))
+	assert.Equal(t, MergeLastWins, modes.KeyMode("Section", "LastWins"))
+	assert.Equal(t, MergeKeepAll, modes.KeyMode("Section", "First"))
+
+	merged := rules.Merge(
+		&File{Sections: []Section{
+			{Name: "Match", Keys: []Key{{Name: "Name", Value: "eth0"}, {Name: "MACAddress", Value: "aa"}}},
+		}},
+		&File{Sections: []Section{
+			{Name: "Match", Keys: []Key{{Name: "MACAddress", Value: "bb"}, {Name: "Name", Value: ""}}},
+		}},
+	)
+	assert.Equal(t, &File{Sections: []Section{
+		{Name: "Match", Keys: []Key{
+			{Name: "MACAddress", Value: "aa"},
+			{Name: "MACAddress", Value: "bb"},
+			{Name: "Name", Value: ""},
+		}},
+	}}, merged)
+}
+
+func mustRel(t *testing.T, root, path string) string {
+	t.Helper()
+	rel, err := filepath.Rel(root, path)
+	require.NoError(t, err)
+	return rel
+}
+
+func TestMergeRulesDropIn(t *testing.T) {
+	const original = `[Match]
+Name=eth0
+
+[Network]
+Address=10.0.0.2/24
+Gateway=10.0.0.1
+Gateways=10.0.0.2
+
+[Route]
+Gateway=10.0.0.1
+Destination=10.1.0.0/16
+`
+	tests := []struct {
+		Name    string
+		Desired string
+		DropIn  string
+	}{
+		{
+			Name:    "unchanged",
+			Desired: original,
+			DropIn:  "",
+		},
+		{
+			Name: "changes",
+			Desired: `[Match]
+Name=eth1
+
+[Network]
+Address=10.0.0.2/24
+Address=10.0.0.3/24
+Gateway=10.0.0.254
+
+[Route]
+Gateway=10.0.0.1
+Destination=10.1.0.0/16
+
+[Route]
+Gateway=10.0.0.1
+Destination=10.2.0.0/16
+`,
+			DropIn: `[Match]
+Name=eth1
+
+[Network]
+Address=10.0.0.3/24
+Gateway=
+Gateway=10.0.0.254
+Gateways=
+
+[Route]
+Gateway=10.0.0.1
+Destination=10.2.0.0/16
+`,
+		},
+		{
+			Name: "resets",
+			Desired: `[Network]
+Address=10.0.0.3/24
+
+[Route]
+Gateway=10.0.0.1
+Destination=10.1.0.0/16
+`,
+			DropIn: `[Network]
+Address=
+Address=10.0.0.3/24
+Gateway=
+Gateways=
+
+[Match]
+Name=
+`,
+		},
+	}
+
+	rules := MergeRulesFor(&testFile{})
+	keyMode := rules.KeyMode
+	rules.KeyMode = func(section, key string) MergeMode {
+		if key == "Gateways" {
+			// unknown keys are kept as they are, see "inexpressible"
+			return MergeResetOnEmpty
+		}
+		return keyMode(section, key)
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			from, err := Decode([]byte(original))
+			require.NoError(t, err)
+			to, err := Decode([]byte(test.Desired))
+			require.NoError(t, err)
+
+			dropIn, err := rules.DropIn(from, to)
+			require.NoError(t, err)
+			var buf bytes.Buffer
+			require.NoError(t, Encode(&buf, dropIn))
+			assert.Equal(t, test.DropIn, buf.String())
+
+			// applying the drop-in results in the desired configuration
+			var got, want testFile
+			require.NoError(t, rules.Merge(from, dropIn).Unmarshal(&got))
+			require.NoError(t, to.Unmarshal(&want))
+			if want.Match == nil && assert.NotNil(t, got.Match) {
+				// a drop-in can only reset the keys of removed sections
+				assert.Empty(t, got.Match.Name)
+				got.Match = nil
+			}
+			assert.Equal(t, want, got)
+		})
+	}
+
+	t.Run("inexpressible", func(t *testing.T) {
+		from, err := Decode([]byte(original))
+		require.NoError(t, err)
+		to, err := Decode([]byte("[Match]\nName=eth0\n[Network]\nAddress=10.0.0.2/24\nGateway=10.0.0.1\nGateways=10.0.0.3\n"))
+		require.NoError(t, err)
+
+		_, err = MergeRulesFor(&testFile{}).DropIn(from, to)
+		assert.EqualError(t, err, "systemd: changes cannot be expressed as drop-in: "+
+			"-[Route], [Network] Gateways=10.0.0.2 -> 10.0.0.3")
+	})
+}
@@ -6,65 +6,250 @@ import (
 )
 
 // Encode takes the runtime representsation of a systemd configuration file and writes out a normal systemd file.
+// Sections and keys decoded by DecodeLossless keep their original formatting, unless they have been modified.
+// Comments after the last key of a decoded file are written after the last section,
+// separated by a blank line unless BlankLinesNone is set.
 func Encode(out io.Writer, file *File) error {
-	return writeFile(out, file)
+	return EncodeOptions{}.Encode(out, file)
 }
 
-func writeFile(out io.Writer, file *File) error {
-	for i, section := range file.Sections {
-		if i != 0 {
-			if _, err := out.Write([]byte("\n")); err != nil {
+// BlankLinePolicy controls where blank lines are inserted
+// between freshly written sections and keys.
+type BlankLinePolicy int
+
+const (
+	// BlankLinesDefault separates sections and keys with comments by a blank line.
+	BlankLinesDefault BlankLinePolicy = iota
+	// BlankLinesBetweenSections only separates sections by a blank line.
+	BlankLinesBetweenSections
+	// BlankLinesNone writes no blank lines.
+	BlankLinesNone
+)
+
+// EncodeOptions configures how systemd configuration files are encoded.
+// Options only apply to freshly written sections and keys,
+// unmodified source decoded by DecodeLossless is kept as it is.
+// The zero value uses the default format.
+type EncodeOptions struct {
+	// Indent is written in front of every key and key comment.
+	Indent string
+
+	// BlankLines controls the separation of sections and keys.
+	BlankLines BlankLinePolicy
+
+	// CommentPrefix is written in front of every comment line, defaults to "# ".
+	CommentPrefix string
+
+	// Version is the systemd version targeted when Encoder.Encode marshals structs,
+	// see MarshalForVersion. The zero value targets the latest version.
+	Version int
+}
+
+// Encode writes file to out.
+func (o EncodeOptions) Encode(out io.Writer, file *File) error {
+	e := o.newEncodeState(out)
+	return e.writeFile(file)
+}
+
+func (o EncodeOptions) newEncodeState(out io.Writer) *encodeState {
+	if o.CommentPrefix == "" {
+		o.CommentPrefix = "# "
+	}
+	return &encodeState{out: out, opts: o, newline: "\n"}
+}
+
+// An Encoder writes systemd configuration to an output stream.
+type Encoder struct {
+	e *encodeState
+}
+
+// NewEncoder returns a new encoder that writes to w.
+func NewEncoder(w io.Writer) *Encoder {
+	return EncodeOptions{}.NewEncoder(w)
+}
+
+// NewEncoder returns a new encoder that writes to w using the options of o.
+func (o EncodeOptions) NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{e: o.newEncodeState(w)}
+}
+
+// Encode writes v to the stream.
+// If v is a *File, it is written as is, otherwise v is marshaled as with Marshal.
+// Sections are written as soon as they are marshaled.
+// Successive calls continue the output, as if all sections belonged to one file.
+func (enc *Encoder) Encode(v interface{}) error {
+	if f, ok := v.(*File); ok {
+		return enc.e.writeFile(f)
+	}
+	return marshalSections(v, enc.e.opts.Version, enc.e.writeSection)
+}
+
+// encodeState stores the current state of an encode operation.
+type encodeState struct {
+	out  io.Writer
+	opts EncodeOptions
+	// true if something was written and
+	// the output does not end with a newline
+	openLine bool
+	// number of sections written
+	sections int
+	// line ending of freshly written lines
+	newline string
+}
+
+func (e *encodeState) write(s string) error {
+	if s == "" {
+		return nil
+	}
+	if _, err := io.WriteString(e.out, s); err != nil {
+		return err
+	}
+	e.openLine = !strings.HasSuffix(s, "\n")
+	return nil
+}
+
+// startLine ensures that the next write starts on a new line.
+// Only needed after verbatim source that did not end with a newline.
+func (e *encodeState) startLine() error {
+	if !e.openLine {
+		return nil
+	}
+	return e.write(e.newline)
+}
+
+func (e *encodeState) writeFile(file *File) error {
+	if file.lineEnding != "" {
+		// edits of a lossless file keep its line ending
+		e.newline = file.lineEnding
+		defer func() { e.newline = "\n" }()
+	}
+	for i := range file.Sections {
+		if err := e.writeSection(&file.Sections[i]); err != nil {
+			return err
+		}
+	}
+	if file.trailingComment != "" {
+		if err := e.startLine(); err != nil {
+			return err
+		}
+		if e.sections != 0 && e.opts.BlankLines != BlankLinesNone {
+			if err := e.write(e.newline); err != nil {
 				return err
 			}
 		}
-		if err := writeSection(out, &section); err != nil {
+		if err := e.writeComment("", file.trailingComment); err != nil {
 			return err
 		}
 	}
-	return nil
+	return e.write(file.trailing)
 }
 
-func writeSection(out io.Writer, section *Section) error {
-	if err := writeComment(out, section.Comment); err != nil {
+func (e *encodeState) writeSection(section *Section) error {
+	if err := e.startLine(); err != nil {
 		return err
 	}
-	if _, err := out.Write([]byte("[" + section.Name + "]\n")); err != nil {
-		return err
+	e.sections++
+	if e.sections != 1 && section.src == nil && e.opts.BlankLines != BlankLinesNone {
+		if err := e.write(e.newline); err != nil {
+			return err
+		}
+	}
+
+	if src := section.src; src != nil {
+		if err := e.writeLeading(src.leading, src.comment, section.Comment, ""); err != nil {
+			return err
+		}
+		header := src.header
+		if src.name != section.Name {
+			header = "[" + section.Name + "]" + e.newline
+		}
+		if err := e.write(header); err != nil {
+			return err
+		}
+	} else {
+		if err := e.writeComment("", section.Comment); err != nil {
+			return err
+		}
+		if section.Name != "" {
+			// sections without name hold keys in front of the first section
+			if err := e.write("[" + section.Name + "]" + e.newline); err != nil {
+				return err
+			}
+		}
 	}
-	for i, key := range section.Keys {
-		if i != 0 && key.Comment != "" {
-			if _, err := out.Write([]byte("\n")); err != nil {
+
+	for i := range section.Keys {
+		key := &section.Keys[i]
+		if i != 0 && key.src == nil && key.Comment != "" && e.opts.BlankLines == BlankLinesDefault {
+			if err := e.write(e.newline); err != nil {
 				return err
 			}
 		}
-		if err := writeKey(out, &key); err != nil {
+		if err := e.writeKey(key); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
-func writeKey(out io.Writer, key *Key) error {
-	if err := writeComment(out, key.Comment); err != nil {
+func (e *encodeState) writeKey(key *Key) error {
+	if err := e.startLine(); err != nil {
 		return err
 	}
-	if _, err := out.Write([]byte(key.Name + "=" + key.Value + "\n")); err != nil {
+
+	if src := key.src; src != nil {
+		if err := e.writeLeading(src.leading, src.comment, key.Comment, e.opts.Indent); err != nil {
+			return err
+		}
+		if src.name == key.Name && src.value == key.Value &&
+			(src.comment == key.Comment || !hasCommentLines(src.text)) {
+			return e.write(src.text)
+		}
+		return e.write(e.opts.Indent + key.Name + "=" + key.Value + e.newline)
+	}
+
+	if err := e.writeComment(e.opts.Indent, key.Comment); err != nil {
 		return err
 	}
-	return nil
+	return e.write(e.opts.Indent + key.Name + "=" + key.Value + e.newline)
 }
 
-func writeComment(out io.Writer, comment string) error {
+// writeLeading writes the comment and blank lines in front of a section or key.
+// If the comment was modified, blank lines are kept and the comment is written anew.
+func (e *encodeState) writeLeading(leading, oldComment, comment, indent string) error {
+	if oldComment == comment {
+		return e.write(leading)
+	}
+
+	for _, line := range strings.SplitAfter(leading, "\n") {
+		if strings.TrimSpace(line) != "" {
+			continue
+		}
+		if err := e.write(line); err != nil {
+			return err
+		}
+	}
+	return e.writeComment(indent, comment)
+}
+
+func (e *encodeState) writeComment(indent, comment string) error {
 	if comment == "" {
 		return nil
 	}
 	commentBlock := ""
 	for _, line := range strings.Split(comment, "\n") {
-		commentBlock += "# " + line + "\n"
+		commentBlock += indent + e.opts.CommentPrefix + line + e.newline
 	}
-	_, err := out.Write([]byte(commentBlock))
-	if err != nil {
-		return err
+	return e.write(commentBlock)
+}
+
+// hasCommentLines reports whether the given source contains comment lines.
+func hasCommentLines(src string) bool {
+	for _, line := range strings.Split(src, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			return true
+		}
 	}
-	return nil
+	return false
 }
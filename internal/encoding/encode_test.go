@@ -2,6 +2,7 @@ package encoding
 
 import (
 	"bytes"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -161,3 +162,288 @@ Environment=ETCD_KEY_FILE=/path/to/server.key
 		})
 	}
 }
+
+func TestEncodeLossless(t *testing.T) {
+	const handWritten = `# network comment
+[Network]
+# start desc
+Description= test1 \
+	# in the middle
+	test2 \
+	test3
+  # address 1
+Address = 10.1.10.9/24  
+Address=
+
+
+Gateway=10.1.10.1
+# address 2
+	; something else
+Address=10.1.10.11/24
+
+# route
+[Route]
+Gateway=192.168.0.11
+Destination=10.0.0.0/8
+# trailing comment
+
+`
+
+	t.Run("round trip", func(t *testing.T) {
+		inputs := []string{
+			handWritten,
+			"[Route]\nGateway=192.168.0.11\nDestination=10.0.0.0/8",
+			"\n\n; only a comment\n",
+			"[Service]\r\nExecStart=/bin/true\r\n",
+		}
+		for _, input := range inputs {
+			f, err := DecodeLossless([]byte(input))
+			require.NoError(t, err)
+
+			var buf bytes.Buffer
+			require.NoError(t, Encode(&buf, f))
+			assert.Equal(t, input, buf.String())
+		}
+	})
+
+	tests := []struct {
+		Name string
+		Edit func(f *File)
+		Out  string
+	}{
+		{
+			Name: "change value",
+			Edit: func(f *File) {
+				f.Sections[0].Keys[3].Value = "10.1.10.254"
+			},
+			Out: strings.Replace(handWritten,
+				"Gateway=10.1.10.1\n", "Gateway=10.1.10.254\n", 1),
+		},
+		{
+			Name: "change comment",
+			Edit: func(f *File) {
+				f.Sections[0].Keys[1].Comment = "first address"
+			},
+			Out: strings.Replace(handWritten,
+				"  # address 1\n", "# first address\n", 1),
+		},
+		{
+			Name: "remove key",
+			Edit: func(f *File) {
+				f.Sections[0].Keys = append(f.Sections[0].Keys[:2], f.Sections[0].Keys[3:]...)
+			},
+			Out: strings.Replace(handWritten,
+				"Address=\n", "", 1),
+		},
+		{
+			Name: "add key and section",
+			Edit: func(f *File) {
+				f.Sections[1].Keys = append(f.Sections[1].Keys, Key{Name: "Metric", Value: "100"})
+				f.Sections = append(f.Sections, Section{
+					Name: "DHCPv4",
+					Keys: []Key{{Name: "UseDNS", Value: "no"}},
+				})
+			},
+			Out: strings.Replace(handWritten,
+				"Destination=10.0.0.0/8\n", "Destination=10.0.0.0/8\nMetric=100\n\n[DHCPv4]\nUseDNS=no\n", 1),
+		},
+		{
+			Name: "rename section",
+			Edit: func(f *File) {
+				f.Sections[1].Name = "NextHop"
+			},
+			Out: strings.Replace(handWritten, "[Route]", "[NextHop]", 1),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			f, err := DecodeLossless([]byte(handWritten))
+			require.NoError(t, err)
+			test.Edit(f)
+
+			var buf bytes.Buffer
+			require.NoError(t, Encode(&buf, f))
+			assert.Equal(t, test.Out, buf.String())
+		})
+	}
+
+	t.Run("missing final newline", func(t *testing.T) {
+		f, err := DecodeLossless([]byte("[Route]\nGateway=192.168.0.11"))
+		require.NoError(t, err)
+		f.Sections[0].Keys = append(f.Sections[0].Keys, Key{Name: "Metric", Value: "100"})
+
+		var buf bytes.Buffer
+		require.NoError(t, Encode(&buf, f))
+		assert.Equal(t, "[Route]\nGateway=192.168.0.11\nMetric=100\n", buf.String())
+	})
+
+	t.Run("CRLF", func(t *testing.T) {
+		f, err := DecodeLossless([]byte("[Route]\r\nGateway=192.168.0.11\r\nMetric=10\r\n"))
+		require.NoError(t, err)
+		f.Sections[0].Keys[1].Value = "100"
+		f.Sections[0].Keys[1].Comment = "lower priority"
+		f.Sections = append(f.Sections, Section{
+			Name: "DHCPv4",
+			Keys: []Key{{Name: "UseDNS", Value: "no"}},
+		})
+
+		var buf bytes.Buffer
+		require.NoError(t, Encode(&buf, f))
+		assert.Equal(t, "[Route]\r\nGateway=192.168.0.11\r\n# lower priority\r\nMetric=100\r\n\r\n[DHCPv4]\r\nUseDNS=no\r\n", buf.String())
+	})
+}
+
+func TestEncodeOptions(t *testing.T) {
+	file := &File{
+		Sections: []Section{
+			{
+				Name: "Network",
+				Keys: []Key{
+					{Name: "Address", Value: "10.1.10.9/24"},
+					{Name: "Gateway", Value: "10.1.10.1", Comment: "default route"},
+				},
+			},
+			{
+				Name:    "Route",
+				Comment: "extra route",
+				Keys:    []Key{{Name: "Gateway", Value: "192.168.0.11"}},
+			},
+		},
+	}
+
+	tests := []struct {
+		Name string
+		Opts EncodeOptions
+		Out  string
+	}{
+		{
+			Name: "default",
+			Out:  "[Network]\nAddress=10.1.10.9/24\n\n# default route\nGateway=10.1.10.1\n\n# extra route\n[Route]\nGateway=192.168.0.11\n",
+		},
+		{
+			Name: "indent and prefix",
+			Opts: EncodeOptions{Indent: "  ", CommentPrefix: ";"},
+			Out:  "[Network]\n  Address=10.1.10.9/24\n\n  ;default route\n  Gateway=10.1.10.1\n\n;extra route\n[Route]\n  Gateway=192.168.0.11\n",
+		},
+		{
+			Name: "blank lines between sections",
+			Opts: EncodeOptions{BlankLines: BlankLinesBetweenSections},
+			Out:  "[Network]\nAddress=10.1.10.9/24\n# default route\nGateway=10.1.10.1\n\n# extra route\n[Route]\nGateway=192.168.0.11\n",
+		},
+		{
+			Name: "no blank lines",
+			Opts: EncodeOptions{BlankLines: BlankLinesNone},
+			Out:  "[Network]\nAddress=10.1.10.9/24\n# default route\nGateway=10.1.10.1\n# extra route\n[Route]\nGateway=192.168.0.11\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			var buf bytes.Buffer
+			require.NoError(t, test.Opts.Encode(&buf, file))
+			assert.Equal(t, test.Out, buf.String())
+		})
+	}
+
+	t.Run("lossless source is kept", func(t *testing.T) {
+		const input = "[Network]\n# old\nAddress=10.1.10.9/24\n"
+		f, err := DecodeLossless([]byte(input))
+		require.NoError(t, err)
+		f.Sections[0].Keys = append(f.Sections[0].Keys, Key{Name: "DNS", Value: "1.1.1.1"})
+
+		var buf bytes.Buffer
+		require.NoError(t, EncodeOptions{Indent: "\t"}.Encode(&buf, f))
+		assert.Equal(t, input+"\tDNS=1.1.1.1\n", buf.String())
+	})
+}
+
+func TestEncodeTrailingComment(t *testing.T) {
+	tests := []struct {
+		Name  string
+		Input string
+		Opts  EncodeOptions
+		Out   string
+	}{
+		{
+			Name:  "after last key",
+			Input: "[Network]\nDNS=1.1.1.1\n; end of file\n",
+			Out:   "[Network]\nDNS=1.1.1.1\n\n# end of file\n",
+		},
+		{
+			Name:  "multiple lines",
+			Input: "[Network]\nDNS=1.1.1.1\n# end\n\n# of file",
+			Out:   "[Network]\nDNS=1.1.1.1\n\n# end\n# of file\n",
+		},
+		{
+			Name:  "no blank lines",
+			Input: "[Network]\nDNS=1.1.1.1\n# end of file\n",
+			Opts:  EncodeOptions{BlankLines: BlankLinesNone, CommentPrefix: ";"},
+			Out:   "[Network]\nDNS=1.1.1.1\n;end of file\n",
+		},
+		{
+			Name:  "only comments",
+			Input: "# nothing\n# configured\n",
+			Out:   "# nothing\n# configured\n",
+		},
+		{
+			Name:  "empty section",
+			Input: "[Network]\n# end of file\n",
+			Out:   "[Network]\n\n# end of file\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			f, err := Decode([]byte(test.Input))
+			require.NoError(t, err)
+
+			var buf bytes.Buffer
+			require.NoError(t, test.Opts.Encode(&buf, f))
+			assert.Equal(t, test.Out, buf.String())
+
+			// the output is stable
+			f, err = Decode(buf.Bytes())
+			require.NoError(t, err)
+			var again bytes.Buffer
+			require.NoError(t, test.Opts.Encode(&again, f))
+			assert.Equal(t, buf.String(), again.String())
+		})
+	}
+
+	t.Run("lossless", func(t *testing.T) {
+		const input = "[Network]\nDNS=1.1.1.1\n; end of file\n"
+		f, err := DecodeLossless([]byte(input))
+		require.NoError(t, err)
+
+		var buf bytes.Buffer
+		require.NoError(t, Encode(&buf, f))
+		assert.Equal(t, input, buf.String(), "written once, as it is")
+	})
+}
+
+func TestEncoder(t *testing.T) {
+	var buf bytes.Buffer
+	enc := NewEncoder(&buf)
+	require.NoError(t, enc.Encode(&testFile{
+		Match:  &matchSection{Name: "eth0"},
+		Routes: []routeSection{{Gateway: "10.0.0.1"}},
+	}))
+	require.NoError(t, enc.Encode(&File{
+		Sections: []Section{{Name: "Route", Keys: []Key{{Name: "Gateway", Value: "10.0.0.2"}}}},
+	}))
+	assert.Equal(t, `[Match]
+Name=eth0
+
+[Network]
+
+[Route]
+Gateway=10.0.0.1
+Disable=
+
+[Route]
+Gateway=10.0.0.2
+`, buf.String())
+
+	assert.Error(t, enc.Encode(testFile{}))
+}
@@ -0,0 +1,242 @@
+package encoding
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"routerd.net/go-systemd/internal/parser"
+)
+
+// Equal reports whether a and b hold the same configuration.
+// Each of them is either a *File or a pointer to a file struct, which is marshaled first.
+// The comparison follows the EqualOptions derived from the file struct, see EqualOptionsFor.
+// Values that cannot be marshaled are not equal to anything.
+func Equal(a, b interface{}) bool {
+	fa, err := toFile(a)
+	if err != nil {
+		return false
+	}
+	fb, err := toFile(b)
+	if err != nil {
+		return false
+	}
+	o := EqualOptionsFor(a)
+	if _, ok := a.(*File); ok {
+		o = EqualOptionsFor(b)
+	}
+	return o.Equal(fa, fb)
+}
+
+// Fingerprint returns a hash of the configuration in v, which is either a *File
+// or a pointer to a file struct. Values that are Equal have the same fingerprint,
+// which is stable across runs and processes.
+func Fingerprint(v interface{}) (string, error) {
+	f, err := toFile(v)
+	if err != nil {
+		return "", err
+	}
+	return EqualOptionsFor(v).Fingerprint(f), nil
+}
+
+// Fingerprint returns a hash of the configuration in f, see Fingerprint.
+// Without type information, every section is compared on its own and all keys are kept.
+func (f *File) Fingerprint() string {
+	return EqualOptions{}.Fingerprint(f)
+}
+
+// EqualOptions configure how files are compared by Equal and Fingerprint.
+//
+// Comments, positions and the original formatting are ignored,
+// as well as the order of sections and the order of keys with different names.
+// Repeated keys are combined according to MergeRules first,
+// so overridden and reset assignments do not count.
+type EqualOptions struct {
+	// MergeRules combine the sections with the same name and the assignments of a key.
+	// If RepeatedSection is nil, every section is compared on its own.
+	MergeRules
+
+	// Unordered reports whether the values of a key form a set,
+	// like the whitespace separated lists of [Match] sections.
+	// Its assignments are combined into a single sorted list.
+	// If nil, the order of all values is significant.
+	Unordered func(section, key string) bool
+}
+
+// EqualOptionsFor derives the options from the file struct pointed to by v,
+// see MergeRulesFor. Keys tagged with the unordered option are compared as sets.
+// For a *File, the zero EqualOptions are returned.
+func EqualOptionsFor(v interface{}) EqualOptions {
+	if _, ok := v.(*File); ok {
+		return EqualOptions{}
+	}
+	t := reflect.TypeOf(v)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return EqualOptions{}
+	}
+
+	unordered := map[string]map[string]bool{}
+	fields := cachedFields(t)
+	for _, f := range fields.list {
+		if st := sectionType(f.typ); st != nil {
+			unordered[f.Name] = unorderedKeys(st)
+		}
+	}
+	var mapKeys map[string]bool // unordered keys of the sections stored in a map
+	if m := fields.mapField; m != nil && isSectionMap(m.typ) {
+		mapKeys = unorderedKeys(sectionType(m.typ))
+	}
+
+	return EqualOptions{
+		MergeRules: MergeRulesFor(v),
+		Unordered: func(section, key string) bool {
+			keys, ok := unordered[section]
+			if !ok {
+				keys = mapKeys
+			}
+			return keys[key]
+		},
+	}
+}
+
+// unorderedKeys returns the names of the keys of the section struct t tagged as unordered.
+func unorderedKeys(t reflect.Type) map[string]bool {
+	keys := map[string]bool{}
+	for _, f := range cachedFields(t).list {
+		if f.Unordered {
+			keys[f.Name] = true
+		}
+	}
+	return keys
+}
+
+// Equal reports whether a and b hold the same configuration.
+func (o EqualOptions) Equal(a, b *File) bool {
+	return equalStrings(o.canonical(a), o.canonical(b))
+}
+
+// Fingerprint returns a hash of the configuration in f.
+// Files that are Equal have the same fingerprint.
+func (o EqualOptions) Fingerprint(f *File) string {
+	h := sha256.New()
+	for _, section := range o.canonical(f) {
+		h.Write([]byte(section))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// canonical returns the sections of f in a canonical text form, sorted.
+func (o EqualOptions) canonical(f *File) []string {
+	rules := o.MergeRules
+	if rules.RepeatedSection == nil {
+		rules.RepeatedSection = func(string) bool { return true }
+	}
+	merged := rules.Merge(f)
+
+	out := make([]string, len(merged.Sections))
+	for i, section := range merged.Sections {
+		var b strings.Builder
+		b.WriteString("[" + section.Name + "]\n")
+		for _, key := range o.canonicalKeys(section.Name, section.Keys) {
+			b.WriteString(key.Name + "=" + strconv.Quote(key.Value) + "\n")
+		}
+		out[i] = b.String()
+	}
+	sort.Strings(out)
+	return out
+}
+
+// canonicalKeys returns the effective keys sorted by name,
+// keeping the order of the assignments of a key.
+func (o EqualOptions) canonicalKeys(section string, keys []Key) []Key {
+	var out []Key
+	for _, name := range keyNames(keys) {
+		var values []string
+		for _, key := range keys {
+			if key.Name == name {
+				values = append(values, key.Value)
+			}
+		}
+		if o.KeyMode != nil && o.KeyMode(section, name) == MergeResetOnEmpty &&
+			len(values) > 0 && values[0] == "" {
+			// a leading reset has no effect on its own
+			values = values[1:]
+		}
+		if o.Unordered != nil && o.Unordered(section, name) {
+			values = unorderedValues(values)
+		}
+		for _, value := range values {
+			out = append(out, Key{Name: name, Value: value})
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		return out[i].Name < out[j].Name
+	})
+	return out
+}
+
+// unorderedValues sorts the words of every value and merges them into a single value.
+// Values inverted by a leading "!" keep the prefix and are not merged.
+func unorderedValues(values []string) []string {
+	var (
+		out    []string
+		merged []string
+	)
+	for _, value := range values {
+		inverted := strings.HasPrefix(value, "!")
+		words := splitWords(strings.TrimPrefix(value, "!"))
+		if !inverted {
+			merged = append(merged, words...)
+			continue
+		}
+		sort.Strings(words)
+		out = append(out, "!"+parser.JoinWords(dedupe(words)))
+	}
+	if len(merged) > 0 {
+		sort.Strings(merged)
+		out = append(out, parser.JoinWords(dedupe(merged)))
+	}
+	return out
+}
+
+// splitWords splits a whitespace separated list, respecting quotes where possible.
+func splitWords(s string) []string {
+	words, err := parser.SplitWords(s)
+	if err != nil {
+		return strings.Fields(s)
+	}
+	return words
+}
+
+// dedupe removes consecutive duplicates from the sorted words.
+func dedupe(words []string) []string {
+	var out []string
+	for i, w := range words {
+		if i == 0 || w != words[i-1] {
+			out = append(out, w)
+		}
+	}
+	return out
+}
+
+// toFile returns v if it is a *File, or the file struct pointed to by v marshaled into a File.
+func toFile(v interface{}) (*File, error) {
+	if f, ok := v.(*File); ok {
+		return f, nil
+	}
+	f := &File{}
+	err := marshalSections(v, 0, func(section *Section) error {
+		f.Sections = append(f.Sections, *section)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return f, nil
+}
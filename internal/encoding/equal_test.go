@@ -0,0 +1,240 @@
+package encoding
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type equalFile struct {
+	Match   *equalMatchSection
+	Network *equalNetworkSection
+	Routes  []equalRouteSection `systemd:"Route"`
+}
+
+type equalMatchSection struct {
+	KeyList
+	Comment string
+	KeyComments
+
+	Names   []string `systemd:"Name,omitempty,wslist,unordered"`
+	Drivers []string `systemd:"Driver,omitempty,wslist"`
+}
+
+type equalNetworkSection struct {
+	KeyList
+	Comment string
+	KeyComments
+
+	Description string   `systemd:",omitempty"`
+	DHCP        *bool    `systemd:",omitempty"`
+	DNS         []string `systemd:",omitempty"`
+}
+
+type equalRouteSection struct {
+	Destination string `systemd:",omitempty"`
+	Gateway     string `systemd:",omitempty"`
+}
+
+const equalBase = `[Match]
+Name=eth0 eth1
+
+[Network]
+DHCP=yes
+DNS=1.1.1.1
+DNS=8.8.8.8
+
+[Route]
+Destination=10.1.0.0/16
+Gateway=10.0.0.1
+
+[Route]
+Destination=10.2.0.0/16
+Gateway=10.0.0.1
+`
+
+func TestEqual(t *testing.T) {
+	tests := []struct {
+		Name  string
+		Other string
+		Equal bool
+	}{
+		{Name: "same", Other: equalBase, Equal: true},
+		{
+			Name: "comments, formatting and section order",
+			Other: `# routes first
+[Route]
+Gateway = 10.0.0.1
+Destination = 10.2.0.0/16
+
+[Route]
+Destination=10.1.0.0/16
+Gateway=10.0.0.1
+
+[Network]
+# primary
+DNS=1.1.1.1
+DHCP=yes
+DNS=8.8.8.8
+
+[Match]
+Name=eth0 eth1
+`,
+			Equal: true,
+		},
+		{
+			Name: "unordered list",
+			Other: `[Match]
+Name=eth1
+Name=eth0 eth1
+
+[Network]
+DHCP=yes
+DNS=1.1.1.1
+DNS=8.8.8.8
+
+[Route]
+Destination=10.1.0.0/16
+Gateway=10.0.0.1
+
+[Route]
+Destination=10.2.0.0/16
+Gateway=10.0.0.1
+`,
+			Equal: true,
+		},
+		{
+			Name: "overridden and reset keys",
+			Other: `[Match]
+Name=eth0 eth1
+
+[Network]
+DHCP=no
+DNS=9.9.9.9
+DNS=
+
+[Network]
+DHCP=yes
+DNS=1.1.1.1
+DNS=8.8.8.8
+
+[Route]
+Destination=10.1.0.0/16
+Gateway=10.0.0.1
+
+[Route]
+Destination=10.2.0.0/16
+Gateway=10.0.0.1
+`,
+			Equal: true,
+		},
+		{
+			Name: "list order",
+			Other: `[Match]
+Name=eth0 eth1
+
+[Network]
+DHCP=yes
+DNS=8.8.8.8
+DNS=1.1.1.1
+
+[Route]
+Destination=10.1.0.0/16
+Gateway=10.0.0.1
+
+[Route]
+Destination=10.2.0.0/16
+Gateway=10.0.0.1
+`,
+		},
+		{
+			Name: "merged repeated sections",
+			Other: `[Match]
+Name=eth0 eth1
+
+[Network]
+DHCP=yes
+DNS=1.1.1.1
+DNS=8.8.8.8
+
+[Route]
+Destination=10.1.0.0/16
+Gateway=10.0.0.1
+Destination=10.2.0.0/16
+`,
+		},
+		{
+			Name: "inverted list",
+			Other: `[Match]
+Name=!eth0 eth1
+
+[Network]
+DHCP=yes
+DNS=1.1.1.1
+DNS=8.8.8.8
+
+[Route]
+Destination=10.1.0.0/16
+Gateway=10.0.0.1
+
+[Route]
+Destination=10.2.0.0/16
+Gateway=10.0.0.1
+`,
+		},
+	}
+
+	base := mustDecode(t, equalBase)
+	o := EqualOptionsFor(&equalFile{})
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			other := mustDecode(t, test.Other)
+			assert.Equal(t, test.Equal, o.Equal(base, other))
+			assert.Equal(t, test.Equal, o.Fingerprint(base) == o.Fingerprint(other))
+		})
+	}
+}
+
+func TestEqualTyped(t *testing.T) {
+	base := mustDecode(t, equalBase)
+	typed := &equalFile{}
+	require.NoError(t, base.Unmarshal(typed))
+
+	assert.True(t, Equal(base, typed))
+	assert.True(t, Equal(typed, base))
+
+	other := &equalFile{}
+	require.NoError(t, base.Unmarshal(other))
+	other.Match.Names = []string{"eth1", "eth0"}
+	other.Network.Comment = "changed"
+	other.Routes[0], other.Routes[1] = other.Routes[1], other.Routes[0]
+	assert.True(t, Equal(typed, other))
+
+	fp, err := Fingerprint(typed)
+	require.NoError(t, err)
+	otherFP, err := Fingerprint(other)
+	require.NoError(t, err)
+	assert.Equal(t, fp, otherFP)
+
+	other.Network.DHCP = BoolPtr(false)
+	assert.False(t, Equal(typed, other))
+	otherFP, err = Fingerprint(other)
+	require.NoError(t, err)
+	assert.NotEqual(t, fp, otherFP)
+
+	assert.False(t, Equal(typed, equalFile{}))
+	_, err = Fingerprint(equalFile{})
+	assert.Error(t, err)
+}
+
+func TestFileFingerprint(t *testing.T) {
+	a := mustDecode(t, "[A]\nX=1\nY=2\n\n[B]\nZ=3\n")
+	b := mustDecode(t, "[B]\n# comment\nZ=3\n\n[A]\nY=2\nX=1\n")
+	assert.Equal(t, a.Fingerprint(), b.Fingerprint())
+	assert.Len(t, a.Fingerprint(), 64)
+	// without type information, sections are not merged and all keys count
+	assert.NotEqual(t, a.Fingerprint(), mustDecode(t, "[A]\nX=1\n\n[A]\nY=2\n\n[B]\nZ=3\n").Fingerprint())
+	assert.NotEqual(t, a.Fingerprint(), mustDecode(t, "[A]\nX=0\nX=1\nY=2\n\n[B]\nZ=3\n").Fingerprint())
+	assert.True(t, Equal(a, b))
+}
@@ -0,0 +1,274 @@
+package encoding
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// structFields describes the fields of a file or section struct.
+type structFields struct {
+	// list holds the fields storing sections or keys in declaration order.
+	// Fields of embedded structs are flattened like encoding/json does:
+	// they are promoted unless a shallower field or a tagged field
+	// at the same depth has the same name.
+	list []structField
+
+	// index sequences of the special fields, nil if there is none
+	comment     []int // section comment
+	sectionList []int // embedded SectionList
+	keyList     []int // embedded KeyList
+	keyComments []int // embedded KeyComments
+
+	// mapField stores the sections or keys without a field of their own,
+	// see isSectionMap and isKeyMap. It is nil if there is none.
+	mapField *structField
+}
+
+// structField describes a struct field storing a section or key.
+type structField struct {
+	fieldConfig
+	index  []int // index sequence for fieldByIndex
+	typ    reflect.Type
+	tagged bool // the name is set by the tag
+}
+
+var fieldCache sync.Map // map[reflect.Type]*structFields
+
+// cachedFields returns the fields of the struct type t,
+// computing them only once per type.
+func cachedFields(t reflect.Type) *structFields {
+	if f, ok := fieldCache.Load(t); ok {
+		return f.(*structFields)
+	}
+	f, _ := fieldCache.LoadOrStore(t, typeFields(t))
+	return f.(*structFields)
+}
+
+var (
+	sectionListType = reflect.TypeOf(SectionList(nil))
+	keyListType     = reflect.TypeOf(KeyList(nil))
+	keyCommentsType = reflect.TypeOf(KeyComments{})
+)
+
+// typeFields computes the fields of the struct type t.
+func typeFields(t reflect.Type) *structFields {
+	sf := &structFields{}
+	var candidates []structField
+	sf.collect(t, nil, map[reflect.Type]bool{}, &candidates)
+
+	// group the candidates by name, keeping the dominant one of every group
+	byName := map[string][]structField{}
+	var names []string
+	for _, f := range candidates {
+		if _, ok := byName[f.Name]; !ok {
+			names = append(names, f.Name)
+		}
+		byName[f.Name] = append(byName[f.Name], f)
+	}
+	for _, name := range names {
+		if f, ok := dominantField(byName[name]); ok {
+			sf.list = append(sf.list, f)
+		}
+	}
+	sort.Slice(sf.list, func(i, j int) bool {
+		return lessIndex(sf.list[i].index, sf.list[j].index)
+	})
+	return sf
+}
+
+// collect appends the fields of the struct type t to candidates,
+// descending into embedded structs. Special fields are recorded in sf,
+// the shallowest one wins.
+func (sf *structFields) collect(t reflect.Type, index []int, visited map[reflect.Type]bool, candidates *[]structField) {
+	if visited[t] {
+		return
+	}
+	visited[t] = true
+	defer delete(visited, t)
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		fieldIndex := append(append([]int(nil), index...), i)
+
+		switch {
+		case field.Anonymous && field.Type == sectionListType:
+			setSpecial(&sf.sectionList, fieldIndex)
+			continue
+		case field.Anonymous && field.Type == keyListType:
+			setSpecial(&sf.keyList, fieldIndex)
+			continue
+		case field.Anonymous && field.Type == keyCommentsType:
+			setSpecial(&sf.keyComments, fieldIndex)
+			continue
+		case field.Name == "Comment" && field.Type.Kind() == reflect.String:
+			setSpecial(&sf.comment, fieldIndex)
+			continue
+		}
+
+		tag := field.Tag.Get(fieldTagName)
+		if tag == "-" {
+			continue
+		}
+		ft := field.Type
+		if ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		if field.Anonymous {
+			if field.PkgPath != "" && (ft.Kind() != reflect.Struct || field.Type.Kind() == reflect.Ptr) {
+				// unexported embedded non-struct or pointer, which cannot be set
+				continue
+			}
+			if tagName(tag) == "" && ft.Kind() == reflect.Struct {
+				sf.collect(ft, fieldIndex, visited, candidates)
+				continue
+			}
+		} else if field.PkgPath != "" {
+			// unexported
+			continue
+		}
+
+		f := structField{
+			fieldConfig: configForField(field),
+			index:       fieldIndex,
+			typ:         field.Type,
+			tagged:      tagName(tag) != "",
+		}
+		if (isSectionMap(field.Type) || isKeyMap(field.Type)) && !hasValueCodec(field.Type) {
+			if sf.mapField == nil || len(fieldIndex) < len(sf.mapField.index) {
+				sf.mapField = &f
+			}
+			continue
+		}
+		*candidates = append(*candidates, f)
+	}
+}
+
+// isSectionMap reports whether t is a map of section structs by section name,
+// like map[string]T or map[string]*T.
+func isSectionMap(t reflect.Type) bool {
+	return t.Kind() == reflect.Map && t.Key().Kind() == reflect.String &&
+		(t.Elem().Kind() == reflect.Struct ||
+			t.Elem().Kind() == reflect.Ptr && t.Elem().Elem().Kind() == reflect.Struct)
+}
+
+// isKeyMap reports whether t is a map of key values by key name,
+// map[string]string or map[string][]string.
+func isKeyMap(t reflect.Type) bool {
+	return t.Kind() == reflect.Map && t.Key().Kind() == reflect.String &&
+		(t.Elem().Kind() == reflect.String ||
+			t.Elem().Kind() == reflect.Slice && t.Elem().Elem().Kind() == reflect.String)
+}
+
+// hasValueCodec reports whether t converts its own values, see ValueMarshaler.
+func hasValueCodec(t reflect.Type) bool {
+	ptr := reflect.PtrTo(t)
+	return t.Implements(valueMarshalerType) || ptr.Implements(valueMarshalerType) ||
+		t.Implements(valueUnmarshalerType) || ptr.Implements(valueUnmarshalerType)
+}
+
+// sectionType returns the section struct type of a field of a file struct, or nil.
+func sectionType(t reflect.Type) reflect.Type {
+	if t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Map {
+		t = t.Elem()
+	}
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+	return t
+}
+
+// setSpecial records the index of a special field, unless a shallower one is known.
+func setSpecial(special *[]int, index []int) {
+	if *special == nil || len(index) < len(*special) {
+		*special = index
+	}
+}
+
+// dominantField returns the field hiding all others with the same name:
+// the shallowest one, or the only tagged one among the shallowest.
+// Ambiguous names are dropped.
+func dominantField(fields []structField) (structField, bool) {
+	depth := len(fields[0].index)
+	for _, f := range fields[1:] {
+		if len(f.index) < depth {
+			depth = len(f.index)
+		}
+	}
+	var shallowest []structField
+	for _, f := range fields {
+		if len(f.index) == depth {
+			shallowest = append(shallowest, f)
+		}
+	}
+	if len(shallowest) == 1 {
+		return shallowest[0], true
+	}
+	var tagged []structField
+	for _, f := range shallowest {
+		if f.tagged {
+			tagged = append(tagged, f)
+		}
+	}
+	if len(tagged) == 1 {
+		return tagged[0], true
+	}
+	return structField{}, false
+}
+
+func lessIndex(a, b []int) bool {
+	for i := range a {
+		if i >= len(b) {
+			return false
+		}
+		if a[i] != b[i] {
+			return a[i] < b[i]
+		}
+	}
+	return len(a) < len(b)
+}
+
+// tagName returns the name part of a systemd tag.
+func tagName(tag string) string {
+	if i := strings.IndexByte(tag, ','); i >= 0 {
+		return tag[:i]
+	}
+	return tag
+}
+
+// fieldByIndex returns the nested field of the struct v.
+// It reports false if index is nil or an embedded struct pointer on the way is nil.
+func fieldByIndex(v reflect.Value, index []int) (reflect.Value, bool) {
+	if index == nil {
+		return reflect.Value{}, false
+	}
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return reflect.Value{}, false
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, true
+}
+
+// fieldByIndexAlloc returns the nested field of the struct v,
+// allocating nil embedded struct pointers on the way.
+func fieldByIndexAlloc(v reflect.Value, index []int) reflect.Value {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v
+}
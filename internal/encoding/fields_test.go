@@ -0,0 +1,163 @@
+package encoding
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// QdiscParent is a reusable block of keys shared by queueing discipline sections.
+type QdiscParent struct {
+	Parent string `systemd:",omitempty"`
+	Handle string `systemd:",omitempty"`
+}
+
+type matchPredicates struct {
+	Name   []string `systemd:",omitempty,wslist"`
+	Driver string   `systemd:",omitempty"`
+}
+
+type embeddedFile struct {
+	SectionList
+
+	Match  *embeddedMatchSection
+	Queues []embeddedQueueSection `systemd:"FairQueueing"`
+	*SharedSections
+}
+
+// SharedSections holds sections shared by several file structs.
+type SharedSections struct {
+	Link *embeddedLinkSection
+}
+
+type embeddedMatchSection struct {
+	KeyList
+	Comment string
+	KeyComments
+
+	matchPredicates
+	Host string `systemd:",omitempty"`
+}
+
+type embeddedQueueSection struct {
+	QdiscParent
+	PacketLimit int    `systemd:",omitempty"`
+	Handle      string `systemd:"Id,omitempty"` // Handle of QdiscParent keeps its name
+	Internal    string `systemd:"-"`
+	unexported  string
+}
+
+type embeddedLinkSection struct {
+	*QdiscParent
+	MTUBytes string `systemd:",omitempty"`
+}
+
+const embeddedInput = `[Match]
+# match comment
+Name=eth0 eth1
+Driver=e1000
+Host=router
+
+[FairQueueing]
+Parent=root
+Handle=8001
+Id=1
+PacketLimit=100
+
+[Link]
+Parent=clsact
+MTUBytes=1500
+
+[Other]
+Foo=bar
+`
+
+func TestEmbedded(t *testing.T) {
+	f := &embeddedFile{}
+	require.NoError(t, Unmarshal([]byte(embeddedInput), f))
+
+	require.NotNil(t, f.Match)
+	assert.Equal(t, []string{"eth0", "eth1"}, f.Match.Name)
+	assert.Equal(t, "e1000", f.Match.Driver)
+	assert.Equal(t, "router", f.Match.Host)
+	assert.Empty(t, f.Match.KeyList)
+	assert.Equal(t, []embeddedQueueSection{{
+		QdiscParent: QdiscParent{Parent: "root", Handle: "8001"},
+		PacketLimit: 100,
+		Handle:      "1",
+	}}, f.Queues)
+	require.NotNil(t, f.SharedSections)
+	assert.Equal(t, &embeddedLinkSection{QdiscParent: &QdiscParent{Parent: "clsact"}, MTUBytes: "1500"}, f.Link)
+	assert.Len(t, f.SectionList, 1)
+
+	out, err := Marshal(f)
+	require.NoError(t, err)
+	assert.Equal(t, `[Match]
+# match comment
+Name=eth0 eth1
+Driver=e1000
+Host=router
+
+[FairQueueing]
+Parent=root
+Handle=8001
+PacketLimit=100
+Id=1
+
+[Link]
+Parent=clsact
+MTUBytes=1500
+
+[Other]
+Foo=bar
+`, string(out))
+
+	// nil embedded pointers are skipped
+	out, err = Marshal(&embeddedFile{Match: &embeddedMatchSection{Host: "router"}})
+	require.NoError(t, err)
+	assert.Equal(t, "[Match]\nHost=router\n", string(out))
+
+	assert.NoError(t, UnmarshalStrict([]byte(embeddedInput[:len(embeddedInput)-len("[Other]\nFoo=bar\n")]), &embeddedFile{}))
+
+	b, err := MarshalJSON(f)
+	require.NoError(t, err)
+	fromJSON := &embeddedFile{}
+	require.NoError(t, UnmarshalJSON(b, fromJSON))
+	assert.Equal(t, f.Queues, fromJSON.Queues)
+	assert.Equal(t, f.Link, fromJSON.Link)
+}
+
+func TestTypeFields(t *testing.T) {
+	names := func(v interface{}) []string {
+		var out []string
+		for _, f := range cachedFields(reflect.TypeOf(v)).list {
+			out = append(out, f.Name)
+		}
+		return out
+	}
+	assert.Equal(t, []string{"Parent", "Handle", "PacketLimit", "Id"}, names(embeddedQueueSection{}))
+	assert.Equal(t, []string{"Name", "Driver", "Host"}, names(embeddedMatchSection{}))
+	assert.Equal(t, []string{"Match", "FairQueueing", "Link"}, names(embeddedFile{}))
+
+	fields := cachedFields(reflect.TypeOf(embeddedMatchSection{}))
+	assert.Equal(t, []int{0}, fields.keyList)
+	assert.Equal(t, []int{1}, fields.comment)
+	assert.Equal(t, []int{2}, fields.keyComments)
+	assert.Nil(t, fields.sectionList)
+	assert.Same(t, fields, cachedFields(reflect.TypeOf(embeddedMatchSection{})))
+
+	// conflicting names at the same depth are dropped, unless one is tagged
+	type a struct{ Name, Other string }
+	type b struct {
+		Name  string
+		Other string `systemd:"Other"`
+	}
+	type conflict struct {
+		a
+		b
+	}
+	assert.Equal(t, []string{"Other"}, names(conflict{}))
+	assert.Equal(t, []int{1, 1}, cachedFields(reflect.TypeOf(conflict{})).list[0].index)
+}
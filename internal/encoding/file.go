@@ -1,7 +1,23 @@
 package encoding
 
+import "routerd.net/go-systemd/internal/parser"
+
+// Position describes a location in a systemd configuration file.
+type Position = parser.Position
+
 type File struct {
+	Name     string // file name, if known
 	Sections []Section
+
+	// comments and blank lines after the last key,
+	// only recorded by DecodeLossless.
+	trailing string
+	// line ending of the source, "\r\n" or "\n",
+	// only recorded by DecodeLossless.
+	lineEnding string
+	// comment lines after the last key, recorded by Decode
+	// and written by Encode after the last section
+	trailingComment string
 }
 
 func (f *File) SectionsByName(name string) (out []Section) {
@@ -15,10 +31,82 @@ func (f *File) SectionsByName(name string) (out []Section) {
 	return
 }
 
+// Section returns the first section with the given name, or nil if there is none.
+// The returned pointer is valid until sections are added or removed.
+func (f *File) Section(name string) *Section {
+	if i := f.SectionIndex(name); i != -1 {
+		return &f.Sections[i]
+	}
+	return nil
+}
+
+// SectionIndex returns the index of the first section with the given name, or -1 if there is none.
+func (f *File) SectionIndex(name string) int {
+	for i := range f.Sections {
+		if f.Sections[i].Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// AddSection appends a new section and returns it.
+// The returned pointer is valid until sections are added or removed.
+func (f *File) AddSection(name string) *Section {
+	return f.InsertSection(len(f.Sections), name)
+}
+
+// InsertSection inserts a new section at index i and returns it.
+// It panics if i is out of range.
+// The returned pointer is valid until sections are added or removed.
+func (f *File) InsertSection(i int, name string) *Section {
+	f.Sections = append(f.Sections, Section{})
+	copy(f.Sections[i+1:], f.Sections[i:])
+	f.Sections[i] = Section{Name: name}
+	return &f.Sections[i]
+}
+
+// DeleteSection removes the section at index i.
+// It panics if i is out of range.
+func (f *File) DeleteSection(i int) {
+	f.Sections = append(f.Sections[:i], f.Sections[i+1:]...)
+}
+
+// DeleteSections removes all sections with the given name
+// and returns the number of removed sections.
+func (f *File) DeleteSections(name string) int {
+	n := 0
+	for i := 0; i < len(f.Sections); {
+		if f.Sections[i].Name != name {
+			i++
+			continue
+		}
+		f.DeleteSection(i)
+		n++
+	}
+	return n
+}
+
+// RenameSections renames all sections named from to to
+// and returns the number of renamed sections.
+func (f *File) RenameSections(from, to string) int {
+	n := 0
+	for i := range f.Sections {
+		if f.Sections[i].Name == from {
+			f.Sections[i].Name = to
+			n++
+		}
+	}
+	return n
+}
+
 type Section struct {
-	Name    string
-	Comment string
-	Keys    []Key
+	Name    string   `json:"name" yaml:"name"`
+	Comment string   `json:"comment,omitempty" yaml:"comment,omitempty"`
+	Keys    []Key    `json:"keys" yaml:"keys"`
+	Pos     Position `json:"-" yaml:"-"` // position of the section header, only set by Decode
+
+	src *sectionSource // original formatting, only recorded by DecodeLossless
 }
 
 func (s *Section) KeysByName(name string) (out []Key) {
@@ -32,8 +120,138 @@ func (s *Section) KeysByName(name string) (out []Key) {
 	return
 }
 
+// Key returns the last key with the given name, which holds the effective value
+// of single value keys, or nil if there is none.
+// The returned pointer is valid until keys are added or removed.
+func (s *Section) Key(name string) *Key {
+	for i := len(s.Keys) - 1; i >= 0; i-- {
+		if s.Keys[i].Name == name {
+			return &s.Keys[i]
+		}
+	}
+	return nil
+}
+
+// Get returns the value of the last key with the given name
+// and whether such a key exists.
+func (s *Section) Get(name string) (string, bool) {
+	if key := s.Key(name); key != nil {
+		return key.Value, true
+	}
+	return "", false
+}
+
+// Set assigns value to the key with the given name.
+// The first existing key is updated in place, keeping its position and comment,
+// and all further keys with this name are removed.
+// If there is no such key, a new key is added.
+func (s *Section) Set(name, value string) {
+	for i := range s.Keys {
+		if s.Keys[i].Name != name {
+			continue
+		}
+		s.Keys[i].Value = value
+		for j := len(s.Keys) - 1; j > i; j-- {
+			if s.Keys[j].Name == name {
+				s.DeleteKey(j)
+			}
+		}
+		return
+	}
+	s.Add(name, value)
+}
+
+// Add adds a key with the given name and value. The key is inserted after the
+// last key with the same name, or appended if there is none, and returned.
+// The returned pointer is valid until keys are added or removed.
+func (s *Section) Add(name, value string) *Key {
+	i := len(s.Keys)
+	for j := len(s.Keys) - 1; j >= 0; j-- {
+		if s.Keys[j].Name == name {
+			i = j + 1
+			break
+		}
+	}
+	return s.InsertKey(i, name, value)
+}
+
+// InsertKey inserts a new key at index i and returns it.
+// It panics if i is out of range.
+// The returned pointer is valid until keys are added or removed.
+func (s *Section) InsertKey(i int, name, value string) *Key {
+	s.Keys = append(s.Keys, Key{})
+	copy(s.Keys[i+1:], s.Keys[i:])
+	s.Keys[i] = Key{Name: name, Value: value}
+	return &s.Keys[i]
+}
+
+// Delete removes all keys with the given name and returns the number of removed keys.
+func (s *Section) Delete(name string) int {
+	n := 0
+	for i := 0; i < len(s.Keys); {
+		if s.Keys[i].Name != name {
+			i++
+			continue
+		}
+		s.DeleteKey(i)
+		n++
+	}
+	return n
+}
+
+// DeleteKey removes the key at index i, together with its comment.
+// It panics if i is out of range.
+func (s *Section) DeleteKey(i int) {
+	s.Keys = append(s.Keys[:i], s.Keys[i+1:]...)
+}
+
+// MoveKey moves the key at index from to index to,
+// shifting the keys in between. It panics if an index is out of range.
+func (s *Section) MoveKey(from, to int) {
+	key := s.Keys[from]
+	if from < to {
+		copy(s.Keys[from:to], s.Keys[from+1:to+1])
+	} else {
+		copy(s.Keys[to+1:from+1], s.Keys[to:from])
+	}
+	s.Keys[to] = key
+}
+
+// SetKeyComment sets the comment of the first key with the given name.
+// It reports whether such a key exists.
+func (s *Section) SetKeyComment(name, comment string) bool {
+	for i := range s.Keys {
+		if s.Keys[i].Name == name {
+			s.Keys[i].Comment = comment
+			return true
+		}
+	}
+	return false
+}
+
 type Key struct {
-	Name    string
-	Value   string
-	Comment string
+	Name    string   `json:"name" yaml:"name"`
+	Value   string   `json:"value" yaml:"value"`
+	Comment string   `json:"comment,omitempty" yaml:"comment,omitempty"`
+	Pos     Position `json:"-" yaml:"-"` // position of the key name, only set by Decode
+
+	src *keySource // original formatting, only recorded by DecodeLossless
+}
+
+// sectionSource stores the original formatting of a section.
+type sectionSource struct {
+	leading string // comment and blank lines before the header
+	header  string // header line
+
+	// values as decoded, to detect modifications
+	name, comment string
+}
+
+// keySource stores the original formatting of a key.
+type keySource struct {
+	leading string // comment and blank lines before the key
+	text    string // key line, including continuation lines
+
+	// values as decoded, to detect modifications
+	name, value, comment string
 }
@@ -0,0 +1,113 @@
+package encoding
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileSections(t *testing.T) {
+	f := &File{}
+	f.AddSection("Network")
+	f.AddSection("Route")
+	f.InsertSection(0, "Match").Add("Name", "eth0")
+	f.InsertSection(3, "Route")
+
+	names := func() []string {
+		var out []string
+		for _, s := range f.Sections {
+			out = append(out, s.Name)
+		}
+		return out
+	}
+	assert.Equal(t, []string{"Match", "Network", "Route", "Route"}, names())
+	assert.Equal(t, 2, f.SectionIndex("Route"))
+	assert.Equal(t, -1, f.SectionIndex("Link"))
+	assert.Nil(t, f.Section("Link"))
+	assert.Equal(t, "Name", f.Section("Match").Keys[0].Name)
+
+	assert.Equal(t, 2, f.RenameSections("Route", "Address"))
+	assert.Equal(t, []string{"Match", "Network", "Address", "Address"}, names())
+
+	f.DeleteSection(0)
+	assert.Equal(t, 2, f.DeleteSections("Address"))
+	assert.Equal(t, []string{"Network"}, names())
+}
+
+func TestSectionKeys(t *testing.T) {
+	s := &Section{Name: "Network"}
+	s.Add("Address", "10.0.0.1/24")
+	s.Add("DNS", "1.1.1.1")
+	s.Add("Address", "10.0.0.2/24")
+	assert.Equal(t, []Key{
+		{Name: "Address", Value: "10.0.0.1/24"},
+		{Name: "Address", Value: "10.0.0.2/24"},
+		{Name: "DNS", Value: "1.1.1.1"},
+	}, s.Keys)
+
+	v, ok := s.Get("Address")
+	assert.True(t, ok)
+	assert.Equal(t, "10.0.0.2/24", v)
+	_, ok = s.Get("Gateway")
+	assert.False(t, ok)
+
+	s.Keys[0].Comment = "primary"
+	s.Set("Address", "10.0.0.3/24")
+	s.Set("Gateway", "10.0.0.254")
+	assert.Equal(t, []Key{
+		{Name: "Address", Value: "10.0.0.3/24", Comment: "primary"},
+		{Name: "DNS", Value: "1.1.1.1"},
+		{Name: "Gateway", Value: "10.0.0.254"},
+	}, s.Keys)
+
+	s.MoveKey(2, 0)
+	s.MoveKey(1, 2)
+	assert.True(t, s.SetKeyComment("DNS", "resolver"))
+	assert.False(t, s.SetKeyComment("NTP", "time"))
+	assert.Equal(t, []Key{
+		{Name: "Gateway", Value: "10.0.0.254"},
+		{Name: "DNS", Value: "1.1.1.1", Comment: "resolver"},
+		{Name: "Address", Value: "10.0.0.3/24", Comment: "primary"},
+	}, s.Keys)
+
+	s.InsertKey(1, "NTP", "ntp.example.com")
+	assert.Equal(t, 1, s.Delete("Gateway"))
+	assert.Equal(t, 0, s.Delete("Gateway"))
+	assert.Equal(t, "NTP", s.Keys[0].Name)
+	s.DeleteKey(0)
+	assert.Equal(t, "DNS", s.Keys[0].Name)
+}
+
+func TestEditLossless(t *testing.T) {
+	const input = `[Match]
+Name = eth0   # trailing text is part of the value
+
+[Network]
+# uplink
+Gateway = 10.0.0.1
+DNS=1.1.1.1 \
+    8.8.8.8
+`
+	f, err := DecodeLossless([]byte(input))
+	require.NoError(t, err)
+
+	f.Section("Network").Set("Gateway", "10.0.0.254")
+	f.AddSection("Route").Add("Gateway", "10.0.1.1")
+
+	var buf bytes.Buffer
+	require.NoError(t, Encode(&buf, f))
+	assert.Equal(t, `[Match]
+Name = eth0   # trailing text is part of the value
+
+[Network]
+# uplink
+Gateway=10.0.0.254
+DNS=1.1.1.1 \
+    8.8.8.8
+
+[Route]
+Gateway=10.0.1.1
+`, buf.String())
+}
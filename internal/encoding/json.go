@@ -0,0 +1,564 @@
+package encoding
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// MarshalJSON returns the JSON encoding of v, which is either a *File
+// or a pointer to a struct as accepted by Marshal.
+//
+// Files are encoded as an object with a list of sections, each holding a list of keys.
+// Structs are encoded as an object with a member per section, named like the section
+// and holding an object or, for repeated sections, a list of objects.
+// Section objects hold a member per key, named like the key. Booleans and numbers
+// are encoded as such, all other scalars as strings and slices as arrays.
+// Nil pointers and slices as well as empty values of omitempty fields are left out.
+// Section comments, key comments, unknown keys and unknown sections are stored in the
+// "comment", "keyComments", "keys" and "sections" members.
+func MarshalJSON(v interface{}) ([]byte, error) {
+	if file, ok := v.(*File); ok {
+		return json.Marshal(file)
+	}
+	tree, err := marshalTree(v)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(tree)
+}
+
+// UnmarshalJSON parses JSON data in the format written by MarshalJSON
+// and stores the result in the value pointed to by v,
+// which is either a *File or a pointer to a struct.
+// Scalars may be given as strings in systemd syntax as well, like "yes" for booleans.
+func UnmarshalJSON(data []byte, v interface{}) error {
+	if file, ok := v.(*File); ok {
+		return json.Unmarshal(data, file)
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return &InvalidUnmarshalError{reflect.TypeOf(v)}
+	}
+	return unmarshalTree(data, rv)
+}
+
+// fileJSON is the JSON and YAML representation of a File.
+type fileJSON struct {
+	Name            string    `json:"name,omitempty" yaml:"name,omitempty"`
+	Sections        []Section `json:"sections" yaml:"sections"`
+	TrailingComment string    `json:"trailingComment,omitempty" yaml:"trailingComment,omitempty"`
+}
+
+func (f *File) toJSON() fileJSON {
+	sections := f.Sections
+	if sections == nil {
+		sections = []Section{}
+	}
+	return fileJSON{Name: f.Name, Sections: sections, TrailingComment: f.trailingComment}
+}
+
+func (f *File) fromJSON(j fileJSON) {
+	*f = File{Name: j.Name, Sections: j.Sections, trailingComment: j.TrailingComment}
+}
+
+// MarshalJSON implements json.Marshaler.
+func (f *File) MarshalJSON() ([]byte, error) {
+	return json.Marshal(f.toJSON())
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+// The original formatting of a lossless decoded file is not preserved.
+func (f *File) UnmarshalJSON(data []byte) error {
+	var j fileJSON
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+	f.fromJSON(j)
+	return nil
+}
+
+// Names of the members holding the special fields of sections.
+// They start with a lower case letter, so they cannot clash with systemd names.
+const (
+	jsonComment     = "comment"
+	jsonKeyComments = "keyComments"
+	jsonKeys        = "keys"
+	jsonSections    = "sections"
+)
+
+// object is a JSON object, keeping the order of its members.
+type object []member
+
+type member struct {
+	Name  string
+	Value interface{}
+}
+
+func (o object) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, m := range o {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		name, err := json.Marshal(m.Name)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(m.Value)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// marshalTree converts the file struct pointed to by v into an object.
+func marshalTree(v interface{}) (object, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return nil, &InvalidUnmarshalError{reflect.TypeOf(v)}
+	}
+
+	var (
+		out     object
+		unknown object // unknown sections, written last like Marshal does
+	)
+	fields := cachedFields(rv.Elem().Type())
+	if sectionList, ok := fieldByIndex(rv.Elem(), fields.sectionList); ok && sectionList.Len() > 0 {
+		unknown = object{{jsonSections, sectionList.Interface()}}
+	}
+	for _, f := range fields.list {
+		field, ok := fieldByIndex(rv.Elem(), f.index)
+		if !ok {
+			continue
+		}
+		name := f.Name
+		switch field.Kind() {
+		case reflect.Ptr:
+			if field.IsNil() {
+				continue
+			}
+			section, err := marshalSectionTree(field)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, member{name, section})
+
+		case reflect.Struct:
+			section, err := marshalSectionTree(field.Addr())
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, member{name, section})
+
+		case reflect.Slice:
+			if field.IsNil() {
+				continue
+			}
+			sections := make([]object, field.Len())
+			for j := range sections {
+				section, err := marshalSectionTree(field.Index(j).Addr())
+				if err != nil {
+					return nil, err
+				}
+				sections[j] = section
+			}
+			out = append(out, member{name, sections})
+		}
+	}
+
+	// sections stored in a map, in order of their names
+	if m := fields.mapField; m != nil && isSectionMap(m.typ) {
+		if field, ok := fieldByIndex(rv.Elem(), m.index); ok {
+			for _, name := range sortedMapKeys(field) {
+				ptr, ok := sectionPointer(field.MapIndex(name))
+				if !ok {
+					continue
+				}
+				section, err := marshalSectionTree(ptr)
+				if err != nil {
+					return nil, err
+				}
+				out = append(out, member{name.String(), section})
+			}
+		}
+	}
+	return append(out, unknown...), nil
+}
+
+// marshalSectionTree converts the section struct pointed to by rv into an object.
+func marshalSectionTree(rv reflect.Value) (object, error) {
+	var (
+		comment, out object
+		unknown      object // unknown keys, written after the known keys like Marshal does
+		keyComments  = map[string]string{}
+	)
+	fields := cachedFields(rv.Elem().Type())
+	if field, ok := fieldByIndex(rv.Elem(), fields.comment); ok && field.String() != "" {
+		comment = object{{jsonComment, field.String()}}
+	}
+	if field, ok := fieldByIndex(rv.Elem(), fields.keyList); ok && field.Len() > 0 {
+		unknown = object{{jsonKeys, field.Interface()}}
+	}
+	for _, f := range fields.list {
+		field, ok := fieldByIndex(rv.Elem(), f.index)
+		if !ok {
+			continue
+		}
+		fieldConfig := f.fieldConfig
+		if comment := keyComment(rv, fieldConfig.Name); comment != "" {
+			keyComments[fieldConfig.Name] = comment
+		}
+		value, ok, err := marshalTreeValue(field, fieldConfig)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			out = append(out, member{fieldConfig.Name, value})
+		}
+	}
+
+	// keys stored in a map, in order of their names
+	if m := fields.mapField; m != nil && isKeyMap(m.typ) {
+		if field, ok := fieldByIndex(rv.Elem(), m.index); ok {
+			for _, name := range sortedMapKeys(field) {
+				if comment := keyComment(rv, name.String()); comment != "" {
+					keyComments[name.String()] = comment
+				}
+				out = append(out, member{name.String(), field.MapIndex(name).Interface()})
+			}
+		}
+	}
+	out = append(comment, append(out, unknown...)...)
+	if len(keyComments) > 0 {
+		out = append(out, member{jsonKeyComments, keyComments})
+	}
+	return out, nil
+}
+
+// marshalTreeValue returns the JSON value of a key field
+// and whether it should be written at all.
+func marshalTreeValue(field reflect.Value, fieldConfig fieldConfig) (interface{}, bool, error) {
+	if m, ok := asValueMarshaler(field); ok {
+		values, err := m.MarshalSystemdValue()
+		if err != nil {
+			return nil, false, &MarshalerError{Type: field.Type(), Err: err}
+		}
+		return values, values != nil, nil
+	}
+
+	switch t := field.Type(); {
+	case isScalar(t):
+		if field.IsZero() && fieldConfig.Omitempty {
+			return nil, false, nil
+		}
+		value, err := scalarTreeValue(field)
+		return value, true, err
+
+	case t.Kind() == reflect.Ptr && isScalar(t.Elem()):
+		if field.IsNil() {
+			return nil, false, nil
+		}
+		value, err := scalarTreeValue(field.Elem())
+		return value, true, err
+
+	case t.Kind() == reflect.Slice && isScalar(t.Elem()):
+		if field.IsNil() {
+			return nil, false, nil
+		}
+		values := make([]interface{}, field.Len())
+		for i := range values {
+			value, err := scalarTreeValue(field.Index(i))
+			if err != nil {
+				return nil, false, err
+			}
+			values[i] = value
+		}
+		return values, true, nil
+	}
+	// unsupported type
+	return nil, false, nil
+}
+
+// scalarTreeValue returns the JSON value of the scalar rv.
+// Booleans and numbers are kept, everything else is converted into its systemd string.
+func scalarTreeValue(rv reflect.Value) (interface{}, error) {
+	if hasTextRepresentation(rv.Type()) {
+		return marshalValue(rv)
+	}
+	switch rv.Kind() {
+	case reflect.Bool:
+		return rv.Bool(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return rv.Uint(), nil
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), nil
+	}
+	return marshalValue(rv)
+}
+
+// hasTextRepresentation returns true for scalar types with their own string representation.
+func hasTextRepresentation(t reflect.Type) bool {
+	ptr := reflect.PtrTo(t)
+	return t == durationType || t == hardwareAddrType ||
+		ptr.Implements(textMarshalerType) || ptr.Implements(textUnmarshalerType)
+}
+
+// unmarshalTree parses a JSON object written by marshalTree into the file struct pointed to by rv.
+func unmarshalTree(data []byte, rv reflect.Value) error {
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(data, &members); err != nil {
+		return err
+	}
+
+	known := map[string]bool{}
+	fields := cachedFields(rv.Elem().Type())
+	if fields.sectionList != nil {
+		known[jsonSections] = true
+		if raw, ok := members[jsonSections]; ok {
+			field := fieldByIndexAlloc(rv.Elem(), fields.sectionList)
+			if err := json.Unmarshal(raw, field.Addr().Interface()); err != nil {
+				return err
+			}
+		}
+	}
+	for _, f := range fields.list {
+		name := f.Name
+		known[name] = true
+		raw, ok := members[name]
+		if !ok || isNull(raw) {
+			continue
+		}
+		field := fieldByIndexAlloc(rv.Elem(), f.index)
+		switch field.Kind() {
+		case reflect.Ptr:
+			section := reflect.New(field.Type().Elem())
+			if err := unmarshalSectionTree(name, raw, section); err != nil {
+				return err
+			}
+			field.Set(section)
+
+		case reflect.Struct:
+			if err := unmarshalSectionTree(name, raw, field.Addr()); err != nil {
+				return err
+			}
+
+		case reflect.Slice:
+			var sections []json.RawMessage
+			if err := json.Unmarshal(raw, &sections); err != nil {
+				return fmt.Errorf("systemd: cannot unmarshal JSON section [%s]: %w", name, err)
+			}
+			slice := reflect.MakeSlice(field.Type(), len(sections), len(sections))
+			for j, raw := range sections {
+				if err := unmarshalSectionTree(name, raw, slice.Index(j).Addr()); err != nil {
+					return err
+				}
+			}
+			field.Set(slice)
+		}
+	}
+
+	for name, raw := range members {
+		if known[name] {
+			continue
+		}
+		m := fields.mapField
+		if m == nil || !isSectionMap(m.typ) {
+			return fmt.Errorf("systemd: unknown JSON section %q", name)
+		}
+		if err := unmarshalSectionTreeMap(name, raw, fieldByIndexAlloc(rv.Elem(), m.index)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// unmarshalSectionTree parses a JSON object written by marshalSectionTree
+// into the section struct pointed to by rv.
+func unmarshalSectionTree(section string, data []byte, rv reflect.Value) error {
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(data, &members); err != nil {
+		return fmt.Errorf("systemd: cannot unmarshal JSON section [%s]: %w", section, err)
+	}
+
+	known := map[string]bool{}
+	fields := cachedFields(rv.Elem().Type())
+	if fields.keyComments != nil {
+		known[jsonKeyComments] = true
+	}
+	// the section comment and unknown keys are stored as they are
+	special := []struct {
+		name  string
+		index []int
+	}{{jsonComment, fields.comment}, {jsonKeys, fields.keyList}}
+	for _, sp := range special {
+		if sp.index == nil {
+			continue
+		}
+		known[sp.name] = true
+		raw, ok := members[sp.name]
+		if !ok || isNull(raw) {
+			continue
+		}
+		field := fieldByIndexAlloc(rv.Elem(), sp.index)
+		if err := json.Unmarshal(raw, field.Addr().Interface()); err != nil {
+			return fmt.Errorf("systemd: cannot unmarshal JSON value %s of key [%s] %s into Go value of type %s: %w",
+				raw, section, sp.name, field.Type(), err)
+		}
+	}
+
+	for _, f := range fields.list {
+		name := f.Name
+		known[name] = true
+		raw, ok := members[name]
+		if !ok || isNull(raw) {
+			continue
+		}
+
+		field := fieldByIndexAlloc(rv.Elem(), f.index)
+		if err := unmarshalTreeValue(raw, field); err != nil {
+			return fmt.Errorf("systemd: cannot unmarshal JSON value %s of key [%s] %s into Go value of type %s: %w",
+				raw, section, name, field.Type(), err)
+		}
+	}
+
+	if raw, ok := members[jsonKeyComments]; ok && !isNull(raw) {
+		var comments map[string]string
+		if err := json.Unmarshal(raw, &comments); err != nil {
+			return fmt.Errorf("systemd: cannot unmarshal JSON key comments of section [%s]: %w", section, err)
+		}
+		if addComment := rv.MethodByName("AddKeyComment"); addComment.IsValid() {
+			for key, comment := range comments {
+				addComment.Call([]reflect.Value{reflect.ValueOf(key), reflect.ValueOf(comment)})
+			}
+		}
+	}
+
+	for name, raw := range members {
+		if known[name] {
+			continue
+		}
+		m := fields.mapField
+		if m == nil || !isKeyMap(m.typ) {
+			return fmt.Errorf("systemd: unknown JSON key %q in section [%s]", name, section)
+		}
+		field := fieldByIndexAlloc(rv.Elem(), m.index)
+		if field.IsNil() {
+			field.Set(reflect.MakeMap(field.Type()))
+		}
+		value := reflect.New(field.Type().Elem())
+		if err := json.Unmarshal(raw, value.Interface()); err != nil {
+			return fmt.Errorf("systemd: cannot unmarshal JSON value %s of key [%s] %s into Go value of type %s: %w",
+				raw, section, name, value.Elem().Type(), err)
+		}
+		field.SetMapIndex(reflect.ValueOf(name).Convert(field.Type().Key()), value.Elem())
+	}
+	return nil
+}
+
+// unmarshalSectionTreeMap parses the JSON object of the section name
+// into a new value stored in the map m of a file struct.
+func unmarshalSectionTreeMap(name string, raw json.RawMessage, m reflect.Value) error {
+	if isNull(raw) {
+		return nil
+	}
+	if m.IsNil() {
+		m.Set(reflect.MakeMap(m.Type()))
+	}
+	et := m.Type().Elem()
+	ptr := reflect.New(et)
+	if et.Kind() == reflect.Ptr {
+		ptr = reflect.New(et.Elem())
+	}
+	if err := unmarshalSectionTree(name, raw, ptr); err != nil {
+		return err
+	}
+	if et.Kind() == reflect.Struct {
+		ptr = ptr.Elem()
+	}
+	m.SetMapIndex(reflect.ValueOf(name).Convert(m.Type().Key()), ptr)
+	return nil
+}
+
+// unmarshalTreeValue parses the JSON value of a key field.
+func unmarshalTreeValue(data []byte, field reflect.Value) error {
+	if u, ok := asValueUnmarshaler(field); ok {
+		var values []string
+		if err := json.Unmarshal(data, &values); err != nil {
+			return err
+		}
+		return u.UnmarshalSystemdValue(values)
+	}
+
+	switch t := field.Type(); {
+	case isScalar(t):
+		return unmarshalScalarTree(data, field)
+
+	case t.Kind() == reflect.Ptr && isScalar(t.Elem()):
+		value := reflect.New(t.Elem())
+		if err := unmarshalScalarTree(data, value.Elem()); err != nil {
+			return err
+		}
+		field.Set(value)
+
+	case t.Kind() == reflect.Slice && isScalar(t.Elem()):
+		var values []json.RawMessage
+		if err := json.Unmarshal(data, &values); err != nil {
+			return err
+		}
+		slice := reflect.MakeSlice(t, len(values), len(values))
+		for i, value := range values {
+			if err := unmarshalScalarTree(value, slice.Index(i)); err != nil {
+				return err
+			}
+		}
+		field.Set(slice)
+	}
+	return nil
+}
+
+// unmarshalScalarTree parses a JSON scalar into rv.
+// Strings are parsed with the systemd syntax of the type,
+// other values are stored as they are.
+// Numbers and booleans are stored literally in string fields.
+func unmarshalScalarTree(data []byte, rv reflect.Value) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		if rv.Kind() == reflect.String && !hasTextRepresentation(rv.Type()) {
+			rv.SetString(s)
+			return nil
+		}
+		return unmarshalValue(s, rv)
+	}
+
+	if hasTextRepresentation(rv.Type()) {
+		return unmarshalValue(string(data), rv)
+	}
+	if rv.Kind() == reflect.String {
+		var literal interface{}
+		if err := json.Unmarshal(data, &literal); err != nil {
+			return err
+		}
+		switch literal.(type) {
+		case bool, float64:
+			rv.SetString(string(data))
+			return nil
+		}
+		return fmt.Errorf("expected string")
+	}
+	return json.Unmarshal(data, rv.Addr().Interface())
+}
+
+func isNull(data []byte) bool {
+	return strings.TrimSpace(string(data)) == "null"
+}
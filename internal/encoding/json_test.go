@@ -0,0 +1,217 @@
+package encoding
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const jsonTestInput = `[Match]
+# some comment
+Name=eth*
+MACAddress=01:23:45:67:89:ab 00-11-22-33-44-55
+Unknown=value
+
+[Network]
+Address=10.10.10.2/24
+Address=10.10.10.3/24
+
+[Route]
+Gateway=10.10.10.1
+Destination=10.10.20.1/24
+Enable=yes
+Disable=
+
+[Route]
+Gateway=10.10.10.1
+Source=something
+Disable=
+
+[Unknown]
+Key=value
+`
+
+const jsonTestOutput = `{
+  "Match": {
+    "Name": "eth*",
+    "MACAddress": [
+      "01:23:45:67:89:ab",
+      "00-11-22-33-44-55"
+    ],
+    "keys": [
+      {
+        "name": "Unknown",
+        "value": "value"
+      }
+    ],
+    "keyComments": {
+      "Name": "some comment"
+    }
+  },
+  "Network": {
+    "Address": [
+      "10.10.10.2/24",
+      "10.10.10.3/24"
+    ]
+  },
+  "Route": [
+    {
+      "Gateway": "10.10.10.1",
+      "Destination": "10.10.20.1/24",
+      "Enable": true
+    },
+    {
+      "Gateway": "10.10.10.1",
+      "Source": "something"
+    }
+  ],
+  "sections": [
+    {
+      "name": "Unknown",
+      "keys": [
+        {
+          "name": "Key",
+          "value": "value"
+        }
+      ]
+    }
+  ]
+}`
+
+func TestMarshalJSON(t *testing.T) {
+	var f testFile
+	require.NoError(t, Unmarshal([]byte(jsonTestInput), &f))
+
+	data, err := MarshalJSON(&f)
+	require.NoError(t, err)
+	var out bytes.Buffer
+	require.NoError(t, json.Indent(&out, data, "", "  "))
+	assert.Equal(t, jsonTestOutput, out.String())
+
+	// round trip
+	var got testFile
+	require.NoError(t, UnmarshalJSON(data, &got))
+	again, err := MarshalJSON(&got)
+	require.NoError(t, err)
+	assert.Equal(t, string(data), string(again))
+	b, err := Marshal(&got)
+	require.NoError(t, err)
+	assert.Equal(t, jsonTestInput, string(b))
+}
+
+func TestUnmarshalJSON(t *testing.T) {
+	type section struct {
+		KeyComments
+		Comment  string
+		String   string
+		Bool     *bool
+		Uint     uint
+		Duration time.Duration
+		Strings  []string `systemd:"String2"`
+		Reset    []string `systemd:",reset"`
+	}
+	type file struct {
+		Section *section
+	}
+
+	tests := []struct {
+		Name  string
+		Input string
+		Want  file
+		Err   string
+	}{
+		{
+			Name:  "typed",
+			Input: `{"Section": {"String": "a", "Bool": true, "Uint": 5, "Duration": "5min", "String2": ["a", "b"]}}`,
+			Want: file{Section: &section{
+				String: "a", Bool: BoolPtr(true), Uint: 5, Duration: 5 * time.Minute, Strings: []string{"a", "b"},
+			}},
+		},
+		{
+			Name:  "systemd syntax",
+			Input: `{"Section": {"String": 10, "Bool": "yes", "Uint": "5", "Duration": 30}}`,
+			Want: file{Section: &section{
+				String: "10", Bool: BoolPtr(true), Uint: 5, Duration: 30 * time.Second,
+			}},
+		},
+		{
+			Name:  "null and empty",
+			Input: `{"Section": {"Bool": null, "Reset": []}}`,
+			Want:  file{Section: &section{Reset: []string{}}},
+		},
+		{
+			Name:  "comments",
+			Input: `{"Section": {"comment": "section", "String": "a", "keyComments": {"String": "key"}}}`,
+			Want: func() file {
+				s := &section{Comment: "section", String: "a"}
+				s.AddKeyComment("String", "key")
+				return file{Section: s}
+			}(),
+		},
+		{
+			Name:  "unknown section",
+			Input: `{"Other": {}}`,
+			Err:   `systemd: unknown JSON section "Other"`,
+		},
+		{
+			Name:  "unknown key",
+			Input: `{"Section": {"Other": "a"}}`,
+			Err:   `systemd: unknown JSON key "Other" in section [Section]`,
+		},
+		{
+			Name:  "invalid value",
+			Input: `{"Section": {"Bool": "maybe"}}`,
+			Err: `systemd: cannot unmarshal JSON value "maybe" of key [Section] Bool ` +
+				`into Go value of type *bool: invalid syntax`,
+		},
+		{
+			Name:  "invalid string",
+			Input: `{"Section": {"String": ["a"]}}`,
+			Err: `systemd: cannot unmarshal JSON value ["a"] of key [Section] String ` +
+				`into Go value of type string: expected string`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			var got file
+			err := UnmarshalJSON([]byte(test.Input), &got)
+			if test.Err != "" {
+				assert.EqualError(t, err, test.Err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.Want, got)
+		})
+	}
+}
+
+func TestFileJSON(t *testing.T) {
+	f, err := Decode([]byte("# header\n[Match]\nName=eth0\n# key\nType=ether\n; trailing\n"))
+	require.NoError(t, err)
+
+	data, err := MarshalJSON(f)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"sections": [{"name": "Match", "comment": "header", "keys": [
+			{"name": "Name", "value": "eth0"},
+			{"name": "Type", "value": "ether", "comment": "key"}
+		]}],
+		"trailingComment": "trailing"
+	}`, string(data))
+
+	var got File
+	require.NoError(t, json.Unmarshal(data, &got))
+	var want, out bytes.Buffer
+	require.NoError(t, Encode(&want, f))
+	require.NoError(t, Encode(&out, &got))
+	assert.Equal(t, want.String(), out.String())
+
+	data, err = MarshalJSON(&File{})
+	require.NoError(t, err)
+	assert.Equal(t, `{"sections":[]}`, string(data))
+}
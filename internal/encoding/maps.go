@@ -0,0 +1,158 @@
+package encoding
+
+import (
+	"reflect"
+	"sort"
+)
+
+// unmarshalSectionMap stores the section in the map m of a file struct, see isSectionMap.
+// Sections with the same name are merged into the same map value.
+func unmarshalSectionMap(section *Section, m reflect.Value) error {
+	if m.IsNil() {
+		m.Set(reflect.MakeMap(m.Type()))
+	}
+	name := reflect.ValueOf(section.Name).Convert(m.Type().Key())
+
+	et := m.Type().Elem()
+	ptr := m.MapIndex(name)
+	if et.Kind() == reflect.Struct {
+		// map values are not addressable, work on a copy
+		value := reflect.New(et)
+		if ptr.IsValid() {
+			value.Elem().Set(ptr)
+		}
+		ptr = value
+	} else if !ptr.IsValid() || ptr.IsNil() {
+		ptr = reflect.New(et.Elem())
+	}
+
+	if err := unmarshalKeys(section, ptr); err != nil {
+		return err
+	}
+	if comment, ok := fieldByIndex(ptr.Elem(), cachedFields(ptr.Elem().Type()).comment); ok && section.Comment != "" {
+		// merged sections keep the first comment
+		comment.SetString(section.Comment)
+	}
+
+	if et.Kind() == reflect.Struct {
+		ptr = ptr.Elem()
+	}
+	m.SetMapIndex(name, ptr)
+	return nil
+}
+
+// marshalSectionMap passes the sections stored in the map m of a file struct to emit,
+// ordered by name.
+func marshalSectionMap(m reflect.Value, version int, emit func(section *Section) error) error {
+	for _, name := range sortedMapKeys(m) {
+		ptr, ok := sectionPointer(m.MapIndex(name))
+		if !ok {
+			continue
+		}
+
+		section := Section{Name: name.String()}
+		if err := marshalSection(&section, ptr, version); err != nil {
+			return err
+		}
+		if err := emit(&section); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sectionPointer returns a pointer to the section struct stored as a map value,
+// which is copied unless it is a pointer already. It reports false for nil pointers.
+func sectionPointer(value reflect.Value) (reflect.Value, bool) {
+	if value.Kind() == reflect.Ptr {
+		return value, !value.IsNil()
+	}
+	ptr := reflect.New(value.Type())
+	ptr.Elem().Set(value)
+	return ptr, true
+}
+
+// unmarshalKeyMap stores the keys in the map m of a section struct, see isKeyMap.
+// String values take the last assignment, slices collect all assignments
+// and an empty assignment resets them.
+// Comments are attached by calling addComment, if it is valid.
+func unmarshalKeyMap(keys []Key, m, addComment reflect.Value) {
+	if m.IsNil() {
+		m.Set(reflect.MakeMap(m.Type()))
+	}
+	et := m.Type().Elem()
+	for _, name := range keyNames(keys) {
+		var (
+			values  []string
+			comment string
+		)
+		for _, key := range keys {
+			if key.Name != name {
+				continue
+			}
+			if et.Kind() == reflect.String {
+				values, comment = []string{key.Value}, key.Comment
+				continue
+			}
+			if key.Value == "" {
+				values, comment = nil, ""
+				continue
+			}
+			values = append(values, key.Value)
+			if key.Comment != "" {
+				if comment != "" {
+					comment += "\n"
+				}
+				comment += key.Comment
+			}
+		}
+
+		value := reflect.New(et).Elem()
+		if et.Kind() == reflect.String {
+			value.SetString(values[0])
+		} else if values != nil {
+			value.Set(reflect.MakeSlice(et, len(values), len(values)))
+			for i, v := range values {
+				value.Index(i).SetString(v)
+			}
+		}
+		m.SetMapIndex(reflect.ValueOf(name).Convert(m.Type().Key()), value)
+
+		if comment != "" && addComment.IsValid() {
+			addComment.Call([]reflect.Value{reflect.ValueOf(name), reflect.ValueOf(comment)})
+		}
+	}
+}
+
+// marshalKeyMap appends the keys stored in the map m of the section struct
+// pointed to by rv to section, ordered by name.
+// Every entry is written, entries without values as an empty assignment.
+func marshalKeyMap(section *Section, rv, m reflect.Value) {
+	for _, name := range sortedMapKeys(m) {
+		value := m.MapIndex(name)
+		key := Key{Name: name.String(), Comment: keyComment(rv, name.String())}
+		if value.Kind() == reflect.String {
+			key.Value = value.String()
+			section.Keys = append(section.Keys, key)
+			continue
+		}
+		if value.Len() == 0 {
+			section.Keys = append(section.Keys, key)
+			continue
+		}
+		for i := 0; i < value.Len(); i++ {
+			key.Value = value.Index(i).String()
+			section.Keys = append(section.Keys, key)
+			key.Comment = ""
+		}
+	}
+}
+
+// sortedMapKeys returns the keys of the map m with string keys in sorted order.
+func sortedMapKeys(m reflect.Value) []reflect.Value {
+	keys := m.MapKeys()
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].String() < keys[j].String()
+	})
+	return keys
+}
@@ -0,0 +1,136 @@
+package encoding
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mapFile struct {
+	Unit    *mapUnitSection
+	Service *mapServiceSection
+	Vendors map[string]*mapVendorSection
+}
+
+type mapUnitSection struct {
+	Description string            `systemd:",omitempty"`
+	Other       map[string]string // all other keys
+}
+
+type mapServiceSection struct {
+	KeyComments
+
+	ExecStart   string              `systemd:",omitempty"`
+	Environment map[string][]string // all other keys
+}
+
+type mapVendorSection struct {
+	Comment string
+	Enabled *bool             `systemd:",omitempty"`
+	Options map[string]string // all other keys
+}
+
+const mapInput = `[Unit]
+Description=test
+After=network.target
+After=local-fs.target
+
+[Service]
+ExecStart=/bin/true
+# list
+Env=A=1
+Env=
+Env=B=2
+Env=C=3
+Reset=
+
+# vendor comment
+[X-Vendor]
+Enabled=yes
+Mode=fast
+
+[X-Other]
+Enabled=no
+
+[X-Vendor]
+Level=3
+`
+
+func TestMapFields(t *testing.T) {
+	f := &mapFile{}
+	require.NoError(t, Unmarshal([]byte(mapInput), f))
+
+	assert.Equal(t, "test", f.Unit.Description)
+	assert.Equal(t, map[string]string{"After": "local-fs.target"}, f.Unit.Other)
+	assert.Equal(t, "/bin/true", f.Service.ExecStart)
+	assert.Equal(t, map[string][]string{"Env": {"B=2", "C=3"}, "Reset": nil}, f.Service.Environment)
+	assert.Equal(t, map[string]*mapVendorSection{
+		"X-Vendor": {Comment: "vendor comment", Enabled: BoolPtr(true), Options: map[string]string{"Mode": "fast", "Level": "3"}},
+		"X-Other":  {Enabled: BoolPtr(false)},
+	}, f.Vendors)
+
+	out, err := Marshal(f)
+	require.NoError(t, err)
+	assert.Equal(t, `[Unit]
+Description=test
+After=local-fs.target
+
+[Service]
+ExecStart=/bin/true
+Env=B=2
+Env=C=3
+Reset=
+
+[X-Other]
+Enabled=no
+
+# vendor comment
+[X-Vendor]
+Enabled=yes
+Level=3
+Mode=fast
+`, string(out))
+
+	assert.NoError(t, UnmarshalStrict([]byte(mapInput), &mapFile{}))
+
+	rules := MergeRulesFor(f)
+	assert.Equal(t, MergeLastWins, rules.KeyMode("Unit", "After"))
+	assert.Equal(t, MergeResetOnEmpty, rules.KeyMode("Service", "Env"))
+	assert.Equal(t, MergeLastWins, rules.KeyMode("X-Vendor", "Enabled"))
+	assert.Equal(t, MergeLastWins, rules.KeyMode("X-Vendor", "Mode"))
+	assert.False(t, rules.RepeatedSection("X-Vendor"))
+
+	b, err := MarshalJSON(f)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"Unit": {"Description": "test", "After": "local-fs.target"},
+		"Service": {"ExecStart": "/bin/true", "Env": ["B=2", "C=3"], "Reset": null},
+		"X-Other": {"Enabled": false},
+		"X-Vendor": {"comment": "vendor comment", "Enabled": true, "Level": "3", "Mode": "fast"}
+	}`, string(b))
+	fromJSON := &mapFile{}
+	require.NoError(t, UnmarshalJSON(b, fromJSON))
+	assert.Equal(t, f.Vendors, fromJSON.Vendors)
+	assert.Equal(t, f.Unit, fromJSON.Unit)
+}
+
+func TestMapFieldsValues(t *testing.T) {
+	type section struct {
+		Keys map[string]string
+	}
+	type file struct {
+		Sections map[string]section
+	}
+
+	f := &file{}
+	require.NoError(t, Unmarshal([]byte("[A]\nX=1\n\n[B]\nY=2\n\n[A]\nZ=3\n"), f))
+	assert.Equal(t, map[string]section{
+		"A": {Keys: map[string]string{"X": "1", "Z": "3"}},
+		"B": {Keys: map[string]string{"Y": "2"}},
+	}, f.Sections)
+
+	out, err := Marshal(f)
+	require.NoError(t, err)
+	assert.Equal(t, "[A]\nX=1\nZ=3\n\n[B]\nY=2\n", string(out))
+}
@@ -7,23 +7,44 @@ import (
 )
 
 func Marshal(v interface{}) ([]byte, error) {
+	var out bytes.Buffer
+	if err := NewEncoder(&out).Encode(v); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+// marshalSections marshals the sections of the file struct pointed to by v
+// for the systemd version, see MarshalForVersion, and passes them to emit in file order.
+// Without a version, a Marshaler marshals itself.
+func marshalSections(v interface{}, version int, emit func(section *Section) error) error {
 	rv := reflect.ValueOf(v)
 
 	// must be a pointer
 	if rv.Kind() != reflect.Ptr || rv.IsNil() {
-		return nil, &InvalidUnmarshalError{rv.Type()}
+		return &InvalidUnmarshalError{reflect.TypeOf(v)}
 	}
 
-	file := &File{}
-	tv := rv.Elem().Type()
-	for i := 0; i < rv.Elem().NumField(); i++ {
-		structField := tv.Field(i)
-		if structField.Type.Name() == "SectionList" {
-			continue
+	if m, ok := v.(Marshaler); ok && version == 0 {
+		sections, err := m.MarshalSystemd()
+		if err != nil {
+			return &MarshalerError{Type: rv.Type(), Err: err}
+		}
+		for i := range sections {
+			if err := emit(&sections[i]); err != nil {
+				return err
+			}
 		}
+		return nil
+	}
 
-		field := rv.Elem().Field(i)
-		fieldConfig := configForField(structField)
+	fields := cachedFields(rv.Elem().Type())
+	for _, f := range fields.list {
+		field, ok := fieldByIndex(rv.Elem(), f.index)
+		if !ok {
+			continue
+		}
+		fieldConfig := f.fieldConfig
 		switch field.Type().Kind() {
 		case reflect.Ptr:
 			if field.IsNil() {
@@ -32,143 +53,214 @@ func Marshal(v interface{}) ([]byte, error) {
 			section := Section{
 				Name: fieldConfig.Name,
 			}
-			marshalSection(&section, field)
-			file.Sections = append(file.Sections, section)
+			if err := sectionForVersion(&section, fieldConfig, version); err != nil {
+				return err
+			}
+			if err := marshalSection(&section, field, version); err != nil {
+				return err
+			}
+			if err := emit(&section); err != nil {
+				return err
+			}
 
 		case reflect.Struct:
 			section := Section{
 				Name: fieldConfig.Name,
 			}
-			marshalSection(&section, field.Addr())
-			file.Sections = append(file.Sections, section)
+			if err := sectionForVersion(&section, fieldConfig, version); err != nil {
+				return err
+			}
+			if err := marshalSection(&section, field.Addr(), version); err != nil {
+				return err
+			}
+			if err := emit(&section); err != nil {
+				return err
+			}
 
 		case reflect.Slice:
 			for i := 0; i < field.Len(); i++ {
 				section := Section{
 					Name: fieldConfig.Name,
 				}
-				marshalSection(&section, field.Index(i).Addr())
-				file.Sections = append(file.Sections, section)
+				if err := sectionForVersion(&section, fieldConfig, version); err != nil {
+					return err
+				}
+				if err := marshalSection(&section, field.Index(i).Addr(), version); err != nil {
+					return err
+				}
+				if err := emit(&section); err != nil {
+					return err
+				}
 			}
 		}
 
 	}
 
-	// Check, if the SectionList for arbitrary sections is embedded.
-	sectionList := rv.Elem().FieldByName("SectionList")
-	if sectionList.IsValid() {
-		for i := 0; i < sectionList.Len(); i++ {
-			file.Sections = append(file.Sections, sectionList.Index(i).Interface().(Section))
+	// Sections stored in a map follow in order of their names.
+	if m := fields.mapField; m != nil && isSectionMap(m.typ) {
+		if field, ok := fieldByIndex(rv.Elem(), m.index); ok {
+			if err := marshalSectionMap(field, version, emit); err != nil {
+				return err
+			}
 		}
 	}
 
-	// Encode to bytes
-	var out bytes.Buffer
-	if err := Encode(&out, file); err != nil {
-		return nil, err
+	// Check, if the SectionList for arbitrary sections is embedded.
+	if sectionList, ok := fieldByIndex(rv.Elem(), fields.sectionList); ok {
+		for i := 0; i < sectionList.Len(); i++ {
+			section := sectionList.Index(i).Interface().(Section)
+			if err := emit(&section); err != nil {
+				return err
+			}
+		}
 	}
-	return out.Bytes(), nil
+	return nil
 }
 
-func marshalSection(section *Section, rv reflect.Value) {
-	tv := rv.Elem().Type()
-	for i := 0; i < rv.Elem().NumField(); i++ {
-		structField := tv.Field(i)
-		field := rv.Elem().Field(i)
+func marshalSection(section *Section, rv reflect.Value, version int) error {
+	fields := cachedFields(rv.Elem().Type())
+	if comment, ok := fieldByIndex(rv.Elem(), fields.comment); ok {
+		section.Comment = comment.String()
+	}
 
-		if structField.Name == "Comment" &&
-			structField.Type.Kind() == reflect.String {
-			section.Comment = field.String()
+	for _, f := range fields.list {
+		field, ok := fieldByIndex(rv.Elem(), f.index)
+		if !ok {
 			continue
 		}
-		if structField.Name == "KeyComments" ||
-			structField.Name == "KeyList" {
-			continue
+		n := len(section.Keys)
+		if err := marshalKey(section, field, f.fieldConfig, keyComment(rv, f.Name)); err != nil {
+			return err
 		}
+		if err := keysForVersion(section.Name, section.Keys[n:], f.fieldConfig, version); err != nil {
+			return err
+		}
+	}
 
-		fieldConfig := configForField(tv.Field(i))
-		switch structField.Type.Kind() {
-		case reflect.Ptr:
-			if field.IsNil() && fieldConfig.Omitempty {
-				continue
-			}
+	// Keys stored in a map follow in order of their names.
+	if m := fields.mapField; m != nil && isKeyMap(m.typ) {
+		if field, ok := fieldByIndex(rv.Elem(), m.index); ok {
+			marshalKeyMap(section, rv, field)
+		}
+	}
+
+	// Check if KeyList for arbitrary keys is embedded.
+	keyList, ok := fieldByIndex(rv.Elem(), fields.keyList)
+	if !ok {
+		return nil
+	}
+	for i := 0; i < keyList.Len(); i++ {
+		section.Keys = append(section.Keys, keyList.Index(i).Interface().(Key))
+	}
+	return nil
+}
+
+// marshalKey appends the keys of the field of a section struct to section,
+// the comment is attached to the first key.
+func marshalKey(section *Section, field reflect.Value, fieldConfig fieldConfig, comment string) error {
+	if m, ok := asValueMarshaler(field); ok {
+		values, err := m.MarshalSystemdValue()
+		if err != nil {
+			return &MarshalerError{Type: field.Type(), Err: err}
+		}
+		for i, val := range values {
 			key := Key{
-				Name:    fieldConfig.Name,
-				Comment: keyComment(rv, fieldConfig.Name),
+				Name:  fieldConfig.Name,
+				Value: val,
 			}
+			if i == 0 {
+				// Add the comment to the first Key
+				key.Comment = comment
+			}
+			section.Keys = append(section.Keys, key)
+		}
+		return nil
+	}
 
-			switch structField.Type.Elem().Kind() {
-			case reflect.String:
-				if !field.IsNil() {
-					key.Value = field.Elem().String()
-				}
+	switch {
+	case isScalar(field.Type()):
+		value, err := marshalValue(field)
+		if err != nil {
+			return err
+		}
+		key := Key{
+			Name:    fieldConfig.Name,
+			Value:   value,
+			Comment: comment,
+		}
+		if field.IsZero() && fieldConfig.Omitempty {
+			return nil
+		}
+		section.Keys = append(section.Keys, key)
 
-			case reflect.Bool:
-				if !field.IsNil() {
-					key.Value = BoolToStr(field.Elem().Bool())
-				}
+	case field.Type().Kind() == reflect.Ptr &&
+		isScalar(field.Type().Elem()):
+		if field.IsNil() && fieldConfig.Omitempty {
+			return nil
+		}
+		key := Key{
+			Name:    fieldConfig.Name,
+			Comment: comment,
+		}
+		if !field.IsNil() {
+			value, err := marshalValue(field.Elem())
+			if err != nil {
+				return err
+			}
+			key.Value = value
+		}
+		section.Keys = append(section.Keys, key)
 
-			default:
-				continue
+	case field.Type().Kind() == reflect.Slice &&
+		isScalar(field.Type().Elem()):
+		values := make([]string, field.Len())
+		for i := range values {
+			value, err := marshalValue(field.Index(i))
+			if err != nil {
+				return err
 			}
-			section.Keys = append(section.Keys, key)
+			values[i] = value
+		}
+
+		if fieldConfig.Mode == assignReset && !field.IsNil() {
+			// write the reset explicitly
+			section.Keys = append(section.Keys, Key{
+				Name:    fieldConfig.Name,
+				Comment: comment,
+			})
+			comment = ""
+		}
 
-		case reflect.String:
+		if fieldConfig.WSlist || fieldConfig.Mode.singleAssignment() {
 			key := Key{
 				Name:    fieldConfig.Name,
-				Value:   field.String(),
-				Comment: keyComment(rv, fieldConfig.Name),
+				Value:   strings.Join(values, " "),
+				Comment: comment,
 			}
-			if key.Value == "" && fieldConfig.Omitempty {
-				continue
+			if key.Value == "" {
+				return nil
 			}
 			section.Keys = append(section.Keys, key)
+			return nil
+		}
 
-		case reflect.Slice:
-			if field.Type().Elem().Kind() != reflect.String {
-				// wrong key type
-				continue
+		for i, val := range values {
+			key := Key{
+				Name:  fieldConfig.Name,
+				Value: val,
 			}
-
-			if fieldConfig.WSlist {
-				key := Key{
-					Name:    fieldConfig.Name,
-					Value:   strings.Join(field.Interface().([]string), " "),
-					Comment: keyComment(rv, fieldConfig.Name),
-				}
-				if key.Value == "" {
-					continue
-				}
-				section.Keys = append(section.Keys, key)
+			if key.Value == "" {
 				continue
 			}
 
-			for i, val := range field.Interface().([]string) {
-				key := Key{
-					Name:  fieldConfig.Name,
-					Value: val,
-				}
-				if key.Value == "" {
-					continue
-				}
-
-				if i == 0 {
-					// Add the comment to the first Key
-					key.Comment = keyComment(rv, fieldConfig.Name)
-				}
-				section.Keys = append(section.Keys, key)
+			if i == 0 {
+				// Add the comment to the first Key
+				key.Comment = comment
 			}
+			section.Keys = append(section.Keys, key)
 		}
 	}
-
-	// Check if KeyList for arbitrary keys is embedded.
-	keyList := rv.Elem().FieldByName("KeyList")
-	if !keyList.IsValid() {
-		return
-	}
-	for i := 0; i < keyList.Len(); i++ {
-		section.Keys = append(section.Keys, keyList.Index(i).Interface().(Key))
-	}
+	return nil
 }
 
 // keyComment gets the registered comment for the given key
@@ -184,3 +276,18 @@ func keyComment(rv reflect.Value, keyName string) string {
 	}
 	return ""
 }
+
+// A MarshalerError describes an error returned
+// by a ValueMarshaler or encoding.TextMarshaler.
+type MarshalerError struct {
+	Type reflect.Type
+	Err  error
+}
+
+func (e *MarshalerError) Error() string {
+	return "systemd: error calling marshaler for type " + e.Type.String() + ": " + e.Err.Error()
+}
+
+func (e *MarshalerError) Unwrap() error {
+	return e.Err
+}
@@ -1,7 +1,10 @@
 package encoding
 
 import (
+	"errors"
+	"net"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -77,3 +80,90 @@ UndefinedKey=something
 [Whatever]
 `, string(b))
 }
+
+func TestMarshalNumbers(t *testing.T) {
+	var sixteen int8 = 16
+	b, err := Marshal(&numberFile{
+		Numbers: numberSection{
+			Int:      -42,
+			Int8Ptr:  &sixteen,
+			Float:    1.5,
+			Duration: 5*time.Minute + 20*time.Second,
+			Ports:    []uint16{80, 443},
+		},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, `[Numbers]
+Int=-42
+Int8Ptr=16
+UintPtr=
+Float=1.5
+Duration=5min 20s
+Port=80 443
+`, string(b))
+}
+
+func TestMarshalCodecs(t *testing.T) {
+	dns := net.ParseIP("1.1.1.1")
+	b, err := Marshal(&codecFile{
+		Codec: codecSection{
+			Address:     net.ParseIP("10.0.0.1"),
+			Gateways:    []net.IP{net.ParseIP("10.0.0.254"), net.ParseIP("fe80::1")},
+			DNS:         &dns,
+			MACAddress:  net.HardwareAddr{0x01, 0x23, 0x45, 0x67, 0x89, 0xab},
+			Mode:        modeActive,
+			Environment: envList{"B": "2", "A": "1"},
+		},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, `[Codec]
+Address=10.0.0.1
+Gateway=10.0.0.254
+Gateway=fe80::1
+DNS=1.1.1.1
+MACAddress=01:23:45:67:89:ab
+Mode=active
+Environment=A=1
+Environment=B=2
+`, string(b))
+
+	t.Run("marshaler error", func(t *testing.T) {
+		_, err := Marshal(&codecFile{})
+		var marshalerErr *MarshalerError
+		require.True(t, errors.As(err, &marshalerErr))
+		assert.EqualError(t, err, "systemd: error calling marshaler for type encoding.mode: unknown mode 0")
+	})
+}
+
+func TestMarshalAssignModes(t *testing.T) {
+	b, err := Marshal(&assignFile{
+		Section: assignSection{
+			Append:   []string{"a", "b"},
+			Reset:    []string{"c"},
+			LastWins: []string{"d", "e"},
+			First:    []string{"f"},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, `[Section]
+Append=a
+Append=b
+Reset=
+Reset=c
+LastWins=d e
+First=f
+AppendString=
+FirstString=
+ResetPtr=
+`, string(b))
+
+	// an explicit reset survives a round trip
+	const input = "[Section]\nReset=\n"
+	f := &assignFile{}
+	require.NoError(t, Unmarshal([]byte(input), f))
+	b, err = Marshal(f)
+	require.NoError(t, err)
+	assert.Contains(t, string(b), input)
+}
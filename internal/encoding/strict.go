@@ -0,0 +1,157 @@
+package encoding
+
+import (
+	"reflect"
+	"strings"
+)
+
+// An UnknownFieldError describes a section or key without a matching struct field.
+type UnknownFieldError struct {
+	Section    string   // name of the section
+	Key        string   // name of the key, empty for unknown sections
+	Pos        Position // position of the section or key
+	Suggestion string   // most similar known name, if any
+}
+
+func (e *UnknownFieldError) Error() string {
+	msg := e.Pos.String() + ": unknown section [" + e.Section + "]"
+	name := e.Section
+	if e.Key != "" {
+		msg = e.Pos.String() + ": unknown key " + e.Key + " in section [" + e.Section + "]"
+		name = e.Key
+	}
+	if e.Suggestion != "" && e.Suggestion != name {
+		msg += ", did you mean " + e.Suggestion + "?"
+	}
+	return msg
+}
+
+// An UnknownFieldsError lists all unknown sections and keys
+// found by UnmarshalStrict in file order.
+type UnknownFieldsError struct {
+	Errors []*UnknownFieldError
+}
+
+func (e *UnknownFieldsError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// unknownFields returns all sections and keys of file
+// without a matching field in the file struct type t.
+func unknownFields(file *File, t reflect.Type) []*UnknownFieldError {
+	var (
+		sectionNames []string
+		sectionTypes = map[string]reflect.Type{}
+		mapType      reflect.Type // type of the sections stored in a map
+	)
+	fields := cachedFields(t)
+	for _, f := range fields.list {
+		sectionNames = append(sectionNames, f.Name)
+		sectionTypes[f.Name] = sectionType(f.typ)
+	}
+	if m := fields.mapField; m != nil && isSectionMap(m.typ) {
+		mapType = sectionType(m.typ)
+	}
+
+	var unknown []*UnknownFieldError
+	for _, section := range file.Sections {
+		st, ok := sectionTypes[section.Name]
+		if !ok && mapType == nil {
+			unknown = append(unknown, &UnknownFieldError{
+				Section:    section.Name,
+				Pos:        section.Pos,
+				Suggestion: suggest(section.Name, sectionNames),
+			})
+			continue
+		}
+		if !ok {
+			st = mapType
+		}
+
+		var keyNames []string
+		if st != nil {
+			sectionFields := cachedFields(st)
+			if m := sectionFields.mapField; m != nil && isKeyMap(m.typ) {
+				// all keys are stored
+				continue
+			}
+			for _, key := range sectionFields.list {
+				keyNames = append(keyNames, key.Name)
+			}
+		}
+		for _, key := range section.Keys {
+			if contains(keyNames, key.Name) {
+				continue
+			}
+			unknown = append(unknown, &UnknownFieldError{
+				Section:    section.Name,
+				Key:        key.Name,
+				Pos:        key.Pos,
+				Suggestion: suggest(key.Name, keyNames),
+			})
+		}
+	}
+	return unknown
+}
+
+func contains(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// suggest returns the known name most similar to name,
+// or an empty string if none is similar enough.
+func suggest(name string, known []string) string {
+	var (
+		best     string
+		bestDist = len(name)/3 + 1 // allow about one typo per three characters
+	)
+	lower := strings.ToLower(name)
+	for _, k := range known {
+		d := levenshtein(lower, strings.ToLower(k))
+		if d < bestDist {
+			best, bestDist = k, d
+		}
+	}
+	return best
+}
+
+// levenshtein returns the edit distance between a and b.
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
+}
+
+func min(values ...int) int {
+	m := values[0]
+	for _, v := range values[1:] {
+		if v < m {
+			m = v
+		}
+	}
+	return m
+}
@@ -0,0 +1,93 @@
+package encoding
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnmarshalStrict(t *testing.T) {
+	const input = `[Match]
+Name=eth0
+name=eth1
+
+[Netwrk]
+Address=10.0.0.2/24
+
+[Network]
+Adress=10.0.0.2/24
+Gateway=10.0.0.1
+
+[Route]
+Gateway=10.0.0.1
+Metrik=10
+
+[Unrelated]
+Foo=bar
+`
+
+	f := &testFile{}
+	err := UnmarshalStrict([]byte(input), f)
+
+	var unknownErr *UnknownFieldsError
+	require.True(t, errors.As(err, &unknownErr))
+	assert.Equal(t, []*UnknownFieldError{
+		{Section: "Match", Key: "name", Pos: Position{Line: 3, Column: 1}, Suggestion: "Name"},
+		{Section: "Netwrk", Pos: Position{Line: 5, Column: 1}, Suggestion: "Network"},
+		{Section: "Network", Key: "Adress", Pos: Position{Line: 9, Column: 1}, Suggestion: "Address"},
+		{Section: "Route", Key: "Metrik", Pos: Position{Line: 14, Column: 1}},
+		{Section: "Unrelated", Pos: Position{Line: 16, Column: 1}},
+	}, unknownErr.Errors)
+	assert.Equal(t, strings.Join([]string{
+		"3:1: unknown key name in section [Match], did you mean Name?",
+		"5:1: unknown section [Netwrk], did you mean Network?",
+		"9:1: unknown key Adress in section [Network], did you mean Address?",
+		"14:1: unknown key Metrik in section [Route]",
+		"16:1: unknown section [Unrelated]",
+	}, "\n"), err.Error())
+
+	// the value is filled in nevertheless
+	assert.Equal(t, []string{"10.0.0.1"}, f.Network.Gateways)
+	assert.Len(t, f.SectionList, 2)
+
+	// known fields only
+	require.NoError(t, UnmarshalStrict([]byte("[Match]\nName=eth0\n"), &testFile{}))
+
+	// type errors take precedence
+	err = UnmarshalStrict([]byte("[Numbers]\nInt=x\nFoo=1\n"), &numberFile{})
+	var typeErr *UnmarshalTypeError
+	assert.True(t, errors.As(err, &typeErr))
+}
+
+func TestDecoderDisallowUnknownFields(t *testing.T) {
+	const input = "[Match]\nNme=eth0\n"
+
+	dec := NewDecoder(strings.NewReader(input))
+	require.NoError(t, dec.Decode(&testFile{}))
+
+	dec = NewDecoder(strings.NewReader(input))
+	dec.DisallowUnknownFields()
+	err := dec.Decode(&testFile{})
+	assert.EqualError(t, err, "2:1: unknown key Nme in section [Match], did you mean Name?")
+}
+
+func TestSuggest(t *testing.T) {
+	known := []string{"Address", "Gateway", "DNS", "Domains"}
+	tests := []struct {
+		Name       string
+		Suggestion string
+	}{
+		{"Adress", "Address"},
+		{"Gatway", "Gateway"},
+		{"dns", "DNS"},
+		{"Domain", "Domains"},
+		{"NTP", ""},
+		{"Foo", ""},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.Suggestion, suggest(test.Name, known), test.Name)
+	}
+}
@@ -0,0 +1,152 @@
+package encoding
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Length of a month and year as used by systemd.
+const (
+	month = 2629800 * time.Second
+	year  = 31557600 * time.Second
+)
+
+// timespanUnits maps all unit suffixes accepted by systemd to their duration.
+var timespanUnits = map[string]time.Duration{
+	"usec": time.Microsecond,
+	"us":   time.Microsecond,
+	"µs":   time.Microsecond,
+
+	"msec": time.Millisecond,
+	"ms":   time.Millisecond,
+
+	"seconds": time.Second,
+	"second":  time.Second,
+	"sec":     time.Second,
+	"s":       time.Second,
+
+	"minutes": time.Minute,
+	"minute":  time.Minute,
+	"min":     time.Minute,
+	"m":       time.Minute,
+
+	"hours": time.Hour,
+	"hour":  time.Hour,
+	"hr":    time.Hour,
+	"h":     time.Hour,
+
+	"days": 24 * time.Hour,
+	"day":  24 * time.Hour,
+	"d":    24 * time.Hour,
+
+	"weeks": 7 * 24 * time.Hour,
+	"week":  7 * 24 * time.Hour,
+	"w":     7 * 24 * time.Hour,
+
+	"months": month,
+	"month":  month,
+	"M":      month,
+
+	"years": year,
+	"year":  year,
+	"y":     year,
+}
+
+// formatUnits lists the units used by FormatTimespan, largest first.
+var formatUnits = []struct {
+	suffix string
+	d      time.Duration
+}{
+	{"y", year},
+	{"month", month},
+	{"w", 7 * 24 * time.Hour},
+	{"d", 24 * time.Hour},
+	{"h", time.Hour},
+	{"min", time.Minute},
+	{"s", time.Second},
+	{"ms", time.Millisecond},
+	{"us", time.Microsecond},
+}
+
+// ParseTimespan parses a systemd time span like "5min 20s", "1h" or "500ms".
+// Values without unit are interpreted as seconds.
+// "infinity" is mapped to the maximum representable duration.
+func ParseTimespan(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, fmt.Errorf("empty time span")
+	}
+	if s == "infinity" {
+		return time.Duration(math.MaxInt64), nil
+	}
+
+	var (
+		total    time.Duration
+		original = s
+	)
+	for len(s) > 0 {
+		// number
+		i := 0
+		for i < len(s) && (s[i] >= '0' && s[i] <= '9' || s[i] == '.') {
+			i++
+		}
+		if i == 0 {
+			return 0, fmt.Errorf("invalid time span %q", original)
+		}
+		n, err := strconv.ParseFloat(s[:i], 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid time span %q", original)
+		}
+		s = strings.TrimLeft(s[i:], " \t")
+
+		// unit
+		j := strings.IndexFunc(s, func(r rune) bool {
+			return !unicode.IsLetter(r)
+		})
+		if j == -1 {
+			j = len(s)
+		}
+		unit := time.Second
+		if j > 0 {
+			var ok bool
+			unit, ok = timespanUnits[s[:j]]
+			if !ok {
+				return 0, fmt.Errorf("invalid time span %q: unknown unit %q", original, s[:j])
+			}
+		}
+		s = strings.TrimLeft(s[j:], " \t")
+
+		d := n * float64(unit)
+		if d+float64(total) >= 1<<63 {
+			return 0, fmt.Errorf("invalid time span %q: out of range", original)
+		}
+		total += time.Duration(d)
+	}
+	return total, nil
+}
+
+// FormatTimespan formats the given duration in the systemd time span notation.
+// The duration is rounded to microseconds, as this is the finest unit systemd understands.
+func FormatTimespan(d time.Duration) string {
+	if d == time.Duration(math.MaxInt64) {
+		return "infinity"
+	}
+	if d < time.Microsecond {
+		return "0"
+	}
+
+	var parts []string
+	d = d.Round(time.Microsecond)
+	for _, u := range formatUnits {
+		if d < u.d {
+			continue
+		}
+		parts = append(parts, strconv.FormatInt(int64(d/u.d), 10)+u.suffix)
+		d %= u.d
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,76 @@
+package encoding
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTimespan(t *testing.T) {
+	tests := []struct {
+		Input    string
+		Expected time.Duration
+	}{
+		{Input: "5min 20s", Expected: 5*time.Minute + 20*time.Second},
+		{Input: "1h", Expected: time.Hour},
+		{Input: "500ms", Expected: 500 * time.Millisecond},
+		{Input: "30", Expected: 30 * time.Second},
+		{Input: "2 h", Expected: 2 * time.Hour},
+		{Input: "1.5s", Expected: 1500 * time.Millisecond},
+		{Input: "1y 2months", Expected: year + 2*month},
+		{Input: "3weeks2d", Expected: 23 * 24 * time.Hour},
+		{Input: "10us", Expected: 10 * time.Microsecond},
+		{Input: "infinity", Expected: time.Duration(math.MaxInt64)},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Input, func(t *testing.T) {
+			d, err := ParseTimespan(test.Input)
+			require.NoError(t, err)
+			assert.Equal(t, test.Expected, d)
+		})
+	}
+
+	// durations of 2^63ns and more are out of range,
+	// the largest int64 is rounded up to 2^63 as float64
+	_, err := ParseTimespan("9223372036854775807ns")
+	assert.Error(t, err)
+	_, err = ParseTimespan("9223372036.854775808s")
+	assert.Error(t, err)
+	_, err = ParseTimespan("9223372036s")
+	assert.NoError(t, err)
+
+	for _, input := range []string{"", "abc", "5 parsecs", "1h x", "300y"} {
+		t.Run("invalid "+input, func(t *testing.T) {
+			_, err := ParseTimespan(input)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestFormatTimespan(t *testing.T) {
+	tests := []struct {
+		Input    time.Duration
+		Expected string
+	}{
+		{Input: 0, Expected: "0"},
+		{Input: 5*time.Minute + 20*time.Second, Expected: "5min 20s"},
+		{Input: 1500 * time.Millisecond, Expected: "1s 500ms"},
+		{Input: 25 * time.Hour, Expected: "1d 1h"},
+		{Input: time.Duration(math.MaxInt64), Expected: "infinity"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Expected, func(t *testing.T) {
+			s := FormatTimespan(test.Input)
+			assert.Equal(t, test.Expected, s)
+
+			d, err := ParseTimespan(s)
+			require.NoError(t, err)
+			assert.Equal(t, test.Input, d)
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package encoding
+
+import "reflect"
+
+// StructInfo describes how Marshal and Unmarshal map a file or section struct, see TypeInfo.
+type StructInfo struct {
+	// Fields holds the fields storing sections or keys in declaration order.
+	// Fields of embedded structs are flattened, fields tagged with "-" are left out.
+	Fields []FieldInfo
+
+	// Map is the map field storing the sections or keys
+	// without a field of their own, nil if there is none.
+	Map *FieldInfo
+
+	// index sequences of the special fields, nil if there is none
+	Comment     []int // section comment
+	SectionList []int // embedded SectionList
+	KeyList     []int // embedded KeyList
+	KeyComments []int // embedded KeyComments
+}
+
+// FieldInfo describes a struct field storing a section or key.
+type FieldInfo struct {
+	Name  string       // section or key name
+	Index []int        // index sequence for reflect.Type.FieldByIndex
+	Type  reflect.Type // type of the field
+
+	Omitempty, WSlist, Unordered bool
+
+	// Mode is the assignment mode of the tag, "append", "reset", "lastwins", "first",
+	// or empty for the default, see Unmarshal.
+	Mode string
+
+	// systemd versions adding and deprecating the key or section, 0 if unknown
+	Since, Deprecated int
+	// name written for systemd versions older than Since
+	Legacy string
+}
+
+// Section returns the section struct type stored by a field of a file struct,
+// or nil if the field does not store a section struct.
+func (f *FieldInfo) Section() reflect.Type {
+	return sectionType(f.Type)
+}
+
+// TypeInfo returns the fields of the file or section struct type t,
+// which may be a pointer to a struct. It returns nil for other types.
+// It serves the commands of this module and is not exported by package systemd.
+func TypeInfo(t reflect.Type) *StructInfo {
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	fields := cachedFields(t)
+	info := &StructInfo{
+		Comment:     copyIndex(fields.comment),
+		SectionList: copyIndex(fields.sectionList),
+		KeyList:     copyIndex(fields.keyList),
+		KeyComments: copyIndex(fields.keyComments),
+	}
+	for _, f := range fields.list {
+		info.Fields = append(info.Fields, f.info())
+	}
+	if fields.mapField != nil {
+		m := fields.mapField.info()
+		info.Map = &m
+	}
+	return info
+}
+
+func (f *structField) info() FieldInfo {
+	return FieldInfo{
+		Name:       f.Name,
+		Index:      copyIndex(f.index),
+		Type:       f.typ,
+		Omitempty:  f.Omitempty,
+		WSlist:     f.WSlist,
+		Unordered:  f.Unordered,
+		Mode:       f.Mode.option(),
+		Since:      f.Since,
+		Deprecated: f.Deprecated,
+		Legacy:     f.Legacy,
+	}
+}
+
+// copyIndex returns a copy of the index sequence, which is shared by the field cache.
+func copyIndex(index []int) []int {
+	if index == nil {
+		return nil
+	}
+	return append([]int(nil), index...)
+}
@@ -0,0 +1,60 @@
+package encoding
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTypeInfo(t *testing.T) {
+	assert.Nil(t, TypeInfo(reflect.TypeOf("")))
+	assert.Nil(t, TypeInfo(nil))
+
+	info := TypeInfo(reflect.TypeOf(&embeddedFile{}))
+	require.NotNil(t, info)
+	assert.Equal(t, []int{0}, info.SectionList)
+	assert.Nil(t, info.Map)
+	require.Len(t, info.Fields, 3)
+	assert.Equal(t, "FairQueueing", info.Fields[1].Name)
+	assert.Equal(t, reflect.TypeOf(embeddedQueueSection{}), info.Fields[1].Section())
+	// sections of embedded structs are flattened
+	assert.Equal(t, "Link", info.Fields[2].Name)
+	assert.Equal(t, []int{3, 0}, info.Fields[2].Index)
+	assert.Equal(t, reflect.TypeOf(embeddedLinkSection{}), info.Fields[2].Section())
+
+	info = TypeInfo(reflect.TypeOf(embeddedQueueSection{}))
+	var names []string
+	for _, f := range info.Fields {
+		names = append(names, f.Name)
+	}
+	// fields tagged with "-" and unexported fields are left out
+	assert.Equal(t, []string{"Parent", "Handle", "PacketLimit", "Id"}, names)
+	assert.Nil(t, info.Fields[0].Section())
+
+	// the index sequences are copies
+	info.Fields[0].Index[0] = 42
+	assert.Equal(t, []int{0, 0}, TypeInfo(reflect.TypeOf(embeddedQueueSection{})).Fields[0].Index)
+
+	type options struct {
+		Name    []string          `systemd:",omitempty,wslist,unordered,first"`
+		DNS     []string          `systemd:",append,since=246,deprecated=250"`
+		NewName string            `systemd:",lastwins,since=250,legacy=OldName"`
+		Other   map[string]string `systemd:"-"`
+		Rest    map[string]string
+	}
+	info = TypeInfo(reflect.TypeOf(options{}))
+	assert.Equal(t, []FieldInfo{{
+		Name: "Name", Index: []int{0}, Type: reflect.TypeOf([]string{}),
+		Omitempty: true, WSlist: true, Unordered: true, Mode: "first",
+	}, {
+		Name: "DNS", Index: []int{1}, Type: reflect.TypeOf([]string{}),
+		Mode: "append", Since: 246, Deprecated: 250,
+	}, {
+		Name: "NewName", Index: []int{2}, Type: reflect.TypeOf(""),
+		Mode: "lastwins", Since: 250, Legacy: "OldName",
+	}}, info.Fields)
+	require.NotNil(t, info.Map)
+	assert.Equal(t, []int{4}, info.Map.Index)
+}
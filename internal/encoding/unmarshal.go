@@ -2,23 +2,83 @@ package encoding
 
 import (
 	"reflect"
+	"strconv"
 	"strings"
 )
 
 // Unmarshal parses the systemd unit data and stores the result in the value pointed to by v.
+//
+// By default scalar fields take the last assignment of a key, while slices collect
+// the values of all assignments and an empty assignment resets them.
+// The "append", "reset", "lastwins" and "first" tag options
+// change how repeated assignments are combined:
+//
+//	DNS    []string `systemd:",append"`   // empty assignments are ignored
+//	Domain []string `systemd:",reset"`    // empty assignments reset and survive Marshal
+//	Names  []string `systemd:",lastwins"` // only the last assignment counts
+//	Host   string   `systemd:",first"`    // only the first assignment counts
+//
+// The fields of embedded structs without a name in their tag are treated
+// as fields of the outer struct, like encoding/json does, so sections and keys
+// can be shared between structs. Fields tagged with "-" are ignored.
+//
+// Sections and keys without a field of their own can be stored in a map field,
+// as a typed alternative to SectionList and KeyList. A map[string]string field
+// of a section struct takes the last assignment of every other key,
+// a map[string][]string field collects all assignments with empty ones resetting the list.
+// A map[string]T or map[string]*T field of a file struct stores every other section
+// in a section struct T by name, merging sections with the same name.
+// Marshal writes map entries after the other fields, ordered by name.
+//
+// Values of types implementing encoding.TextUnmarshaler are decoded by UnmarshalText.
+// Types implementing encoding.TextMarshaler without encoding.TextUnmarshaler
+// cannot be decoded and result in an *UnmarshalTypeError.
 func Unmarshal(data []byte, v interface{}) error {
 	file, err := Decode(data)
 	if err != nil {
 		return err
 	}
+	return file.Unmarshal(v)
+}
+
+// UnmarshalStrict works like Unmarshal, but reports sections and keys
+// without a matching struct field as *UnknownFieldsError,
+// even if they are stored in an embedded SectionList or KeyList.
+// v is filled in completely before the error is returned.
+func UnmarshalStrict(data []byte, v interface{}) error {
+	file, err := Decode(data)
+	if err != nil {
+		return err
+	}
+	return file.UnmarshalStrict(v)
+}
 
+// Unmarshal stores the contents of the file in the value pointed to by v,
+// following the same rules as the package level Unmarshal function.
+// An Unmarshaler unmarshals itself.
+func (file *File) Unmarshal(v interface{}) error {
 	rv := reflect.ValueOf(v)
 	if rv.Kind() != reflect.Ptr || rv.IsNil() {
 		return &InvalidUnmarshalError{reflect.TypeOf(v)}
 	}
+	if u, ok := v.(Unmarshaler); ok {
+		return u.UnmarshalSystemd(file)
+	}
 	return unmarshalSections(file, rv)
 }
 
+// UnmarshalStrict stores the contents of the file in the value pointed to by v,
+// following the same rules as the package level UnmarshalStrict function.
+func (file *File) UnmarshalStrict(v interface{}) error {
+	if err := file.Unmarshal(v); err != nil {
+		return err
+	}
+	if unknown := unknownFields(file, reflect.TypeOf(v).Elem()); len(unknown) > 0 {
+		return &UnknownFieldsError{Errors: unknown}
+	}
+	return nil
+}
+
 func unmarshalSections(file *File, rv reflect.Value) error {
 	// must be a pointer
 	if rv.Kind() != reflect.Ptr || rv.IsNil() {
@@ -26,17 +86,16 @@ func unmarshalSections(file *File, rv reflect.Value) error {
 	}
 
 	knownSections := map[string]struct{}{}
-	tv := rv.Elem().Type()
-	for i := 0; i < rv.Elem().NumField(); i++ {
-		field := rv.Elem().Field(i)
-		fieldConfig := configForField(tv.Field(i))
-		knownSections[fieldConfig.Name] = struct{}{}
-		sections := file.SectionsByName(fieldConfig.Name)
+	fields := cachedFields(rv.Elem().Type())
+	for _, f := range fields.list {
+		knownSections[f.Name] = struct{}{}
+		sections := file.SectionsByName(f.Name)
 		if len(sections) == 0 {
 			// no section with this name
 			continue
 		}
 
+		field := fieldByIndexAlloc(rv.Elem(), f.index)
 		for _, section := range sections {
 			var newObject reflect.Value
 			switch field.Type().Kind() {
@@ -55,9 +114,8 @@ func unmarshalSections(file *File, rv reflect.Value) error {
 			if err := unmarshalKeys(&section, newObjectPtr); err != nil {
 				return err
 			}
-			commentField := newObjectPtr.Elem().FieldByName("Comment")
-			if commentField.IsValid() {
-				commentField.Set(reflect.ValueOf(section.Comment))
+			if comment, ok := fieldByIndex(newObjectPtr.Elem(), cachedFields(newObjectPtr.Elem().Type()).comment); ok {
+				comment.SetString(section.Comment)
 			}
 
 			if field.Type().Kind() != reflect.Slice {
@@ -68,6 +126,19 @@ func unmarshalSections(file *File, rv reflect.Value) error {
 		}
 	}
 
+	// Store the other sections in the map of sections, if there is one.
+	if m := fields.mapField; m != nil && isSectionMap(m.typ) {
+		for i := range file.Sections {
+			if _, ok := knownSections[file.Sections[i].Name]; ok {
+				continue
+			}
+			if err := unmarshalSectionMap(&file.Sections[i], fieldByIndexAlloc(rv.Elem(), m.index)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
 	// Add Sections that don't fit into any other place
 	// if there is a AddSection function implemented.
 	addSection := rv.MethodByName("AddSection")
@@ -90,11 +161,9 @@ func unmarshalKeys(section *Section, rv reflect.Value) error {
 	}
 
 	knownKeys := map[string]struct{}{}
-	tv := rv.Elem().Type()
-	for i := 0; i < rv.Elem().NumField(); i++ {
-		field := rv.Elem().Field(i)
-
-		fieldConfig := configForField(tv.Field(i))
+	fields := cachedFields(rv.Elem().Type())
+	for _, f := range fields.list {
+		fieldConfig := f.fieldConfig
 		knownKeys[fieldConfig.Name] = struct{}{}
 		keys := section.KeysByName(fieldConfig.Name)
 		if len(keys) == 0 {
@@ -102,76 +171,14 @@ func unmarshalKeys(section *Section, rv reflect.Value) error {
 			continue
 		}
 
-		var comment string
-		switch field.Type().Kind() {
-		case reflect.String:
-			key := keys[len(keys)-1]
-			field.SetString(key.Value)
-			comment = key.Comment
-
-		case reflect.Ptr:
-			switch field.Type().Elem().Kind() {
-			case reflect.Bool:
-				key := keys[len(keys)-1]
-				if key.Value == "" && fieldConfig.Omitempty {
-					// skip empty
-					continue
-				}
-
-				b := StrToBool(key.Value)
-				if b == nil {
-					// TODO: warning?
-					continue
-				}
-
-				boolRV := reflect.New(field.Type().Elem())
-				boolRV.Elem().SetBool(*b)
-				field.Set(boolRV)
-
-			case reflect.String:
-				key := keys[len(keys)-1]
-				if key.Value == "" && fieldConfig.Omitempty {
-					// skip empty
-					continue
-				}
-
-				stringRV := reflect.New(field.Type().Elem())
-				stringRV.Elem().SetString(key.Value)
-				field.Set(stringRV)
-
-			default:
-				continue
-			}
-
-		case reflect.Slice:
-			if field.Type().Elem().Kind() != reflect.String {
-				// wrong key type
-				continue
-			}
-
-			var values []string
-			for _, key := range keys {
-				if comment != "" {
-					comment += "\n"
-				}
-				comment += key.Comment
-
-				if len(key.Value) == 0 {
-					// A key with no value reset's all previously read values.
-					values = nil
-					// no need to save key comments that don't apply
-					comment = ""
-					continue
-				}
-
-				if fieldConfig.WSlist {
-					values = append(values,
-						filterEmpty(strings.Split(key.Value, " "))...)
-					continue
-				}
-				values = append(values, key.Value)
-			}
-			field.Set(reflect.ValueOf(values))
+		keys = fieldConfig.Mode.effectiveKeys(keys)
+		if len(keys) == 0 {
+			// only ignored assignments
+			continue
+		}
+		comment, err := unmarshalField(section, keys, fieldByIndexAlloc(rv.Elem(), f.index), fieldConfig)
+		if err != nil {
+			return err
 		}
 
 		// comment handling
@@ -188,6 +195,20 @@ func unmarshalKeys(section *Section, rv reflect.Value) error {
 		})
 	}
 
+	// Store the other keys in the map of keys, if there is one.
+	if m := fields.mapField; m != nil && isKeyMap(m.typ) {
+		var keys []Key
+		for _, key := range section.Keys {
+			if _, ok := knownKeys[key.Name]; !ok {
+				keys = append(keys, key)
+			}
+		}
+		if len(keys) > 0 {
+			unmarshalKeyMap(keys, fieldByIndexAlloc(rv.Elem(), m.index), rv.MethodByName("AddKeyComment"))
+		}
+		return nil
+	}
+
 	// Add Keys that don't fit into any other place
 	// if there is a AddKey function implemented.
 	addKey := rv.MethodByName("AddKey")
@@ -203,6 +224,129 @@ func unmarshalKeys(section *Section, rv reflect.Value) error {
 	return nil
 }
 
+// unmarshalField stores the assignments keys of a key in field
+// and returns the comment of the key.
+// The assignment mode of the field must already be applied to keys.
+func unmarshalField(section *Section, keys []Key, field reflect.Value, fieldConfig fieldConfig) (string, error) {
+	var comment string
+	if u, ok := asValueUnmarshaler(field); ok {
+		values := make([]string, len(keys))
+		for i, key := range keys {
+			values[i] = key.Value
+			if key.Comment == "" {
+				continue
+			}
+			if comment != "" {
+				comment += "\n"
+			}
+			comment += key.Comment
+		}
+		if err := u.UnmarshalSystemdValue(values); err != nil {
+			// the values are checked as a whole, report the last assignment
+			return "", newUnmarshalTypeError(section, keys[len(keys)-1], field.Type(), err)
+		}
+		keys = nil
+	}
+
+	switch {
+	case len(keys) == 0:
+		// already handled by ValueUnmarshaler
+
+	case isScalar(field.Type()):
+		key := keys[len(keys)-1]
+		comment = key.Comment
+		if field.Kind() == reflect.Bool && StrToBool(key.Value) == nil {
+			// TODO: warning?
+			return "", nil
+		}
+		if key.Value == "" && field.Kind() != reflect.String {
+			// An empty value resets the key to its default.
+			field.Set(reflect.Zero(field.Type()))
+			break
+		}
+		if err := unmarshalValue(key.Value, field); err != nil {
+			return "", newUnmarshalTypeError(section, key, field.Type(), err)
+		}
+
+	case field.Kind() == reflect.Ptr && isScalar(field.Type().Elem()):
+		key := keys[len(keys)-1]
+		if key.Value == "" && fieldConfig.Mode == assignReset {
+			// An empty value resets the key to unset.
+			field.Set(reflect.Zero(field.Type()))
+			comment = key.Comment
+			break
+		}
+		if key.Value == "" &&
+			(fieldConfig.Omitempty || field.Type().Elem().Kind() != reflect.String) {
+			// skip empty
+			return "", nil
+		}
+		if field.Type().Elem().Kind() == reflect.Bool && StrToBool(key.Value) == nil {
+			// TODO: warning?
+			return "", nil
+		}
+
+		valueRV := reflect.New(field.Type().Elem())
+		if err := unmarshalValue(key.Value, valueRV.Elem()); err != nil {
+			return "", newUnmarshalTypeError(section, key, field.Type(), err)
+		}
+		field.Set(valueRV)
+		comment = key.Comment
+
+	case field.Kind() == reflect.Slice && isScalar(field.Type().Elem()):
+		var (
+			values  []string
+			valKeys []Key // key each value originates from
+			reset   bool
+		)
+		for _, key := range keys {
+			if comment != "" {
+				comment += "\n"
+			}
+			comment += key.Comment
+
+			if len(key.Value) == 0 {
+				// A key with no value reset's all previously read values.
+				values, valKeys = nil, nil
+				reset = true
+				// no need to save key comments that don't apply,
+				// an explicit reset keeps its own comment
+				comment = ""
+				if fieldConfig.Mode == assignReset {
+					comment = key.Comment
+				}
+				continue
+			}
+
+			if fieldConfig.WSlist || fieldConfig.Mode.singleAssignment() {
+				for _, value := range filterEmpty(strings.Split(key.Value, " ")) {
+					values = append(values, value)
+					valKeys = append(valKeys, key)
+				}
+				continue
+			}
+			values = append(values, key.Value)
+			valKeys = append(valKeys, key)
+		}
+
+		slice := reflect.MakeSlice(field.Type(), len(values), len(values))
+		if values == nil && !(reset && fieldConfig.Mode == assignReset) {
+			slice = reflect.Zero(field.Type())
+		}
+		for i, value := range values {
+			if err := unmarshalValue(value, slice.Index(i)); err != nil {
+				return "", newUnmarshalTypeError(section, valKeys[i], field.Type(), err)
+			}
+		}
+		field.Set(slice)
+
+	default:
+		// unsupported type
+		return "", nil
+	}
+	return comment, nil
+}
+
 const fieldTagName = "systemd"
 
 type fieldConfig struct {
@@ -210,28 +354,132 @@ type fieldConfig struct {
 	Omitempty bool
 	// white space list
 	WSlist bool
+	// the order of the values does not matter, see EqualOptions
+	Unordered bool
+	// how repeated assignments are combined
+	Mode assignMode
+	// systemd versions adding and deprecating the key or section, 0 if unknown
+	Since, Deprecated int
+	// name written for systemd versions older than Since
+	Legacy string
 }
 
-func configForField(structField reflect.StructField) (c fieldConfig) {
-	c.Name = structField.Name
+// assignMode describes how repeated assignments of a key are combined.
+type assignMode int
+
+const (
+	// Scalars take the last assignment,
+	// slices append all values and an empty assignment resets them.
+	assignDefault assignMode = iota
+	// "append": all values accumulate, empty assignments are ignored.
+	assignAppend
+	// "reset": like the default, but an explicit reset is preserved.
+	// A trailing reset leaves an empty, non-nil slice and
+	// Marshal writes an empty assignment in front of the values of non-nil slices.
+	assignReset
+	// "lastwins": the last assignment replaces all earlier ones,
+	// slices are read from and written to a single whitespace separated assignment.
+	assignLastWins
+	// "first": the first assignment wins, later ones are ignored,
+	// slices are read from and written to a single whitespace separated assignment.
+	assignFirst
+)
+
+// option returns the tag option of the mode, or "" for the default.
+func (m assignMode) option() string {
+	switch m {
+	case assignAppend:
+		return "append"
+	case assignReset:
+		return "reset"
+	case assignLastWins:
+		return "lastwins"
+	case assignFirst:
+		return "first"
+	}
+	return ""
+}
 
-	if tag := structField.Tag.Get(fieldTagName); tag != "" {
-		idx := strings.Index(tag, ",")
-		if idx == -1 {
-			c.Name = tag
-			return
+// singleAssignment reports whether all values are stored in a single assignment.
+func (m assignMode) singleAssignment() bool {
+	return m == assignLastWins || m == assignFirst
+}
+
+// effectiveKeys returns the keys taken into account by the mode.
+func (m assignMode) effectiveKeys(keys []Key) []Key {
+	switch m {
+	case assignAppend:
+		var out []Key
+		for _, key := range keys {
+			if key.Value != "" {
+				out = append(out, key)
+			}
 		}
+		return out
+
+	case assignLastWins:
+		return keys[len(keys)-1:]
+
+	case assignFirst:
+		return keys[:1]
+	}
+	return keys
+}
+
+func configForField(structField reflect.StructField) fieldConfig {
+	return configForTag(structField.Name, structField.Tag.Get(fieldTagName))
+}
+
+// configForTag returns the configuration of the field name with the systemd tag.
+func configForTag(name, tag string) (c fieldConfig) {
+	c.Name = name
+	if tag == "" {
+		return
+	}
 
-		name := tag[:idx]
-		if name != "" {
-			c.Name = tag[:idx]
+	opts := strings.Split(tag, ",")
+	if opts[0] != "" {
+		c.Name = opts[0]
+	}
+	for _, opt := range opts[1:] {
+		switch opt {
+		case "omitempty":
+			c.Omitempty = true
+		case "wslist":
+			c.WSlist = true
+		case "unordered":
+			c.Unordered = true
+		case "append":
+			c.Mode = assignAppend
+		case "reset":
+			c.Mode = assignReset
+		case "lastwins":
+			c.Mode = assignLastWins
+		case "first":
+			c.Mode = assignFirst
+		default:
+			name, value := splitOption(opt)
+			switch name {
+			case "since":
+				c.Since, _ = strconv.Atoi(value)
+			case "deprecated":
+				c.Deprecated, _ = strconv.Atoi(value)
+			case "legacy":
+				c.Legacy = value
+			}
 		}
-		c.Omitempty = strings.Contains(tag[idx:], "omitempty")
-		c.WSlist = strings.Contains(tag[idx:], "wslist")
 	}
 	return
 }
 
+// splitOption splits a tag option of the form name=value.
+func splitOption(opt string) (name, value string) {
+	if i := strings.IndexByte(opt, '='); i >= 0 {
+		return opt[:i], opt[i+1:]
+	}
+	return opt, ""
+}
+
 // An InvalidUnmarshalError describes an invalid argument passed to Unmarshal.
 // (The argument to Unmarshal must be a non-nil pointer.)
 type InvalidUnmarshalError struct {
@@ -248,3 +496,35 @@ func (e *InvalidUnmarshalError) Error() string {
 	}
 	return "json: Unmarshal(nil " + e.Type.String() + ")"
 }
+
+// An UnmarshalTypeError describes a systemd value that was
+// not appropriate for the Go type it should be stored in.
+type UnmarshalTypeError struct {
+	Value   string       // key value
+	Type    reflect.Type // type of Go value it could not be assigned to
+	Section string       // name of the section
+	Key     string       // name of the key
+	Pos     Position     // position of the key
+	Err     error        // underlying parse error
+}
+
+func newUnmarshalTypeError(section *Section, key Key, t reflect.Type, err error) *UnmarshalTypeError {
+	return &UnmarshalTypeError{
+		Value:   key.Value,
+		Type:    t,
+		Section: section.Name,
+		Key:     key.Name,
+		Pos:     key.Pos,
+		Err:     err,
+	}
+}
+
+func (e *UnmarshalTypeError) Error() string {
+	return e.Pos.String() + ": cannot unmarshal " + strconv.Quote(e.Value) +
+		" of key [" + e.Section + "] " + e.Key + " into Go value of type " + e.Type.String() +
+		": " + e.Err.Error()
+}
+
+func (e *UnmarshalTypeError) Unwrap() error {
+	return e.Err
+}
@@ -1,7 +1,13 @@
 package encoding
 
 import (
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -106,15 +112,351 @@ Source=something
 				Gateway: "10.10.10.1/24",
 				Source:  StringPtr("something"),
 				KeyList: KeyList{
-					{Name: "UndefinedKey", Value: "something"},
+					{Name: "UndefinedKey", Value: "something", Pos: Position{Line: 28, Column: 1}},
 				},
 			},
 		},
 		SectionList: SectionList{
-			{Name: "Whatever"},
+			{Name: "Whatever", Pos: Position{Line: 31, Column: 1}},
 		},
 	}
 
 	require.NoError(t, err)
 	assert.Equal(t, expected, f)
 }
+
+type numberSection struct {
+	Int      int
+	Int8Ptr  *int8
+	Uint     uint `systemd:",omitempty"`
+	UintPtr  *uint
+	Float    float64
+	Duration time.Duration
+	Ports    []uint16 `systemd:"Port,wslist"`
+}
+
+type numberFile struct {
+	Numbers numberSection
+}
+
+func TestUnmarshalNumbers(t *testing.T) {
+	f := &numberFile{}
+	err := Unmarshal([]byte(`[Numbers]
+Int=-42
+Int8Ptr=16
+Uint=42
+UintPtr=7
+Float=1.5
+Duration=5min 20s
+Port=80 443
+Port=8080
+`), f)
+	require.NoError(t, err)
+
+	var seven uint = 7
+	var sixteen int8 = 16
+	assert.Equal(t, &numberFile{
+		Numbers: numberSection{
+			Int:      -42,
+			Int8Ptr:  &sixteen,
+			Uint:     42,
+			UintPtr:  &seven,
+			Float:    1.5,
+			Duration: 5*time.Minute + 20*time.Second,
+			Ports:    []uint16{80, 443, 8080},
+		},
+	}, f)
+
+	t.Run("errors", func(t *testing.T) {
+		tests := []struct {
+			Name  string
+			Input string
+			Err   string
+		}{
+			{
+				Name:  "invalid int",
+				Input: "[Numbers]\nInt=abc\n",
+				Err:   `2:1: cannot unmarshal "abc" of key [Numbers] Int into Go value of type int: invalid syntax`,
+			},
+			{
+				Name:  "out of range",
+				Input: "[Numbers]\n\nInt8Ptr=1000\n",
+				Err:   `3:1: cannot unmarshal "1000" of key [Numbers] Int8Ptr into Go value of type *int8: value out of range`,
+			},
+			{
+				Name:  "hexadecimal",
+				Input: "[Numbers]\nInt8Ptr=0x10\n",
+				Err:   `2:1: cannot unmarshal "0x10" of key [Numbers] Int8Ptr into Go value of type *int8: invalid syntax`,
+			},
+			{
+				Name:  "underscores",
+				Input: "[Numbers]\nUint=1_000\n",
+				Err:   `2:1: cannot unmarshal "1_000" of key [Numbers] Uint into Go value of type uint: invalid syntax`,
+			},
+			{
+				Name:  "negative uint in list",
+				Input: "[Numbers]\nPort=80 -1\n",
+				Err:   `2:1: cannot unmarshal "80 -1" of key [Numbers] Port into Go value of type []uint16: invalid syntax`,
+			},
+			{
+				Name:  "invalid duration",
+				Input: "[Numbers]\nDuration=5 parsecs\n",
+				Err:   `2:1: cannot unmarshal "5 parsecs" of key [Numbers] Duration into Go value of type time.Duration: invalid time span "5 parsecs": unknown unit "parsecs"`,
+			},
+		}
+
+		for _, test := range tests {
+			t.Run(test.Name, func(t *testing.T) {
+				err := Unmarshal([]byte(test.Input), &numberFile{})
+				var typeErr *UnmarshalTypeError
+				require.True(t, errors.As(err, &typeErr))
+				assert.EqualError(t, err, test.Err)
+			})
+		}
+	})
+}
+
+// mode is an enum implementing encoding.TextMarshaler and encoding.TextUnmarshaler.
+type mode int
+
+const (
+	modeUnknown mode = iota
+	modeActive
+	modePassive
+)
+
+func (m mode) MarshalText() ([]byte, error) {
+	switch m {
+	case modeActive:
+		return []byte("active"), nil
+	case modePassive:
+		return []byte("passive"), nil
+	}
+	return nil, fmt.Errorf("unknown mode %d", m)
+}
+
+func (m *mode) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "active":
+		*m = modeActive
+	case "passive":
+		*m = modePassive
+	default:
+		return fmt.Errorf("unknown mode %q", text)
+	}
+	return nil
+}
+
+// envList implements ValueMarshaler and ValueUnmarshaler,
+// storing every key value as a map entry.
+type envList map[string]string
+
+func (l envList) MarshalSystemdValue() ([]string, error) {
+	var values []string
+	for k, v := range l {
+		values = append(values, k+"="+v)
+	}
+	sort.Strings(values)
+	return values, nil
+}
+
+func (l *envList) UnmarshalSystemdValue(values []string) error {
+	*l = envList{}
+	for _, value := range values {
+		if value == "" {
+			*l = envList{}
+			continue
+		}
+		parts := strings.SplitN(value, "=", 2)
+		if len(parts) != 2 {
+			return fmt.Errorf("missing = in %q", value)
+		}
+		(*l)[parts[0]] = parts[1]
+	}
+	return nil
+}
+
+type codecSection struct {
+	Address     net.IP
+	Gateways    []net.IP `systemd:"Gateway"`
+	DNS         *net.IP  `systemd:",omitempty"`
+	MACAddress  net.HardwareAddr
+	Mode        mode
+	ModePtr     *mode `systemd:",omitempty"`
+	Environment envList
+}
+
+type codecFile struct {
+	Codec codecSection
+}
+
+func TestUnmarshalCodecs(t *testing.T) {
+	f := &codecFile{}
+	err := Unmarshal([]byte(`[Codec]
+Address=10.0.0.1
+Gateway=10.0.0.254
+Gateway=fe80::1
+MACAddress=01:23:45:67:89:ab
+Mode=passive
+Environment=A=1
+Environment=
+Environment=B=2
+Environment=C=3
+`), f)
+	require.NoError(t, err)
+
+	assert.Equal(t, &codecFile{
+		Codec: codecSection{
+			Address:     net.ParseIP("10.0.0.1"),
+			Gateways:    []net.IP{net.ParseIP("10.0.0.254"), net.ParseIP("fe80::1")},
+			MACAddress:  net.HardwareAddr{0x01, 0x23, 0x45, 0x67, 0x89, 0xab},
+			Mode:        modePassive,
+			Environment: envList{"B": "2", "C": "3"},
+		},
+	}, f)
+
+	t.Run("errors", func(t *testing.T) {
+		tests := []struct {
+			Name  string
+			Input string
+			Err   string
+		}{
+			{
+				Name:  "text unmarshaler",
+				Input: "[Codec]\nMode=fast\n",
+				Err:   `2:1: cannot unmarshal "fast" of key [Codec] Mode into Go value of type encoding.mode: unknown mode "fast"`,
+			},
+			{
+				Name:  "value unmarshaler",
+				Input: "[Codec]\nEnvironment=A\n",
+				Err:   `2:1: cannot unmarshal "A" of key [Codec] Environment into Go value of type encoding.envList: missing = in "A"`,
+			},
+			{
+				Name:  "value unmarshaler with later error",
+				Input: "[Codec]\nEnvironment=A=1\nEnvironment=\nEnvironment=B\n",
+				Err:   `4:1: cannot unmarshal "B" of key [Codec] Environment into Go value of type encoding.envList: missing = in "B"`,
+			},
+			{
+				Name:  "hardware address",
+				Input: "[Codec]\nMACAddress=xx\n",
+				Err:   `2:1: cannot unmarshal "xx" of key [Codec] MACAddress into Go value of type net.HardwareAddr: address xx: invalid MAC address`,
+			},
+		}
+
+		for _, test := range tests {
+			t.Run(test.Name, func(t *testing.T) {
+				err := Unmarshal([]byte(test.Input), &codecFile{})
+				assert.EqualError(t, err, test.Err)
+			})
+		}
+	})
+}
+
+// upperName implements encoding.TextMarshaler, but not encoding.TextUnmarshaler.
+type upperName string
+
+func (n upperName) MarshalText() ([]byte, error) {
+	return []byte(strings.ToUpper(string(n))), nil
+}
+
+func TestUnmarshalMarshalOnly(t *testing.T) {
+	type section struct {
+		Name  upperName
+		Names []upperName `systemd:"Alias"`
+	}
+	type file struct {
+		Link section
+	}
+
+	b, err := Marshal(&file{Link: section{Name: "eth0"}})
+	require.NoError(t, err)
+	assert.Equal(t, "[Link]\nName=ETH0\n", string(b))
+
+	// the text format is unknown, so the value is not decoded as a string
+	f := &file{Link: section{Name: "unchanged"}}
+	err = Unmarshal(b, f)
+	assert.EqualError(t, err, `2:1: cannot unmarshal "ETH0" of key [Link] Name into Go value of type encoding.upperName: `+
+		`type implements encoding.TextMarshaler, but not encoding.TextUnmarshaler`)
+	var typeErr *UnmarshalTypeError
+	assert.True(t, errors.As(err, &typeErr))
+
+	err = Unmarshal([]byte("[Link]\nAlias=a\n"), f)
+	assert.True(t, errors.Is(err, errNoTextUnmarshaler))
+}
+
+type assignSection struct {
+	Append   []string `systemd:",append"`
+	Reset    []string `systemd:",reset"`
+	LastWins []string `systemd:",lastwins"`
+	First    []string `systemd:",first"`
+
+	AppendString string  `systemd:"AppendString,append"`
+	FirstString  string  `systemd:"FirstString,first"`
+	ResetPtr     *string `systemd:"ResetPtr,reset"`
+}
+
+type assignFile struct {
+	Section assignSection
+}
+
+func TestUnmarshalAssignModes(t *testing.T) {
+	tests := []struct {
+		Name  string
+		Input string
+		Out   assignSection
+	}{
+		{
+			Name: "append ignores resets",
+			Input: `[Section]
+Append=a
+Append=
+Append=b
+AppendString=x
+AppendString=
+`,
+			Out: assignSection{Append: []string{"a", "b"}, AppendString: "x"},
+		},
+		{
+			Name: "reset",
+			Input: `[Section]
+Reset=a
+Reset=
+Reset=b
+ResetPtr=x
+ResetPtr=
+`,
+			Out: assignSection{Reset: []string{"b"}},
+		},
+		{
+			Name:  "trailing reset",
+			Input: "[Section]\nReset=a\nReset=\n",
+			Out:   assignSection{Reset: []string{}},
+		},
+		{
+			Name: "lastwins",
+			Input: `[Section]
+LastWins=a b
+LastWins=c  d
+`,
+			Out: assignSection{LastWins: []string{"c", "d"}},
+		},
+		{
+			Name: "first",
+			Input: `[Section]
+First=a b
+First=c
+FirstString=x
+FirstString=y
+`,
+			Out: assignSection{First: []string{"a", "b"}, FirstString: "x"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			f := &assignFile{}
+			require.NoError(t, Unmarshal([]byte(test.Input), f))
+			assert.Equal(t, test.Out, f.Section)
+		})
+	}
+}
@@ -0,0 +1,206 @@
+package encoding
+
+import (
+	"encoding"
+	"errors"
+	"net"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+// ValueMarshaler is the interface implemented by types that
+// can marshal themselves into the values of one or more keys.
+// Every returned value is written as a separate key.
+type ValueMarshaler interface {
+	MarshalSystemdValue() ([]string, error)
+}
+
+// ValueUnmarshaler is the interface implemented by types that
+// can unmarshal themselves from the values of all keys with the field's name.
+// Values are passed in file order, including empty values,
+// so implementations are in control of list resets.
+// Errors are reported at the position of the last key.
+type ValueUnmarshaler interface {
+	UnmarshalSystemdValue(values []string) error
+}
+
+var (
+	durationType        = reflect.TypeOf(time.Duration(0))
+	hardwareAddrType    = reflect.TypeOf(net.HardwareAddr(nil))
+	textMarshalerType   = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+
+	valueMarshalerType   = reflect.TypeOf((*ValueMarshaler)(nil)).Elem()
+	valueUnmarshalerType = reflect.TypeOf((*ValueUnmarshaler)(nil)).Elem()
+)
+
+// errNoTextUnmarshaler is returned when decoding a value into a type
+// that implements encoding.TextMarshaler, but not encoding.TextUnmarshaler.
+var errNoTextUnmarshaler = errors.New("type implements encoding.TextMarshaler, but not encoding.TextUnmarshaler")
+
+// isScalar returns true for all types that are stored in a single key value.
+func isScalar(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr {
+		// pointers are handled by the caller
+		return false
+	}
+	if t == durationType || t == hardwareAddrType {
+		return true
+	}
+	ptr := reflect.PtrTo(t)
+	if ptr.Implements(textMarshalerType) ||
+		ptr.Implements(textUnmarshalerType) {
+		return true
+	}
+
+	switch t.Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// asValueMarshaler returns the ValueMarshaler implementation of rv or a pointer to rv.
+func asValueMarshaler(rv reflect.Value) (ValueMarshaler, bool) {
+	if !rv.CanInterface() ||
+		rv.Kind() == reflect.Ptr && rv.IsNil() {
+		return nil, false
+	}
+	if m, ok := rv.Interface().(ValueMarshaler); ok {
+		return m, true
+	}
+	if rv.CanAddr() {
+		m, ok := rv.Addr().Interface().(ValueMarshaler)
+		return m, ok
+	}
+	return nil, false
+}
+
+// asValueUnmarshaler returns the ValueUnmarshaler implementation of rv or a pointer to rv.
+// Nil pointers are allocated, so rv has to be settable.
+func asValueUnmarshaler(rv reflect.Value) (ValueUnmarshaler, bool) {
+	if !rv.CanSet() {
+		return nil, false
+	}
+	if rv.Kind() == reflect.Ptr && rv.Type().Implements(valueUnmarshalerType) {
+		if rv.IsNil() {
+			rv.Set(reflect.New(rv.Type().Elem()))
+		}
+		return rv.Interface().(ValueUnmarshaler), true
+	}
+	m, ok := rv.Addr().Interface().(ValueUnmarshaler)
+	return m, ok
+}
+
+// unmarshalValue parses value and stores the result in rv.
+// rv has to be settable and of a scalar type.
+// Types implementing only encoding.TextMarshaler cannot be decoded,
+// as their text format is unknown.
+func unmarshalValue(value string, rv reflect.Value) error {
+	if u, ok := rv.Addr().Interface().(encoding.TextUnmarshaler); ok {
+		return u.UnmarshalText([]byte(value))
+	}
+	if rv.Addr().Type().Implements(textMarshalerType) {
+		return errNoTextUnmarshaler
+	}
+
+	switch rv.Type() {
+	case durationType:
+		d, err := ParseTimespan(value)
+		if err != nil {
+			return err
+		}
+		rv.SetInt(int64(d))
+		return nil
+
+	case hardwareAddrType:
+		mac, err := net.ParseMAC(value)
+		if err != nil {
+			return err
+		}
+		rv.SetBytes(mac)
+		return nil
+	}
+
+	switch rv.Kind() {
+	case reflect.String:
+		rv.SetString(value)
+
+	case reflect.Bool:
+		b := StrToBool(value)
+		if b == nil {
+			return strconv.ErrSyntax
+		}
+		rv.SetBool(*b)
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(value, 10, rv.Type().Bits())
+		if err != nil {
+			return err.(*strconv.NumError).Err
+		}
+		rv.SetInt(i)
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(value, 10, rv.Type().Bits())
+		if err != nil {
+			return err.(*strconv.NumError).Err
+		}
+		rv.SetUint(u)
+
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(value, rv.Type().Bits())
+		if err != nil {
+			return err.(*strconv.NumError).Err
+		}
+		rv.SetFloat(f)
+	}
+	return nil
+}
+
+// marshalValue returns the string representation of rv.
+// rv has to be of a scalar type.
+func marshalValue(rv reflect.Value) (string, error) {
+	if rv.CanInterface() {
+		m, ok := rv.Interface().(encoding.TextMarshaler)
+		if !ok && rv.CanAddr() {
+			m, ok = rv.Addr().Interface().(encoding.TextMarshaler)
+		}
+		if ok {
+			b, err := m.MarshalText()
+			if err != nil {
+				return "", &MarshalerError{Type: rv.Type(), Err: err}
+			}
+			return string(b), nil
+		}
+	}
+
+	switch rv.Type() {
+	case durationType:
+		return FormatTimespan(time.Duration(rv.Int())), nil
+
+	case hardwareAddrType:
+		return net.HardwareAddr(rv.Bytes()).String(), nil
+	}
+
+	switch rv.Kind() {
+	case reflect.String:
+		return rv.String(), nil
+
+	case reflect.Bool:
+		return BoolToStr(rv.Bool()), nil
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(rv.Int(), 10), nil
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(rv.Uint(), 10), nil
+
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(rv.Float(), 'g', -1, rv.Type().Bits()), nil
+	}
+	return "", nil
+}
@@ -0,0 +1,82 @@
+package encoding
+
+import (
+	"bytes"
+	"strconv"
+)
+
+// MarshalForVersion returns the systemd encoding of v like Marshal,
+// targeting the systemd version.
+//
+// Sections and keys are annotated with the version adding them by the since option
+// of their tag, like `systemd:"RouteTable,omitempty,since=247"`.
+// If a section or key that is added after version is set, it is written
+// under its legacy name, given by the legacy option like `systemd:",since=243,legacy=Id"`.
+// Without a legacy name, a *VersionError is returned.
+//
+// The deprecated option, like `systemd:",deprecated=250"`, records the version
+// deprecating a section or key. Deprecated names are still understood by systemd
+// and written as they are.
+//
+// Sections and keys without the since option are assumed to exist in every version
+// and are not checked. The structs of the network, netdev and link packages
+// are not annotated exhaustively, mainly sections and keys added in systemd 245
+// and later are, so a nil error does not guarantee that the targeted version
+// understands every key.
+func MarshalForVersion(v interface{}, version int) ([]byte, error) {
+	var out bytes.Buffer
+	if err := (EncodeOptions{Version: version}).NewEncoder(&out).Encode(v); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+// A VersionError describes a section or key which is not supported
+// by the systemd version targeted by MarshalForVersion.
+type VersionError struct {
+	Section string // name of the section
+	Key     string // name of the key, empty for sections
+	Since   int    // version adding the section or key
+	Version int    // targeted version
+}
+
+func (e *VersionError) Error() string {
+	name := "section [" + e.Section + "]"
+	if e.Key != "" {
+		name = "key " + e.Key + " in section [" + e.Section + "]"
+	}
+	return "systemd: " + name + " requires systemd " + strconv.Itoa(e.Since) +
+		", targeting " + strconv.Itoa(e.Version)
+}
+
+// supported reports whether the section or key of the field exists in the systemd version.
+// Version 0 targets the latest version.
+func (c fieldConfig) supported(version int) bool {
+	return version == 0 || c.Since <= version
+}
+
+// sectionForVersion renames a section added after version to its legacy name.
+func sectionForVersion(section *Section, c fieldConfig, version int) error {
+	if c.supported(version) {
+		return nil
+	}
+	if c.Legacy == "" {
+		return &VersionError{Section: section.Name, Since: c.Since, Version: version}
+	}
+	section.Name = c.Legacy
+	return nil
+}
+
+// keysForVersion renames the keys of a field added after version to its legacy name.
+func keysForVersion(section string, keys []Key, c fieldConfig, version int) error {
+	if len(keys) == 0 || c.supported(version) {
+		return nil
+	}
+	if c.Legacy == "" {
+		return &VersionError{Section: section, Key: c.Name, Since: c.Since, Version: version}
+	}
+	for i := range keys {
+		keys[i].Name = c.Legacy
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package encoding
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type versionFile struct {
+	Tunnel  *versionTunnelSection
+	Bridges []versionBridgeSection `systemd:"Bridge,since=250"`
+	Renamed *versionBridgeSection  `systemd:"DHCPPrefixDelegation,since=250,legacy=DHCPv6PrefixDelegation"`
+}
+
+type versionTunnelSection struct {
+	Name       string `systemd:",omitempty"`
+	VNI        string `systemd:",omitempty,since=243,legacy=Id"`
+	RouteTable string `systemd:",omitempty,since=247"`
+	Old        string `systemd:",omitempty,deprecated=240"`
+}
+
+type versionBridgeSection struct {
+	STP *bool `systemd:",omitempty"`
+}
+
+func TestConfigForFieldVersions(t *testing.T) {
+	typ := reflect.TypeOf(versionTunnelSection{})
+	field, _ := typ.FieldByName("VNI")
+	assert.Equal(t, fieldConfig{Name: "VNI", Omitempty: true, Since: 243, Legacy: "Id"}, configForField(field))
+	field, _ = typ.FieldByName("Old")
+	assert.Equal(t, fieldConfig{Name: "Old", Omitempty: true, Deprecated: 240}, configForField(field))
+}
+
+func TestMarshalForVersion(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Version  int
+		File     versionFile
+		Expected string
+		Err      *VersionError
+	}{
+		{
+			Name:     "latest",
+			File:     versionFile{Tunnel: &versionTunnelSection{VNI: "10", RouteTable: "100", Old: "x"}},
+			Expected: "[Tunnel]\nVNI=10\nRouteTable=100\nOld=x\n",
+		},
+		{
+			Name:     "supported",
+			Version:  247,
+			File:     versionFile{Tunnel: &versionTunnelSection{VNI: "10", RouteTable: "100"}},
+			Expected: "[Tunnel]\nVNI=10\nRouteTable=100\n",
+		},
+		{
+			Name:     "unset keys are not checked",
+			Version:  240,
+			File:     versionFile{Tunnel: &versionTunnelSection{Name: "tun0"}},
+			Expected: "[Tunnel]\nName=tun0\n",
+		},
+		{
+			Name:     "legacy key",
+			Version:  242,
+			File:     versionFile{Tunnel: &versionTunnelSection{VNI: "10"}},
+			Expected: "[Tunnel]\nId=10\n",
+		},
+		{
+			Name:    "unsupported key",
+			Version: 245,
+			File:    versionFile{Tunnel: &versionTunnelSection{RouteTable: "100"}},
+			Err:     &VersionError{Section: "Tunnel", Key: "RouteTable", Since: 247, Version: 245},
+		},
+		{
+			Name:     "legacy section",
+			Version:  249,
+			File:     versionFile{Renamed: &versionBridgeSection{STP: BoolPtr(true)}},
+			Expected: "[DHCPv6PrefixDelegation]\nSTP=yes\n",
+		},
+		{
+			Name:    "unsupported section",
+			Version: 249,
+			File:    versionFile{Bridges: []versionBridgeSection{{}}},
+			Err:     &VersionError{Section: "Bridge", Since: 250, Version: 249},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			out, err := MarshalForVersion(&test.File, test.Version)
+			if test.Err != nil {
+				assert.Equal(t, test.Err, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.Expected, string(out))
+		})
+	}
+}
+
+func TestVersionError(t *testing.T) {
+	assert.EqualError(t, &VersionError{Section: "IPv6AcceptRA", Key: "RouteTable", Since: 247, Version: 245},
+		"systemd: key RouteTable in section [IPv6AcceptRA] requires systemd 247, targeting 245")
+	assert.EqualError(t, &VersionError{Section: "SR-IOV", Since: 246, Version: 245},
+		"systemd: section [SR-IOV] requires systemd 246, targeting 245")
+}
@@ -0,0 +1,86 @@
+package encoding
+
+import (
+	"encoding/json"
+
+	"gopkg.in/yaml.v3"
+)
+
+// MarshalYAML returns the YAML encoding of v,
+// using the same representation as MarshalJSON.
+func MarshalYAML(v interface{}) ([]byte, error) {
+	node, err := MarshalYAMLNode(v)
+	if err != nil {
+		return nil, err
+	}
+	return yaml.Marshal(node)
+}
+
+// UnmarshalYAML parses YAML data in the format written by MarshalYAML
+// and stores the result in the value pointed to by v.
+func UnmarshalYAML(data []byte, v interface{}) error {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+	if len(doc.Content) == 0 {
+		// empty document
+		return UnmarshalJSON([]byte("{}"), v)
+	}
+	return UnmarshalYAMLNode(doc.Content[0], v)
+}
+
+// MarshalYAMLNode returns the YAML representation of v as a node,
+// to implement yaml.Marshaler.
+func MarshalYAMLNode(v interface{}) (*yaml.Node, error) {
+	data, err := MarshalJSON(v)
+	if err != nil {
+		return nil, err
+	}
+	// JSON is valid YAML, decoding it into a node keeps the order of members
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	node := doc.Content[0]
+	resetStyle(node)
+	return node, nil
+}
+
+// UnmarshalYAMLNode stores the contents of node in the value pointed to by v,
+// to implement yaml.Unmarshaler.
+func UnmarshalYAMLNode(node *yaml.Node, v interface{}) error {
+	var raw interface{}
+	if err := node.Decode(&raw); err != nil {
+		return err
+	}
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return err
+	}
+	return UnmarshalJSON(data, v)
+}
+
+// MarshalYAML implements yaml.Marshaler.
+func (f *File) MarshalYAML() (interface{}, error) {
+	return f.toJSON(), nil
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler.
+func (f *File) UnmarshalYAML(node *yaml.Node) error {
+	var j fileJSON
+	if err := node.Decode(&j); err != nil {
+		return err
+	}
+	f.fromJSON(j)
+	return nil
+}
+
+// resetStyle switches node and its children from the JSON flow style to the YAML block style.
+// Strings that would be read as another type are quoted by the encoder.
+func resetStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		resetStyle(child)
+	}
+}
@@ -0,0 +1,96 @@
+package encoding
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestMarshalYAML(t *testing.T) {
+	var f testFile
+	require.NoError(t, Unmarshal([]byte(jsonTestInput), &f))
+
+	data, err := MarshalYAML(&f)
+	require.NoError(t, err)
+	assert.Equal(t, `Match:
+    Name: eth*
+    MACAddress:
+        - 01:23:45:67:89:ab
+        - 00-11-22-33-44-55
+    keys:
+        - name: Unknown
+          value: value
+    keyComments:
+        Name: some comment
+Network:
+    Address:
+        - 10.10.10.2/24
+        - 10.10.10.3/24
+Route:
+    - Gateway: 10.10.10.1
+      Destination: 10.10.20.1/24
+      Enable: true
+    - Gateway: 10.10.10.1
+      Source: something
+sections:
+    - name: Unknown
+      keys:
+        - name: Key
+          value: value
+`, string(data))
+
+	// round trip
+	var got testFile
+	require.NoError(t, UnmarshalYAML(data, &got))
+	b, err := Marshal(&got)
+	require.NoError(t, err)
+	assert.Equal(t, jsonTestInput, string(b))
+}
+
+func TestUnmarshalYAML(t *testing.T) {
+	var f testFile
+	require.NoError(t, UnmarshalYAML([]byte(`
+Match:
+  Name: "10"
+Network:
+  Address: [10.0.0.2/24]
+Route:
+  - Gateway: 10.0.0.1
+    Enable: yes
+`), &f))
+	assert.Equal(t, "10", f.Match.Name)
+	assert.Equal(t, []string{"10.0.0.2/24"}, f.Network.Addresses)
+	require.Len(t, f.Routes, 1)
+	assert.Equal(t, BoolPtr(true), f.Routes[0].Enable)
+
+	require.NoError(t, UnmarshalYAML(nil, &f))
+	assert.EqualError(t, UnmarshalYAML([]byte("Other: {}"), &f), `systemd: unknown JSON section "Other"`)
+}
+
+func TestFileYAML(t *testing.T) {
+	f, err := Decode([]byte("[Match]\nName=eth0\n# key\nType=ether\n; trailing\n"))
+	require.NoError(t, err)
+
+	data, err := yaml.Marshal(f)
+	require.NoError(t, err)
+	assert.Equal(t, `sections:
+    - name: Match
+      keys:
+        - name: Name
+          value: eth0
+        - name: Type
+          value: ether
+          comment: key
+trailingComment: trailing
+`, string(data))
+
+	var got File
+	require.NoError(t, UnmarshalYAML(data, &got))
+	var want, out bytes.Buffer
+	require.NoError(t, Encode(&want, f))
+	require.NoError(t, Encode(&out, &got))
+	assert.Equal(t, want.String(), out.String())
+}
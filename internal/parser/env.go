@@ -0,0 +1,296 @@
+package parser
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// This file implements the env-file dialect of EnvironmentFile= and os-release,
+// following the rules of systemd's parse_env_file_internal().
+// Unlike unit files, values are not split into words and
+// only the escapes of shell double quotes are resolved.
+
+// An EnvAssignment is a variable assignment of an env file.
+type EnvAssignment struct {
+	Name  string
+	Value string
+	// Export is set if the assignment is prefixed by "export", like in shell scripts.
+	Export bool
+	// Comment holds the comment lines in front of the assignment,
+	// without the leading "#" or ";".
+	Comment string
+	Pos     Position // position of the name
+}
+
+// An EnvError describes an invalid line of an env file.
+type EnvError struct {
+	Pos Position
+	Msg string
+}
+
+func (e *EnvError) Error() string {
+	return e.Pos.String() + ": " + e.Msg
+}
+
+// envState is the state of the env-file scanner.
+type envState int
+
+const (
+	envPreName envState = iota
+	envName
+	envPreValue
+	envValue
+	envValueEscape
+	envSingleQuote
+	envDoubleQuote
+	envDoubleQuoteEscape
+	envComment
+	envCommentEscape
+)
+
+// ScanEnv returns the assignments of the env file in src, in order of appearance,
+// and the comment lines following the last assignment.
+//
+// Lines starting with "#" or ";" are comments. Values are taken verbatim,
+// except that whitespace around them is removed, single and double quotes
+// group characters and are removed, and a backslash escapes the next character,
+// or continues the line in front of a newline.
+// Inside double quotes, only the escapes \", \\, \` and \$ are resolved.
+// Names are checked by IsEnvName, after an optional "export" prefix is removed.
+// Lines without "=" are skipped, like systemd does.
+// Lines may end in "\n" or "\r\n".
+func ScanEnv(src []byte, filename string) (assignments []EnvAssignment, trailingComment string, err error) {
+	var (
+		state   = envPreName
+		name    strings.Builder
+		value   strings.Builder
+		comment strings.Builder
+		// comment lines of the next assignment
+		comments []string
+		// length of the value without trailing whitespace
+		valueEnd int
+		pos      = Position{Filename: filename, Line: 1, Column: 1}
+		namePos  Position
+	)
+
+	push := func() error {
+		a := EnvAssignment{
+			Name:    strings.TrimSpace(name.String()),
+			Value:   value.String()[:valueEnd],
+			Comment: strings.Join(comments, "\n"),
+			Pos:     namePos,
+		}
+		if rest := strings.TrimPrefix(a.Name, "export"); rest != a.Name && strings.TrimLeft(rest, " \t") != rest {
+			a.Name, a.Export = strings.TrimLeft(rest, " \t"), true
+		}
+		if !IsEnvName(a.Name) {
+			return &EnvError{Pos: namePos, Msg: "invalid variable name " + Quote(a.Name)}
+		}
+		if !utf8.ValidString(a.Value) {
+			return &EnvError{Pos: namePos, Msg: "invalid UTF-8 in value of " + a.Name}
+		}
+		assignments = append(assignments, a)
+		name.Reset()
+		value.Reset()
+		valueEnd, comments = 0, nil
+		return nil
+	}
+	// appendValue adds c to the value, whitespace is trimmed from the end unless keep is set
+	appendValue := func(c byte, keep bool) {
+		value.WriteByte(c)
+		if keep || !isEnvSpace(c) {
+			valueEnd = value.Len()
+		}
+	}
+
+	for i := 0; i < len(src); i++ {
+		c := src[i]
+		if c == '\r' && i+1 < len(src) && src[i+1] == '\n' {
+			// CRLF is a single line break in every state
+			continue
+		}
+		newline := c == '\n' || c == '\r'
+
+		switch state {
+		case envPreName:
+			switch {
+			case c == '#' || c == ';':
+				state = envComment
+			case c == '=':
+				// reported as invalid name
+				state, namePos = envPreValue, pos
+			case !newline && !isEnvSpace(c):
+				state, namePos = envName, pos
+				name.WriteByte(c)
+			}
+
+		case envName:
+			switch {
+			case newline:
+				// lines without "=" are ignored, like systemd does
+				name.Reset()
+				state = envPreName
+			case c == '=':
+				state = envPreValue
+			default:
+				name.WriteByte(c)
+			}
+
+		case envPreValue:
+			switch {
+			case newline:
+				if err := push(); err != nil {
+					return nil, "", err
+				}
+				state = envPreName
+			case c == '\'':
+				state = envSingleQuote
+			case c == '"':
+				state = envDoubleQuote
+			case c == '\\':
+				state = envValueEscape
+			case !isEnvSpace(c):
+				state = envValue
+				appendValue(c, true)
+			}
+
+		case envValue:
+			switch {
+			case newline:
+				if err := push(); err != nil {
+					return nil, "", err
+				}
+				state = envPreName
+			case c == '\\':
+				state = envValueEscape
+			default:
+				appendValue(c, false)
+			}
+
+		case envValueEscape:
+			state = envValue
+			if !newline {
+				// escaped characters are kept, even whitespace
+				appendValue(c, true)
+			}
+
+		case envSingleQuote:
+			if c == '\'' {
+				state = envPreValue
+				break
+			}
+			appendValue(c, true)
+
+		case envDoubleQuote:
+			switch c {
+			case '"':
+				state = envPreValue
+			case '\\':
+				state = envDoubleQuoteEscape
+			default:
+				appendValue(c, true)
+			}
+
+		case envDoubleQuoteEscape:
+			state = envDoubleQuote
+			switch c {
+			case '"', '\\', '`', '$':
+				appendValue(c, true)
+			case '\n', '\r':
+				// line continuation
+			default:
+				appendValue('\\', true)
+				appendValue(c, true)
+			}
+
+		case envComment:
+			switch {
+			case c == '\\':
+				state = envCommentEscape
+			case newline:
+				comments = append(comments, strings.TrimSpace(comment.String()))
+				comment.Reset()
+				state = envPreName
+			default:
+				comment.WriteByte(c)
+			}
+
+		case envCommentEscape:
+			state = envComment
+			if !newline {
+				comment.WriteByte('\\')
+				comment.WriteByte(c)
+			}
+		}
+
+		if c == '\n' {
+			pos.Line++
+			pos.Column = 1
+		} else {
+			pos.Column++
+		}
+	}
+
+	switch state {
+	case envComment, envCommentEscape:
+		comments = append(comments, strings.TrimSpace(comment.String()))
+	case envPreName, envName:
+	default:
+		// unterminated quotes and escapes end with the file
+		if err := push(); err != nil {
+			return nil, "", err
+		}
+	}
+	return assignments, strings.Join(comments, "\n"), nil
+}
+
+func isEnvSpace(c byte) bool {
+	return c == ' ' || c == '\t'
+}
+
+// IsEnvName reports whether name is a valid environment variable name,
+// consisting of ASCII letters, digits and underscores, not starting with a digit.
+func IsEnvName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		switch {
+		case c == '_', 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z':
+		case '0' <= c && c <= '9' && i > 0:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// QuoteEnv returns value as written in an env file, as understood by ScanEnv.
+// Values with characters other than letters, digits and a few punctuation characters
+// are put in double quotes, escaping \, ", ` and $. Newlines are kept as they are.
+func QuoteEnv(value string) string {
+	safe := true
+	for i := 0; i < len(value) && safe; i++ {
+		c := value[i]
+		safe = 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' ||
+			strings.IndexByte("_-+.,:/@%=^~", c) >= 0
+	}
+	if safe {
+		return value
+	}
+
+	var b strings.Builder
+	b.WriteByte('"')
+	for i := 0; i < len(value); i++ {
+		switch c := value[i]; c {
+		case '"', '\\', '`', '$':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
@@ -0,0 +1,140 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScanEnv(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Src      string
+		Expected []EnvAssignment
+		Trailing string
+	}{
+		{Name: "empty", Src: ""},
+		{
+			Name: "plain",
+			Src:  "A=1\n  B = two words  \nC=\n",
+			Expected: []EnvAssignment{
+				{Name: "A", Value: "1", Pos: Position{Line: 1, Column: 1}},
+				{Name: "B", Value: "two words", Pos: Position{Line: 2, Column: 3}},
+				{Name: "C", Value: "", Pos: Position{Line: 3, Column: 1}},
+			},
+		},
+		{
+			Name: "quotes",
+			Src:  "A='single \\ $x' \nB=\"double \\\" \\\\ \\` \\$ \\n\"\nC='a'\"b\" c\nD=un\"quoted\"\nE=\"multi\nline\"",
+			Expected: []EnvAssignment{
+				{Name: "A", Value: `single \ $x`, Pos: Position{Line: 1, Column: 1}},
+				{Name: "B", Value: "double \" \\ ` $ \\n", Pos: Position{Line: 2, Column: 1}},
+				{Name: "C", Value: "abc", Pos: Position{Line: 3, Column: 1}},
+				{Name: "D", Value: `un"quoted"`, Pos: Position{Line: 4, Column: 1}},
+				{Name: "E", Value: "multi\nline", Pos: Position{Line: 5, Column: 1}},
+			},
+		},
+		{
+			Name: "escapes and continuation",
+			Src:  "A=one\\\ntwo\nB=\\ space\\ \nC=\"con\\\ntinued\"\nD=a\\#b",
+			Expected: []EnvAssignment{
+				{Name: "A", Value: "onetwo", Pos: Position{Line: 1, Column: 1}},
+				{Name: "B", Value: " space ", Pos: Position{Line: 3, Column: 1}},
+				{Name: "C", Value: "continued", Pos: Position{Line: 4, Column: 1}},
+				{Name: "D", Value: "a#b", Pos: Position{Line: 6, Column: 1}},
+			},
+		},
+		{
+			Name: "comments and export",
+			Src:  "# first\n; second\nexport A=1\nB=2 # not a comment\n\texport\tC=3\nexportD=4\n# trailing \\\ncontinued",
+			Expected: []EnvAssignment{
+				{Name: "A", Value: "1", Export: true, Comment: "first\nsecond", Pos: Position{Line: 3, Column: 1}},
+				{Name: "B", Value: "2 # not a comment", Pos: Position{Line: 4, Column: 1}},
+				{Name: "C", Value: "3", Export: true, Pos: Position{Line: 5, Column: 2}},
+				{Name: "exportD", Value: "4", Pos: Position{Line: 6, Column: 1}},
+			},
+			Trailing: "trailing continued",
+		},
+		{
+			Name: "lines without assignment",
+			Src:  "A=1\nno assignment\n# comment\nB=2\n  C",
+			Expected: []EnvAssignment{
+				{Name: "A", Value: "1", Pos: Position{Line: 1, Column: 1}},
+				{Name: "B", Value: "2", Comment: "comment", Pos: Position{Line: 4, Column: 1}},
+			},
+		},
+		{
+			Name: "CRLF",
+			Src:  "A=1\r\nB=\"2\"\r\n",
+			Expected: []EnvAssignment{
+				{Name: "A", Value: "1", Pos: Position{Line: 1, Column: 1}},
+				{Name: "B", Value: "2", Pos: Position{Line: 2, Column: 1}},
+			},
+		},
+		{
+			Name: "CRLF continuation",
+			Src:  "A=one\\\r\ntwo\r\nB=\"con\\\r\ntinued\"\r\nC=\"multi\r\nline\"\r\n# comment \\\r\ncontinued\r\nD='a\r\nb'\r\n",
+			Expected: []EnvAssignment{
+				{Name: "A", Value: "onetwo", Pos: Position{Line: 1, Column: 1}},
+				{Name: "B", Value: "continued", Pos: Position{Line: 3, Column: 1}},
+				{Name: "C", Value: "multi\nline", Pos: Position{Line: 5, Column: 1}},
+				{Name: "D", Value: "a\nb", Comment: "comment continued", Pos: Position{Line: 9, Column: 1}},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			assignments, trailing, err := ScanEnv([]byte(test.Src), "")
+			require.NoError(t, err)
+			assert.Equal(t, test.Expected, assignments)
+			assert.Equal(t, test.Trailing, trailing)
+		})
+	}
+
+	errorTests := []struct {
+		Src string
+		Err string
+	}{
+		{Src: "1A=1", Err: "test.env:1:1: invalid variable name 1A"},
+		{Src: "A B=1", Err: `test.env:1:1: invalid variable name "A B"`},
+		{Src: "=1", Err: `test.env:1:1: invalid variable name ""`},
+		{Src: "A=\xff", Err: "test.env:1:1: invalid UTF-8 in value of A"},
+	}
+	for _, test := range errorTests {
+		_, _, err := ScanEnv([]byte(test.Src), "test.env")
+		assert.IsType(t, &EnvError{}, err, test.Src)
+		assert.EqualError(t, err, test.Err, test.Src)
+	}
+}
+
+func TestQuoteEnv(t *testing.T) {
+	tests := []struct {
+		Value    string
+		Expected string
+	}{
+		{Value: "", Expected: ""},
+		{Value: "plain-value_1.2,3:/@%=^~+", Expected: "plain-value_1.2,3:/@%=^~+"},
+		{Value: "two words", Expected: `"two words"`},
+		{Value: ` leading`, Expected: `" leading"`},
+		{Value: `quote " and 'single'`, Expected: `"quote \" and 'single'"`},
+		{Value: "$HOME `cmd` \\", Expected: "\"\\$HOME \\`cmd\\` \\\\\""},
+		{Value: "multi\nline", Expected: "\"multi\nline\""},
+		{Value: "#hash", Expected: `"#hash"`},
+		{Value: "ünïcödé", Expected: `"ünïcödé"`},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.Expected, QuoteEnv(test.Value), test.Value)
+
+		// round trip
+		assignments, _, err := ScanEnv([]byte("A="+QuoteEnv(test.Value)+"\n"), "")
+		require.NoError(t, err)
+		require.Len(t, assignments, 1)
+		assert.Equal(t, test.Value, assignments[0].Value)
+	}
+
+	assert.True(t, IsEnvName("_A1"))
+	assert.False(t, IsEnvName("A-1"))
+	assert.False(t, IsEnvName(""))
+}
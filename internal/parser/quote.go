@@ -0,0 +1,273 @@
+package parser
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// This file implements the value layer of systemd configuration files,
+// following the rules of systemd's extract_first_word() and cunescape().
+// Specifiers (%n, %i, ...) are not interpreted and passed through verbatim,
+// so they can be expanded after splitting.
+
+// EndsWithContinuation reports whether the line ends in an unescaped backslash,
+// which joins it with the following line.
+func EndsWithContinuation(line string) bool {
+	line = strings.TrimSuffix(line, "\r")
+	n := 0
+	for i := len(line) - 1; i >= 0 && line[i] == '\\'; i-- {
+		n++
+	}
+	return n%2 == 1
+}
+
+// TrimContinuation replaces the trailing continuation backslash of the line with a space.
+// It returns the line unchanged if it does not end in a continuation.
+func TrimContinuation(line string) string {
+	if !EndsWithContinuation(line) {
+		return line
+	}
+	line = strings.TrimSuffix(line, "\r")
+	return line[:len(line)-1] + " "
+}
+
+// SplitWords splits a value into whitespace separated words.
+// Single and double quotes group words and are removed,
+// C-style escapes are resolved inside and outside of quotes.
+func SplitWords(s string) ([]string, error) {
+	var (
+		words  []string
+		word   strings.Builder
+		inWord bool
+		quote  rune // active quote character, 0 if unquoted
+	)
+
+	for i := 0; i < len(s); {
+		r, w := utf8.DecodeRuneInString(s[i:])
+		switch {
+		case r == '\\':
+			u, n, err := unescapeOne(s[i+1:])
+			if err != nil {
+				return nil, fmt.Errorf("offset %d: %w", i, err)
+			}
+			word.WriteString(u)
+			inWord = true
+			i += 1 + n
+			continue
+
+		case quote != 0 && r == quote:
+			quote = 0
+
+		case quote != 0:
+			word.WriteRune(r)
+
+		case r == '\'' || r == '"':
+			quote = r
+			inWord = true
+
+		case isSeparator(r):
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+
+		default:
+			word.WriteRune(r)
+			inWord = true
+		}
+		i += w
+	}
+
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated quote %q", quote)
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words, nil
+}
+
+// JoinWords is the inverse of SplitWords,
+// quoting each word as needed.
+func JoinWords(words []string) string {
+	quoted := make([]string, len(words))
+	for i, word := range words {
+		quoted[i] = Quote(word)
+	}
+	return strings.Join(quoted, " ")
+}
+
+// Unquote returns the single word contained in s,
+// with quotes removed and escapes resolved.
+func Unquote(s string) (string, error) {
+	words, err := SplitWords(s)
+	if err != nil {
+		return "", err
+	}
+	switch len(words) {
+	case 0:
+		return "", nil
+	case 1:
+		return words[0], nil
+	}
+	return "", fmt.Errorf("expected a single word, found %d", len(words))
+}
+
+// Quote returns s as a single word, as understood by SplitWords.
+// Strings without special characters are returned as is.
+func Quote(s string) string {
+	if s != "" && !strings.ContainsAny(s, " \t\n\r\v\f\"'\\") && isPrintable(s) {
+		return s
+	}
+	return `"` + Escape(s) + `"`
+}
+
+// Escape returns s with backslashes, quotes and
+// non-printable characters replaced by C-style escapes.
+func Escape(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); {
+		r, w := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && w == 1 {
+			fmt.Fprintf(&b, `\x%02x`, s[i])
+			i++
+			continue
+		}
+		i += w
+
+		switch r {
+		case '\\':
+			b.WriteString(`\\`)
+		case '"':
+			b.WriteString(`\"`)
+		case '\a':
+			b.WriteString(`\a`)
+		case '\b':
+			b.WriteString(`\b`)
+		case '\f':
+			b.WriteString(`\f`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		case '\v':
+			b.WriteString(`\v`)
+		default:
+			switch {
+			case r < 0x20, r == 0x7f:
+				fmt.Fprintf(&b, `\x%02x`, r)
+			case !unicode.IsPrint(r) && r <= 0xffff:
+				fmt.Fprintf(&b, `\u%04x`, r)
+			case !unicode.IsPrint(r):
+				fmt.Fprintf(&b, `\U%08x`, r)
+			default:
+				b.WriteRune(r)
+			}
+		}
+	}
+	return b.String()
+}
+
+// Unescape resolves all C-style escapes in s.
+// Quotes are not interpreted.
+func Unescape(s string) (string, error) {
+	var b strings.Builder
+	for i := 0; i < len(s); {
+		if s[i] != '\\' {
+			b.WriteByte(s[i])
+			i++
+			continue
+		}
+		u, n, err := unescapeOne(s[i+1:])
+		if err != nil {
+			return "", fmt.Errorf("offset %d: %w", i, err)
+		}
+		b.WriteString(u)
+		i += 1 + n
+	}
+	return b.String(), nil
+}
+
+// unescapeOne resolves a single escape sequence,
+// s starts after the backslash.
+// Returns the resolved string and the number of bytes consumed.
+func unescapeOne(s string) (string, int, error) {
+	if s == "" {
+		return "", 0, fmt.Errorf("trailing backslash")
+	}
+
+	switch s[0] {
+	case 'a':
+		return "\a", 1, nil
+	case 'b':
+		return "\b", 1, nil
+	case 'f':
+		return "\f", 1, nil
+	case 'n':
+		return "\n", 1, nil
+	case 'r':
+		return "\r", 1, nil
+	case 't':
+		return "\t", 1, nil
+	case 'v':
+		return "\v", 1, nil
+	case 's':
+		return " ", 1, nil
+	case '\\', '"', '\'':
+		return s[:1], 1, nil
+
+	case 'x':
+		return unescapeCode(s, 2, 16)
+	case 'u':
+		return unescapeCode(s, 4, 16)
+	case 'U':
+		return unescapeCode(s, 8, 16)
+	case '0', '1', '2', '3', '4', '5', '6', '7':
+		return unescapeCode(s, 3, 8)
+	}
+	return "", 0, fmt.Errorf("invalid escape sequence %q", "\\"+s[:1])
+}
+
+// unescapeCode resolves numeric escapes like \xNN, \uNNNN or \NNN.
+func unescapeCode(s string, digits, base int) (string, int, error) {
+	offs := 1
+	if base == 8 {
+		// octal escapes have no prefix character
+		offs = 0
+	}
+	if len(s) < offs+digits {
+		return "", 0, fmt.Errorf("invalid escape sequence %q", "\\"+s)
+	}
+	code := s[offs : offs+digits]
+	n, err := strconv.ParseUint(code, base, 32)
+	if err != nil || n == 0 || n > unicode.MaxRune {
+		return "", 0, fmt.Errorf("invalid escape sequence %q", "\\"+s[:offs+digits])
+	}
+	if base == 16 && digits == 2 || base == 8 {
+		// \xNN and \NNN produce raw bytes
+		if n > 0xff {
+			return "", 0, fmt.Errorf("invalid escape sequence %q", "\\"+s[:offs+digits])
+		}
+		return string([]byte{byte(n)}), offs + digits, nil
+	}
+	return string(rune(n)), offs + digits, nil
+}
+
+func isSeparator(r rune) bool {
+	return r == ' ' || r == '\t' || r == '\n' || r == '\r'
+}
+
+func isPrintable(s string) bool {
+	for _, r := range s {
+		if !unicode.IsPrint(r) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,101 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitWords(t *testing.T) {
+	tests := []struct {
+		Name  string
+		Input string
+		Words []string
+	}{
+		{Name: "empty", Input: "  "},
+		{Name: "plain", Input: "a b\tc", Words: []string{"a", "b", "c"}},
+		{
+			Name:  "double quotes",
+			Input: `/bin/sh -c "echo \"a=b\""`,
+			Words: []string{"/bin/sh", "-c", `echo "a=b"`},
+		},
+		{
+			Name:  "single quotes",
+			Input: `'a b' 'it"s'`,
+			Words: []string{"a b", `it"s`},
+		},
+		{
+			Name:  "quotes within word",
+			Input: `--opt="a b"c ""`,
+			Words: []string{"--opt=a bc", ""},
+		},
+		{
+			Name:  "c escapes",
+			Input: `a\x20b ä \101 \n\t\s \\`,
+			Words: []string{"a b", "ä", "A", "\n\t ", `\`},
+		},
+		{
+			Name:  "specifiers",
+			Input: `%i "%n.service" %%`,
+			Words: []string{"%i", "%n.service", "%%"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			words, err := SplitWords(test.Input)
+			require.NoError(t, err)
+			assert.Equal(t, test.Words, words)
+		})
+	}
+
+	for _, input := range []string{`"abc`, `abc\`, `\q`, `\x2`, `\x00`} {
+		t.Run("invalid "+input, func(t *testing.T) {
+			_, err := SplitWords(input)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestQuote(t *testing.T) {
+	tests := []struct {
+		Input  string
+		Quoted string
+	}{
+		{Input: "abc", Quoted: "abc"},
+		{Input: "", Quoted: `""`},
+		{Input: "a b", Quoted: `"a b"`},
+		{Input: `echo "a=b"`, Quoted: `"echo \"a=b\""`},
+		{Input: "tab\there", Quoted: `"tab\there"`},
+		{Input: "\x01\xff", Quoted: `"\x01\xff"`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Input, func(t *testing.T) {
+			assert.Equal(t, test.Quoted, Quote(test.Input))
+
+			unquoted, err := Unquote(test.Quoted)
+			require.NoError(t, err)
+			assert.Equal(t, test.Input, unquoted)
+		})
+	}
+
+	t.Run("JoinWords", func(t *testing.T) {
+		words := []string{"/bin/sh", "-c", `echo "a b"`}
+		split, err := SplitWords(JoinWords(words))
+		require.NoError(t, err)
+		assert.Equal(t, words, split)
+	})
+}
+
+func TestContinuation(t *testing.T) {
+	assert.True(t, EndsWithContinuation(`a \`))
+	assert.True(t, EndsWithContinuation("a \\\r"))
+	assert.False(t, EndsWithContinuation(`a \\`))
+	assert.True(t, EndsWithContinuation(`a \\\`))
+	assert.False(t, EndsWithContinuation(`a \ `))
+
+	assert.Equal(t, "a  ", TrimContinuation(`a \`))
+	assert.Equal(t, `a \\`, TrimContinuation(`a \\`))
+}
@@ -1,39 +1,77 @@
 package parser
 
 import (
+	"io"
 	"unicode"
 	"unicode/utf8"
 )
 
+// readChunkSize is the number of bytes read at once when scanning from an io.Reader.
+const readChunkSize = 4096
+
 // An ErrorHandler may be provided to Scanner.Init.
 type ErrorHandler func(pos Position, msg string)
 
 // Scanner implements a scanner for systemd unit files.
-// It takes a []byte as source which can then be tokenized
+// It takes a []byte or io.Reader as source which can then be tokenized
 // through repeated calls to the Scan method.
 type Scanner struct {
-	pos Position
-	src []byte
-	err ErrorHandler
+	pos  Position
+	src  []byte
+	r    io.Reader // source to read from, nil when src is complete
+	rerr error     // error returned by r
+	err  ErrorHandler
 
 	// scanning state
 	ch       rune // current character
 	offset   int  // character offset
 	rdOffset int  // reading offset (position after current character)
 
+	// line state
+	lineStart bool // no token scanned on the current line yet
+	inKey     bool // scanning a key, the next = starts the value
+	inValue   bool // the rest of the line is a value
+	continued bool // the last value line ended in a backslash
+
 	ErrorCount int // number of errors encountered
 }
 
+// Init prepares the scanner to tokenize src.
 func (s *Scanner) Init(src []byte, err ErrorHandler) {
 	s.src = src
+	s.r = nil
+	s.reset(err)
+}
+
+// InitReader prepares the scanner to tokenize the contents of r.
+// Data is read incrementally and released once it has been tokenized.
+func (s *Scanner) InitReader(r io.Reader, err ErrorHandler) {
+	s.src = nil
+	s.r = r
+	s.reset(err)
+}
+
+// Err returns the first non-EOF error returned by the io.Reader given to InitReader.
+func (s *Scanner) Err() error {
+	return s.rerr
+}
+
+func (s *Scanner) reset(err ErrorHandler) {
+	s.rerr = nil
 	s.pos.Line = 1
 	s.pos.Column = 0
 
 	s.ch = ' '
 	s.offset = 0
 	s.rdOffset = 0
+	s.err = err
 	s.ErrorCount = 0
 
+	s.lineStart = true
+	s.inKey = false
+	s.inValue = false
+	s.continued = false
+
 	s.next()
 }
 
@@ -44,8 +82,44 @@ func (s *Scanner) error(msg string) {
 	s.ErrorCount++
 }
 
+// fill reads more data into src.
+// It reports whether new data is available.
+func (s *Scanner) fill() bool {
+	for s.r != nil {
+		if cap(s.src)-len(s.src) < readChunkSize {
+			src := make([]byte, len(s.src), 2*cap(s.src)+readChunkSize)
+			copy(src, s.src)
+			s.src = src
+		}
+
+		n, err := s.r.Read(s.src[len(s.src):cap(s.src)])
+		s.src = s.src[:len(s.src)+n]
+		if err != nil {
+			if err != io.EOF {
+				s.rerr = err
+			}
+			s.r = nil
+		}
+		if n > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// release drops all data in front of the current character.
+func (s *Scanner) release() {
+	if s.r == nil || s.offset == 0 {
+		return
+	}
+	n := copy(s.src, s.src[s.offset:])
+	s.src = s.src[:n]
+	s.rdOffset -= s.offset
+	s.offset = 0
+}
+
 func (s *Scanner) next() {
-	if s.rdOffset >= len(s.src) {
+	if s.rdOffset >= len(s.src) && !s.fill() {
 		// we are at the end of our buffer
 		// -> EOF
 		s.offset = len(s.src)
@@ -67,6 +141,9 @@ func (s *Scanner) next() {
 		s.error("illegal character NUL")
 
 	case r >= utf8.RuneSelf:
+		for !utf8.FullRune(s.src[s.rdOffset:]) && s.fill() {
+			// read the rest of the rune
+		}
 		r, w = utf8.DecodeRune(s.src[s.rdOffset:])
 		if r == utf8.RuneError && w == 1 {
 			s.error("illegal UTF-8 encoding")
@@ -76,24 +153,21 @@ func (s *Scanner) next() {
 	s.rdOffset += w
 }
 
-func (s *Scanner) scanString() string {
-	offs := s.offset - 1
+func (s *Scanner) scanString(offs int) string {
 	for !IsDelimiter(s.ch) && s.ch != -1 {
 		s.next()
 	}
 	return string(s.src[offs:s.offset])
 }
 
-func (s *Scanner) scanComment() string {
-	offs := s.offset - 1
+func (s *Scanner) scanComment(offs int) string {
 	for s.ch != '\n' && s.ch != -1 {
 		s.next()
 	}
 	return string(s.src[offs:s.offset])
 }
 
-func (s *Scanner) scanSection() string {
-	offs := s.offset - 1
+func (s *Scanner) scanSection(offs int) string {
 	for s.ch != ']' && s.ch != '\n' && s.ch != -1 {
 		s.next()
 	}
@@ -103,11 +177,80 @@ func (s *Scanner) scanSection() string {
 	return string(s.src[offs:s.offset])
 }
 
+// scanValue scans the rest of the line.
+func (s *Scanner) scanValue() string {
+	offs := s.offset
+	for s.ch != '\n' && s.ch != -1 {
+		s.next()
+	}
+	return string(s.src[offs:s.offset])
+}
+
+// skipWhitespace skips all whitespace up to the next newline.
+func (s *Scanner) skipWhitespace() {
+	for s.ch != '\n' && unicode.IsSpace(s.ch) {
+		s.next()
+	}
+}
+
+// peekLine returns the first non-whitespace byte of the current line or 0 at EOF.
+func (s *Scanner) peekLine() byte {
+	if s.ch == -1 {
+		return 0
+	}
+	for i := s.offset; ; i++ {
+		if i >= len(s.src) && !s.fill() {
+			return 0
+		}
+		switch s.src[i] {
+		case ' ', '\t', '\r', '\v', '\f':
+			continue
+		}
+		return s.src[i]
+	}
+}
+
+// Scan returns the next token.
+// Values are returned as raw STRING tokens spanning the rest of the line,
+// including any trailing backslash. Lines continuing a value are
+// returned as a single STRING token including their leading whitespace.
 func (s *Scanner) Scan() (pos Position, tok Token, lit string) {
-	pos = s.pos
+	s.release()
+	if s.lineStart && s.continued {
+		switch s.peekLine() {
+		case '#', ';':
+			// comment lines within a continuation are skipped
 
-skip:
+		case '\n', 0:
+			// empty lines end the value
+			s.continued = false
+
+		default:
+			s.lineStart = false
+			pos = s.pos
+			tok = STRING
+			lit = s.scanValue()
+			s.continued = EndsWithContinuation(lit)
+			return
+		}
+	}
+
+	if s.inValue {
+		s.skipWhitespace()
+		s.inValue = false
+		if s.ch != '\n' && s.ch != -1 {
+			pos = s.pos
+			tok = STRING
+			lit = s.scanValue()
+			s.continued = EndsWithContinuation(lit)
+			return
+		}
+	}
+
+	s.skipWhitespace()
+	pos = s.pos
 	ch := s.ch
+	offs := s.offset
 	s.next()
 
 	switch ch {
@@ -116,26 +259,30 @@ skip:
 
 	case '\n':
 		tok = NEWLINE
+		s.lineStart = true
+		s.inKey = false
+		return
 
 	case '[':
 		tok = SECTION
-		lit = s.scanSection()
+		lit = s.scanSection(offs)
 
 	case '#', ';':
 		tok = COMMENT
-		lit = s.scanComment()
+		lit = s.scanComment(offs)
 
 	case '=':
 		tok = ASSIGN
-
-	default:
-		if unicode.IsSpace(ch) {
-			// skip whitespace
-			goto skip
+		if s.inKey {
+			s.inKey = false
+			s.inValue = true
 		}
 
+	default:
 		tok = STRING
-		lit = s.scanString()
+		lit = s.scanString(offs)
+		s.inKey = s.lineStart
 	}
+	s.lineStart = false
 	return
 }
@@ -1,7 +1,9 @@
 package parser
 
 import (
+	"strings"
 	"testing"
+	"testing/iotest"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -22,13 +24,13 @@ var example1tokens = []tokenEntry{
 	{pos: Position{Line: 1, Column: 10}, tok: NEWLINE},
 	{pos: Position{Line: 2, Column: 1}, tok: STRING, lit: "Description"},
 	{pos: Position{Line: 2, Column: 12}, tok: ASSIGN}, // =
-	{pos: Position{Line: 2, Column: 13}, tok: STRING, lit: "test1 \\"},
+	{pos: Position{Line: 2, Column: 14}, tok: STRING, lit: "test1 \\"},
 	{pos: Position{Line: 2, Column: 21}, tok: NEWLINE},
-	{pos: Position{Line: 3, Column: 1}, tok: COMMENT, lit: "# in the middle"},
+	{pos: Position{Line: 3, Column: 2}, tok: COMMENT, lit: "# in the middle"},
 	{pos: Position{Line: 3, Column: 17}, tok: NEWLINE},
-	{pos: Position{Line: 4, Column: 1}, tok: STRING, lit: "test2 \\"},
+	{pos: Position{Line: 4, Column: 1}, tok: STRING, lit: "\ttest2 \\"},
 	{pos: Position{Line: 4, Column: 9}, tok: NEWLINE},
-	{pos: Position{Line: 5, Column: 1}, tok: STRING, lit: "test3"},
+	{pos: Position{Line: 5, Column: 1}, tok: STRING, lit: "\ttest3"},
 	{pos: Position{Line: 5, Column: 7}, tok: NEWLINE},
 	{pos: Position{Line: 6, Column: 1}, tok: COMMENT, lit: "# address 1"},
 	{pos: Position{Line: 6, Column: 12}, tok: NEWLINE},
@@ -99,21 +101,46 @@ var example3tokens = []tokenEntry{
 	{tok: NEWLINE},
 	{tok: STRING, lit: "Environment"},
 	{tok: ASSIGN}, // =
-	{tok: STRING, lit: "ETCD_CA_FILE"},
-	{tok: ASSIGN}, // =
-	{tok: STRING, lit: "/path/to/CA.pem"},
+	{tok: STRING, lit: "ETCD_CA_FILE=/path/to/CA.pem"},
 	{tok: NEWLINE},
 	{tok: STRING, lit: "Environment"},
 	{tok: ASSIGN}, // =
-	{tok: STRING, lit: "ETCD_CERT_FILE"},
-	{tok: ASSIGN}, // =
-	{tok: STRING, lit: "/path/to/server.crt"},
+	{tok: STRING, lit: "ETCD_CERT_FILE=/path/to/server.crt"},
 	{tok: NEWLINE},
 	{tok: STRING, lit: "Environment"},
 	{tok: ASSIGN}, // =
-	{tok: STRING, lit: "ETCD_KEY_FILE"},
+	{tok: STRING, lit: "ETCD_KEY_FILE=/path/to/server.key"},
+	{tok: EOF},
+}
+
+const example4 = `[Service]
+ExecStart=/bin/sh -c "echo \"a = b\"" # no comment \
+  --flag=x \\
+Name = eth0 \\\
+; skipped
+	[not a section]
+
+[Network]`
+
+var example4tokens = []tokenEntry{
+	{tok: SECTION, lit: "[Service]"},
+	{tok: NEWLINE},
+	{tok: STRING, lit: "ExecStart"},
+	{tok: ASSIGN}, // =
+	{tok: STRING, lit: `/bin/sh -c "echo \"a = b\"" # no comment \`},
+	{tok: NEWLINE},
+	{tok: STRING, lit: `  --flag=x \\`},
+	{tok: NEWLINE},
+	{tok: STRING, lit: "Name "},
 	{tok: ASSIGN}, // =
-	{tok: STRING, lit: "/path/to/server.key"},
+	{tok: STRING, lit: `eth0 \\\`},
+	{tok: NEWLINE},
+	{tok: COMMENT, lit: "; skipped"},
+	{tok: NEWLINE},
+	{tok: STRING, lit: "\t[not a section]"},
+	{tok: NEWLINE},
+	{tok: NEWLINE},
+	{tok: SECTION, lit: "[Network]"},
 	{tok: EOF},
 }
 
@@ -146,15 +173,16 @@ func TestScanner(t *testing.T) {
 			Input:  example3,
 			Tokens: example3tokens,
 		},
+		{
+			Name:   "Example 4",
+			Input:  example4,
+			Tokens: example4tokens,
+		},
 	}
 
 	for _, test := range tests {
-		t.Run(test.Name, func(t *testing.T) {
-			// Init
-			var s Scanner
-			s.Init([]byte(test.Input), nil)
-
-			// Scan
+		test := test
+		scan := func(t *testing.T, s *Scanner) {
 			tokens := []tokenEntry{}
 			for {
 				pos, tok, lit := s.Scan()
@@ -172,6 +200,82 @@ func TestScanner(t *testing.T) {
 				}
 			}
 			assert.Equal(t, test.Tokens, tokens)
+		}
+
+		t.Run(test.Name, func(t *testing.T) {
+			var s Scanner
+			s.Init([]byte(test.Input), nil)
+			scan(t, &s)
+		})
+		t.Run(test.Name+" Reader", func(t *testing.T) {
+			// read one byte at a time to hit all buffer boundaries
+			var s Scanner
+			s.InitReader(iotest.OneByteReader(strings.NewReader(test.Input)), nil)
+			scan(t, &s)
+			assert.NoError(t, s.Err())
 		})
 	}
 }
+
+func TestScannerReader(t *testing.T) {
+	input := "[Netzwerk]\nÜber=größe\n" + strings.Repeat("[Route]\nGateway=10.0.0.1\n", 1000)
+
+	var s Scanner
+	s.InitReader(iotest.HalfReader(strings.NewReader(input)), nil)
+	assert.Equal(t, "[Netzwerk]", lit(s.Scan()))
+	assert.Equal(t, NEWLINE, tok(s.Scan()))
+	assert.Equal(t, "Über", lit(s.Scan()))
+	assert.Equal(t, ASSIGN, tok(s.Scan()))
+	assert.Equal(t, "größe", lit(s.Scan()))
+
+	sections := 0
+	for {
+		_, tk, _ := s.Scan()
+		if tk == EOF {
+			break
+		}
+		if tk == SECTION {
+			sections++
+		}
+		// consumed data is released
+		assert.Less(t, len(s.src), 2*readChunkSize)
+	}
+	assert.Equal(t, 1000, sections)
+	assert.NoError(t, s.Err())
+
+	s.InitReader(iotest.TimeoutReader(strings.NewReader(input)), nil)
+	for {
+		if _, tk, _ := s.Scan(); tk == EOF {
+			break
+		}
+	}
+	assert.Equal(t, iotest.ErrTimeout, s.Err())
+}
+
+func lit(_ Position, _ Token, lit string) string { return lit }
+
+func tok(_ Position, tok Token, _ string) Token { return tok }
+
+func TestScannerErrors(t *testing.T) {
+	type scanError struct {
+		pos Position
+		msg string
+	}
+	var errs []scanError
+
+	var s Scanner
+	s.Init([]byte("[Match]\nName=a\x00b\xffc\n"), func(pos Position, msg string) {
+		errs = append(errs, scanError{pos: pos, msg: msg})
+	})
+	for {
+		if _, tok, _ := s.Scan(); tok == EOF {
+			break
+		}
+	}
+
+	assert.Equal(t, 2, s.ErrorCount)
+	assert.Equal(t, []scanError{
+		{pos: Position{Line: 2, Column: 7}, msg: "illegal character NUL"},
+		{pos: Position{Line: 2, Column: 9}, msg: "illegal UTF-8 encoding"},
+	}, errs)
+}
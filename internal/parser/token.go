@@ -55,6 +55,7 @@ func IsDelimiter(ch rune) bool {
 }
 
 type Position struct {
+	Filename     string // filename, if any
 	Line, Column int
 }
 
@@ -62,17 +63,25 @@ func (pos *Position) IsValid() bool { return pos.Line > 0 }
 
 // String returns a string representation of the position, depending on available information.
 //
+//	file:line:column    valid position with file name
+//	file:line           valid position with file name but no column (column == 0)
 //	line:column         valid position without file name
 //	line                valid position without file name and no column (column == 0)
+//	file                invalid position with file name
 //	-                   invalid position without file name
 func (pos Position) String() string {
-	if !pos.IsValid() {
-		return "-"
+	s := pos.Filename
+	if pos.IsValid() {
+		if s != "" {
+			s += ":"
+		}
+		s += fmt.Sprintf("%d", pos.Line)
+		if pos.Column != 0 {
+			s += fmt.Sprintf(":%d", pos.Column)
+		}
 	}
-
-	s := fmt.Sprintf("%d", pos.Line)
-	if pos.Column != 0 {
-		s += fmt.Sprintf(":%d", pos.Column)
+	if s == "" {
+		s = "-"
 	}
 	return s
 }
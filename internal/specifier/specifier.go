@@ -0,0 +1,472 @@
+// Package specifier expands the specifiers of systemd configuration files,
+// like %n or %H, following the table of systemd.unit(5).
+package specifier
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"routerd.net/go-systemd/internal/parser"
+)
+
+// ErrUnknown is returned for specifiers without a value.
+var ErrUnknown = errors.New("unknown specifier")
+
+// An Error describes a specifier that could not be resolved.
+type Error struct {
+	Specifier rune
+	Err       error
+}
+
+func (e *Error) Error() string {
+	return "specifier %" + string(e.Specifier) + ": " + e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// A Resolver returns the values of specifiers.
+type Resolver interface {
+	// Resolve returns the value of the specifier, or an error wrapping ErrUnknown.
+	Resolve(specifier rune) (string, error)
+}
+
+// ResolverFunc adapts a function to the Resolver interface.
+type ResolverFunc func(specifier rune) (string, error)
+
+// Resolve calls f(specifier).
+func (f ResolverFunc) Resolve(specifier rune) (string, error) {
+	return f(specifier)
+}
+
+// Expand replaces the specifiers in value by their values returned by r.
+// "%%" is replaced by a single "%", a "%" at the end of value is kept as is.
+// Expansion stops at the first specifier that cannot be resolved,
+// which is reported as *Error.
+func Expand(value string, r Resolver) (string, error) {
+	if !strings.Contains(value, "%") {
+		return value, nil
+	}
+
+	var (
+		b       strings.Builder
+		percent bool
+	)
+	for _, c := range value {
+		switch {
+		case !percent && c == '%':
+			percent = true
+
+		case !percent:
+			b.WriteRune(c)
+
+		case c == '%':
+			b.WriteByte('%')
+			percent = false
+
+		default:
+			s, err := r.Resolve(c)
+			if err != nil {
+				return "", &Error{Specifier: c, Err: err}
+			}
+			b.WriteString(s)
+			percent = false
+		}
+	}
+	if percent {
+		b.WriteByte('%')
+	}
+	return b.String(), nil
+}
+
+// Specifiers resolves the specifiers of a unit or network file.
+// Values derived from the unit name are taken from Unit and FragmentPath,
+// values of the host are read from the files below Root,
+// so they can be taken from a container image or a fake tree in tests:
+//
+//	%b  boot ID, from /proc/sys/kernel/random/boot_id
+//	%H  host name, from /proc/sys/kernel/hostname
+//	%l  short host name, the host name up to the first dot
+//	%m  machine ID, from /etc/machine-id
+//	%q  pretty host name, from /etc/machine-info, defaults to the host name
+//	%v  kernel release, from /proc/sys/kernel/osrelease
+//	%o, %w, %W, %B, %M, %A  ID, VERSION_ID, VARIANT_ID, BUILD_ID, IMAGE_ID and IMAGE_VERSION
+//	    from /etc/os-release or /usr/lib/os-release, empty if not set
+//
+// The user and group of the user manager are looked up in /etc/passwd and /etc/group.
+// The zero value resolves the specifiers of the system manager on the running host.
+type Specifiers struct {
+	// Unit is the full unit name, like "getty@tty1.service".
+	Unit string
+
+	// FragmentPath is the path of the unit file.
+	FragmentPath string
+
+	// UserManager resolves the directories and the user of the
+	// user manager, instead of the system manager.
+	UserManager bool
+
+	// Root is the directory the host files are read from, "/" if empty.
+	Root string
+
+	// Architecture is the architecture in the naming of systemd, like "x86-64".
+	// If empty, it is derived from runtime.GOARCH.
+	Architecture string
+
+	// Getenv returns the environment variables of the service manager, os.Getenv if nil.
+	Getenv func(key string) string
+
+	// Getuid returns the user ID of the user manager, os.Getuid if nil.
+	Getuid func() int
+}
+
+// Resolve implements Resolver.
+func (s *Specifiers) Resolve(specifier rune) (string, error) {
+	switch specifier {
+	// unit names
+	case 'n':
+		return s.Unit, nil
+	case 'N':
+		return strings.TrimSuffix(s.Unit, filepath.Ext(s.Unit)), nil
+	case 'p':
+		prefix, _ := s.splitUnit()
+		return prefix, nil
+	case 'P':
+		prefix, _ := s.splitUnit()
+		return unescapeUnitName(prefix), nil
+	case 'i':
+		_, instance := s.splitUnit()
+		return instance, nil
+	case 'I':
+		_, instance := s.splitUnit()
+		return unescapeUnitName(instance), nil
+	case 'j':
+		return s.finalComponent(), nil
+	case 'J':
+		return unescapeUnitName(s.finalComponent()), nil
+	case 'f':
+		prefix, instance := s.splitUnit()
+		if instance != "" {
+			prefix = instance
+		}
+		return "/" + strings.TrimLeft(unescapeUnitName(prefix), "/"), nil
+	case 'y':
+		return s.FragmentPath, nil
+	case 'Y':
+		if s.FragmentPath == "" {
+			return "", nil
+		}
+		return filepath.Dir(s.FragmentPath), nil
+
+	// host
+	case 'a':
+		return s.architecture(), nil
+	case 'b':
+		id, err := s.readFile("/proc/sys/kernel/random/boot_id")
+		return strings.ReplaceAll(id, "-", ""), err
+	case 'H':
+		return s.readFile("/proc/sys/kernel/hostname")
+	case 'l':
+		host, err := s.readFile("/proc/sys/kernel/hostname")
+		if i := strings.IndexByte(host, '.'); i >= 0 {
+			host = host[:i]
+		}
+		return host, err
+	case 'q':
+		info, err := s.readEnvFile("/etc/machine-info")
+		if err != nil && !os.IsNotExist(err) {
+			return "", err
+		}
+		if info["PRETTY_HOSTNAME"] != "" {
+			return info["PRETTY_HOSTNAME"], nil
+		}
+		return s.readFile("/proc/sys/kernel/hostname")
+	case 'm':
+		return s.readFile("/etc/machine-id")
+	case 'v':
+		return s.readFile("/proc/sys/kernel/osrelease")
+	case 'o', 'w', 'W', 'B', 'M', 'A':
+		return s.osRelease(osReleaseKeys[specifier])
+
+	// directories
+	case 'C':
+		return s.userDir("XDG_CACHE_HOME", ".cache", "/var/cache")
+	case 'E':
+		return s.userDir("XDG_CONFIG_HOME", ".config", "/etc")
+	case 'L':
+		dir, err := s.userDir("XDG_STATE_HOME", ".local/state", "/var")
+		if err != nil {
+			return "", err
+		}
+		return dir + "/log", nil
+	case 'S':
+		return s.userDir("XDG_STATE_HOME", ".local/state", "/var/lib")
+	case 't':
+		return s.runtimeDir()
+	case 'T':
+		return s.tmpDir("/tmp"), nil
+	case 'V':
+		return s.tmpDir("/var/tmp"), nil
+	case 'd':
+		if dir := s.getenv("CREDENTIALS_DIRECTORY"); dir != "" {
+			return dir, nil
+		}
+		dir, err := s.runtimeDir()
+		if err != nil {
+			return "", err
+		}
+		return dir + "/credentials/" + s.Unit, nil
+
+	// user
+	case 'u', 'U', 'h', 's', 'g', 'G':
+		return s.user(specifier)
+	}
+	return "", ErrUnknown
+}
+
+// osReleaseKeys maps specifiers to the os-release keys holding their values.
+var osReleaseKeys = map[rune]string{
+	'o': "ID",
+	'w': "VERSION_ID",
+	'W': "VARIANT_ID",
+	'B': "BUILD_ID",
+	'M': "IMAGE_ID",
+	'A': "IMAGE_VERSION",
+}
+
+// architectures maps GOARCH values to the architecture names of systemd.
+var architectures = map[string]string{
+	"386":      "x86",
+	"amd64":    "x86-64",
+	"arm":      "arm",
+	"arm64":    "arm64",
+	"loong64":  "loongarch64",
+	"mips":     "mips",
+	"mipsle":   "mips-le",
+	"mips64":   "mips64",
+	"mips64le": "mips64-le",
+	"ppc64":    "ppc64",
+	"ppc64le":  "ppc64-le",
+	"riscv64":  "riscv64",
+	"s390x":    "s390x",
+}
+
+func (s *Specifiers) architecture() string {
+	if s.Architecture != "" {
+		return s.Architecture
+	}
+	if arch, ok := architectures[runtime.GOARCH]; ok {
+		return arch
+	}
+	return runtime.GOARCH
+}
+
+// splitUnit returns the prefix and instance of the unit name.
+// Units which are no template instances have an empty instance.
+func (s *Specifiers) splitUnit() (prefix, instance string) {
+	name := strings.TrimSuffix(s.Unit, filepath.Ext(s.Unit))
+	if i := strings.IndexByte(name, '@'); i >= 0 {
+		return name[:i], name[i+1:]
+	}
+	return name, ""
+}
+
+// finalComponent returns the part of the prefix after the last dash.
+func (s *Specifiers) finalComponent() string {
+	prefix, _ := s.splitUnit()
+	return prefix[strings.LastIndexByte(prefix, '-')+1:]
+}
+
+// unescapeUnitName reverts the escaping of systemd-escape,
+// replacing dashes by slashes and resolving \x escapes.
+func unescapeUnitName(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '-':
+			b.WriteByte('/')
+		case s[i] == '\\' && i+3 < len(s) && s[i+1] == 'x':
+			if c, err := strconv.ParseUint(s[i+2:i+4], 16, 8); err == nil {
+				b.WriteByte(byte(c))
+				i += 3
+				continue
+			}
+			b.WriteByte(s[i])
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String()
+}
+
+func (s *Specifiers) path(name string) string {
+	root := s.Root
+	if root == "" {
+		root = "/"
+	}
+	return filepath.Join(root, name)
+}
+
+// readFile returns the first line of the file below Root.
+func (s *Specifiers) readFile(name string) (string, error) {
+	b, err := ioutil.ReadFile(s.path(name))
+	if err != nil {
+		return "", err
+	}
+	line := strings.TrimSpace(strings.SplitN(string(b), "\n", 2)[0])
+	if line == "" {
+		return "", errors.New(name + " is empty")
+	}
+	return line, nil
+}
+
+// readEnvFile reads the assignments of a file like os-release below Root.
+func (s *Specifiers) readEnvFile(name string) (map[string]string, error) {
+	data, err := ioutil.ReadFile(s.path(name))
+	if err != nil {
+		return nil, err
+	}
+	assignments, _, err := parser.ScanEnv(data, name)
+	if err != nil {
+		return nil, err
+	}
+	vars := map[string]string{}
+	for _, a := range assignments {
+		vars[a.Name] = a.Value
+	}
+	return vars, nil
+}
+
+// osRelease returns the value of the key in /etc/os-release,
+// or /usr/lib/os-release if the former does not exist.
+func (s *Specifiers) osRelease(key string) (string, error) {
+	vars, err := s.readEnvFile("/etc/os-release")
+	if os.IsNotExist(err) {
+		vars, err = s.readEnvFile("/usr/lib/os-release")
+	}
+	if err != nil {
+		return "", err
+	}
+	return vars[key], nil
+}
+
+func (s *Specifiers) getenv(key string) string {
+	if s.Getenv != nil {
+		return s.Getenv(key)
+	}
+	return os.Getenv(key)
+}
+
+// userDir returns the directory of the system manager,
+// or the directory in $env or below the home directory of the user manager.
+func (s *Specifiers) userDir(env, home, system string) (string, error) {
+	if !s.UserManager {
+		return system, nil
+	}
+	if dir := s.getenv(env); dir != "" {
+		return dir, nil
+	}
+	dir, err := s.user('h')
+	if err != nil {
+		return "", err
+	}
+	return dir + "/" + home, nil
+}
+
+func (s *Specifiers) runtimeDir() (string, error) {
+	if !s.UserManager {
+		return "/run", nil
+	}
+	if dir := s.getenv("XDG_RUNTIME_DIR"); dir != "" {
+		return dir, nil
+	}
+	return "", errors.New("$XDG_RUNTIME_DIR is not set")
+}
+
+func (s *Specifiers) tmpDir(fallback string) string {
+	for _, env := range []string{"TMPDIR", "TEMP", "TMP"} {
+		if dir := s.getenv(env); filepath.IsAbs(dir) {
+			return dir
+		}
+	}
+	return fallback
+}
+
+// user resolves the specifiers of the user and group running the service manager.
+// The system manager runs as root.
+func (s *Specifiers) user(specifier rune) (string, error) {
+	if !s.UserManager {
+		switch specifier {
+		case 'u', 'g':
+			return "root", nil
+		case 'U', 'G':
+			return "0", nil
+		case 'h':
+			return "/root", nil
+		}
+		return "/bin/sh", nil
+	}
+
+	uid := strconv.Itoa(s.getuid())
+	if specifier == 'U' {
+		return uid, nil
+	}
+	// name:password:UID:GID:GECOS:directory:shell
+	passwd, err := s.lookup("/etc/passwd", 7, 2, uid)
+	if err != nil {
+		return "", err
+	}
+	if passwd == nil {
+		if specifier == 'u' {
+			// like systemd, fall back to the ID
+			return uid, nil
+		}
+		return "", errors.New("user " + uid + " not found in /etc/passwd")
+	}
+
+	switch specifier {
+	case 'u':
+		return passwd[0], nil
+	case 'h':
+		return passwd[5], nil
+	case 's':
+		return passwd[6], nil
+	case 'G':
+		return passwd[3], nil
+	}
+	// name:password:GID:members
+	group, err := s.lookup("/etc/group", 4, 2, passwd[3])
+	if err != nil || group == nil {
+		return passwd[3], err
+	}
+	return group[0], nil
+}
+
+func (s *Specifiers) getuid() int {
+	if s.Getuid != nil {
+		return s.Getuid()
+	}
+	return os.Getuid()
+}
+
+// lookup returns the fields of the first entry of the colon separated database below Root,
+// which has n fields and value in the field at index, or nil if there is none.
+func (s *Specifiers) lookup(name string, n, index int, value string) ([]string, error) {
+	b, err := ioutil.ReadFile(s.path(name))
+	if err != nil {
+		return nil, err
+	}
+	for _, line := range strings.Split(string(b), "\n") {
+		fields := strings.Split(line, ":")
+		if len(fields) == n && fields[index] == value {
+			return fields, nil
+		}
+	}
+	return nil, nil
+}
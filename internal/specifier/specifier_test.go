@@ -0,0 +1,142 @@
+package specifier
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpand(t *testing.T) {
+	r := ResolverFunc(func(specifier rune) (string, error) {
+		switch specifier {
+		case 'n':
+			return "test.service", nil
+		case 'i':
+			return "", nil
+		}
+		return "", ErrUnknown
+	})
+
+	tests := []struct {
+		Value    string
+		Expected string
+	}{
+		{Value: "", Expected: ""},
+		{Value: "no specifiers", Expected: "no specifiers"},
+		{Value: "%n", Expected: "test.service"},
+		{Value: "unit=%n instance=%i.", Expected: "unit=test.service instance=."},
+		{Value: "100%%", Expected: "100%"},
+		{Value: "%%n", Expected: "%n"},
+		{Value: "trailing %", Expected: "trailing %"},
+		{Value: "ünïcödé %n", Expected: "ünïcödé test.service"},
+	}
+	for _, test := range tests {
+		out, err := Expand(test.Value, r)
+		require.NoError(t, err, test.Value)
+		assert.Equal(t, test.Expected, out, test.Value)
+	}
+
+	_, err := Expand("%n %ä", r)
+	assert.Equal(t, &Error{Specifier: 'ä', Err: ErrUnknown}, err)
+	assert.True(t, errors.Is(err, ErrUnknown))
+	assert.EqualError(t, err, "specifier %ä: unknown specifier")
+}
+
+func TestSpecifiers(t *testing.T) {
+	env := map[string]string{
+		"XDG_RUNTIME_DIR": "/run/user/1000",
+		"XDG_CONFIG_HOME": "/home/router/etc",
+		"TMPDIR":          "/scratch",
+	}
+	system := &Specifiers{
+		Unit:         "dhcp-relay@eth0-vlan\\x2d10.service",
+		FragmentPath: "/etc/systemd/system/dhcp-relay@.service",
+		Root:         "testdata/root",
+		Architecture: "arm64",
+		Getenv:       func(string) string { return "" },
+	}
+	user := &Specifiers{
+		Unit:        "sync.timer",
+		UserManager: true,
+		Root:        "testdata/root",
+		Getenv:      func(key string) string { return env[key] },
+		Getuid:      func() int { return 1000 },
+	}
+
+	tests := []struct {
+		Specifiers *Specifiers
+		Value      string
+		Expected   string
+	}{
+		// unit names
+		{system, "%n", "dhcp-relay@eth0-vlan\\x2d10.service"},
+		{system, "%N", "dhcp-relay@eth0-vlan\\x2d10"},
+		{system, "%p", "dhcp-relay"},
+		{system, "%P", "dhcp/relay"},
+		{system, "%i", "eth0-vlan\\x2d10"},
+		{system, "%I", "eth0/vlan-10"},
+		{system, "%j", "relay"},
+		{system, "%J", "relay"},
+		{system, "%f", "/eth0/vlan-10"},
+		{system, "%y", "/etc/systemd/system/dhcp-relay@.service"},
+		{system, "%Y", "/etc/systemd/system"},
+		{user, "%p", "sync"},
+		{user, "%i", ""},
+		{user, "%f", "/sync"},
+		{user, "%Y", ""},
+
+		// host
+		{system, "%a", "arm64"},
+		{system, "%b", "f7a2c1b45d3e4c6f8a9b0c1d2e3f4a5b"},
+		{system, "%H", "router1.example.com"},
+		{system, "%l", "router1"},
+		{system, "%q", "Edge Router"},
+		{system, "%m", "b08dfa6083e7567a1921a715000001fb"},
+		{system, "%v", "6.1.0-13-amd64"},
+		{system, "%o %w %W %B", "debian 12 router 20231017"},
+		{system, "%M %A", "routerd 1.2 beta"},
+		{&Specifiers{Root: "testdata/minimal"}, "%o %w %W", "fedora 39 "},
+
+		// directories
+		{system, "%C %E %L %S %t %T %V", "/var/cache /etc /var/log /var/lib /run /tmp /var/tmp"},
+		{system, "%d", "/run/credentials/dhcp-relay@eth0-vlan\\x2d10.service"},
+		{user, "%C %E %L %S %t %T %V", "/home/router/.cache /home/router/etc /home/router/.local/state/log /home/router/.local/state /run/user/1000 /scratch /scratch"},
+		{user, "%d", "/run/user/1000/credentials/sync.timer"},
+
+		// user
+		{system, "%u %U %h %s %g %G", "root 0 /root /bin/sh root 0"},
+		{user, "%u %U %h %s %g %G", "router 1000 /home/router /bin/zsh users 100"},
+		{&Specifiers{UserManager: true, Root: "testdata/root", Getuid: func() int { return 1001 }}, "%g %G", "4242 4242"},
+		{&Specifiers{UserManager: true, Root: "testdata/root", Getuid: func() int { return 2000 }}, "%u %U", "2000 2000"},
+	}
+	for _, test := range tests {
+		out, err := Expand(test.Value, test.Specifiers)
+		require.NoError(t, err, test.Value)
+		assert.Equal(t, test.Expected, out, test.Value)
+	}
+
+	errorTests := []struct {
+		Specifiers *Specifiers
+		Value      string
+		Check      func(error) bool
+	}{
+		{system, "%x", func(err error) bool { return errors.Is(err, ErrUnknown) }},
+		{&Specifiers{Root: "testdata/minimal"}, "%m", os.IsNotExist},
+		{&Specifiers{Root: "testdata/missing"}, "%o", os.IsNotExist},
+		{&Specifiers{UserManager: true, Getenv: func(string) string { return "" }}, "%t", func(err error) bool {
+			return err.Error() == "specifier %t: $XDG_RUNTIME_DIR is not set"
+		}},
+		{&Specifiers{UserManager: true, Root: "testdata/root", Getuid: func() int { return 2000 }}, "%h", func(err error) bool {
+			return err.Error() == "specifier %h: user 2000 not found in /etc/passwd"
+		}},
+	}
+	for _, test := range errorTests {
+		_, err := Expand(test.Value, test.Specifiers)
+		require.Error(t, err, test.Value)
+		assert.IsType(t, &Error{}, err, test.Value)
+		assert.True(t, test.Check(errors.Unwrap(err)) || test.Check(err), "%s: %v", test.Value, err)
+	}
+}
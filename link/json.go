@@ -0,0 +1,43 @@
+/*
+Copyright 2020 The routerd Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package link
+
+import (
+	"gopkg.in/yaml.v3"
+
+	"routerd.net/go-systemd"
+)
+
+// MarshalJSON implements json.Marshaler, naming sections and keys like systemd.
+func (l Link) MarshalJSON() ([]byte, error) {
+	return systemd.MarshalJSON(&l)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (l *Link) UnmarshalJSON(data []byte) error {
+	return systemd.UnmarshalJSON(data, l)
+}
+
+// MarshalYAML implements yaml.Marshaler, naming sections and keys like systemd.
+func (l Link) MarshalYAML() (interface{}, error) {
+	return systemd.MarshalYAMLNode(&l)
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler.
+func (l *Link) UnmarshalYAML(node *yaml.Node) error {
+	return systemd.UnmarshalYAMLNode(node, l)
+}
@@ -6,7 +6,7 @@ type Link struct {
 	systemd.SectionList // SectionList to store unknown sections
 
 	Match       *MatchSection
-	LinkSection *LinkSection
+	LinkSection *LinkSection `systemd:"Link"`
 }
 
 // A link file is said to match a device if all matches specified by the [Match] section are satisfied. When a link file does not contain valid settings in [Match] section, then the file will match all devices and systemd-udevd warns about that.
@@ -20,46 +20,46 @@ type MatchSection struct {
 	// A whitespace-separated list of hardware addresses. Use full colon-, hyphen- or dot-delimited hexadecimal. See the example below. This option may appear more than once, in which case the lists are merged. If the empty string is assigned to this option, the list of hardware addresses defined prior to this is reset.
 	// Example:
 	// MACAddress=01:23:45:67:89:ab 00-11-22-33-44-55 AABB.CCDD.EEFF
-	MACAddresses []string `systemd:"MACAddress,omitempty,wslist"`
+	MACAddresses []string `systemd:"MACAddress,omitempty,wslist,unordered"`
 
 	// A whitespace-separated list of hardware's permanent addresses. While MACAddress= matches the device's current MAC address, this matches the device's permanent MAC address, which may be different from the current one. Use full colon-, hyphen- or dot-delimited hexadecimal. This option may appear more than once, in which case the lists are merged. If the empty string is assigned to this option, the list of hardware addresses defined prior to this is reset.
-	PermanentMACAddresses []string `systemd:"PermanentMACAddress,omitempty,wslist"`
+	PermanentMACAddresses []string `systemd:"PermanentMACAddress,omitempty,wslist,unordered,since=245"`
 
 	// A whitespace-separated list of shell-style globs matching the persistent path, as exposed by the udev property ID_PATH.
-	Paths []string `systemd:"Path,omitempty,wslist"`
+	Paths []string `systemd:"Path,omitempty,wslist,unordered"`
 
 	// A whitespace-separated list of shell-style globs matching the driver currently bound to the device, as exposed by the udev property ID_NET_DRIVER of its parent device, or if that is not set, the driver as exposed by ethtool -i of the device itself. If the list is prefixed with a "!", the test is inverted.
-	Drivers []string `systemd:"Driver,omitempty,wslist"`
+	Drivers []string `systemd:"Driver,omitempty,wslist,unordered"`
 
 	// A whitespace-separated list of shell-style globs matching the device type, as exposed by networkctl list. If the list is prefixed with a "!", the test is inverted. Some valid values are "ether", "loopback", "wlan", "wwan". Valid types are named either from the udev "DEVTYPE" attribute, or "ARPHRD_" macros in linux/if_arp.h, so this is not comprehensive.
-	Types []string `systemd:"Type,omitempty,wslist"`
+	Types []string `systemd:"Type,omitempty,wslist,unordered"`
 
 	// A whitespace-separated list of udev property names with their values after equals sign ("="). If multiple properties are specified, the test results are ANDed. If the list is prefixed with a "!", the test is inverted. If a value contains white spaces, then please quote whole key and value pair. If a value contains quotation, then please escape the quotation with "\".
 	// Example: if a .link file has the following:
 	// Property=ID_MODEL_ID=9999 "ID_VENDOR_FROM_DATABASE=vendor name" "KEY=with \"quotation\""
 	// then, the .link file matches only when an interface has all the above three properties.
-	Properties []string `systemd:"Property,omitempty,wslist"`
+	Properties []string `systemd:"Property,omitempty,wslist,unordered"`
 
 	// A whitespace-separated list of shell-style globs matching the device name, as exposed by the udev property "INTERFACE". This cannot be used to match on names that have already been changed from userspace. Caution is advised when matching on kernel-assigned names, as they are known to be unstable between reboots.
-	OriginalNames []string `systemd:"OriginalName,omitempty,wslist"`
+	OriginalNames []string `systemd:"OriginalName,omitempty,wslist,unordered"`
 
 	// Matches against the hostname or machine ID of the host. See ConditionHost= in systemd.unit(5) for details. When prefixed with an exclamation mark ("!"), the result is negated. If an empty string is assigned, then previously assigned value is cleared.
-	Host *string `systemd:",omitempty"`
+	Host *string `systemd:",omitempty,lastwins"`
 
 	// Checks whether the system is executed in a virtualized environment and optionally test whether it is a specific implementation. See ConditionVirtualization= in systemd.unit(5) for details. When prefixed with an exclamation mark ("!"), the result is negated. If an empty string is assigned, then previously assigned value is cleared.
-	Virtualization *string `systemd:",omitempty"`
+	Virtualization *string `systemd:",omitempty,lastwins"`
 
 	// Checks whether a specific kernel command line option is set. See ConditionKernelCommandLine= in systemd.unit(5) for details. When prefixed with an exclamation mark ("!"), the result is negated. If an empty string is assigned, then previously assigned value is cleared.
-	KernelCommandLine *string `systemd:",omitempty"`
+	KernelCommandLine *string `systemd:",omitempty,lastwins"`
 
 	// Checks whether the kernel version (as reported by uname -r) matches a certain expression. See ConditionKernelVersion= in systemd.unit(5) for details. When prefixed with an exclamation mark ("!"), the result is negated. If an empty string is assigned, then previously assigned value is cleared.
-	KernelVersion *string `systemd:",omitempty"`
+	KernelVersion *string `systemd:",omitempty,lastwins"`
 
 	// Checks whether the system is running on a specific architecture. See ConditionArchitecture= in systemd.unit(5) for details. When prefixed with an exclamation mark ("!"), the result is negated. If an empty string is assigned, then previously assigned value is cleared.
-	Architecture *string `systemd:",omitempty"`
+	Architecture *string `systemd:",omitempty,lastwins"`
 
 	// Checks whether the system is running on a machine with the specified firmware. See ConditionFirmware= in systemd.unit(5) for details. When prefixed with an exclamation mark ("!"), the result is negated. If an empty string is assigned, then previously assigned value is cleared.
-	Firmware *string `systemd:",omitempty"`
+	Firmware *string `systemd:",omitempty,lastwins,since=249"`
 }
 
 type LinkSection struct {
@@ -84,11 +84,11 @@ type LinkSection struct {
 	// Keeps the MAC address assigned by the kernel. Or use the MAC address specified in MACAddress=.
 	//
 	// An empty string assignment is equivalent to setting "none".
-	//
-	// MACAddress=
+	MACAddressPolicy *string `systemd:",omitempty"`
+
 	// The interface MAC address to use. For this setting to take effect, MACAddressPolicy= must either be unset, empty, or "none".
-	//
-	// NamePolicy=
+	MACAddress *string `systemd:",omitempty"`
+
 	// An ordered, space-separated list of policies by which the interface name should be set. NamePolicy= may be disabled by specifying net.ifnames=0 on the kernel command line. Each of the policies may fail, and the first successful one is used. The name is not set directly, but is exported to udev as the property ID_NET_NAME, which is, by default, used by a udev(7), rule to set NAME. The available policies are:
 	//
 	// kernel
@@ -111,26 +111,26 @@ type LinkSection struct {
 	//
 	// keep
 	// If the device already had a name given by userspace (as part of creation of the device or a rename), keep it.
-	MACAddressPolicy *string `systemd:",omitempty"`
+	NamePolicies []string `systemd:"NamePolicy,omitempty,wslist,lastwins"`
 
 	// The interface name to use. This option has lower precedence than NamePolicy=, so for this setting to take effect, NamePolicy= must either be unset, empty, disabled, or all policies configured there must fail. Also see the example below with "Name=dmz0".
 	// Note that specifying a name that the kernel might use for another interface (for example "eth0") is dangerous because the name assignment done by udev will race with the assignment done by the kernel, and only one interface may use the name. Depending on the order of operations, either udev or the kernel will win, making the naming unpredictable. It is best to use some different prefix, for example "internal0"/"external0" or "lan0"/"lan1"/"lan3".
 	Name *string `systemd:",omitempty"`
 
 	// A space-separated list of policies by which the interface's alternative names should be set. Each of the policies may fail, and all successful policies are used. The available policies are "database", "onboard", "slot", "path", and "mac". If the kernel does not support the alternative names, then this setting will be ignored.
-	AlternativeNamesPolicies []string `systemd:"AlternativeNamesPolicy,omitempty,wslist"`
+	AlternativeNamesPolicies []string `systemd:"AlternativeNamesPolicy,omitempty,wslist,lastwins,since=245"`
 
 	// The alternative interface name to use. This option can be specified multiple times. If the empty string is assigned to this option, the list is reset, and all prior assignments have no effect. If the kernel does not support the alternative names, then this setting will be ignored.
-	AlternativeNames []string `systemd:"AlternativeName,omitempty"`
+	AlternativeNames []string `systemd:"AlternativeName,omitempty,since=245"`
 
 	// Specifies the device's number of transmit queues. An integer in the range 1…4096. When unset, the kernel's default will be used.
-	TransmitQueues uint `systemd:",omitempty"`
+	TransmitQueues uint `systemd:",omitempty,since=248"`
 
 	// Specifies the device's number of receive queues. An integer in the range 1…4096. When unset, the kernel's default will be used.
-	ReceiveQueues uint `systemd:",omitempty"`
+	ReceiveQueues uint `systemd:",omitempty,since=248"`
 
 	// Specifies the transmit queue length of the device in number of packets. An unsigned integer in the range 0…4294967294. When unset, the kernel's default will be used.
-	TransmitQueueLength *uint `systemd:",omitempty"`
+	TransmitQueueLength *uint `systemd:",omitempty,since=248"`
 
 	// The maximum transmission unit in bytes to set for the device. The usual suffixes K, M, G are supported and are understood to the base of 1024.
 	MTUBytes *string `systemd:",omitempty"`
@@ -168,7 +168,7 @@ type LinkSection struct {
 	// Enable secureon(tm) password for MagicPacket(tm).
 	//
 	// Defaults to unset, and the device's default will be used. This setting can be specified multiple times. If an empty string is assigned, then the all previous assignments are cleared.
-	WakeOnLan *string `systemd:",omitempty"`
+	WakeOnLan []string `systemd:",omitempty,wslist"`
 
 	// The port option is used to select the device port. The supported values are:
 	// tp
@@ -208,13 +208,13 @@ type LinkSection struct {
 	// 20000basekr2-full	20000	full
 	//
 	// By default this is unset, i.e. all possible modes will be advertised. This option may be specified more than once, in which case all specified speeds and modes are advertised. If the empty string is assigned to this option, the list is reset, and all prior assignments have no effect.
-	Advertise *string `systemd:",omitempty"`
+	Advertise []string `systemd:",omitempty,wslist"`
 
 	// Takes a boolean. If set to true, hardware offload for checksumming of ingress network packets is enabled. When unset, the kernel's default will be used.
-	ReceiveChecksumOffload *bool `systemd:",omitempty"`
+	ReceiveChecksumOffload *bool `systemd:",omitempty,since=245"`
 
 	// Takes a boolean. If set to true, hardware offload for checksumming of egress network packets is enabled. When unset, the kernel's default will be used.
-	TransmitChecksumOffload *bool `systemd:",omitempty"`
+	TransmitChecksumOffload *bool `systemd:",omitempty,since=245"`
 
 	// Takes a boolean. If set to true, TCP Segmentation Offload (TSO) is enabled. When unset, the kernel's default will be used.
 	TCPSegmentationOffload *bool `systemd:",omitempty"`
@@ -229,7 +229,7 @@ type LinkSection struct {
 	GenericReceiveOffload *bool `systemd:",omitempty"`
 
 	// Takes a boolean. If set to true, hardware accelerated Generic Receive Offload (GRO) is enabled. When unset, the kernel's default will be used.
-	GenericReceiveOffloadHardware *bool `systemd:",omitempty"`
+	GenericReceiveOffloadHardware *bool `systemd:",omitempty,since=250"`
 
 	// Takes a boolean. If set to true, Large Receive Offload (LRO) is enabled. When unset, the kernel's default will be used.
 	LargeReceiveOffload *bool `systemd:",omitempty"`
@@ -242,56 +242,56 @@ type LinkSection struct {
 
 	// Specifies the maximum number of pending packets in the NIC receive buffer, mini receive buffer, jumbo receive buffer, or transmit buffer, respectively. Takes an unsigned integer in the range 1…4294967295 or "max". If set to "max", the advertised maximum value of the hardware will be used. When unset, the number will not be changed. Defaults to unset.
 	RxBufferSize      *string `systemd:",omitempty"`
-	RxMiniBufferSize  *string `systemd:",omitempty"`
-	RxJumboBufferSize *string `systemd:",omitempty"`
+	RxMiniBufferSize  *string `systemd:",omitempty,since=250"`
+	RxJumboBufferSize *string `systemd:",omitempty,since=250"`
 	TxBufferSize      *string `systemd:",omitempty"`
 
 	// Takes a boolean. When set, enables receive flow control, also known as the ethernet receive PAUSE message (generate and send ethernet PAUSE frames). When unset, the kernel's default will be used.
-	RxFlowControl *bool `systemd:",omitempty"`
+	RxFlowControl *bool `systemd:",omitempty,since=246"`
 
 	// Takes a boolean. When set, enables transmit flow control, also known as the ethernet transmit PAUSE message (respond to received ethernet PAUSE frames). When unset, the kernel's default will be used.
-	TxFlowControl *bool `systemd:",omitempty"`
+	TxFlowControl *bool `systemd:",omitempty,since=246"`
 
 	// Takes a boolean. When set, auto negotiation enables the interface to exchange state advertisements with the connected peer so that the two devices can agree on the ethernet PAUSE configuration. When unset, the kernel's default will be used.
-	AutoNegotiationFlowControl *bool `systemd:",omitempty"`
+	AutoNegotiationFlowControl *bool `systemd:",omitempty,since=246"`
 
 	// Specifies the maximum size of a Generic Segment Offload (GSO) packet the device should accept. The usual suffixes K, M, G are supported and are understood to the base of 1024. An unsigned integer in the range 1…65536. Defaults to unset.
-	GenericSegmentOffloadMaxBytes *uint `systemd:",omitempty"`
+	GenericSegmentOffloadMaxBytes *uint `systemd:",omitempty,since=246"`
 
 	// Specifies the maximum number of Generic Segment Offload (GSO) segments the device should accept. An unsigned integer in the range 1…65535. Defaults to unset.
-	GenericSegmentOffloadMaxSegments *uint `systemd:",omitempty"`
+	GenericSegmentOffloadMaxSegments *uint `systemd:",omitempty,since=246"`
 
 	// Boolean properties that, when set, enable/disable adaptive Rx/Tx coalescing if the hardware supports it. When unset, the kernel's default will be used.
-	UseAdaptiveRxCoalesce *bool `systemd:",omitempty"`
-	UseAdaptiveTxCoalesce *bool `systemd:",omitempty"`
+	UseAdaptiveRxCoalesce *bool `systemd:",omitempty,since=250"`
+	UseAdaptiveTxCoalesce *bool `systemd:",omitempty,since=250"`
 
 	// These properties configure the delay before Rx/Tx interrupts are generated after a packet is sent/received. The "Irq" properties come into effect when the host is servicing an IRQ. The "Low" and "High" properties come into effect when the packet rate drops below the low packet rate threshold or exceeds the high packet rate threshold respectively if adaptive Rx/Tx coalescing is enabled. When unset, the kernel's defaults will be used.
-	RxCoalesceSec     *string `systemd:",omitempty"`
-	RxCoalesceIrqSec  *string `systemd:",omitempty"`
-	RxCoalesceLowSec  *string `systemd:",omitempty"`
-	RxCoalesceHighSec *string `systemd:",omitempty"`
-	TxCoalesceSec     *string `systemd:",omitempty"`
-	TxCoalesceIrqSec  *string `systemd:",omitempty"`
-	TxCoalesceLowSec  *string `systemd:",omitempty"`
-	TxCoalesceHighSec *string `systemd:",omitempty"`
+	RxCoalesceSec     *string `systemd:",omitempty,since=250"`
+	RxCoalesceIrqSec  *string `systemd:",omitempty,since=250"`
+	RxCoalesceLowSec  *string `systemd:",omitempty,since=250"`
+	RxCoalesceHighSec *string `systemd:",omitempty,since=250"`
+	TxCoalesceSec     *string `systemd:",omitempty,since=250"`
+	TxCoalesceIrqSec  *string `systemd:",omitempty,since=250"`
+	TxCoalesceLowSec  *string `systemd:",omitempty,since=250"`
+	TxCoalesceHighSec *string `systemd:",omitempty,since=250"`
 
 	// These properties configure the maximum number of frames that are sent/received before a Rx/Tx interrupt is generated. The "Irq" properties come into effect when the host is servicing an IRQ. The "Low" and "High" properties come into effect when the packet rate drops below the low packet rate threshold or exceeds the high packet rate threshold respectively if adaptive Rx/Tx coalescing is enabled. When unset, the kernel's defaults will be used.
-	RxMaxCoalescedFrames     *string `systemd:",omitempty"`
-	RxMaxCoalescedIrqFrames  *string `systemd:",omitempty"`
-	RxMaxCoalescedLowFrames  *string `systemd:",omitempty"`
-	RxMaxCoalescedHighFrames *string `systemd:",omitempty"`
-	TxMaxCoalescedFrames     *string `systemd:",omitempty"`
-	TxMaxCoalescedIrqFrames  *string `systemd:",omitempty"`
-	TxMaxCoalescedLowFrames  *string `systemd:",omitempty"`
-	TxMaxCoalescedHighFrames *string `systemd:",omitempty"`
+	RxMaxCoalescedFrames     *string `systemd:",omitempty,since=250"`
+	RxMaxCoalescedIrqFrames  *string `systemd:",omitempty,since=250"`
+	RxMaxCoalescedLowFrames  *string `systemd:",omitempty,since=250"`
+	RxMaxCoalescedHighFrames *string `systemd:",omitempty,since=250"`
+	TxMaxCoalescedFrames     *string `systemd:",omitempty,since=250"`
+	TxMaxCoalescedIrqFrames  *string `systemd:",omitempty,since=250"`
+	TxMaxCoalescedLowFrames  *string `systemd:",omitempty,since=250"`
+	TxMaxCoalescedHighFrames *string `systemd:",omitempty,since=250"`
 
 	// These properties configure the low and high packet rate (expressed in packets per second) threshold respectively and are used to determine when the corresponding coalescing settings for low and high packet rates come into effect if adaptive Rx/Tx coalescing is enabled. If unset, the kernel's defaults will be used.
-	CoalescePacketRateLow  *uint `systemd:",omitempty"`
-	CoalescePacketRateHigh *uint `systemd:",omitempty"`
+	CoalescePacketRateLow  *uint `systemd:",omitempty,since=250"`
+	CoalescePacketRateHigh *uint `systemd:",omitempty,since=250"`
 
 	// Configures how often to sample the packet rate used for adaptive Rx/Tx coalescing. This property cannot be zero. This lowest time granularity supported by this property is seconds. Partial seconds will be rounded up before being passed to the kernel. If unset, the kernel's default will be used.
-	CoalescePacketRateSampleIntervalSec uint `systemd:",omitempty"`
+	CoalescePacketRateSampleIntervalSec uint `systemd:",omitempty,since=250"`
 
 	// How long to delay driver in-memory statistics block updates. If the driver does not have an in-memory statistic block, this property is ignored. This property cannot be zero. If unset, the kernel's default will be used.
-	StatisticsBlockCoalesceSec uint `systemd:",omitempty"`
+	StatisticsBlockCoalesceSec uint `systemd:",omitempty,since=250"`
 }
@@ -0,0 +1,224 @@
+// Code generated by systemd-codegen. DO NOT EDIT.
+
+package link
+
+import "routerd.net/go-systemd"
+
+// MarshalSystemd implements systemd.Marshaler.
+func (l *Link) MarshalSystemd() ([]systemd.Section, error) {
+	var (
+		sections []systemd.Section
+		section  systemd.Section
+		err      error
+	)
+	if l.Match != nil {
+		if section, err = l.Match.marshalSystemd("Match"); err != nil {
+			return nil, err
+		}
+		sections = append(sections, section)
+	}
+	if l.LinkSection != nil {
+		if section, err = l.LinkSection.marshalSystemd("Link"); err != nil {
+			return nil, err
+		}
+		sections = append(sections, section)
+	}
+	sections = append(sections, l.SectionList...)
+	return sections, nil
+}
+
+// UnmarshalSystemd implements systemd.Unmarshaler.
+func (l *Link) UnmarshalSystemd(file *systemd.File) error {
+	for i := range file.Sections {
+		section := &file.Sections[i]
+		switch section.Name {
+		case "Match":
+			s := &MatchSection{}
+			if err := s.unmarshalSystemd(section); err != nil {
+				return err
+			}
+			l.Match = s
+		case "Link":
+			s := &LinkSection{}
+			if err := s.unmarshalSystemd(section); err != nil {
+				return err
+			}
+			l.LinkSection = s
+		default:
+			l.AddSection(*section)
+		}
+	}
+	return nil
+}
+
+func (s *LinkSection) marshalSystemd(name string) (systemd.Section, error) {
+	e := systemd.NewSectionEncoder(name, s.Comment, s.GetKeyComment)
+	e.StringPtr("Description", s.Description, true)
+	e.StringPtr("Alias", s.Alias, true)
+	e.StringPtr("MACAddressPolicy", s.MACAddressPolicy, true)
+	e.StringPtr("MACAddress", s.MACAddress, true)
+	e.Value("NamePolicy", &s.NamePolicies, ",omitempty,wslist,lastwins")
+	e.StringPtr("Name", s.Name, true)
+	e.Value("AlternativeNamesPolicy", &s.AlternativeNamesPolicies, ",omitempty,wslist,lastwins")
+	e.Strings("AlternativeName", s.AlternativeNames, false)
+	e.Uint("TransmitQueues", s.TransmitQueues, true)
+	e.Uint("ReceiveQueues", s.ReceiveQueues, true)
+	e.UintPtr("TransmitQueueLength", s.TransmitQueueLength, true)
+	e.StringPtr("MTUBytes", s.MTUBytes, true)
+	e.StringPtr("BitsPerSecond", s.BitsPerSecond, true)
+	e.StringPtr("Duplex", s.Duplex, true)
+	e.BoolPtr("AutoNegotiation", s.AutoNegotiation, true)
+	e.Strings("WakeOnLan", s.WakeOnLan, true)
+	e.StringPtr("Port", s.Port, true)
+	e.Strings("Advertise", s.Advertise, true)
+	e.BoolPtr("ReceiveChecksumOffload", s.ReceiveChecksumOffload, true)
+	e.BoolPtr("TransmitChecksumOffload", s.TransmitChecksumOffload, true)
+	e.BoolPtr("TCPSegmentationOffload", s.TCPSegmentationOffload, true)
+	e.BoolPtr("TCP6SegmentationOffload", s.TCP6SegmentationOffload, true)
+	e.BoolPtr("GenericSegmentationOffload", s.GenericSegmentationOffload, true)
+	e.BoolPtr("GenericReceiveOffload", s.GenericReceiveOffload, true)
+	e.BoolPtr("GenericReceiveOffloadHardware", s.GenericReceiveOffloadHardware, true)
+	e.BoolPtr("LargeReceiveOffload", s.LargeReceiveOffload, true)
+	e.StringPtr("RxChannels", s.RxChannels, true)
+	e.StringPtr("TxChannels", s.TxChannels, true)
+	e.StringPtr("OtherChannels", s.OtherChannels, true)
+	e.StringPtr("CombinedChannels", s.CombinedChannels, true)
+	e.StringPtr("RxBufferSize", s.RxBufferSize, true)
+	e.StringPtr("RxMiniBufferSize", s.RxMiniBufferSize, true)
+	e.StringPtr("RxJumboBufferSize", s.RxJumboBufferSize, true)
+	e.StringPtr("TxBufferSize", s.TxBufferSize, true)
+	e.BoolPtr("RxFlowControl", s.RxFlowControl, true)
+	e.BoolPtr("TxFlowControl", s.TxFlowControl, true)
+	e.BoolPtr("AutoNegotiationFlowControl", s.AutoNegotiationFlowControl, true)
+	e.UintPtr("GenericSegmentOffloadMaxBytes", s.GenericSegmentOffloadMaxBytes, true)
+	e.UintPtr("GenericSegmentOffloadMaxSegments", s.GenericSegmentOffloadMaxSegments, true)
+	e.BoolPtr("UseAdaptiveRxCoalesce", s.UseAdaptiveRxCoalesce, true)
+	e.BoolPtr("UseAdaptiveTxCoalesce", s.UseAdaptiveTxCoalesce, true)
+	e.StringPtr("RxCoalesceSec", s.RxCoalesceSec, true)
+	e.StringPtr("RxCoalesceIrqSec", s.RxCoalesceIrqSec, true)
+	e.StringPtr("RxCoalesceLowSec", s.RxCoalesceLowSec, true)
+	e.StringPtr("RxCoalesceHighSec", s.RxCoalesceHighSec, true)
+	e.StringPtr("TxCoalesceSec", s.TxCoalesceSec, true)
+	e.StringPtr("TxCoalesceIrqSec", s.TxCoalesceIrqSec, true)
+	e.StringPtr("TxCoalesceLowSec", s.TxCoalesceLowSec, true)
+	e.StringPtr("TxCoalesceHighSec", s.TxCoalesceHighSec, true)
+	e.StringPtr("RxMaxCoalescedFrames", s.RxMaxCoalescedFrames, true)
+	e.StringPtr("RxMaxCoalescedIrqFrames", s.RxMaxCoalescedIrqFrames, true)
+	e.StringPtr("RxMaxCoalescedLowFrames", s.RxMaxCoalescedLowFrames, true)
+	e.StringPtr("RxMaxCoalescedHighFrames", s.RxMaxCoalescedHighFrames, true)
+	e.StringPtr("TxMaxCoalescedFrames", s.TxMaxCoalescedFrames, true)
+	e.StringPtr("TxMaxCoalescedIrqFrames", s.TxMaxCoalescedIrqFrames, true)
+	e.StringPtr("TxMaxCoalescedLowFrames", s.TxMaxCoalescedLowFrames, true)
+	e.StringPtr("TxMaxCoalescedHighFrames", s.TxMaxCoalescedHighFrames, true)
+	e.UintPtr("CoalescePacketRateLow", s.CoalescePacketRateLow, true)
+	e.UintPtr("CoalescePacketRateHigh", s.CoalescePacketRateHigh, true)
+	e.Uint("CoalescePacketRateSampleIntervalSec", s.CoalescePacketRateSampleIntervalSec, true)
+	e.Uint("StatisticsBlockCoalesceSec", s.StatisticsBlockCoalesceSec, true)
+	return e.Section(s.KeyList), e.Err()
+}
+
+func (s *LinkSection) unmarshalSystemd(section *systemd.Section) error {
+	s.Comment = section.Comment
+	d := systemd.NewSectionDecoder(section, s.AddKeyComment)
+	d.StringPtr("Description", &s.Description, true)
+	d.StringPtr("Alias", &s.Alias, true)
+	d.StringPtr("MACAddressPolicy", &s.MACAddressPolicy, true)
+	d.StringPtr("MACAddress", &s.MACAddress, true)
+	d.Value("NamePolicy", &s.NamePolicies, ",omitempty,wslist,lastwins")
+	d.StringPtr("Name", &s.Name, true)
+	d.Value("AlternativeNamesPolicy", &s.AlternativeNamesPolicies, ",omitempty,wslist,lastwins")
+	d.Strings("AlternativeName", &s.AlternativeNames, false)
+	d.Uint("TransmitQueues", &s.TransmitQueues)
+	d.Uint("ReceiveQueues", &s.ReceiveQueues)
+	d.UintPtr("TransmitQueueLength", &s.TransmitQueueLength)
+	d.StringPtr("MTUBytes", &s.MTUBytes, true)
+	d.StringPtr("BitsPerSecond", &s.BitsPerSecond, true)
+	d.StringPtr("Duplex", &s.Duplex, true)
+	d.BoolPtr("AutoNegotiation", &s.AutoNegotiation)
+	d.Strings("WakeOnLan", &s.WakeOnLan, true)
+	d.StringPtr("Port", &s.Port, true)
+	d.Strings("Advertise", &s.Advertise, true)
+	d.BoolPtr("ReceiveChecksumOffload", &s.ReceiveChecksumOffload)
+	d.BoolPtr("TransmitChecksumOffload", &s.TransmitChecksumOffload)
+	d.BoolPtr("TCPSegmentationOffload", &s.TCPSegmentationOffload)
+	d.BoolPtr("TCP6SegmentationOffload", &s.TCP6SegmentationOffload)
+	d.BoolPtr("GenericSegmentationOffload", &s.GenericSegmentationOffload)
+	d.BoolPtr("GenericReceiveOffload", &s.GenericReceiveOffload)
+	d.BoolPtr("GenericReceiveOffloadHardware", &s.GenericReceiveOffloadHardware)
+	d.BoolPtr("LargeReceiveOffload", &s.LargeReceiveOffload)
+	d.StringPtr("RxChannels", &s.RxChannels, true)
+	d.StringPtr("TxChannels", &s.TxChannels, true)
+	d.StringPtr("OtherChannels", &s.OtherChannels, true)
+	d.StringPtr("CombinedChannels", &s.CombinedChannels, true)
+	d.StringPtr("RxBufferSize", &s.RxBufferSize, true)
+	d.StringPtr("RxMiniBufferSize", &s.RxMiniBufferSize, true)
+	d.StringPtr("RxJumboBufferSize", &s.RxJumboBufferSize, true)
+	d.StringPtr("TxBufferSize", &s.TxBufferSize, true)
+	d.BoolPtr("RxFlowControl", &s.RxFlowControl)
+	d.BoolPtr("TxFlowControl", &s.TxFlowControl)
+	d.BoolPtr("AutoNegotiationFlowControl", &s.AutoNegotiationFlowControl)
+	d.UintPtr("GenericSegmentOffloadMaxBytes", &s.GenericSegmentOffloadMaxBytes)
+	d.UintPtr("GenericSegmentOffloadMaxSegments", &s.GenericSegmentOffloadMaxSegments)
+	d.BoolPtr("UseAdaptiveRxCoalesce", &s.UseAdaptiveRxCoalesce)
+	d.BoolPtr("UseAdaptiveTxCoalesce", &s.UseAdaptiveTxCoalesce)
+	d.StringPtr("RxCoalesceSec", &s.RxCoalesceSec, true)
+	d.StringPtr("RxCoalesceIrqSec", &s.RxCoalesceIrqSec, true)
+	d.StringPtr("RxCoalesceLowSec", &s.RxCoalesceLowSec, true)
+	d.StringPtr("RxCoalesceHighSec", &s.RxCoalesceHighSec, true)
+	d.StringPtr("TxCoalesceSec", &s.TxCoalesceSec, true)
+	d.StringPtr("TxCoalesceIrqSec", &s.TxCoalesceIrqSec, true)
+	d.StringPtr("TxCoalesceLowSec", &s.TxCoalesceLowSec, true)
+	d.StringPtr("TxCoalesceHighSec", &s.TxCoalesceHighSec, true)
+	d.StringPtr("RxMaxCoalescedFrames", &s.RxMaxCoalescedFrames, true)
+	d.StringPtr("RxMaxCoalescedIrqFrames", &s.RxMaxCoalescedIrqFrames, true)
+	d.StringPtr("RxMaxCoalescedLowFrames", &s.RxMaxCoalescedLowFrames, true)
+	d.StringPtr("RxMaxCoalescedHighFrames", &s.RxMaxCoalescedHighFrames, true)
+	d.StringPtr("TxMaxCoalescedFrames", &s.TxMaxCoalescedFrames, true)
+	d.StringPtr("TxMaxCoalescedIrqFrames", &s.TxMaxCoalescedIrqFrames, true)
+	d.StringPtr("TxMaxCoalescedLowFrames", &s.TxMaxCoalescedLowFrames, true)
+	d.StringPtr("TxMaxCoalescedHighFrames", &s.TxMaxCoalescedHighFrames, true)
+	d.UintPtr("CoalescePacketRateLow", &s.CoalescePacketRateLow)
+	d.UintPtr("CoalescePacketRateHigh", &s.CoalescePacketRateHigh)
+	d.Uint("CoalescePacketRateSampleIntervalSec", &s.CoalescePacketRateSampleIntervalSec)
+	d.Uint("StatisticsBlockCoalesceSec", &s.StatisticsBlockCoalesceSec)
+	d.Unknown(s.AddKey)
+	return d.Err()
+}
+
+func (s *MatchSection) marshalSystemd(name string) (systemd.Section, error) {
+	e := systemd.NewSectionEncoder(name, s.Comment, s.GetKeyComment)
+	e.Strings("MACAddress", s.MACAddresses, true)
+	e.Strings("PermanentMACAddress", s.PermanentMACAddresses, true)
+	e.Strings("Path", s.Paths, true)
+	e.Strings("Driver", s.Drivers, true)
+	e.Strings("Type", s.Types, true)
+	e.Strings("Property", s.Properties, true)
+	e.Strings("OriginalName", s.OriginalNames, true)
+	e.Value("Host", &s.Host, ",omitempty,lastwins")
+	e.Value("Virtualization", &s.Virtualization, ",omitempty,lastwins")
+	e.Value("KernelCommandLine", &s.KernelCommandLine, ",omitempty,lastwins")
+	e.Value("KernelVersion", &s.KernelVersion, ",omitempty,lastwins")
+	e.Value("Architecture", &s.Architecture, ",omitempty,lastwins")
+	e.Value("Firmware", &s.Firmware, ",omitempty,lastwins")
+	return e.Section(s.KeyList), e.Err()
+}
+
+func (s *MatchSection) unmarshalSystemd(section *systemd.Section) error {
+	s.Comment = section.Comment
+	d := systemd.NewSectionDecoder(section, s.AddKeyComment)
+	d.Strings("MACAddress", &s.MACAddresses, true)
+	d.Strings("PermanentMACAddress", &s.PermanentMACAddresses, true)
+	d.Strings("Path", &s.Paths, true)
+	d.Strings("Driver", &s.Drivers, true)
+	d.Strings("Type", &s.Types, true)
+	d.Strings("Property", &s.Properties, true)
+	d.Strings("OriginalName", &s.OriginalNames, true)
+	d.Value("Host", &s.Host, ",omitempty,lastwins")
+	d.Value("Virtualization", &s.Virtualization, ",omitempty,lastwins")
+	d.Value("KernelCommandLine", &s.KernelCommandLine, ",omitempty,lastwins")
+	d.Value("KernelVersion", &s.KernelVersion, ",omitempty,lastwins")
+	d.Value("Architecture", &s.Architecture, ",omitempty,lastwins")
+	d.Value("Firmware", &s.Firmware, ",omitempty,lastwins")
+	d.Unknown(s.AddKey)
+	return d.Err()
+}
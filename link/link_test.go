@@ -1,10 +1,12 @@
 package link
 
 import (
+	"encoding/json"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
 
 	"routerd.net/go-systemd"
 )
@@ -46,6 +48,52 @@ MTUBytes=1450
 BitsPerSecond=10M
 WakeOnLan=magic
 MACAddress=cb:a9:87:65:43:21
+`
+
+	// Marshal writes the keys of [Link] in field order.
+	example1Marshaled = `[Link]
+MACAddressPolicy=persistent
+NamePolicy=kernel database onboard slot path
+`
+
+	example5Marshaled = `[Match]
+MACAddress=12:34:56:78:9a:bc
+Path=pci-0000:02:00.0-*
+Driver=brcmsmac
+Type=wlan
+Host=my-laptop
+Virtualization=no
+Architecture=x86-64
+
+[Link]
+MACAddress=cb:a9:87:65:43:21
+Name=wireless0
+MTUBytes=1450
+BitsPerSecond=10M
+WakeOnLan=magic
+`
+
+	// example 5 + comments, repeated and unknown sections and keys
+	example6 = `[Match]
+# match
+MACAddress=12:34:56:78:9a:bc
+MACAddress=
+MACAddress=cb:a9:87:65:43:21  00:a0:de:63:7a:e6
+Driver=brcmsmac
+Foo=bar
+
+[Link]
+Name=wireless0
+# alternative names
+AlternativeName=wlan0
+AlternativeName=wifi0
+TransmitQueues=4
+TransmitQueueLength=
+AutoNegotiation=maybe
+RxChannels=2
+
+[X-Vendor]
+Mode=fast
 `
 )
 
@@ -54,11 +102,12 @@ func TestNetDev(t *testing.T) {
 		tests := []struct {
 			Name string
 			File string
+			Out  string // marshaled file, if it differs
 		}{
-			{Name: "Example 1", File: example1},
+			{Name: "Example 1", File: example1, Out: example1Marshaled},
 			{Name: "Example 2", File: example2},
 			{Name: "Example 4", File: example4},
-			{Name: "Example 5", File: example5},
+			{Name: "Example 5", File: example5, Out: example5Marshaled},
 		}
 
 		for _, test := range tests {
@@ -69,8 +118,135 @@ func TestNetDev(t *testing.T) {
 
 				b, err := systemd.Marshal(netdev)
 				require.NoError(t, err, "error in marshal")
-				assert.Equal(t, test.File, string(b))
+				if test.Out == "" {
+					test.Out = test.File
+				}
+				assert.Equal(t, test.Out, string(b))
+			})
+		}
+	})
+
+	t.Run("test JSON and YAML conversion", func(t *testing.T) {
+		tests := []struct {
+			Name string
+			File string
+			Out  string // marshaled file, if it differs
+		}{
+			{Name: "Example 1", File: example1, Out: example1Marshaled},
+			{Name: "Example 2", File: example2},
+			{Name: "Example 4", File: example4},
+			{Name: "Example 5", File: example5, Out: example5Marshaled},
+		}
+
+		for _, test := range tests {
+			t.Run(test.Name, func(t *testing.T) {
+				in := &Link{}
+				require.NoError(t, systemd.Unmarshal([]byte(test.File), in))
+
+				b, err := json.Marshal(in)
+				require.NoError(t, err, "error in JSON marshal")
+				fromJSON := &Link{}
+				require.NoError(t, json.Unmarshal(b, fromJSON), "error in JSON unmarshal")
+
+				b, err = yaml.Marshal(fromJSON)
+				require.NoError(t, err, "error in YAML marshal")
+				fromYAML := &Link{}
+				require.NoError(t, yaml.Unmarshal(b, fromYAML), "error in YAML unmarshal")
+
+				b, err = systemd.Marshal(fromYAML)
+				require.NoError(t, err, "error in marshal")
+				if test.Out == "" {
+					test.Out = test.File
+				}
+				assert.Equal(t, test.Out, string(b))
 			})
 		}
 	})
+
+	t.Run("test marshal for version", func(t *testing.T) {
+		in := &Link{LinkSection: &LinkSection{TransmitQueues: 4}}
+		b, err := systemd.MarshalForVersion(in, 248)
+		require.NoError(t, err)
+		assert.Equal(t, "[Link]\nTransmitQueues=4\n", string(b))
+
+		_, err = systemd.MarshalForVersion(in, 247)
+		assert.Equal(t, &systemd.VersionError{Section: "Link", Key: "TransmitQueues", Since: 248, Version: 247}, err)
+	})
+
+	t.Run("test generated code", func(t *testing.T) {
+		tests := []struct {
+			Name string
+			File string
+		}{
+			{Name: "Example 1", File: example1},
+			{Name: "Example 2", File: example2},
+			{Name: "Example 4", File: example4},
+			{Name: "Example 5", File: example5},
+			{Name: "Unknown sections and keys", File: example6},
+		}
+
+		for _, test := range tests {
+			t.Run(test.Name, func(t *testing.T) {
+				generated := &Link{}
+				require.NoError(t, systemd.Unmarshal([]byte(test.File), generated))
+				reflected := &reflectLink{}
+				require.NoError(t, systemd.Unmarshal([]byte(test.File), reflected))
+				assert.Equal(t, (Link)(*reflected), *generated)
+
+				b, err := systemd.Marshal(generated)
+				require.NoError(t, err)
+				expected, err := systemd.Marshal(reflected)
+				require.NoError(t, err)
+				assert.Equal(t, string(expected), string(b))
+			})
+		}
+
+		// invalid values are reported the same way
+		in := "[Link]\nTransmitQueueLength=many\n"
+		generatedErr := systemd.Unmarshal([]byte(in), &Link{})
+		require.Error(t, generatedErr)
+		assert.Equal(t, systemd.Unmarshal([]byte(in), &reflectLink{}), generatedErr)
+	})
+}
+
+// reflectLink has the fields of Link without its generated methods,
+// so it is encoded by reflection.
+type reflectLink Link
+
+func BenchmarkLink(b *testing.B) {
+	file, err := systemd.Decode([]byte(example6))
+	require.NoError(b, err)
+
+	b.Run("unmarshal generated", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if err := file.Unmarshal(&Link{}); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("unmarshal reflection", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if err := file.Unmarshal(&reflectLink{}); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	generated := &Link{}
+	require.NoError(b, file.Unmarshal(generated))
+	reflected := (*reflectLink)(generated)
+	b.Run("marshal generated", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := systemd.Marshal(generated); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("marshal reflection", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := systemd.Marshal(reflected); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
 }
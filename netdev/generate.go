@@ -0,0 +1,30 @@
+/*
+Copyright 2020 The routerd Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package netdev
+
+// The structs in netdev.go are generated from the man page in a systemd source checkout:
+//
+//	SYSTEMD_SRC=path/to/systemd go generate
+//
+// Without SYSTEMD_SRC, the structs are kept and only the methods below are generated.
+//
+//go:generate go run ../cmd/systemd-gen -pkg netdev -type NetDev -bool-type *bool -o netdev.go systemd.netdev.xml
+
+// The MarshalSystemd and UnmarshalSystemd methods in netdev_systemd.go are generated from the structs,
+// so Marshal and Unmarshal do not need reflection.
+//
+//go:generate go run ../cmd/systemd-codegen -type NetDev -o netdev_systemd.go
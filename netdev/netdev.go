@@ -32,8 +32,10 @@ type NetDev struct {
 	WireGuard                  *WireGuardSection
 	WireGuardPeer              []WireGuardPeerSection `systemd:"WireGuardPeer"`
 	Bond                       *BondSection
-	Xfrm                       *XfrmSection
+	Xfrm                       *XfrmSection `systemd:",since=243"`
 	VRF                        *VRFSection
+	BatmanAdvanced             *BatmanAdvancedSection `systemd:",since=248"`
+	IPoIB                      *IPoIBSection          `systemd:",since=250"`
 }
 
 // A virtual network device is only created if the [Match] section matches the current environment, or if the section is empty.
@@ -44,23 +46,23 @@ type MatchSection struct {
 
 	// Matches against the hostname or machine ID of the host. See "ConditionHost=" in systemd.unit(5) for details.
 	// When prefixed with an exclamation mark ("!"), the result is negated. If an empty string is assigned, then previously assigned value is cleared.
-	Host *string `systemd:",omitempty"`
+	Host *string `systemd:",omitempty,lastwins"`
 
 	// Checks whether the system is executed in a virtualized environment and optionally test whether it is a specific implementation. See "ConditionVirtualization=" in systemd.unit(5) for details.
 	// When prefixed with an exclamation mark ("!"), the result is negated. If an empty string is assigned, then previously assigned value is cleared.
-	Virtualization *string `systemd:",omitempty"`
+	Virtualization *string `systemd:",omitempty,lastwins"`
 
 	// Checks whether a specific kernel command line option is set. See "ConditionKernelCommandLine=" in systemd.unit(5) for details.
 	// When prefixed with an exclamation mark ("!"), the result is negated. If an empty string is assigned, then previously assigned value is cleared.
-	KernelCommandLine *string `systemd:",omitempty"`
+	KernelCommandLine *string `systemd:",omitempty,lastwins"`
 
 	// Checks whether the kernel version (as reported by uname -r) matches a certain expression. See "ConditionKernelVersion=" in systemd.unit(5) for details.
 	// When prefixed with an exclamation mark ("!"), the result is negated. If an empty string is assigned, then previously assigned value is cleared.
-	KernelVersion *string `systemd:",omitempty"`
+	KernelVersion *string `systemd:",omitempty,lastwins"`
 
 	// Checks whether the system is running on a specific architecture. See "ConditionArchitecture=" in systemd.unit(5) for details.
 	// When prefixed with an exclamation mark ("!"), the result is negated. If an empty string is assigned, then previously assigned value is cleared.
-	Architecture *string `systemd:",omitempty"`
+	Architecture *string `systemd:",omitempty,lastwins"`
 }
 
 type NetDevSection struct {
@@ -73,7 +75,7 @@ type NetDevSection struct {
 	// The interface name used when creating the netdev. This setting is compulsory.
 	Name string
 	// The netdev kind. This setting is compulsory. See the "Supported netdev kinds" section for the valid keys.
-	Kind string
+	Kind string `systemd:",first"`
 	// The maximum transmission unit in bytes to set for the device. The usual suffixes K, M, G are supported and are understood to the base of 1024.
 	// For "tun" or "tap" devices, MTUBytes= setting is not currently supported in [NetDev] section.
 	// Please specify it in [Link] section of corresponding systemd.network(5) files.
@@ -164,7 +166,7 @@ type MACVLANSection struct {
 	Mode string `systemd:",omitempty"`
 
 	// A whitespace-separated list of remote hardware addresses allowed on the MACVLAN. This option only has an effect in source mode. Use full colon-, hyphen- or dot-delimited hexadecimal. This option may appear more than once, in which case the lists are merged. If the empty string is assigned to this option, the list of hardware addresses defined prior to this is reset. Defaults to unset.
-	SourceMACAddress string `systemd:",omitempty"`
+	SourceMACAddress []string `systemd:",omitempty,wslist"`
 }
 
 // The [MACVTAP] section applies for netdevs of kind "macvtap"
@@ -177,7 +179,7 @@ type MACVTAPSection struct {
 	Mode string `systemd:",omitempty"`
 
 	// A whitespace-separated list of remote hardware addresses allowed on the MACVLAN. This option only has an effect in source mode. Use full colon-, hyphen- or dot-delimited hexadecimal. This option may appear more than once, in which case the lists are merged. If the empty string is assigned to this option, the list of hardware addresses defined prior to this is reset. Defaults to unset.
-	SourceMACAddress string `systemd:",omitempty"`
+	SourceMACAddress []string `systemd:",omitempty,wslist"`
 }
 
 // The [IPVLAN] section only applies for netdevs of kind "ipvlan"
@@ -213,7 +215,7 @@ type VXLANSection struct {
 	systemd.KeyComments
 
 	// The VXLAN Network Identifier (or VXLAN Segment ID). Takes a number in the range 1-16777215.
-	VNI string `systemd:",omitempty"`
+	VNI string `systemd:",omitempty,since=243,legacy=Id"`
 
 	// Configures destination IP address.
 	Remote string `systemd:",omitempty"`
@@ -763,3 +765,60 @@ type VRFSection struct {
 	// The numeric routing table identifier. This setting is compulsory.
 	Table string `systemd:",omitempty"`
 }
+
+// The [BatmanAdvanced] section only applies for netdevs of kind "batadv" and accepts the following keys:
+type BatmanAdvancedSection struct {
+	systemd.KeyList        // KeyList to store unknown keys
+	Comment         string // Section Comment
+	systemd.KeyComments
+
+	// Takes one of "off", "server", or "client". A batman-adv node can either run in server mode (sharing its internet connection with the mesh) or in client mode (searching for the most suitable internet connection in the mesh) or having the gateway support turned off entirely (which is the default setting).
+	GatewayMode string `systemd:",omitempty,since=248"`
+
+	// Takes a boolean value. Enables or disables aggregation of originator messages. Defaults to true.
+	Aggregation *bool `systemd:",omitempty,since=248"`
+
+	// Takes a boolean value. Enables or disables avoidance of loops on bridges. Defaults to true.
+	BridgeLoopAvoidance *bool `systemd:",omitempty,since=248"`
+
+	// Takes a boolean value. Enables or disables the distributed ARP table. Defaults to true.
+	DistributedArpTable *bool `systemd:",omitempty,since=248"`
+
+	// Takes a boolean value. Enables or disables fragmentation. Defaults to true.
+	Fragmentation *bool `systemd:",omitempty,since=248"`
+
+	// The hop penalty setting allows to modify batctl(8) preference for multihop routes vs. short routes. This integer value is applied to the TQ (Transmit Quality) of each forwarded OGM (Originator Message), thereby propagating the cost of an extra hop (the packet has to be received and retransmitted which costs airtime). A higher hop penalty will make it more unlikely that other nodes will choose this node as intermediate hop towards any given destination. The default hop penalty of '15' is a reasonable value for most setups and probably does not need to be changed. However, mobile nodes could choose a value of 255 (maximum value) to avoid being chosen as a router by other nodes. The minimum value is 0.
+	HopPenalty string `systemd:",omitempty,since=248"`
+
+	// The value specifies the interval in seconds, unless another time unit is specified in which batman-adv floods the network with its protocol information. See systemd.time(7) for more information.
+	OriginatorIntervalSec string `systemd:",omitempty,since=248"`
+
+	// If the node is a server, this parameter is used to inform other nodes in the network about this node's internet connection download bandwidth in bits per second. Just enter any number suffixed with K, M, G or T (base 1000) and the batman-adv module will propagate the entered value in the mesh.
+	GatewayBandwidthDown string `systemd:",omitempty,since=248"`
+
+	// If the node is a server, this parameter is used to inform other nodes in the network about this node's internet connection upload bandwidth in bits per second. Just enter any number suffixed with K, M, G or T (base 1000) and the batman-adv module will propagate the entered value in the mesh.
+	GatewayBandwidthUp string `systemd:",omitempty,since=248"`
+
+	// This can be either "batman-v" or "batman-iv" and describes which routing_algo of batctl(8) to use. The algorithm cannot be changed after interface creation. Defaults to "batman-v".
+	RoutingAlgorithm string `systemd:",omitempty,since=248"`
+}
+
+// The [IPoIB] section only applies for netdevs of kind "ipoib" and accepts the following keys:
+type IPoIBSection struct {
+	systemd.KeyList        // KeyList to store unknown keys
+	Comment         string // Section Comment
+	systemd.KeyComments
+
+	// Takes an integer in the range 1…0xffff, except for 0x8000. Defaults to unset, and the kernel's default is used.
+	PartitionKey string `systemd:",omitempty,since=250"`
+
+	// Takes one of the special values "datagram" or "connected". Defaults to unset, and the kernel's default is used.
+	//
+	// When "datagram", the Infiniband unreliable datagram (UD) transport is used, and so the interface MTU is equal to the IB L2 MTU minus the IPoIB encapsulation header (4 bytes). For example, in a typical IB fabric with a 2K MTU, the IPoIB MTU will be 2048 - 4 = 2044 bytes.
+	//
+	// When "connected", the Infiniband reliable connected (RC) transport is used. Connected mode takes advantage of the connected nature of the IB transport and allows an MTU up to the maximal IP packet size of 64K, which reduces the number of IP packets needed for handling large UDP datagrams, TCP segments, etc and increases the performance for large messages.
+	Mode string `systemd:",omitempty,since=250"`
+
+	// Takes an boolean value. When true, the kernel ignores multicast groups handled by userspace. Defaults to unset, and the kernel's default is used.
+	IgnoreUserspaceMulticastGroups *bool `systemd:",omitempty,since=250"`
+}
@@ -0,0 +1,1106 @@
+// Code generated by systemd-codegen. DO NOT EDIT.
+
+package netdev
+
+import "routerd.net/go-systemd"
+
+// MarshalSystemd implements systemd.Marshaler.
+func (n *NetDev) MarshalSystemd() ([]systemd.Section, error) {
+	var (
+		sections []systemd.Section
+		section  systemd.Section
+		err      error
+	)
+	if n.Match != nil {
+		if section, err = n.Match.marshalSystemd("Match"); err != nil {
+			return nil, err
+		}
+		sections = append(sections, section)
+	}
+	if section, err = n.NetDev.marshalSystemd("NetDev"); err != nil {
+		return nil, err
+	}
+	sections = append(sections, section)
+	if n.Bridge != nil {
+		if section, err = n.Bridge.marshalSystemd("Bridge"); err != nil {
+			return nil, err
+		}
+		sections = append(sections, section)
+	}
+	if n.VLAN != nil {
+		if section, err = n.VLAN.marshalSystemd("VLAN"); err != nil {
+			return nil, err
+		}
+		sections = append(sections, section)
+	}
+	if n.MACVLAN != nil {
+		if section, err = n.MACVLAN.marshalSystemd("MACVLAN"); err != nil {
+			return nil, err
+		}
+		sections = append(sections, section)
+	}
+	if n.MACVTAP != nil {
+		if section, err = n.MACVTAP.marshalSystemd("MACVTAP"); err != nil {
+			return nil, err
+		}
+		sections = append(sections, section)
+	}
+	if n.IPVLAN != nil {
+		if section, err = n.IPVLAN.marshalSystemd("IPVLAN"); err != nil {
+			return nil, err
+		}
+		sections = append(sections, section)
+	}
+	if n.IPVTAP != nil {
+		if section, err = n.IPVTAP.marshalSystemd("IPVTAP"); err != nil {
+			return nil, err
+		}
+		sections = append(sections, section)
+	}
+	if n.VXLAN != nil {
+		if section, err = n.VXLAN.marshalSystemd("VXLAN"); err != nil {
+			return nil, err
+		}
+		sections = append(sections, section)
+	}
+	if n.GENEVE != nil {
+		if section, err = n.GENEVE.marshalSystemd("GENEVE"); err != nil {
+			return nil, err
+		}
+		sections = append(sections, section)
+	}
+	if n.L2TP != nil {
+		if section, err = n.L2TP.marshalSystemd("L2TP"); err != nil {
+			return nil, err
+		}
+		sections = append(sections, section)
+	}
+	for i := range n.L2TPSessions {
+		if section, err = n.L2TPSessions[i].marshalSystemd("L2TPSession"); err != nil {
+			return nil, err
+		}
+		sections = append(sections, section)
+	}
+	if n.MACsec != nil {
+		if section, err = n.MACsec.marshalSystemd("MACsec"); err != nil {
+			return nil, err
+		}
+		sections = append(sections, section)
+	}
+	for i := range n.MACsecReceiveChannels {
+		if section, err = n.MACsecReceiveChannels[i].marshalSystemd("MACsecReceiveChannel"); err != nil {
+			return nil, err
+		}
+		sections = append(sections, section)
+	}
+	for i := range n.MACsecTransmitAssociations {
+		if section, err = n.MACsecTransmitAssociations[i].marshalSystemd("MACsecTransmitAssociation"); err != nil {
+			return nil, err
+		}
+		sections = append(sections, section)
+	}
+	for i := range n.MACsecReceiveAssociations {
+		if section, err = n.MACsecReceiveAssociations[i].marshalSystemd("MACsecReceiveAssociation"); err != nil {
+			return nil, err
+		}
+		sections = append(sections, section)
+	}
+	if n.Tunnel != nil {
+		if section, err = n.Tunnel.marshalSystemd("Tunnel"); err != nil {
+			return nil, err
+		}
+		sections = append(sections, section)
+	}
+	if n.FooOverUDP != nil {
+		if section, err = n.FooOverUDP.marshalSystemd("FooOverUDP"); err != nil {
+			return nil, err
+		}
+		sections = append(sections, section)
+	}
+	for i := range n.Peers {
+		if section, err = n.Peers[i].marshalSystemd("Peer"); err != nil {
+			return nil, err
+		}
+		sections = append(sections, section)
+	}
+	if n.VXCAN != nil {
+		if section, err = n.VXCAN.marshalSystemd("VXCAN"); err != nil {
+			return nil, err
+		}
+		sections = append(sections, section)
+	}
+	if n.Tun != nil {
+		if section, err = n.Tun.marshalSystemd("Tun"); err != nil {
+			return nil, err
+		}
+		sections = append(sections, section)
+	}
+	if n.Tap != nil {
+		if section, err = n.Tap.marshalSystemd("Tap"); err != nil {
+			return nil, err
+		}
+		sections = append(sections, section)
+	}
+	if n.WireGuard != nil {
+		if section, err = n.WireGuard.marshalSystemd("WireGuard"); err != nil {
+			return nil, err
+		}
+		sections = append(sections, section)
+	}
+	for i := range n.WireGuardPeer {
+		if section, err = n.WireGuardPeer[i].marshalSystemd("WireGuardPeer"); err != nil {
+			return nil, err
+		}
+		sections = append(sections, section)
+	}
+	if n.Bond != nil {
+		if section, err = n.Bond.marshalSystemd("Bond"); err != nil {
+			return nil, err
+		}
+		sections = append(sections, section)
+	}
+	if n.Xfrm != nil {
+		if section, err = n.Xfrm.marshalSystemd("Xfrm"); err != nil {
+			return nil, err
+		}
+		sections = append(sections, section)
+	}
+	if n.VRF != nil {
+		if section, err = n.VRF.marshalSystemd("VRF"); err != nil {
+			return nil, err
+		}
+		sections = append(sections, section)
+	}
+	if n.BatmanAdvanced != nil {
+		if section, err = n.BatmanAdvanced.marshalSystemd("BatmanAdvanced"); err != nil {
+			return nil, err
+		}
+		sections = append(sections, section)
+	}
+	if n.IPoIB != nil {
+		if section, err = n.IPoIB.marshalSystemd("IPoIB"); err != nil {
+			return nil, err
+		}
+		sections = append(sections, section)
+	}
+	sections = append(sections, n.SectionList...)
+	return sections, nil
+}
+
+// UnmarshalSystemd implements systemd.Unmarshaler.
+func (n *NetDev) UnmarshalSystemd(file *systemd.File) error {
+	for i := range file.Sections {
+		section := &file.Sections[i]
+		switch section.Name {
+		case "Match":
+			s := &MatchSection{}
+			if err := s.unmarshalSystemd(section); err != nil {
+				return err
+			}
+			n.Match = s
+		case "NetDev":
+			s := NetDevSection{}
+			if err := s.unmarshalSystemd(section); err != nil {
+				return err
+			}
+			n.NetDev = s
+		case "Bridge":
+			s := &BridgeSection{}
+			if err := s.unmarshalSystemd(section); err != nil {
+				return err
+			}
+			n.Bridge = s
+		case "VLAN":
+			s := &VLANSection{}
+			if err := s.unmarshalSystemd(section); err != nil {
+				return err
+			}
+			n.VLAN = s
+		case "MACVLAN":
+			s := &MACVLANSection{}
+			if err := s.unmarshalSystemd(section); err != nil {
+				return err
+			}
+			n.MACVLAN = s
+		case "MACVTAP":
+			s := &MACVTAPSection{}
+			if err := s.unmarshalSystemd(section); err != nil {
+				return err
+			}
+			n.MACVTAP = s
+		case "IPVLAN":
+			s := &IPVLANSection{}
+			if err := s.unmarshalSystemd(section); err != nil {
+				return err
+			}
+			n.IPVLAN = s
+		case "IPVTAP":
+			s := &IPVTAPSection{}
+			if err := s.unmarshalSystemd(section); err != nil {
+				return err
+			}
+			n.IPVTAP = s
+		case "VXLAN":
+			s := &VXLANSection{}
+			if err := s.unmarshalSystemd(section); err != nil {
+				return err
+			}
+			n.VXLAN = s
+		case "GENEVE":
+			s := &GENEVESection{}
+			if err := s.unmarshalSystemd(section); err != nil {
+				return err
+			}
+			n.GENEVE = s
+		case "L2TP":
+			s := &L2TPSection{}
+			if err := s.unmarshalSystemd(section); err != nil {
+				return err
+			}
+			n.L2TP = s
+		case "L2TPSession":
+			s := L2TPSessionSection{}
+			if err := s.unmarshalSystemd(section); err != nil {
+				return err
+			}
+			n.L2TPSessions = append(n.L2TPSessions, s)
+		case "MACsec":
+			s := &MACsecSection{}
+			if err := s.unmarshalSystemd(section); err != nil {
+				return err
+			}
+			n.MACsec = s
+		case "MACsecReceiveChannel":
+			s := MACsecReceiveChannelSection{}
+			if err := s.unmarshalSystemd(section); err != nil {
+				return err
+			}
+			n.MACsecReceiveChannels = append(n.MACsecReceiveChannels, s)
+		case "MACsecTransmitAssociation":
+			s := MACsecTransmitAssociationSection{}
+			if err := s.unmarshalSystemd(section); err != nil {
+				return err
+			}
+			n.MACsecTransmitAssociations = append(n.MACsecTransmitAssociations, s)
+		case "MACsecReceiveAssociation":
+			s := MACsecReceiveAssociationSection{}
+			if err := s.unmarshalSystemd(section); err != nil {
+				return err
+			}
+			n.MACsecReceiveAssociations = append(n.MACsecReceiveAssociations, s)
+		case "Tunnel":
+			s := &TunnelSection{}
+			if err := s.unmarshalSystemd(section); err != nil {
+				return err
+			}
+			n.Tunnel = s
+		case "FooOverUDP":
+			s := &FooOverUDPSection{}
+			if err := s.unmarshalSystemd(section); err != nil {
+				return err
+			}
+			n.FooOverUDP = s
+		case "Peer":
+			s := PeerSection{}
+			if err := s.unmarshalSystemd(section); err != nil {
+				return err
+			}
+			n.Peers = append(n.Peers, s)
+		case "VXCAN":
+			s := &VXCANSection{}
+			if err := s.unmarshalSystemd(section); err != nil {
+				return err
+			}
+			n.VXCAN = s
+		case "Tun":
+			s := &TunSection{}
+			if err := s.unmarshalSystemd(section); err != nil {
+				return err
+			}
+			n.Tun = s
+		case "Tap":
+			s := &TapSection{}
+			if err := s.unmarshalSystemd(section); err != nil {
+				return err
+			}
+			n.Tap = s
+		case "WireGuard":
+			s := &WireGuardSection{}
+			if err := s.unmarshalSystemd(section); err != nil {
+				return err
+			}
+			n.WireGuard = s
+		case "WireGuardPeer":
+			s := WireGuardPeerSection{}
+			if err := s.unmarshalSystemd(section); err != nil {
+				return err
+			}
+			n.WireGuardPeer = append(n.WireGuardPeer, s)
+		case "Bond":
+			s := &BondSection{}
+			if err := s.unmarshalSystemd(section); err != nil {
+				return err
+			}
+			n.Bond = s
+		case "Xfrm":
+			s := &XfrmSection{}
+			if err := s.unmarshalSystemd(section); err != nil {
+				return err
+			}
+			n.Xfrm = s
+		case "VRF":
+			s := &VRFSection{}
+			if err := s.unmarshalSystemd(section); err != nil {
+				return err
+			}
+			n.VRF = s
+		case "BatmanAdvanced":
+			s := &BatmanAdvancedSection{}
+			if err := s.unmarshalSystemd(section); err != nil {
+				return err
+			}
+			n.BatmanAdvanced = s
+		case "IPoIB":
+			s := &IPoIBSection{}
+			if err := s.unmarshalSystemd(section); err != nil {
+				return err
+			}
+			n.IPoIB = s
+		default:
+			n.AddSection(*section)
+		}
+	}
+	return nil
+}
+
+func (s *BatmanAdvancedSection) marshalSystemd(name string) (systemd.Section, error) {
+	e := systemd.NewSectionEncoder(name, s.Comment, s.GetKeyComment)
+	e.String("GatewayMode", s.GatewayMode, true)
+	e.BoolPtr("Aggregation", s.Aggregation, true)
+	e.BoolPtr("BridgeLoopAvoidance", s.BridgeLoopAvoidance, true)
+	e.BoolPtr("DistributedArpTable", s.DistributedArpTable, true)
+	e.BoolPtr("Fragmentation", s.Fragmentation, true)
+	e.String("HopPenalty", s.HopPenalty, true)
+	e.String("OriginatorIntervalSec", s.OriginatorIntervalSec, true)
+	e.String("GatewayBandwidthDown", s.GatewayBandwidthDown, true)
+	e.String("GatewayBandwidthUp", s.GatewayBandwidthUp, true)
+	e.String("RoutingAlgorithm", s.RoutingAlgorithm, true)
+	return e.Section(s.KeyList), e.Err()
+}
+
+func (s *BatmanAdvancedSection) unmarshalSystemd(section *systemd.Section) error {
+	s.Comment = section.Comment
+	d := systemd.NewSectionDecoder(section, s.AddKeyComment)
+	d.String("GatewayMode", &s.GatewayMode)
+	d.BoolPtr("Aggregation", &s.Aggregation)
+	d.BoolPtr("BridgeLoopAvoidance", &s.BridgeLoopAvoidance)
+	d.BoolPtr("DistributedArpTable", &s.DistributedArpTable)
+	d.BoolPtr("Fragmentation", &s.Fragmentation)
+	d.String("HopPenalty", &s.HopPenalty)
+	d.String("OriginatorIntervalSec", &s.OriginatorIntervalSec)
+	d.String("GatewayBandwidthDown", &s.GatewayBandwidthDown)
+	d.String("GatewayBandwidthUp", &s.GatewayBandwidthUp)
+	d.String("RoutingAlgorithm", &s.RoutingAlgorithm)
+	d.Unknown(s.AddKey)
+	return d.Err()
+}
+
+func (s *BondSection) marshalSystemd(name string) (systemd.Section, error) {
+	e := systemd.NewSectionEncoder(name, s.Comment, s.GetKeyComment)
+	e.String("Mode", s.Mode, true)
+	e.String("TransmitHashPolicy", s.TransmitHashPolicy, true)
+	e.String("LACPTransmitRate", s.LACPTransmitRate, true)
+	e.String("MIIMonitorSec", s.MIIMonitorSec, true)
+	e.String("UpDelaySec", s.UpDelaySec, true)
+	e.String("DownDelaySec", s.DownDelaySec, true)
+	e.String("LearnPacketIntervalSec", s.LearnPacketIntervalSec, true)
+	e.String("AdSelect", s.AdSelect, true)
+	e.String("AdActorSystemPriority", s.AdActorSystemPriority, true)
+	e.String("AdUserPortKey", s.AdUserPortKey, true)
+	e.String("AdActorSystem", s.AdActorSystem, true)
+	e.String("FailOverMACPolicy", s.FailOverMACPolicy, true)
+	e.String("ARPValidate", s.ARPValidate, true)
+	e.String("ARPIntervalSec", s.ARPIntervalSec, true)
+	e.String("ARPIPTargets", s.ARPIPTargets, true)
+	e.String("ARPAllTargets", s.ARPAllTargets, true)
+	e.String("PrimaryReselectPolicy", s.PrimaryReselectPolicy, true)
+	e.String("ResendIGMP", s.ResendIGMP, true)
+	e.String("PacketsPerSlave", s.PacketsPerSlave, true)
+	e.String("GratuitousARP", s.GratuitousARP, true)
+	e.BoolPtr("AllSlavesActive", s.AllSlavesActive, true)
+	e.BoolPtr("DynamicTransmitLoadBalancing", s.DynamicTransmitLoadBalancing, true)
+	e.String("MinLinks", s.MinLinks, true)
+	return e.Section(s.KeyList), e.Err()
+}
+
+func (s *BondSection) unmarshalSystemd(section *systemd.Section) error {
+	s.Comment = section.Comment
+	d := systemd.NewSectionDecoder(section, s.AddKeyComment)
+	d.String("Mode", &s.Mode)
+	d.String("TransmitHashPolicy", &s.TransmitHashPolicy)
+	d.String("LACPTransmitRate", &s.LACPTransmitRate)
+	d.String("MIIMonitorSec", &s.MIIMonitorSec)
+	d.String("UpDelaySec", &s.UpDelaySec)
+	d.String("DownDelaySec", &s.DownDelaySec)
+	d.String("LearnPacketIntervalSec", &s.LearnPacketIntervalSec)
+	d.String("AdSelect", &s.AdSelect)
+	d.String("AdActorSystemPriority", &s.AdActorSystemPriority)
+	d.String("AdUserPortKey", &s.AdUserPortKey)
+	d.String("AdActorSystem", &s.AdActorSystem)
+	d.String("FailOverMACPolicy", &s.FailOverMACPolicy)
+	d.String("ARPValidate", &s.ARPValidate)
+	d.String("ARPIntervalSec", &s.ARPIntervalSec)
+	d.String("ARPIPTargets", &s.ARPIPTargets)
+	d.String("ARPAllTargets", &s.ARPAllTargets)
+	d.String("PrimaryReselectPolicy", &s.PrimaryReselectPolicy)
+	d.String("ResendIGMP", &s.ResendIGMP)
+	d.String("PacketsPerSlave", &s.PacketsPerSlave)
+	d.String("GratuitousARP", &s.GratuitousARP)
+	d.BoolPtr("AllSlavesActive", &s.AllSlavesActive)
+	d.BoolPtr("DynamicTransmitLoadBalancing", &s.DynamicTransmitLoadBalancing)
+	d.String("MinLinks", &s.MinLinks)
+	d.Unknown(s.AddKey)
+	return d.Err()
+}
+
+func (s *BridgeSection) marshalSystemd(name string) (systemd.Section, error) {
+	e := systemd.NewSectionEncoder(name, s.Comment, s.GetKeyComment)
+	e.String("HelloTimeSec", s.HelloTimeSec, true)
+	e.String("MaxAgeSec", s.MaxAgeSec, true)
+	e.String("ForwardDelaySec", s.ForwardDelaySec, true)
+	e.String("AgeingTimeSec", s.AgeingTimeSec, true)
+	e.String("Priority", s.Priority, true)
+	e.String("GroupForwardMask", s.GroupForwardMask, true)
+	e.String("DefaultPVID", s.DefaultPVID, true)
+	e.BoolPtr("MulticastQuerier", s.MulticastQuerier, true)
+	e.BoolPtr("MulticastSnooping", s.MulticastSnooping, true)
+	e.BoolPtr("VLANFiltering", s.VLANFiltering, true)
+	e.String("VLANProtocol", s.VLANProtocol, true)
+	e.BoolPtr("STP", s.STP, true)
+	e.String("MulticastIGMPVersion", s.MulticastIGMPVersion, true)
+	return e.Section(s.KeyList), e.Err()
+}
+
+func (s *BridgeSection) unmarshalSystemd(section *systemd.Section) error {
+	s.Comment = section.Comment
+	d := systemd.NewSectionDecoder(section, s.AddKeyComment)
+	d.String("HelloTimeSec", &s.HelloTimeSec)
+	d.String("MaxAgeSec", &s.MaxAgeSec)
+	d.String("ForwardDelaySec", &s.ForwardDelaySec)
+	d.String("AgeingTimeSec", &s.AgeingTimeSec)
+	d.String("Priority", &s.Priority)
+	d.String("GroupForwardMask", &s.GroupForwardMask)
+	d.String("DefaultPVID", &s.DefaultPVID)
+	d.BoolPtr("MulticastQuerier", &s.MulticastQuerier)
+	d.BoolPtr("MulticastSnooping", &s.MulticastSnooping)
+	d.BoolPtr("VLANFiltering", &s.VLANFiltering)
+	d.String("VLANProtocol", &s.VLANProtocol)
+	d.BoolPtr("STP", &s.STP)
+	d.String("MulticastIGMPVersion", &s.MulticastIGMPVersion)
+	d.Unknown(s.AddKey)
+	return d.Err()
+}
+
+func (s *FooOverUDPSection) marshalSystemd(name string) (systemd.Section, error) {
+	e := systemd.NewSectionEncoder(name, s.Comment, s.GetKeyComment)
+	e.String("Encapsulation", s.Encapsulation, true)
+	e.String("Port", s.Port, true)
+	e.String("PeerPort", s.PeerPort, true)
+	e.String("Protocol", s.Protocol, true)
+	e.String("Peer", s.Peer, true)
+	e.String("Local", s.Local, true)
+	return e.Section(s.KeyList), e.Err()
+}
+
+func (s *FooOverUDPSection) unmarshalSystemd(section *systemd.Section) error {
+	s.Comment = section.Comment
+	d := systemd.NewSectionDecoder(section, s.AddKeyComment)
+	d.String("Encapsulation", &s.Encapsulation)
+	d.String("Port", &s.Port)
+	d.String("PeerPort", &s.PeerPort)
+	d.String("Protocol", &s.Protocol)
+	d.String("Peer", &s.Peer)
+	d.String("Local", &s.Local)
+	d.Unknown(s.AddKey)
+	return d.Err()
+}
+
+func (s *GENEVESection) marshalSystemd(name string) (systemd.Section, error) {
+	e := systemd.NewSectionEncoder(name, s.Comment, s.GetKeyComment)
+	e.String("Id", s.Id, true)
+	e.String("Remote", s.Remote, true)
+	e.String("TOS", s.TOS, true)
+	e.String("TTL", s.TTL, true)
+	e.BoolPtr("UDPChecksum", s.UDPChecksum, true)
+	e.BoolPtr("UDP6ZeroChecksumTx", s.UDP6ZeroChecksumTx, true)
+	e.BoolPtr("UDP6ZeroChecksumRx", s.UDP6ZeroChecksumRx, true)
+	e.String("DestinationPort", s.DestinationPort, true)
+	e.String("FlowLabel", s.FlowLabel, true)
+	e.String("IPDoNotFragment", s.IPDoNotFragment, true)
+	return e.Section(s.KeyList), e.Err()
+}
+
+func (s *GENEVESection) unmarshalSystemd(section *systemd.Section) error {
+	s.Comment = section.Comment
+	d := systemd.NewSectionDecoder(section, s.AddKeyComment)
+	d.String("Id", &s.Id)
+	d.String("Remote", &s.Remote)
+	d.String("TOS", &s.TOS)
+	d.String("TTL", &s.TTL)
+	d.BoolPtr("UDPChecksum", &s.UDPChecksum)
+	d.BoolPtr("UDP6ZeroChecksumTx", &s.UDP6ZeroChecksumTx)
+	d.BoolPtr("UDP6ZeroChecksumRx", &s.UDP6ZeroChecksumRx)
+	d.String("DestinationPort", &s.DestinationPort)
+	d.String("FlowLabel", &s.FlowLabel)
+	d.String("IPDoNotFragment", &s.IPDoNotFragment)
+	d.Unknown(s.AddKey)
+	return d.Err()
+}
+
+func (s *IPVLANSection) marshalSystemd(name string) (systemd.Section, error) {
+	e := systemd.NewSectionEncoder(name, s.Comment, s.GetKeyComment)
+	e.String("Mode", s.Mode, true)
+	e.String("Flags", s.Flags, true)
+	return e.Section(s.KeyList), e.Err()
+}
+
+func (s *IPVLANSection) unmarshalSystemd(section *systemd.Section) error {
+	s.Comment = section.Comment
+	d := systemd.NewSectionDecoder(section, s.AddKeyComment)
+	d.String("Mode", &s.Mode)
+	d.String("Flags", &s.Flags)
+	d.Unknown(s.AddKey)
+	return d.Err()
+}
+
+func (s *IPVTAPSection) marshalSystemd(name string) (systemd.Section, error) {
+	e := systemd.NewSectionEncoder(name, s.Comment, s.GetKeyComment)
+	e.String("Mode", s.Mode, true)
+	e.String("Flags", s.Flags, true)
+	return e.Section(s.KeyList), e.Err()
+}
+
+func (s *IPVTAPSection) unmarshalSystemd(section *systemd.Section) error {
+	s.Comment = section.Comment
+	d := systemd.NewSectionDecoder(section, s.AddKeyComment)
+	d.String("Mode", &s.Mode)
+	d.String("Flags", &s.Flags)
+	d.Unknown(s.AddKey)
+	return d.Err()
+}
+
+func (s *IPoIBSection) marshalSystemd(name string) (systemd.Section, error) {
+	e := systemd.NewSectionEncoder(name, s.Comment, s.GetKeyComment)
+	e.String("PartitionKey", s.PartitionKey, true)
+	e.String("Mode", s.Mode, true)
+	e.BoolPtr("IgnoreUserspaceMulticastGroups", s.IgnoreUserspaceMulticastGroups, true)
+	return e.Section(s.KeyList), e.Err()
+}
+
+func (s *IPoIBSection) unmarshalSystemd(section *systemd.Section) error {
+	s.Comment = section.Comment
+	d := systemd.NewSectionDecoder(section, s.AddKeyComment)
+	d.String("PartitionKey", &s.PartitionKey)
+	d.String("Mode", &s.Mode)
+	d.BoolPtr("IgnoreUserspaceMulticastGroups", &s.IgnoreUserspaceMulticastGroups)
+	d.Unknown(s.AddKey)
+	return d.Err()
+}
+
+func (s *L2TPSection) marshalSystemd(name string) (systemd.Section, error) {
+	e := systemd.NewSectionEncoder(name, s.Comment, s.GetKeyComment)
+	e.String("TunnelId", s.TunnelId, true)
+	e.String("PeerTunnelId", s.PeerTunnelId, true)
+	e.String("Remote", s.Remote, true)
+	e.String("Local", s.Local, true)
+	e.String("EncapsulationType", s.EncapsulationType, true)
+	e.String("UDPSourcePort", s.UDPSourcePort, true)
+	e.String("UDPDestinationPort", s.UDPDestinationPort, true)
+	e.BoolPtr("UDPChecksum", s.UDPChecksum, true)
+	e.BoolPtr("UDP6ZeroChecksumTx", s.UDP6ZeroChecksumTx, true)
+	e.BoolPtr("UDP6ZeroChecksumRx", s.UDP6ZeroChecksumRx, true)
+	return e.Section(s.KeyList), e.Err()
+}
+
+func (s *L2TPSection) unmarshalSystemd(section *systemd.Section) error {
+	s.Comment = section.Comment
+	d := systemd.NewSectionDecoder(section, s.AddKeyComment)
+	d.String("TunnelId", &s.TunnelId)
+	d.String("PeerTunnelId", &s.PeerTunnelId)
+	d.String("Remote", &s.Remote)
+	d.String("Local", &s.Local)
+	d.String("EncapsulationType", &s.EncapsulationType)
+	d.String("UDPSourcePort", &s.UDPSourcePort)
+	d.String("UDPDestinationPort", &s.UDPDestinationPort)
+	d.BoolPtr("UDPChecksum", &s.UDPChecksum)
+	d.BoolPtr("UDP6ZeroChecksumTx", &s.UDP6ZeroChecksumTx)
+	d.BoolPtr("UDP6ZeroChecksumRx", &s.UDP6ZeroChecksumRx)
+	d.Unknown(s.AddKey)
+	return d.Err()
+}
+
+func (s *L2TPSessionSection) marshalSystemd(name string) (systemd.Section, error) {
+	e := systemd.NewSectionEncoder(name, s.Comment, s.GetKeyComment)
+	e.String("Name", s.Name, true)
+	e.String("SessionId", s.SessionId, true)
+	e.String("PeerSessionId", s.PeerSessionId, true)
+	e.String("Layer2SpecificHeader", s.Layer2SpecificHeader, true)
+	return e.Section(s.KeyList), e.Err()
+}
+
+func (s *L2TPSessionSection) unmarshalSystemd(section *systemd.Section) error {
+	s.Comment = section.Comment
+	d := systemd.NewSectionDecoder(section, s.AddKeyComment)
+	d.String("Name", &s.Name)
+	d.String("SessionId", &s.SessionId)
+	d.String("PeerSessionId", &s.PeerSessionId)
+	d.String("Layer2SpecificHeader", &s.Layer2SpecificHeader)
+	d.Unknown(s.AddKey)
+	return d.Err()
+}
+
+func (s *MACVLANSection) marshalSystemd(name string) (systemd.Section, error) {
+	e := systemd.NewSectionEncoder(name, s.Comment, s.GetKeyComment)
+	e.String("Mode", s.Mode, true)
+	e.Strings("SourceMACAddress", s.SourceMACAddress, true)
+	return e.Section(s.KeyList), e.Err()
+}
+
+func (s *MACVLANSection) unmarshalSystemd(section *systemd.Section) error {
+	s.Comment = section.Comment
+	d := systemd.NewSectionDecoder(section, s.AddKeyComment)
+	d.String("Mode", &s.Mode)
+	d.Strings("SourceMACAddress", &s.SourceMACAddress, true)
+	d.Unknown(s.AddKey)
+	return d.Err()
+}
+
+func (s *MACVTAPSection) marshalSystemd(name string) (systemd.Section, error) {
+	e := systemd.NewSectionEncoder(name, s.Comment, s.GetKeyComment)
+	e.String("Mode", s.Mode, true)
+	e.Strings("SourceMACAddress", s.SourceMACAddress, true)
+	return e.Section(s.KeyList), e.Err()
+}
+
+func (s *MACVTAPSection) unmarshalSystemd(section *systemd.Section) error {
+	s.Comment = section.Comment
+	d := systemd.NewSectionDecoder(section, s.AddKeyComment)
+	d.String("Mode", &s.Mode)
+	d.Strings("SourceMACAddress", &s.SourceMACAddress, true)
+	d.Unknown(s.AddKey)
+	return d.Err()
+}
+
+func (s *MACsecReceiveAssociationSection) marshalSystemd(name string) (systemd.Section, error) {
+	e := systemd.NewSectionEncoder(name, s.Comment, s.GetKeyComment)
+	e.String("Port", s.Port, true)
+	e.String("MACAddress", s.MACAddress, true)
+	e.String("PacketNumber", s.PacketNumber, true)
+	e.String("KeyId", s.KeyId, true)
+	e.String("Key", s.Key, true)
+	e.String("KeyFile", s.KeyFile, true)
+	e.BoolPtr("Activate", s.Activate, true)
+	return e.Section(s.KeyList), e.Err()
+}
+
+func (s *MACsecReceiveAssociationSection) unmarshalSystemd(section *systemd.Section) error {
+	s.Comment = section.Comment
+	d := systemd.NewSectionDecoder(section, s.AddKeyComment)
+	d.String("Port", &s.Port)
+	d.String("MACAddress", &s.MACAddress)
+	d.String("PacketNumber", &s.PacketNumber)
+	d.String("KeyId", &s.KeyId)
+	d.String("Key", &s.Key)
+	d.String("KeyFile", &s.KeyFile)
+	d.BoolPtr("Activate", &s.Activate)
+	d.Unknown(s.AddKey)
+	return d.Err()
+}
+
+func (s *MACsecReceiveChannelSection) marshalSystemd(name string) (systemd.Section, error) {
+	e := systemd.NewSectionEncoder(name, s.Comment, s.GetKeyComment)
+	e.String("Port", s.Port, true)
+	e.String("MACAddress", s.MACAddress, true)
+	return e.Section(s.KeyList), e.Err()
+}
+
+func (s *MACsecReceiveChannelSection) unmarshalSystemd(section *systemd.Section) error {
+	s.Comment = section.Comment
+	d := systemd.NewSectionDecoder(section, s.AddKeyComment)
+	d.String("Port", &s.Port)
+	d.String("MACAddress", &s.MACAddress)
+	d.Unknown(s.AddKey)
+	return d.Err()
+}
+
+func (s *MACsecSection) marshalSystemd(name string) (systemd.Section, error) {
+	e := systemd.NewSectionEncoder(name, s.Comment, s.GetKeyComment)
+	e.String("Port", s.Port, true)
+	e.BoolPtr("Encrypt", s.Encrypt, true)
+	return e.Section(s.KeyList), e.Err()
+}
+
+func (s *MACsecSection) unmarshalSystemd(section *systemd.Section) error {
+	s.Comment = section.Comment
+	d := systemd.NewSectionDecoder(section, s.AddKeyComment)
+	d.String("Port", &s.Port)
+	d.BoolPtr("Encrypt", &s.Encrypt)
+	d.Unknown(s.AddKey)
+	return d.Err()
+}
+
+func (s *MACsecTransmitAssociationSection) marshalSystemd(name string) (systemd.Section, error) {
+	e := systemd.NewSectionEncoder(name, s.Comment, s.GetKeyComment)
+	e.String("PacketNumber", s.PacketNumber, true)
+	e.String("KeyId", s.KeyId, true)
+	e.String("Key", s.Key, true)
+	e.String("KeyFile", s.KeyFile, true)
+	e.BoolPtr("Activate", s.Activate, true)
+	e.BoolPtr("UseForEncoding", s.UseForEncoding, true)
+	return e.Section(s.KeyList), e.Err()
+}
+
+func (s *MACsecTransmitAssociationSection) unmarshalSystemd(section *systemd.Section) error {
+	s.Comment = section.Comment
+	d := systemd.NewSectionDecoder(section, s.AddKeyComment)
+	d.String("PacketNumber", &s.PacketNumber)
+	d.String("KeyId", &s.KeyId)
+	d.String("Key", &s.Key)
+	d.String("KeyFile", &s.KeyFile)
+	d.BoolPtr("Activate", &s.Activate)
+	d.BoolPtr("UseForEncoding", &s.UseForEncoding)
+	d.Unknown(s.AddKey)
+	return d.Err()
+}
+
+func (s *MatchSection) marshalSystemd(name string) (systemd.Section, error) {
+	e := systemd.NewSectionEncoder(name, s.Comment, s.GetKeyComment)
+	e.Value("Host", &s.Host, ",omitempty,lastwins")
+	e.Value("Virtualization", &s.Virtualization, ",omitempty,lastwins")
+	e.Value("KernelCommandLine", &s.KernelCommandLine, ",omitempty,lastwins")
+	e.Value("KernelVersion", &s.KernelVersion, ",omitempty,lastwins")
+	e.Value("Architecture", &s.Architecture, ",omitempty,lastwins")
+	return e.Section(s.KeyList), e.Err()
+}
+
+func (s *MatchSection) unmarshalSystemd(section *systemd.Section) error {
+	s.Comment = section.Comment
+	d := systemd.NewSectionDecoder(section, s.AddKeyComment)
+	d.Value("Host", &s.Host, ",omitempty,lastwins")
+	d.Value("Virtualization", &s.Virtualization, ",omitempty,lastwins")
+	d.Value("KernelCommandLine", &s.KernelCommandLine, ",omitempty,lastwins")
+	d.Value("KernelVersion", &s.KernelVersion, ",omitempty,lastwins")
+	d.Value("Architecture", &s.Architecture, ",omitempty,lastwins")
+	d.Unknown(s.AddKey)
+	return d.Err()
+}
+
+func (s *NetDevSection) marshalSystemd(name string) (systemd.Section, error) {
+	e := systemd.NewSectionEncoder(name, s.Comment, s.GetKeyComment)
+	e.String("Description", s.Description, true)
+	e.String("Name", s.Name, false)
+	e.Value("Kind", &s.Kind, ",first")
+	e.String("MTUBytes", s.MTUBytes, true)
+	e.String("MACAddress", s.MACAddress, true)
+	return e.Section(s.KeyList), e.Err()
+}
+
+func (s *NetDevSection) unmarshalSystemd(section *systemd.Section) error {
+	s.Comment = section.Comment
+	d := systemd.NewSectionDecoder(section, s.AddKeyComment)
+	d.String("Description", &s.Description)
+	d.String("Name", &s.Name)
+	d.Value("Kind", &s.Kind, ",first")
+	d.String("MTUBytes", &s.MTUBytes)
+	d.String("MACAddress", &s.MACAddress)
+	d.Unknown(s.AddKey)
+	return d.Err()
+}
+
+func (s *PeerSection) marshalSystemd(name string) (systemd.Section, error) {
+	e := systemd.NewSectionEncoder(name, s.Comment, s.GetKeyComment)
+	e.String("Name", s.Name, true)
+	e.String("MACAddress", s.MACAddress, true)
+	return e.Section(s.KeyList), e.Err()
+}
+
+func (s *PeerSection) unmarshalSystemd(section *systemd.Section) error {
+	s.Comment = section.Comment
+	d := systemd.NewSectionDecoder(section, s.AddKeyComment)
+	d.String("Name", &s.Name)
+	d.String("MACAddress", &s.MACAddress)
+	d.Unknown(s.AddKey)
+	return d.Err()
+}
+
+func (s *TapSection) marshalSystemd(name string) (systemd.Section, error) {
+	e := systemd.NewSectionEncoder(name, s.Comment, s.GetKeyComment)
+	e.BoolPtr("MultiQueue", s.MultiQueue, true)
+	e.BoolPtr("PacketInfo", s.PacketInfo, true)
+	e.BoolPtr("VNetHeader", s.VNetHeader, true)
+	e.String("User", s.User, true)
+	e.String("Group", s.Group, true)
+	return e.Section(s.KeyList), e.Err()
+}
+
+func (s *TapSection) unmarshalSystemd(section *systemd.Section) error {
+	s.Comment = section.Comment
+	d := systemd.NewSectionDecoder(section, s.AddKeyComment)
+	d.BoolPtr("MultiQueue", &s.MultiQueue)
+	d.BoolPtr("PacketInfo", &s.PacketInfo)
+	d.BoolPtr("VNetHeader", &s.VNetHeader)
+	d.String("User", &s.User)
+	d.String("Group", &s.Group)
+	d.Unknown(s.AddKey)
+	return d.Err()
+}
+
+func (s *TunSection) marshalSystemd(name string) (systemd.Section, error) {
+	e := systemd.NewSectionEncoder(name, s.Comment, s.GetKeyComment)
+	e.BoolPtr("MultiQueue", s.MultiQueue, true)
+	e.BoolPtr("PacketInfo", s.PacketInfo, true)
+	e.BoolPtr("VNetHeader", s.VNetHeader, true)
+	e.String("User", s.User, true)
+	e.String("Group", s.Group, true)
+	return e.Section(s.KeyList), e.Err()
+}
+
+func (s *TunSection) unmarshalSystemd(section *systemd.Section) error {
+	s.Comment = section.Comment
+	d := systemd.NewSectionDecoder(section, s.AddKeyComment)
+	d.BoolPtr("MultiQueue", &s.MultiQueue)
+	d.BoolPtr("PacketInfo", &s.PacketInfo)
+	d.BoolPtr("VNetHeader", &s.VNetHeader)
+	d.String("User", &s.User)
+	d.String("Group", &s.Group)
+	d.Unknown(s.AddKey)
+	return d.Err()
+}
+
+func (s *TunnelSection) marshalSystemd(name string) (systemd.Section, error) {
+	e := systemd.NewSectionEncoder(name, s.Comment, s.GetKeyComment)
+	e.String("Local", s.Local, true)
+	e.String("Remote", s.Remote, true)
+	e.String("TOS", s.TOS, true)
+	e.String("TTL", s.TTL, true)
+	e.BoolPtr("DiscoverPathMTU", s.DiscoverPathMTU, true)
+	e.String("IPv6FlowLabel", s.IPv6FlowLabel, true)
+	e.BoolPtr("CopyDSCP", s.CopyDSCP, true)
+	e.String("EncapsulationLimit", s.EncapsulationLimit, true)
+	e.String("Key", s.Key, true)
+	e.String("InputKey", s.InputKey, true)
+	e.String("OutputKey", s.OutputKey, true)
+	e.String("Mode", s.Mode, true)
+	e.BoolPtr("Independent", s.Independent, true)
+	e.BoolPtr("AssignToLoopback", s.AssignToLoopback, true)
+	e.BoolPtr("AllowLocalRemote", s.AllowLocalRemote, true)
+	e.BoolPtr("FooOverUDP", s.FooOverUDP, true)
+	e.String("FOUDestinationPort", s.FOUDestinationPort, true)
+	e.String("FOUSourcePort", s.FOUSourcePort, true)
+	e.String("Encapsulation", s.Encapsulation, true)
+	e.String("IPv6RapidDeploymentPrefix", s.IPv6RapidDeploymentPrefix, true)
+	e.BoolPtr("ISATAP", s.ISATAP, true)
+	e.BoolPtr("SerializeTunneledPackets", s.SerializeTunneledPackets, true)
+	e.String("ERSPANIndex", s.ERSPANIndex, true)
+	return e.Section(s.KeyList), e.Err()
+}
+
+func (s *TunnelSection) unmarshalSystemd(section *systemd.Section) error {
+	s.Comment = section.Comment
+	d := systemd.NewSectionDecoder(section, s.AddKeyComment)
+	d.String("Local", &s.Local)
+	d.String("Remote", &s.Remote)
+	d.String("TOS", &s.TOS)
+	d.String("TTL", &s.TTL)
+	d.BoolPtr("DiscoverPathMTU", &s.DiscoverPathMTU)
+	d.String("IPv6FlowLabel", &s.IPv6FlowLabel)
+	d.BoolPtr("CopyDSCP", &s.CopyDSCP)
+	d.String("EncapsulationLimit", &s.EncapsulationLimit)
+	d.String("Key", &s.Key)
+	d.String("InputKey", &s.InputKey)
+	d.String("OutputKey", &s.OutputKey)
+	d.String("Mode", &s.Mode)
+	d.BoolPtr("Independent", &s.Independent)
+	d.BoolPtr("AssignToLoopback", &s.AssignToLoopback)
+	d.BoolPtr("AllowLocalRemote", &s.AllowLocalRemote)
+	d.BoolPtr("FooOverUDP", &s.FooOverUDP)
+	d.String("FOUDestinationPort", &s.FOUDestinationPort)
+	d.String("FOUSourcePort", &s.FOUSourcePort)
+	d.String("Encapsulation", &s.Encapsulation)
+	d.String("IPv6RapidDeploymentPrefix", &s.IPv6RapidDeploymentPrefix)
+	d.BoolPtr("ISATAP", &s.ISATAP)
+	d.BoolPtr("SerializeTunneledPackets", &s.SerializeTunneledPackets)
+	d.String("ERSPANIndex", &s.ERSPANIndex)
+	d.Unknown(s.AddKey)
+	return d.Err()
+}
+
+func (s *VLANSection) marshalSystemd(name string) (systemd.Section, error) {
+	e := systemd.NewSectionEncoder(name, s.Comment, s.GetKeyComment)
+	e.String("Id", s.Id, true)
+	e.BoolPtr("GVRP", s.GVRP, true)
+	e.BoolPtr("MVRP", s.MVRP, true)
+	e.BoolPtr("LooseBinding", s.LooseBinding, true)
+	e.BoolPtr("ReorderHeader", s.ReorderHeader, true)
+	return e.Section(s.KeyList), e.Err()
+}
+
+func (s *VLANSection) unmarshalSystemd(section *systemd.Section) error {
+	s.Comment = section.Comment
+	d := systemd.NewSectionDecoder(section, s.AddKeyComment)
+	d.String("Id", &s.Id)
+	d.BoolPtr("GVRP", &s.GVRP)
+	d.BoolPtr("MVRP", &s.MVRP)
+	d.BoolPtr("LooseBinding", &s.LooseBinding)
+	d.BoolPtr("ReorderHeader", &s.ReorderHeader)
+	d.Unknown(s.AddKey)
+	return d.Err()
+}
+
+func (s *VRFSection) marshalSystemd(name string) (systemd.Section, error) {
+	e := systemd.NewSectionEncoder(name, s.Comment, s.GetKeyComment)
+	e.String("Table", s.Table, true)
+	return e.Section(s.KeyList), e.Err()
+}
+
+func (s *VRFSection) unmarshalSystemd(section *systemd.Section) error {
+	s.Comment = section.Comment
+	d := systemd.NewSectionDecoder(section, s.AddKeyComment)
+	d.String("Table", &s.Table)
+	d.Unknown(s.AddKey)
+	return d.Err()
+}
+
+func (s *VXCANSection) marshalSystemd(name string) (systemd.Section, error) {
+	e := systemd.NewSectionEncoder(name, s.Comment, s.GetKeyComment)
+	e.String("Peer", s.Peer, true)
+	return e.Section(s.KeyList), e.Err()
+}
+
+func (s *VXCANSection) unmarshalSystemd(section *systemd.Section) error {
+	s.Comment = section.Comment
+	d := systemd.NewSectionDecoder(section, s.AddKeyComment)
+	d.String("Peer", &s.Peer)
+	d.Unknown(s.AddKey)
+	return d.Err()
+}
+
+func (s *VXLANSection) marshalSystemd(name string) (systemd.Section, error) {
+	e := systemd.NewSectionEncoder(name, s.Comment, s.GetKeyComment)
+	e.String("VNI", s.VNI, true)
+	e.String("Remote", s.Remote, true)
+	e.String("Local", s.Local, true)
+	e.String("Group", s.Group, true)
+	e.String("TOS", s.TOS, true)
+	e.String("TTL", s.TTL, true)
+	e.BoolPtr("MacLearning", s.MacLearning, true)
+	e.String("FDBAgeingSec", s.FDBAgeingSec, true)
+	e.String("MaximumFDBEntries", s.MaximumFDBEntries, true)
+	e.BoolPtr("ReduceARPProxy", s.ReduceARPProxy, true)
+	e.BoolPtr("L2MissNotification", s.L2MissNotification, true)
+	e.BoolPtr("L3MissNotification", s.L3MissNotification, true)
+	e.BoolPtr("RouteShortCircuit", s.RouteShortCircuit, true)
+	e.BoolPtr("UDPChecksum", s.UDPChecksum, true)
+	e.BoolPtr("UDP6ZeroChecksumTx", s.UDP6ZeroChecksumTx, true)
+	e.BoolPtr("UDP6ZeroChecksumRx", s.UDP6ZeroChecksumRx, true)
+	e.BoolPtr("RemoteChecksumTx", s.RemoteChecksumTx, true)
+	e.BoolPtr("RemoteChecksumRx", s.RemoteChecksumRx, true)
+	e.BoolPtr("GroupPolicyExtension", s.GroupPolicyExtension, true)
+	e.BoolPtr("GenericProtocolExtension", s.GenericProtocolExtension, true)
+	e.String("DestinationPort", s.DestinationPort, true)
+	e.String("PortRange", s.PortRange, true)
+	e.String("FlowLabel", s.FlowLabel, true)
+	e.String("IPDoNotFragment", s.IPDoNotFragment, true)
+	return e.Section(s.KeyList), e.Err()
+}
+
+func (s *VXLANSection) unmarshalSystemd(section *systemd.Section) error {
+	s.Comment = section.Comment
+	d := systemd.NewSectionDecoder(section, s.AddKeyComment)
+	d.String("VNI", &s.VNI)
+	d.String("Remote", &s.Remote)
+	d.String("Local", &s.Local)
+	d.String("Group", &s.Group)
+	d.String("TOS", &s.TOS)
+	d.String("TTL", &s.TTL)
+	d.BoolPtr("MacLearning", &s.MacLearning)
+	d.String("FDBAgeingSec", &s.FDBAgeingSec)
+	d.String("MaximumFDBEntries", &s.MaximumFDBEntries)
+	d.BoolPtr("ReduceARPProxy", &s.ReduceARPProxy)
+	d.BoolPtr("L2MissNotification", &s.L2MissNotification)
+	d.BoolPtr("L3MissNotification", &s.L3MissNotification)
+	d.BoolPtr("RouteShortCircuit", &s.RouteShortCircuit)
+	d.BoolPtr("UDPChecksum", &s.UDPChecksum)
+	d.BoolPtr("UDP6ZeroChecksumTx", &s.UDP6ZeroChecksumTx)
+	d.BoolPtr("UDP6ZeroChecksumRx", &s.UDP6ZeroChecksumRx)
+	d.BoolPtr("RemoteChecksumTx", &s.RemoteChecksumTx)
+	d.BoolPtr("RemoteChecksumRx", &s.RemoteChecksumRx)
+	d.BoolPtr("GroupPolicyExtension", &s.GroupPolicyExtension)
+	d.BoolPtr("GenericProtocolExtension", &s.GenericProtocolExtension)
+	d.String("DestinationPort", &s.DestinationPort)
+	d.String("PortRange", &s.PortRange)
+	d.String("FlowLabel", &s.FlowLabel)
+	d.String("IPDoNotFragment", &s.IPDoNotFragment)
+	d.Unknown(s.AddKey)
+	return d.Err()
+}
+
+func (s *WireGuardPeerSection) marshalSystemd(name string) (systemd.Section, error) {
+	e := systemd.NewSectionEncoder(name, s.Comment, s.GetKeyComment)
+	e.String("PublicKey", s.PublicKey, true)
+	e.String("PresharedKey", s.PresharedKey, true)
+	e.String("PresharedKeyFile", s.PresharedKeyFile, true)
+	e.String("AllowedIPs", s.AllowedIPs, true)
+	e.String("Endpoint", s.Endpoint, true)
+	e.String("PersistentKeepalive", s.PersistentKeepalive, true)
+	return e.Section(s.KeyList), e.Err()
+}
+
+func (s *WireGuardPeerSection) unmarshalSystemd(section *systemd.Section) error {
+	s.Comment = section.Comment
+	d := systemd.NewSectionDecoder(section, s.AddKeyComment)
+	d.String("PublicKey", &s.PublicKey)
+	d.String("PresharedKey", &s.PresharedKey)
+	d.String("PresharedKeyFile", &s.PresharedKeyFile)
+	d.String("AllowedIPs", &s.AllowedIPs)
+	d.String("Endpoint", &s.Endpoint)
+	d.String("PersistentKeepalive", &s.PersistentKeepalive)
+	d.Unknown(s.AddKey)
+	return d.Err()
+}
+
+func (s *WireGuardSection) marshalSystemd(name string) (systemd.Section, error) {
+	e := systemd.NewSectionEncoder(name, s.Comment, s.GetKeyComment)
+	e.String("PrivateKey", s.PrivateKey, true)
+	e.String("PrivateKeyFile", s.PrivateKeyFile, true)
+	e.String("ListenPort", s.ListenPort, true)
+	e.String("FirewallMark", s.FirewallMark, true)
+	return e.Section(s.KeyList), e.Err()
+}
+
+func (s *WireGuardSection) unmarshalSystemd(section *systemd.Section) error {
+	s.Comment = section.Comment
+	d := systemd.NewSectionDecoder(section, s.AddKeyComment)
+	d.String("PrivateKey", &s.PrivateKey)
+	d.String("PrivateKeyFile", &s.PrivateKeyFile)
+	d.String("ListenPort", &s.ListenPort)
+	d.String("FirewallMark", &s.FirewallMark)
+	d.Unknown(s.AddKey)
+	return d.Err()
+}
+
+func (s *XfrmSection) marshalSystemd(name string) (systemd.Section, error) {
+	e := systemd.NewSectionEncoder(name, s.Comment, s.GetKeyComment)
+	e.String("InterfaceId", s.InterfaceId, true)
+	e.BoolPtr("Independent", s.Independent, true)
+	return e.Section(s.KeyList), e.Err()
+}
+
+func (s *XfrmSection) unmarshalSystemd(section *systemd.Section) error {
+	s.Comment = section.Comment
+	d := systemd.NewSectionDecoder(section, s.AddKeyComment)
+	d.String("InterfaceId", &s.InterfaceId)
+	d.BoolPtr("Independent", &s.Independent)
+	d.Unknown(s.AddKey)
+	return d.Err()
+}
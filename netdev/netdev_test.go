@@ -1,10 +1,12 @@
 package netdev
 
 import (
+	"encoding/json"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
 
 	"routerd.net/go-systemd"
 )
@@ -178,6 +180,54 @@ Kind=xfrm
 
 [Xfrm]
 Independent=yes
+`
+
+	// example 17 + comments, repeated and unknown sections and keys
+	example19 = `[NetDev]
+Name=wg0
+Kind=wireguard
+MTUBytes=
+Foo=bar
+
+# wireguard
+[WireGuard]
+PrivateKey=EEGlnEPYJV//kbvvIqxKkQwOiS+UENyPncC4bF46ong=
+ListenPort=51820
+
+[WireGuardPeer]
+# peer 1
+PublicKey=RDf+LSpeEre7YEIKaxg+wbpsNV7du+ktR99uBEtIiCA=
+AllowedIPs=fd31:bf08:57cb::/48,192.168.26.0/24
+PersistentKeepalive=25
+
+[WireGuardPeer]
+PublicKey=gN65BkIKy1eCE9pP1wdc8ROUtkHLF2PfAqYdyYBz6EA=
+AllowedIPs=fd31:bf08:57cb::/48
+
+[X-Vendor]
+Mode=fast
+`
+
+	// kinds added in later systemd versions
+	example20 = `[NetDev]
+Name=bat0
+Kind=batadv
+
+[BatmanAdvanced]
+GatewayMode=server
+DistributedArpTable=yes
+HopPenalty=10
+RoutingAlgorithm=batman-v
+`
+
+	example21 = `[NetDev]
+Name=ib1
+Kind=ipoib
+
+[IPoIB]
+PartitionKey=0x8001
+Mode=connected
+IgnoreUserspaceMulticastGroups=no
 `
 )
 
@@ -205,6 +255,8 @@ func TestNetDev(t *testing.T) {
 			{Name: "Example 16", File: example16},
 			{Name: "Example 17", File: example17},
 			{Name: "Example 18", File: example18},
+			{Name: "Example 20", File: example20},
+			{Name: "Example 21", File: example21},
 		}
 
 		for _, test := range tests {
@@ -219,4 +271,174 @@ func TestNetDev(t *testing.T) {
 			})
 		}
 	})
+
+	t.Run("test JSON and YAML conversion", func(t *testing.T) {
+		tests := []struct {
+			Name string
+			File string
+		}{
+			{Name: "Example 1", File: example1},
+			{Name: "Example 2", File: example2},
+			{Name: "Example 3", File: example3},
+			{Name: "Example 4", File: example4},
+			{Name: "Example 5", File: example5},
+			{Name: "Example 6", File: example6},
+			{Name: "Example 7", File: example7},
+			{Name: "Example 8", File: example8},
+			{Name: "Example 9", File: example9},
+			{Name: "Example 10", File: example10},
+			{Name: "Example 11", File: example11},
+			{Name: "Example 12", File: example12},
+			{Name: "Example 13", File: example13},
+			{Name: "Example 14", File: example14},
+			{Name: "Example 15", File: example15},
+			{Name: "Example 16", File: example16},
+			{Name: "Example 17", File: example17},
+			{Name: "Example 18", File: example18},
+			{Name: "Example 20", File: example20},
+			{Name: "Example 21", File: example21},
+		}
+
+		for _, test := range tests {
+			t.Run(test.Name, func(t *testing.T) {
+				in := &NetDev{}
+				require.NoError(t, systemd.Unmarshal([]byte(test.File), in))
+
+				b, err := json.Marshal(in)
+				require.NoError(t, err, "error in JSON marshal")
+				fromJSON := &NetDev{}
+				require.NoError(t, json.Unmarshal(b, fromJSON), "error in JSON unmarshal")
+
+				b, err = yaml.Marshal(fromJSON)
+				require.NoError(t, err, "error in YAML marshal")
+				fromYAML := &NetDev{}
+				require.NoError(t, yaml.Unmarshal(b, fromYAML), "error in YAML unmarshal")
+
+				b, err = systemd.Marshal(fromYAML)
+				require.NoError(t, err, "error in marshal")
+				assert.Equal(t, test.File, string(b))
+			})
+		}
+	})
+
+	t.Run("test repeated keys", func(t *testing.T) {
+		in := &NetDev{}
+		require.NoError(t, systemd.Unmarshal([]byte(`[NetDev]
+Name=macvlan0
+Kind=macvlan
+Kind=bridge
+
+[MACVLAN]
+Mode=source
+SourceMACAddress=12:34:56:78:9a:bc
+SourceMACAddress=12:34:56:78:9a:bd 12:34:56:78:9a:be
+`), in))
+		// the kind of a netdev cannot be changed
+		assert.Equal(t, "macvlan", in.NetDev.Kind)
+		assert.Equal(t, []string{"12:34:56:78:9a:bc", "12:34:56:78:9a:bd", "12:34:56:78:9a:be"}, in.MACVLAN.SourceMACAddress)
+	})
+
+	t.Run("test marshal for version", func(t *testing.T) {
+		in := &NetDev{
+			NetDev: NetDevSection{Name: "vxlan0", Kind: "vxlan"},
+			VXLAN:  &VXLANSection{VNI: "42"},
+		}
+		b, err := systemd.MarshalForVersion(in, 242)
+		require.NoError(t, err)
+		assert.Equal(t, "[NetDev]\nName=vxlan0\nKind=vxlan\n\n[VXLAN]\nId=42\n", string(b))
+
+		b, err = systemd.MarshalForVersion(in, 243)
+		require.NoError(t, err)
+		assert.Equal(t, "[NetDev]\nName=vxlan0\nKind=vxlan\n\n[VXLAN]\nVNI=42\n", string(b))
+
+		_, err = systemd.MarshalForVersion(&NetDev{Xfrm: &XfrmSection{}}, 242)
+		assert.Equal(t, &systemd.VersionError{Section: "Xfrm", Since: 243, Version: 242}, err)
+	})
+
+	t.Run("test generated code", func(t *testing.T) {
+		tests := []struct {
+			Name string
+			File string
+		}{
+			{Name: "Example 1", File: example1},
+			{Name: "Example 2", File: example2},
+			{Name: "Example 3", File: example3},
+			{Name: "Example 4", File: example4},
+			{Name: "Example 5", File: example5},
+			{Name: "Example 6", File: example6},
+			{Name: "Example 7", File: example7},
+			{Name: "Example 8", File: example8},
+			{Name: "Example 9", File: example9},
+			{Name: "Example 10", File: example10},
+			{Name: "Example 11", File: example11},
+			{Name: "Example 12", File: example12},
+			{Name: "Example 13", File: example13},
+			{Name: "Example 14", File: example14},
+			{Name: "Example 15", File: example15},
+			{Name: "Example 16", File: example16},
+			{Name: "Example 17", File: example17},
+			{Name: "Example 18", File: example18},
+			{Name: "Example 20", File: example20},
+			{Name: "Example 21", File: example21},
+			{Name: "Unknown sections and keys", File: example19},
+		}
+
+		for _, test := range tests {
+			t.Run(test.Name, func(t *testing.T) {
+				generated := &NetDev{}
+				require.NoError(t, systemd.Unmarshal([]byte(test.File), generated))
+				reflected := &reflectNetDev{}
+				require.NoError(t, systemd.Unmarshal([]byte(test.File), reflected))
+				assert.Equal(t, (NetDev)(*reflected), *generated)
+
+				b, err := systemd.Marshal(generated)
+				require.NoError(t, err)
+				expected, err := systemd.Marshal(reflected)
+				require.NoError(t, err)
+				assert.Equal(t, string(expected), string(b))
+			})
+		}
+	})
+}
+
+// reflectNetDev has the fields of NetDev without its generated methods,
+// so it is encoded by reflection.
+type reflectNetDev NetDev
+
+func BenchmarkNetDev(b *testing.B) {
+	file, err := systemd.Decode([]byte(example19))
+	require.NoError(b, err)
+
+	b.Run("unmarshal generated", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if err := file.Unmarshal(&NetDev{}); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("unmarshal reflection", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if err := file.Unmarshal(&reflectNetDev{}); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	generated := &NetDev{}
+	require.NoError(b, file.Unmarshal(generated))
+	reflected := (*reflectNetDev)(generated)
+	b.Run("marshal generated", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := systemd.Marshal(generated); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("marshal reflection", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := systemd.Marshal(reflected); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
 }
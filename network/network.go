@@ -25,26 +25,29 @@ type Network struct {
 
 	Match                           *MatchSection
 	Link                            *LinkSection
-	SRIOVs                          []SRIOVSection `systemd:"SR-IOV"`
+	SRIOVs                          []SRIOVSection `systemd:"SR-IOV,since=246"`
 	Network                         *NetworkSection
 	Addresses                       []AddressSection           `systemd:"Address"`
 	Neighbors                       []NeighborSection          `systemd:"Neighbor"`
 	IPv6AddressLabels               []IPv6AddressLabelSection  `systemd:"IPv6AddressLabel"`
 	RoutingPolicyRules              []RoutingPolicyRuleSection `systemd:"RoutingPolicyRule"`
-	NextHops                        []NextHopSection           `systemd:"NextHop"`
+	NextHops                        []NextHopSection           `systemd:"NextHop,since=244"`
 	Routes                          []RouteSection             `systemd:"Route"`
 	DHCPv4                          *DHCPv4Section
 	DHCPv6                          *DHCPv6Section
-	DHCPv6PrefixDelegation          *DHCPv6PrefixDelegationSection
+	DHCPPrefixDelegation            *DHCPPrefixDelegationSection   `systemd:",since=250,legacy=DHCPv6PrefixDelegation"`
+	DHCPv6PrefixDelegation          *DHCPv6PrefixDelegationSection `systemd:",since=247,deprecated=250"`
 	IPv6AcceptRA                    *IPv6AcceptRASection
 	DHCPServer                      *DHCPServerSection
-	IPv6PrefixDelegation            *IPv6PrefixDelegationSection
-	IPv6Prefixes                    []IPv6PrefixSection      `systemd:"IPv6Prefix"`
-	IPv6RoutePrefixes               []IPv6RoutePrefixSection `systemd:"IPv6RoutePrefix"`
+	DHCPServerStaticLeases          []DHCPServerStaticLeaseSection `systemd:"DHCPServerStaticLease,since=249"`
+	IPv6PrefixDelegation            *IPv6PrefixDelegationSection   `systemd:",deprecated=247"`
+	IPv6Prefixes                    []IPv6PrefixSection            `systemd:"IPv6Prefix"`
+	IPv6RoutePrefixes               []IPv6RoutePrefixSection       `systemd:"IPv6RoutePrefix"`
 	Bridge                          *BridgeSection
 	BridgeFDBs                      []BridgeFDBSection `systemd:"BridgeFDB"`
 	LLDP                            *LLDPSection
 	CAN                             *CANSection
+	IPoIB                           *IPoIBSection `systemd:",since=250"`
 	QDisc                           *QDiscSection
 	NetworkEmulator                 *NetworkEmulatorSection
 	TokenBucketFilters              []TokenBucketFilterSection          `systemd:"TokenBucketFilter"`
@@ -84,49 +87,52 @@ type MatchSection struct {
 	// A whitespace-separated list of hardware addresses. Use full colon-, hyphen- or dot-delimited hexadecimal. See the example below. This option may appear more than once, in which case the lists are merged. If the empty string is assigned to this option, the list of hardware addresses defined prior to this is reset.
 	// Example:
 	// MACAddress=01:23:45:67:89:ab 00-11-22-33-44-55 AABB.CCDD.EEFF
-	PermanentMACAddress string `systemd:",omitempty"`
+	MACAddress []string `systemd:",omitempty,wslist,unordered"`
+
+	// A whitespace-separated list of hardware's permanent addresses. While MACAddress= matches the device's current MAC address, this matches the device's permanent MAC address, which may be different from the current one. Use full colon-, hyphen- or dot-delimited hexadecimal. This option may appear more than once, in which case the lists are merged. If the empty string is assigned to this option, the list of hardware addresses defined prior to this is reset.
+	PermanentMACAddress []string `systemd:",omitempty,wslist,unordered,since=245"`
 
 	// A whitespace-separated list of shell-style globs matching the persistent path, as exposed by the udev property ID_PATH.
-	Path string `systemd:",omitempty"`
+	Path []string `systemd:",omitempty,wslist,unordered"`
 
 	// A whitespace-separated list of shell-style globs matching the driver currently bound to the device, as exposed by the udev property ID_NET_DRIVER of its parent device, or if that is not set, the driver as exposed by ethtool -i of the device itself. If the list is prefixed with a "!", the test is inverted.
-	Driver string `systemd:",omitempty"`
+	Driver []string `systemd:",omitempty,wslist,unordered"`
 
 	// A whitespace-separated list of shell-style globs matching the device type, as exposed by networkctl status. If the list is prefixed with a "!", the test is inverted.
-	Type string `systemd:",omitempty"`
+	Type []string `systemd:",omitempty,wslist,unordered"`
 
 	// A whitespace-separated list of udev property name with its value after a equal ("="). If multiple properties are specified, the test results are ANDed. If the list is prefixed with a "!", the test is inverted. If a value contains white spaces, then please quote whole key and value pair. If a value contains quotation, then please escape the quotation with "\".
 	// Example: if a .link file has the following:
 	// Property=ID_MODEL_ID=9999 "ID_VENDOR_FROM_DATABASE=vendor name" "KEY=with \"quotation\""
 	// then, the .link file matches only when an interface has all the above three properties.
-	Property string `systemd:",omitempty"`
+	Property []string `systemd:",omitempty,wslist,unordered"`
 
 	// A whitespace-separated list of shell-style globs matching the device name, as exposed by the udev property "INTERFACE", or device's alternative names. If the list is prefixed with a "!", the test is inverted.
-	Name string `systemd:",omitempty"`
+	Name []string `systemd:",omitempty,wslist,unordered"`
 
 	// A whitespace-separated list of wireless network type. Supported values are "ad-hoc", "station", "ap", "ap-vlan", "wds", "monitor", "mesh-point", "p2p-client", "p2p-go", "p2p-device", "ocb", and "nan". If the list is prefixed with a "!", the test is inverted.
-	WLANInterfaceType string `systemd:",omitempty"`
+	WLANInterfaceType []string `systemd:",omitempty,wslist,unordered"`
 
 	// A whitespace-separated list of shell-style globs matching the SSID of the currently connected wireless LAN. If the list is prefixed with a "!", the test is inverted.
-	SSID string `systemd:",omitempty"`
+	SSID []string `systemd:",omitempty,wslist,unordered"`
 
 	// A whitespace-separated list of hardware address of the currently connected wireless LAN. Use full colon-, hyphen- or dot-delimited hexadecimal. See the example in MACAddress=. This option may appear more than once, in which case the lists are merged. If the empty string is assigned to this option, the list is reset.
-	BSSID string `systemd:",omitempty"`
+	BSSID []string `systemd:",omitempty,wslist,unordered"`
 
 	// Matches against the hostname or machine ID of the host. See ConditionHost= in systemd.unit(5) for details. When prefixed with an exclamation mark ("!"), the result is negated. If an empty string is assigned, then previously assigned value is cleared.
-	Host string `systemd:",omitempty"`
+	Host string `systemd:",omitempty,lastwins"`
 
 	// Checks whether the system is executed in a virtualized environment and optionally test whether it is a specific implementation. See ConditionVirtualization= in systemd.unit(5) for details. When prefixed with an exclamation mark ("!"), the result is negated. If an empty string is assigned, then previously assigned value is cleared.
-	Virtualization string `systemd:",omitempty"`
+	Virtualization string `systemd:",omitempty,lastwins"`
 
 	// Checks whether a specific kernel command line option is set. See ConditionKernelCommandLine= in systemd.unit(5) for details. When prefixed with an exclamation mark ("!"), the result is negated. If an empty string is assigned, then previously assigned value is cleared.
-	KernelCommandLine string `systemd:",omitempty"`
+	KernelCommandLine string `systemd:",omitempty,lastwins"`
 
 	// Checks whether the kernel version (as reported by uname -r) matches a certain expression. See ConditionKernelVersion= in systemd.unit(5) for details. When prefixed with an exclamation mark ("!"), the result is negated. If an empty string is assigned, then previously assigned value is cleared.
-	KernelVersion string `systemd:",omitempty"`
+	KernelVersion string `systemd:",omitempty,lastwins"`
 
 	// Checks whether the system is running on a specific architecture. See ConditionArchitecture= in systemd.unit(5) for details. When prefixed with an exclamation mark ("!"), the result is negated. If an empty string is assigned, then previously assigned value is cleared.
-	Architecture string `systemd:",omitempty"`
+	Architecture string `systemd:",omitempty,lastwins"`
 }
 
 type LinkSection struct {
@@ -225,7 +231,7 @@ type NetworkSection struct {
 	LinkLocalAddressing string `systemd:",omitempty"`
 
 	// Specifies how IPv6 link local address is generated. Takes one of "eui64", "none", "stable-privacy" and "random". When unset, the kernel's default will be used. Note that if LinkLocalAdressing= not configured as "ipv6" then IPv6LinkLocalAddressGenerationMode= is ignored.
-	IPv6LinkLocalAddressGenerationMode string `systemd:",omitempty"`
+	IPv6LinkLocalAddressGenerationMode string `systemd:",omitempty,since=246"`
 
 	// Takes a boolean. If set to true, sets up the route needed for non-IPv4LL hosts to communicate with IPv4LL-only hosts. Defaults to false.
 	IPv4LLRoute string `systemd:",omitempty"`
@@ -254,7 +260,7 @@ type NetworkSection struct {
 	DNSSEC string `systemd:",omitempty"`
 
 	// A space-separated list of DNSSEC negative trust anchor domains. If specified and DNSSEC is enabled, look-ups done via the interface's DNS server will be subject to the list of negative trust anchors, and not require authentication for the specified domains, or anything below it. Use this to disable DNSSEC authentication for specific private domains, that cannot be proven valid using the Internet DNS hierarchy. Defaults to the empty list. This setting is read by systemd-resolved.service(8).
-	DNSSECNegativeTrustAnchors string `systemd:",omitempty"`
+	DNSSECNegativeTrustAnchors []string `systemd:",omitempty,wslist"`
 
 	// Controls support for Ethernet LLDP packet reception. LLDP is a link-layer protocol commonly implemented on professional routers and bridges which announces which physical port a system is connected to, as well as other related data. Accepts a boolean or the special value "routers-only". When true, incoming LLDP packets are accepted and a database of all LLDP neighbors maintained. If "routers-only" is set only LLDP data of various types of routers is collected and LLDP data about other types of devices ignored (such as stations, telephones and others). If false, LLDP reception is disabled. Defaults to "routers-only". Use networkctl(1) to query the collected neighbor data. LLDP is only available on Ethernet links. See EmitLLDP= below for enabling LLDP packet emission from the local system.
 	LLDP string `systemd:",omitempty"`
@@ -268,13 +274,13 @@ type NetworkSection struct {
 	// A static IPv4 or IPv6 address and its prefix length, separated by a "/" character. Specify this key more than once to configure several addresses. The format of the address must be as described in inet_pton(3). This is a short-hand for an [Address] section only containing an Address key (see below). This option may be specified more than once.
 	//
 	// If the specified address is "0.0.0.0" (for IPv4) or "::" (for IPv6), a new address range of the requested size is automatically allocated from a system-wide pool of unused ranges. Note that the prefix length must be equal or larger than 8 for IPv4, and 64 for IPv6. The allocated range is checked against all current network interfaces and all known network configuration files to avoid address range conflicts. The default system-wide pool consists of 192.168.0.0/16, 172.16.0.0/12 and 10.0.0.0/8 for IPv4, and fd00::/8 for IPv6. This functionality is useful to manage a large number of dynamically created network interfaces with the same network configuration and automatic address range assignment.
-	Address string `systemd:",omitempty"`
+	Address []string `systemd:",omitempty,append"`
 
 	// The gateway address, which must be in the format described in inet_pton(3). This is a short-hand for a [Route] section only containing a Gateway key. This option may be specified more than once.
-	Gateway string `systemd:",omitempty"`
+	Gateway []string `systemd:",omitempty,append"`
 
 	// A DNS server address, which must be in the format described in inet_pton(3). This option may be specified more than once. Each address can optionally take a port number separated with ":", a network interface name or index separated with "%", and a Server Name Indication (SNI) separated with "#". When IPv6 address is specified with a port number, then the address must be in the square brackets. That is, the acceptable full formats are "111.222.333.444:9953%ifname#example.com" for IPv4 and "[1111:2222::3333]:9953%ifname#example.com" for IPv6. This setting can be specified multiple times. If an empty string is assigned, then the all previous assignments are cleared. This setting is read by systemd-resolved.service(8).
-	DNS string `systemd:",omitempty"`
+	DNS []string `systemd:",omitempty"`
 
 	// A whitespace-separated list of domains which should be resolved using the DNS servers on this link. Each item in the list should be a domain name, optionally prefixed with a tilde ("~"). The domains with the prefix are called "routing-only domains". The domains without the prefix are called "search domains" and are first used as search suffixes for extending single-label hostnames (hostnames containing no dots) to become fully qualified domain names (FQDNs). If a single-label hostname is resolved on this interface, each of the specified search domains are appended to it in turn, converting it into a fully qualified domain name, until one of them may be successfully resolved.
 	//
@@ -283,13 +289,13 @@ type NetworkSection struct {
 	// The "routing-only" domain "~." (the tilde indicating definition of a routing domain, the dot referring to the DNS root domain which is the implied suffix of all valid DNS names) has special effect. It causes all DNS traffic which does not match another configured domain routing entry to be routed to DNS servers specified for this interface. This setting is useful to prefer a certain set of DNS servers if a link on which they are connected is available.
 	//
 	// This setting is read by systemd-resolved.service(8). "Search domains" correspond to the domain and search entries in resolv.conf(5). Domain name routing has no equivalent in the traditional glibc API, which has no concept of domain name servers limited to a specific link.
-	Domains string `systemd:",omitempty"`
+	Domains []string `systemd:",omitempty,wslist"`
 
 	// Takes a boolean argument. If true, this link's configured DNS servers are used for resolving domain names that do not match any link's configured Domains= setting. If false, this link's configured DNS servers are never used for such domains, and are exclusively used for resolving names that match at least one of the domains configured on this link. If not specified defaults to an automatic mode: queries not matching any link's configured domains will be routed to this link if it has no routing-only domains configured.
 	DNSDefaultRoute string `systemd:",omitempty"`
 
 	// An NTP server address (either an IP address, or a hostname). This option may be specified more than once. This setting is read by systemd-timesyncd.service(8).
-	NTP string `systemd:",omitempty"`
+	NTP []string `systemd:",omitempty"`
 
 	// Configures IP packet forwarding for the system. If enabled, incoming packets on any network interface will be forwarded to any other interfaces according to the routing table. Takes a boolean, or the values "ipv4" or "ipv6", which only enable IP packet forwarding for the specified address family. This controls the net.ipv4.ip_forward and net.ipv6.conf.all.forwarding sysctl options of the network interface (see ip-sysctl.txt for details about sysctl options). Defaults to "no".
 	//
@@ -320,7 +326,7 @@ type NetworkSection struct {
 	IPv6HopLimit string `systemd:",omitempty"`
 
 	// Takes a boolean. Accept packets with local source addresses. In combination with suitable routing, this can be used to direct packets between two local interfaces over the wire and have them accepted properly. When unset, the kernel's default will be used.
-	IPv4AcceptLocal string `systemd:",omitempty"`
+	IPv4AcceptLocal string `systemd:",omitempty,since=246"`
 
 	// Takes a boolean. Configures proxy ARP for IPv4. Proxy ARP is the technique in which one host, usually a router, answers ARP requests intended for another machine. By "faking" its identity, the router accepts responsibility for routing packets to the "real" destination. See RFC 1027. When unset, the kernel's default will be used.
 	IPv4ProxyARP string `systemd:",omitempty"`
@@ -329,10 +335,13 @@ type NetworkSection struct {
 	IPv6ProxyNDP string `systemd:",omitempty"`
 
 	// An IPv6 address, for which Neighbour Advertisement messages will be proxied. This option may be specified more than once. systemd-networkd will add the IPv6ProxyNDPAddress= entries to the kernel's IPv6 neighbor proxy table. This option implies IPv6ProxyNDP=yes but has no effect if IPv6ProxyNDP has been set to false. When unset, the kernel's default will be used.
-	IPv6ProxyNDPAddress string `systemd:",omitempty"`
+	IPv6ProxyNDPAddress []string `systemd:",omitempty"`
 
 	// Whether to enable or disable Router Advertisement sending on a link. Allowed values are "static" which distributes prefixes as defined in the [IPv6PrefixDelegation] and any [IPv6Prefix] sections, "dhcpv6" which requests prefixes using a DHCPv6 client configured for another link and any values configured in the [IPv6PrefixDelegation] section while ignoring all static prefix configuration sections, "yes" which uses both static configuration and DHCPv6, and "false" which turns off IPv6 prefix delegation altogether. Defaults to "false". See the [IPv6PrefixDelegation] and the [IPv6Prefix] sections for more configuration options.
-	IPv6PrefixDelegation string `systemd:",omitempty"`
+	IPv6PrefixDelegation string `systemd:",omitempty,deprecated=247"`
+
+	// Takes a boolean value. When enabled, requests subnet prefixes on another link via the DHCPv6 protocol or via the 6RD option in the DHCPv4 protocol, and assigns the prefixes to the link. See the [DHCPPrefixDelegation] section for more details. Defaults to disabled.
+	DHCPPrefixDelegation string `systemd:",omitempty,since=250"`
 
 	// Configures IPv6 maximum transmission unit (MTU). An integer greater than or equal to 1280 bytes. When unset, the kernel's default will be used.
 	IPv6MTUBytes string `systemd:",omitempty"`
@@ -346,23 +355,26 @@ type NetworkSection struct {
 	// The name of the VRF to add the link to. See systemd.netdev(5).
 	VRF string `systemd:",omitempty"`
 
+	// The name of the B.A.T.M.A.N. Advanced interface to add the link to. See systemd.netdev(5).
+	BatmanAdvanced string `systemd:",omitempty,since=248"`
+
 	// The name of a VLAN to create on the link. See systemd.netdev(5). This option may be specified more than once.
-	VLAN string `systemd:",omitempty"`
+	VLAN []string `systemd:",omitempty,append"`
 
 	// The name of a IPVLAN to create on the link. See systemd.netdev(5). This option may be specified more than once.
-	IPVLAN string `systemd:",omitempty"`
+	IPVLAN []string `systemd:",omitempty,append"`
 
 	// The name of a MACVLAN to create on the link. See systemd.netdev(5). This option may be specified more than once.
-	MACVLAN string `systemd:",omitempty"`
+	MACVLAN []string `systemd:",omitempty,append"`
 
 	// The name of a VXLAN to create on the link. See systemd.netdev(5). This option may be specified more than once.
-	VXLAN string `systemd:",omitempty"`
+	VXLAN []string `systemd:",omitempty,append"`
 
 	// The name of a Tunnel to create on the link. See systemd.netdev(5). This option may be specified more than once.
-	Tunnel string `systemd:",omitempty"`
+	Tunnel []string `systemd:",omitempty,append"`
 
 	// The name of a MACsec device to create on the link. See systemd.netdev(5). This option may be specified more than once.
-	MACsec string `systemd:",omitempty"`
+	MACsec []string `systemd:",omitempty,append"`
 
 	ActiveSlave string `systemd:",omitempty"`
 	// Takes a boolean. Specifies the new active slave. The "ActiveSlave=" option is only valid for following modes: "active-backup", "balance-alb" and "balance-tlb". Defaults to false.
@@ -377,7 +389,7 @@ type NetworkSection struct {
 	IgnoreCarrierLoss string `systemd:",omitempty"`
 
 	// The name of the xfrm to create on the link. See systemd.netdev(5). This option may be specified more than once.
-	Xfrm string `systemd:",omitempty"`
+	Xfrm []string `systemd:",omitempty,append"`
 
 	// Takes a boolean or one of "static", "dhcp-on-stop", "dhcp". When "static", systemd-networkd will not drop static addresses and routes on starting up process. When set to "dhcp-on-stop", systemd-networkd will not drop addresses and routes on stopping the daemon. When "dhcp", the addresses and routes provided by a DHCP server will never be dropped even if the DHCP lease expires. This is contrary to the DHCP specification, but may be the best choice if, e.g., the root filesystem relies on this connection. The setting "dhcp" implies "dhcp-on-stop", and "yes" implies "dhcp" and "static". Defaults to "no".
 	KeepConfiguration string `systemd:",omitempty"`
@@ -578,7 +590,7 @@ type RouteSection struct {
 
 	// address[@name] [weight]
 	// Configures multipath route. Multipath routing is the technique of using multiple alternative paths through a network. Takes gateway address. Optionally, takes a network interface name or index separated with "@", and a weight in 1..256 for this multipath route separated with whitespace. This setting can be specified multiple times. If an empty string is assigned, then the all previous assignments are cleared.
-	MultiPathRoute string `systemd:",omitempty"`
+	MultiPathRoute []string `systemd:",omitempty"`
 }
 
 // The [DHCPv4] section configures the DHCPv4 client, if it is enabled with the DHCP= setting described above:
@@ -617,7 +629,7 @@ type DHCPv4Section struct {
 	SendHostname string `systemd:",omitempty"`
 
 	// When configured, the Manufacturer Usage Descriptions (MUD) URL will be sent to the DHCPv4 server. Takes an URL of length up to 255 characters. A superficial verification that the string is a valid URL will be performed. DHCPv4 clients are intended to have at most one MUD URL associated with them. See RFC 8520.
-	MUDURL string `systemd:",omitempty"`
+	MUDURL string `systemd:",omitempty,since=246"`
 
 	// When true (the default), the hostname received from the DHCP server will be set as the transient hostname of the system.
 	UseHostname string `systemd:",omitempty"`
@@ -648,7 +660,7 @@ type DHCPv4Section struct {
 	VendorClassIdentifier string `systemd:",omitempty"`
 
 	// A DHCPv4 client can use UserClass option to identify the type or category of user or applications it represents. The information contained in this option is a string that represents the user class of which the client is a member. Each class sets an identifying string of information to be used by the DHCP service to classify clients. Takes a whitespace-separated list of strings.
-	UserClass string `systemd:",omitempty"`
+	UserClass []string `systemd:",omitempty,wslist"`
 
 	// Specifies how many times the DHCPv4 client configuration should be attempted. Takes a number or "infinity". Defaults to "infinity". Note that the time between retries is increased exponentially, so the network will not be overloaded even if this number is high.
 	MaxAttempts string `systemd:",omitempty"`
@@ -680,7 +692,7 @@ type DHCPv4Section struct {
 	ListenPort string `systemd:",omitempty"`
 
 	// Allows to set DHCPv4 lease lifetime when DHCPv4 server does not send the lease lifetime. Takes one of "forever" or "infinity" means that the address never expires. Defaults to unset.
-	FallbackLeaseLifetimeSec string `systemd:",omitempty"`
+	FallbackLeaseLifetimeSec string `systemd:",omitempty,since=246"`
 
 	// When true, the DHCPv4 client sends a DHCP release packet when it stops. Defaults to true.
 	SendRelease string `systemd:",omitempty"`
@@ -689,19 +701,19 @@ type DHCPv4Section struct {
 	SendDecline string `systemd:",omitempty"`
 
 	// A whitespace-separated list of IPv4 addresses. DHCP offers from servers in the list are rejected. Note that if AllowList= is configured then DenyList= is ignored.
-	DenyList string `systemd:",omitempty"`
+	DenyList []string `systemd:",omitempty,wslist,since=246,legacy=BlackList"`
 
 	// A whitespace-separated list of IPv4 addresses. DHCP offers from servers in the list are accepted.
-	AllowList string `systemd:",omitempty"`
+	AllowList []string `systemd:",omitempty,wslist,since=246"`
 
 	// When configured, allows to set arbitrary request options in the DHCPv4 request options list and will be sent to the DHCPV4 server. A whitespace-separated list of integers in the range 1..254. Defaults to unset.
-	RequestOptions string `systemd:",omitempty"`
+	RequestOptions []string `systemd:",omitempty,wslist"`
 
 	// Send an arbitrary raw option in the DHCPv4 request. Takes a DHCP option number, data type and data separated with a colon ("option:type:value"). The option number must be an integer in the range 1..254. The type takes one of "uint8", "uint16", "uint32", "ipv4address", or "string". Special characters in the data string may be escaped using C-style escapes. This setting can be specified multiple times. If an empty string is specified, then all options specified earlier are cleared. Defaults to unset.
-	SendOption string `systemd:",omitempty"`
+	SendOption []string `systemd:",omitempty"`
 
 	// Send an arbitrary vendor option in the DHCPv4 request. Takes a DHCP option number, data type and data separated with a colon ("option:type:value"). The option number must be an integer in the range 1..254. The type takes one of "uint8", "uint16", "uint32", "ipv4address", or "string". Special characters in the data string may be escaped using C-style escapes. This setting can be specified multiple times. If an empty string is specified, then all options specified earlier are cleared. Defaults to unset.
-	SendVendorOption string `systemd:",omitempty"`
+	SendVendorOption []string `systemd:",omitempty"`
 }
 
 // The [DHCPv6] section configures the DHCPv6 client, if it is enabled with the DHCP= setting described above, or invoked by the IPv6 Router Advertisement:
@@ -722,13 +734,13 @@ type DHCPv6Section struct {
 	RapidCommit string `systemd:",omitempty"`
 
 	// When configured, the Manufacturer Usage Descriptions (MUD) URL will be sent to the DHCPV6 server. Takes an URL of length up to 255 characters. A superficial verification that the string is a valid URL will be performed. DHCPv6 clients are intended to have at most one MUD URL associated with them. See RFC 8520.
-	MUDURL string `systemd:",omitempty"`
+	MUDURL string `systemd:",omitempty,since=246"`
 
 	// When configured, allows to set arbitrary request options in the DHCPv6 request options list and will sent to the DHCPV6 server. A whitespace-separated list of integers in the range 1..254. Defaults to unset.
-	RequestOptions string `systemd:",omitempty"`
+	RequestOptions []string `systemd:",omitempty,wslist"`
 
 	// Send an arbitrary vendor option in the DHCPv6 request. Takes an enterprise identifier, DHCP option number, data type, and data separated with a colon ("enterprise identifier:option:type: value"). Enterprise identifier is an unsigned integer in the range 1–4294967294. The option number must be an integer in the range 1–254. Data type takes one of "uint8", "uint16", "uint32", "ipv4address", "ipv6address", or "string". Special characters in the data string may be escaped using C-style escapes. This setting can be specified multiple times. If an empty string is specified, then all options specified earlier are cleared. Defaults to unset.
-	SendVendorOption string `systemd:",omitempty"`
+	SendVendorOption []string `systemd:",omitempty,since=246"`
 
 	// Takes a boolean that enforces DHCPv6 stateful mode when the 'Other information' bit is set in Router Advertisement messages. By default setting only the 'O' bit in Router Advertisements makes DHCPv6 request network information in a stateless manner using a two-message Information Request and Information Reply message exchange. RFC 7084, requirement WPD-4, updates this behavior for a Customer Edge router so that stateful DHCPv6 Prefix Delegation is also requested when only the 'O' bit is set in Router Advertisements. This option enables such a CE behavior as it is impossible to automatically distinguish the intention of the 'O' bit otherwise. By default this option is set to 'false', enable it if no prefixes are delegated when the device should be acting as a CE router.
 	ForceDHCPv6PDOtherInformation string `systemd:",omitempty"`
@@ -743,13 +755,43 @@ type DHCPv6Section struct {
 	SendOption string `systemd:",omitempty"`
 
 	// A DHCPv6 client can use User Class option to identify the type or category of user or applications it represents. The information contained in this option is a string that represents the user class of which the client is a member. Each class sets an identifying string of information to be used by the DHCP service to classify clients. Special characters in the data string may be escaped using C-style escapes. This setting can be specified multiple times. If an empty string is specified, then all options specified earlier are cleared. Takes a whitespace-separated list of strings. Note that currently NUL bytes are not allowed.
-	UserClass string `systemd:",omitempty"`
+	UserClass []string `systemd:",omitempty,wslist,since=246"`
 
 	// A DHCPv6 client can use VendorClass option to identify the vendor that manufactured the hardware on which the client is running. The information contained in the data area of this option is contained in one or more opaque fields that identify details of the hardware configuration. Takes a whitespace-separated list of strings.
-	VendorClass string `systemd:",omitempty"`
+	VendorClass []string `systemd:",omitempty,wslist,since=246"`
+}
+
+// The [DHCPPrefixDelegation] section configures subnet prefixes of the delegated prefixes acquired by a DHCPv6 client or by a DHCPv4 client through the 6RD option on another interface. The settings in this section are used only when the DHCPPrefixDelegation= setting in the [Network] section is enabled.
+type DHCPPrefixDelegationSection struct {
+	systemd.KeyList        // KeyList to store unknown keys
+	Comment         string // Section Comment
+	systemd.KeyComments
+
+	// Specifies the name or the index of the uplink interface, or one of the special values ":self" and ":auto". When ":self", the interface itself is considered the uplink interface, and WithoutRA= and UseAddress= settings in the [DHCPv6] section are ignored. When ":auto", the first link which acquired prefixes to be delegated from the DHCPv6 or DHCPv4 server is selected. Defaults to ":auto".
+	UplinkInterface string `systemd:",omitempty,since=250"`
+
+	// Configure a specific subnet ID on the interface from a (previously) received prefix delegation. You can either set "auto" (the default) or a specific subnet ID (as defined in RFC 4291, section 2.5.4), in which case the allowed value is hexadecimal, from 0 to 0x7fffffffffffffff inclusive.
+	SubnetId string `systemd:",omitempty"`
+
+	// Takes a boolean. When enabled, and IPv6SendRA= in [Network] section is enabled, the delegated prefixes are distributed through the IPv6 Router Advertisement. This setting will be ignored when the DHCPPrefixDelegation= setting is enabled on the upstream interface. Defaults to yes.
+	Announce string `systemd:",omitempty,since=250"`
+
+	// Takes a boolean. Specifies whether to add an address from the delegated prefixes which are received from the WAN interface by the DHCPv6 or DHCPv4. If true, an address from the delegated prefix is assigned to the interface. Defaults to yes.
+	Assign string `systemd:",omitempty"`
+
+	// Specifies an optional address generation mode for assigning an address in each delegated prefix. This accepts the same syntax as Token= in the [IPv6AcceptRA] section. If Assign= is set to false, then this setting will be ignored. Defaults to unset, which means the EUI-64 algorithm will be used.
+	Token string `systemd:",omitempty"`
+
+	// Takes a boolean. When true, the IFA_F_MANAGETEMPADDR flag will be set to the addresses which assigned from the delegated prefixes. Defaults to false.
+	ManageTemporaryAddress string `systemd:",omitempty,since=248"`
+
+	// The metric of the route to the delegated prefix subnet. Takes an unsigned integer in the range 0…4294967295. When set to 0, the default metric is used. Defaults to 256.
+	RouteMetric string `systemd:",omitempty,since=249"`
 }
 
 // The [DHCPv6PrefixDelegation] section configures delegated prefix assigned by DHCPv6 server. The settings in this section are used only when IPv6PrefixDelegation= setting is enabled, or set to "dhcp6".
+//
+// Renamed to [DHCPPrefixDelegation] in systemd 250, which still accepts the old name.
 type DHCPv6PrefixDelegationSection struct {
 	systemd.KeyList        // KeyList to store unknown keys
 	Comment         string // Section Comment
@@ -784,7 +826,7 @@ type IPv6AcceptRASection struct {
 	// When set to true, this setting corresponds to the domain option in resolv.conf(5).
 
 	// The table identifier for the routes received in the Router Advertisement (a number between 1 and 4294967295, or 0 to unset). The table can be retrieved using ip route show table num.
-	RouteTable string `systemd:",omitempty"`
+	RouteTable string `systemd:",omitempty,since=247"`
 
 	// When true (the default), the autonomous prefix received in the Router Advertisement will be used and take precedence over any statically configured ones.
 	UseAutonomousPrefix string `systemd:",omitempty"`
@@ -793,7 +835,7 @@ type IPv6AcceptRASection struct {
 	UseOnLinkPrefix string `systemd:",omitempty"`
 
 	// A whitespace-separated list of IPv6 prefixes. IPv6 prefixes supplied via router advertisements in the list are ignored.
-	DenyList string `systemd:",omitempty"`
+	DenyList []string `systemd:",omitempty,wslist,since=246,legacy=BlackList"`
 
 	// Takes a boolean, or the special value "always". When true (the default), the DHCPv6 client will be started when the RA has the managed or other information flag. If set to "always", the DHCPv6 client will be started even if there is no managed or other information flag in the RA.
 	DHCPv6Client string `systemd:",omitempty"`
@@ -824,10 +866,23 @@ type DHCPServerSection struct {
 	EmitTimezone, Timezone string `systemd:",omitempty"`
 
 	// Send a raw option with value via DHCPv4 server. Takes a DHCP option number, data type and data ("option:type:value"). The option number is an integer in the range 1..254. The type takes one of "uint8", "uint16", "uint32", "ipv4address", "ipv6address", or "string". Special characters in the data string may be escaped using C-style escapes. This setting can be specified multiple times. If an empty string is specified, then all options specified earlier are cleared. Defaults to unset.
-	SendOption string `systemd:",omitempty"`
+	SendOption []string `systemd:",omitempty"`
 
 	// Send a vendor option with value via DHCPv4 server. Takes a DHCP option number, data type and data ("option:type:value"). The option number is an integer in the range 1..254. The type takes one of "uint8", "uint16", "uint32", "ipv4address", or "string". Special characters in the data string may be escaped using C-style escapes. This setting can be specified multiple times. If an empty string is specified, then all options specified earlier are cleared. Defaults to unset.
-	SendVendorOption string `systemd:",omitempty"`
+	SendVendorOption []string `systemd:",omitempty"`
+}
+
+// The [DHCPServerStaticLease] section configures a static DHCP lease to assign a fixed IPv4 address to a specific device based on its MAC address. This section can be specified multiple times.
+type DHCPServerStaticLeaseSection struct {
+	systemd.KeyList        // KeyList to store unknown keys
+	Comment         string // Section Comment
+	systemd.KeyComments
+
+	// The hardware address of a device to match. This key is mandatory.
+	MACAddress string `systemd:",omitempty,since=249"`
+
+	// The IPv4 address that should be assigned to the device that was matched with MACAddress=. This key is mandatory.
+	Address string `systemd:",omitempty,since=249"`
 }
 
 // The [IPv6PrefixDelegation] section contains settings for sending IPv6 Router Advertisements and whether to act as a router, if enabled via the IPv6PrefixDelegation= option described above. IPv6 network prefixes are defined with one or more [IPv6Prefix] sections.
@@ -964,7 +1019,7 @@ type LLDPSection struct {
 	systemd.KeyComments
 
 	// Controls support for Ethernet LLDP packet's Manufacturer Usage Description (MUD). MUD is an embedded software standard defined by the IETF that allows IoT Device makers to advertise device specifications, including the intended communication patterns for their device when it connects to the network. The network can then use this intent to author a context-specific access policy, so the device functions only within those parameters. Takes an URL of length up to 255 characters. A superficial verification that the string is a valid URL will be performed. See RFC 8520 for details. The MUD URL received from the LLDP packets will be saved at the state files and can be read via sd_lldp_neighbor_get_mud_url() function.
-	MUDURL string `systemd:",omitempty"`
+	MUDURL string `systemd:",omitempty,since=246"`
 }
 
 // The [CAN] section manages the Controller Area Network (CAN bus) and accepts the following keys:
@@ -1001,6 +1056,23 @@ type CANSection struct {
 	ListenOnly string `systemd:",omitempty"`
 }
 
+// The [IPoIB] section manages various settings of IP over Infiniband.
+type IPoIBSection struct {
+	systemd.KeyList        // KeyList to store unknown keys
+	Comment         string // Section Comment
+	systemd.KeyComments
+
+	// Takes one of the special values "datagram" or "connected". Defaults to unset, and the kernel's default is used.
+	//
+	// When "datagram", the Infiniband unreliable datagram (UD) transport is used, and so the interface MTU is equal to the IB L2 MTU minus the IPoIB encapsulation header (4 bytes). For example, in a typical IB fabric with a 2K MTU, the IPoIB MTU will be 2048 - 4 = 2044 bytes.
+	//
+	// When "connected", the Infiniband reliable connected (RC) transport is used. Connected mode takes advantage of the connected nature of the IB transport and allows an MTU up to the maximal IP packet size of 64K, which reduces the number of IP packets needed for handling large UDP datagrams, TCP segments, etc and increases the performance for large messages.
+	Mode string `systemd:",omitempty,since=250"`
+
+	// Takes an boolean value. When true, the kernel ignores multicast groups handled by userspace. Defaults to unset, and the kernel's default is used.
+	IgnoreUserspaceMulticastGroups string `systemd:",omitempty,since=250"`
+}
+
 // The [QDisc] section manages the traffic control queueing discipline (qdisc).
 type QDiscSection struct {
 	systemd.KeyList        // KeyList to store unknown keys
@@ -1280,10 +1352,10 @@ type EnhancedTransmissionSelectionSection struct {
 	StrictBands string `systemd:",omitempty"`
 
 	// Specifies the white-space separated list of quantum used in band-sharing bands. When suffixed with K, M, or G, the specified size is parsed as Kilobytes, Megabytes, or Gigabytes, respectively, to the base of 1024. This setting can be specified multiple times. If an empty string is assigned, then the all previous assignments are cleared.
-	QuantumBytes string `systemd:",omitempty"`
+	QuantumBytes []string `systemd:",omitempty,wslist"`
 
 	// The priority map maps the priority of a packet to a band. The argument is a white-space separated list of numbers. The first number indicates which band the packets with priority 0 should be put to, the second is for priority 1, and so on. There can be up to 16 numbers in the list. If there are fewer, the default band that traffic with one of the unmentioned priorities goes to is the last one. Each band number must be 0..255. This setting can be specified multiple times. If an empty string is assigned, then the all previous assignments are cleared.
-	PriorityMap string `systemd:",omitempty"`
+	PriorityMap []string `systemd:",omitempty,wslist"`
 }
 
 // The [GenericRandomEarlyDetection] section manages the queueing discipline (qdisc) of Generic Random Early Detection (GRED).
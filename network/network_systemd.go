@@ -0,0 +1,2012 @@
+/*
+Copyright 2020 The routerd Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by systemd-codegen. DO NOT EDIT.
+
+package network
+
+import "routerd.net/go-systemd"
+
+// MarshalSystemd implements systemd.Marshaler.
+func (n *Network) MarshalSystemd() ([]systemd.Section, error) {
+	var (
+		sections []systemd.Section
+		section  systemd.Section
+		err      error
+	)
+	if n.Match != nil {
+		if section, err = n.Match.marshalSystemd("Match"); err != nil {
+			return nil, err
+		}
+		sections = append(sections, section)
+	}
+	if n.Link != nil {
+		if section, err = n.Link.marshalSystemd("Link"); err != nil {
+			return nil, err
+		}
+		sections = append(sections, section)
+	}
+	for i := range n.SRIOVs {
+		if section, err = n.SRIOVs[i].marshalSystemd("SR-IOV"); err != nil {
+			return nil, err
+		}
+		sections = append(sections, section)
+	}
+	if n.Network != nil {
+		if section, err = n.Network.marshalSystemd("Network"); err != nil {
+			return nil, err
+		}
+		sections = append(sections, section)
+	}
+	for i := range n.Addresses {
+		if section, err = n.Addresses[i].marshalSystemd("Address"); err != nil {
+			return nil, err
+		}
+		sections = append(sections, section)
+	}
+	for i := range n.Neighbors {
+		if section, err = n.Neighbors[i].marshalSystemd("Neighbor"); err != nil {
+			return nil, err
+		}
+		sections = append(sections, section)
+	}
+	for i := range n.IPv6AddressLabels {
+		if section, err = n.IPv6AddressLabels[i].marshalSystemd("IPv6AddressLabel"); err != nil {
+			return nil, err
+		}
+		sections = append(sections, section)
+	}
+	for i := range n.RoutingPolicyRules {
+		if section, err = n.RoutingPolicyRules[i].marshalSystemd("RoutingPolicyRule"); err != nil {
+			return nil, err
+		}
+		sections = append(sections, section)
+	}
+	for i := range n.NextHops {
+		if section, err = n.NextHops[i].marshalSystemd("NextHop"); err != nil {
+			return nil, err
+		}
+		sections = append(sections, section)
+	}
+	for i := range n.Routes {
+		if section, err = n.Routes[i].marshalSystemd("Route"); err != nil {
+			return nil, err
+		}
+		sections = append(sections, section)
+	}
+	if n.DHCPv4 != nil {
+		if section, err = n.DHCPv4.marshalSystemd("DHCPv4"); err != nil {
+			return nil, err
+		}
+		sections = append(sections, section)
+	}
+	if n.DHCPv6 != nil {
+		if section, err = n.DHCPv6.marshalSystemd("DHCPv6"); err != nil {
+			return nil, err
+		}
+		sections = append(sections, section)
+	}
+	if n.DHCPPrefixDelegation != nil {
+		if section, err = n.DHCPPrefixDelegation.marshalSystemd("DHCPPrefixDelegation"); err != nil {
+			return nil, err
+		}
+		sections = append(sections, section)
+	}
+	if n.DHCPv6PrefixDelegation != nil {
+		if section, err = n.DHCPv6PrefixDelegation.marshalSystemd("DHCPv6PrefixDelegation"); err != nil {
+			return nil, err
+		}
+		sections = append(sections, section)
+	}
+	if n.IPv6AcceptRA != nil {
+		if section, err = n.IPv6AcceptRA.marshalSystemd("IPv6AcceptRA"); err != nil {
+			return nil, err
+		}
+		sections = append(sections, section)
+	}
+	if n.DHCPServer != nil {
+		if section, err = n.DHCPServer.marshalSystemd("DHCPServer"); err != nil {
+			return nil, err
+		}
+		sections = append(sections, section)
+	}
+	for i := range n.DHCPServerStaticLeases {
+		if section, err = n.DHCPServerStaticLeases[i].marshalSystemd("DHCPServerStaticLease"); err != nil {
+			return nil, err
+		}
+		sections = append(sections, section)
+	}
+	if n.IPv6PrefixDelegation != nil {
+		if section, err = n.IPv6PrefixDelegation.marshalSystemd("IPv6PrefixDelegation"); err != nil {
+			return nil, err
+		}
+		sections = append(sections, section)
+	}
+	for i := range n.IPv6Prefixes {
+		if section, err = n.IPv6Prefixes[i].marshalSystemd("IPv6Prefix"); err != nil {
+			return nil, err
+		}
+		sections = append(sections, section)
+	}
+	for i := range n.IPv6RoutePrefixes {
+		if section, err = n.IPv6RoutePrefixes[i].marshalSystemd("IPv6RoutePrefix"); err != nil {
+			return nil, err
+		}
+		sections = append(sections, section)
+	}
+	if n.Bridge != nil {
+		if section, err = n.Bridge.marshalSystemd("Bridge"); err != nil {
+			return nil, err
+		}
+		sections = append(sections, section)
+	}
+	for i := range n.BridgeFDBs {
+		if section, err = n.BridgeFDBs[i].marshalSystemd("BridgeFDB"); err != nil {
+			return nil, err
+		}
+		sections = append(sections, section)
+	}
+	if n.LLDP != nil {
+		if section, err = n.LLDP.marshalSystemd("LLDP"); err != nil {
+			return nil, err
+		}
+		sections = append(sections, section)
+	}
+	if n.CAN != nil {
+		if section, err = n.CAN.marshalSystemd("CAN"); err != nil {
+			return nil, err
+		}
+		sections = append(sections, section)
+	}
+	if n.IPoIB != nil {
+		if section, err = n.IPoIB.marshalSystemd("IPoIB"); err != nil {
+			return nil, err
+		}
+		sections = append(sections, section)
+	}
+	if n.QDisc != nil {
+		if section, err = n.QDisc.marshalSystemd("QDisc"); err != nil {
+			return nil, err
+		}
+		sections = append(sections, section)
+	}
+	if n.NetworkEmulator != nil {
+		if section, err = n.NetworkEmulator.marshalSystemd("NetworkEmulator"); err != nil {
+			return nil, err
+		}
+		sections = append(sections, section)
+	}
+	for i := range n.TokenBucketFilters {
+		if section, err = n.TokenBucketFilters[i].marshalSystemd("TokenBucketFilter"); err != nil {
+			return nil, err
+		}
+		sections = append(sections, section)
+	}
+	for i := range n.PIEs {
+		if section, err = n.PIEs[i].marshalSystemd("PIE"); err != nil {
+			return nil, err
+		}
+		sections = append(sections, section)
+	}
+	for i := range n.StochasticFairBlue {
+		if section, err = n.StochasticFairBlue[i].marshalSystemd("StochasticFairBlue"); err != nil {
+			return nil, err
+		}
+		sections = append(sections, section)
+	}
+	for i := range n.StochasticFairnessQueueing {
+		if section, err = n.StochasticFairnessQueueing[i].marshalSystemd("StochasticFairnessQueueing"); err != nil {
+			return nil, err
+		}
+		sections = append(sections, section)
+	}
+	for i := range n.BFIFO {
+		if section, err = n.BFIFO[i].marshalSystemd("BFIFO"); err != nil {
+			return nil, err
+		}
+		sections = append(sections, section)
+	}
+	for i := range n.PFIFO {
+		if section, err = n.PFIFO[i].marshalSystemd("PFIFO"); err != nil {
+			return nil, err
+		}
+		sections = append(sections, section)
+	}
+	for i := range n.PFIFOHeadDrop {
+		if section, err = n.PFIFOHeadDrop[i].marshalSystemd("PFIFOHeadDrop"); err != nil {
+			return nil, err
+		}
+		sections = append(sections, section)
+	}
+	for i := range n.PFIFOFast {
+		if section, err = n.PFIFOFast[i].marshalSystemd("PFIFOFast"); err != nil {
+			return nil, err
+		}
+		sections = append(sections, section)
+	}
+	for i := range n.CAKE {
+		if section, err = n.CAKE[i].marshalSystemd("CAKE"); err != nil {
+			return nil, err
+		}
+		sections = append(sections, section)
+	}
+	for i := range n.ControlledDelay {
+		if section, err = n.ControlledDelay[i].marshalSystemd("ControlledDelay"); err != nil {
+			return nil, err
+		}
+		sections = append(sections, section)
+	}
+	for i := range n.DeficitRoundRobinScheduler {
+		if section, err = n.DeficitRoundRobinScheduler[i].marshalSystemd("DeficitRoundRobinScheduler"); err != nil {
+			return nil, err
+		}
+		sections = append(sections, section)
+	}
+	for i := range n.DeficitRoundRobinSchedulerClass {
+		if section, err = n.DeficitRoundRobinSchedulerClass[i].marshalSystemd("DeficitRoundRobinSchedulerClass"); err != nil {
+			return nil, err
+		}
+		sections = append(sections, section)
+	}
+	for i := range n.EnhancedTransmissionSelection {
+		if section, err = n.EnhancedTransmissionSelection[i].marshalSystemd("EnhancedTransmissionSelection"); err != nil {
+			return nil, err
+		}
+		sections = append(sections, section)
+	}
+	for i := range n.GenericRandomEarlyDetection {
+		if section, err = n.GenericRandomEarlyDetection[i].marshalSystemd("GenericRandomEarlyDetection"); err != nil {
+			return nil, err
+		}
+		sections = append(sections, section)
+	}
+	for i := range n.FairQueueingControlledDelay {
+		if section, err = n.FairQueueingControlledDelay[i].marshalSystemd("FairQueueingControlledDelay"); err != nil {
+			return nil, err
+		}
+		sections = append(sections, section)
+	}
+	for i := range n.FairQueueing {
+		if section, err = n.FairQueueing[i].marshalSystemd("FairQueueing"); err != nil {
+			return nil, err
+		}
+		sections = append(sections, section)
+	}
+	for i := range n.TrivialLinkEqualizer {
+		if section, err = n.TrivialLinkEqualizer[i].marshalSystemd("TrivialLinkEqualizer"); err != nil {
+			return nil, err
+		}
+		sections = append(sections, section)
+	}
+	for i := range n.HierarchyTokenBucket {
+		if section, err = n.HierarchyTokenBucket[i].marshalSystemd("HierarchyTokenBucket"); err != nil {
+			return nil, err
+		}
+		sections = append(sections, section)
+	}
+	for i := range n.HierarchyTokenBucketClass {
+		if section, err = n.HierarchyTokenBucketClass[i].marshalSystemd("HierarchyTokenBucketClass"); err != nil {
+			return nil, err
+		}
+		sections = append(sections, section)
+	}
+	for i := range n.HeavyHitterFilter {
+		if section, err = n.HeavyHitterFilter[i].marshalSystemd("HeavyHitterFilter"); err != nil {
+			return nil, err
+		}
+		sections = append(sections, section)
+	}
+	for i := range n.QuickFairQueueing {
+		if section, err = n.QuickFairQueueing[i].marshalSystemd("QuickFairQueueing"); err != nil {
+			return nil, err
+		}
+		sections = append(sections, section)
+	}
+	for i := range n.QuickFairQueueingClass {
+		if section, err = n.QuickFairQueueingClass[i].marshalSystemd("QuickFairQueueingClass"); err != nil {
+			return nil, err
+		}
+		sections = append(sections, section)
+	}
+	for i := range n.BridgeVLAN {
+		if section, err = n.BridgeVLAN[i].marshalSystemd("BridgeVLAN"); err != nil {
+			return nil, err
+		}
+		sections = append(sections, section)
+	}
+	sections = append(sections, n.SectionList...)
+	return sections, nil
+}
+
+// UnmarshalSystemd implements systemd.Unmarshaler.
+func (n *Network) UnmarshalSystemd(file *systemd.File) error {
+	for i := range file.Sections {
+		section := &file.Sections[i]
+		switch section.Name {
+		case "Match":
+			s := &MatchSection{}
+			if err := s.unmarshalSystemd(section); err != nil {
+				return err
+			}
+			n.Match = s
+		case "Link":
+			s := &LinkSection{}
+			if err := s.unmarshalSystemd(section); err != nil {
+				return err
+			}
+			n.Link = s
+		case "SR-IOV":
+			s := SRIOVSection{}
+			if err := s.unmarshalSystemd(section); err != nil {
+				return err
+			}
+			n.SRIOVs = append(n.SRIOVs, s)
+		case "Network":
+			s := &NetworkSection{}
+			if err := s.unmarshalSystemd(section); err != nil {
+				return err
+			}
+			n.Network = s
+		case "Address":
+			s := AddressSection{}
+			if err := s.unmarshalSystemd(section); err != nil {
+				return err
+			}
+			n.Addresses = append(n.Addresses, s)
+		case "Neighbor":
+			s := NeighborSection{}
+			if err := s.unmarshalSystemd(section); err != nil {
+				return err
+			}
+			n.Neighbors = append(n.Neighbors, s)
+		case "IPv6AddressLabel":
+			s := IPv6AddressLabelSection{}
+			if err := s.unmarshalSystemd(section); err != nil {
+				return err
+			}
+			n.IPv6AddressLabels = append(n.IPv6AddressLabels, s)
+		case "RoutingPolicyRule":
+			s := RoutingPolicyRuleSection{}
+			if err := s.unmarshalSystemd(section); err != nil {
+				return err
+			}
+			n.RoutingPolicyRules = append(n.RoutingPolicyRules, s)
+		case "NextHop":
+			s := NextHopSection{}
+			if err := s.unmarshalSystemd(section); err != nil {
+				return err
+			}
+			n.NextHops = append(n.NextHops, s)
+		case "Route":
+			s := RouteSection{}
+			if err := s.unmarshalSystemd(section); err != nil {
+				return err
+			}
+			n.Routes = append(n.Routes, s)
+		case "DHCPv4":
+			s := &DHCPv4Section{}
+			if err := s.unmarshalSystemd(section); err != nil {
+				return err
+			}
+			n.DHCPv4 = s
+		case "DHCPv6":
+			s := &DHCPv6Section{}
+			if err := s.unmarshalSystemd(section); err != nil {
+				return err
+			}
+			n.DHCPv6 = s
+		case "DHCPPrefixDelegation":
+			s := &DHCPPrefixDelegationSection{}
+			if err := s.unmarshalSystemd(section); err != nil {
+				return err
+			}
+			n.DHCPPrefixDelegation = s
+		case "DHCPv6PrefixDelegation":
+			s := &DHCPv6PrefixDelegationSection{}
+			if err := s.unmarshalSystemd(section); err != nil {
+				return err
+			}
+			n.DHCPv6PrefixDelegation = s
+		case "IPv6AcceptRA":
+			s := &IPv6AcceptRASection{}
+			if err := s.unmarshalSystemd(section); err != nil {
+				return err
+			}
+			n.IPv6AcceptRA = s
+		case "DHCPServer":
+			s := &DHCPServerSection{}
+			if err := s.unmarshalSystemd(section); err != nil {
+				return err
+			}
+			n.DHCPServer = s
+		case "DHCPServerStaticLease":
+			s := DHCPServerStaticLeaseSection{}
+			if err := s.unmarshalSystemd(section); err != nil {
+				return err
+			}
+			n.DHCPServerStaticLeases = append(n.DHCPServerStaticLeases, s)
+		case "IPv6PrefixDelegation":
+			s := &IPv6PrefixDelegationSection{}
+			if err := s.unmarshalSystemd(section); err != nil {
+				return err
+			}
+			n.IPv6PrefixDelegation = s
+		case "IPv6Prefix":
+			s := IPv6PrefixSection{}
+			if err := s.unmarshalSystemd(section); err != nil {
+				return err
+			}
+			n.IPv6Prefixes = append(n.IPv6Prefixes, s)
+		case "IPv6RoutePrefix":
+			s := IPv6RoutePrefixSection{}
+			if err := s.unmarshalSystemd(section); err != nil {
+				return err
+			}
+			n.IPv6RoutePrefixes = append(n.IPv6RoutePrefixes, s)
+		case "Bridge":
+			s := &BridgeSection{}
+			if err := s.unmarshalSystemd(section); err != nil {
+				return err
+			}
+			n.Bridge = s
+		case "BridgeFDB":
+			s := BridgeFDBSection{}
+			if err := s.unmarshalSystemd(section); err != nil {
+				return err
+			}
+			n.BridgeFDBs = append(n.BridgeFDBs, s)
+		case "LLDP":
+			s := &LLDPSection{}
+			if err := s.unmarshalSystemd(section); err != nil {
+				return err
+			}
+			n.LLDP = s
+		case "CAN":
+			s := &CANSection{}
+			if err := s.unmarshalSystemd(section); err != nil {
+				return err
+			}
+			n.CAN = s
+		case "IPoIB":
+			s := &IPoIBSection{}
+			if err := s.unmarshalSystemd(section); err != nil {
+				return err
+			}
+			n.IPoIB = s
+		case "QDisc":
+			s := &QDiscSection{}
+			if err := s.unmarshalSystemd(section); err != nil {
+				return err
+			}
+			n.QDisc = s
+		case "NetworkEmulator":
+			s := &NetworkEmulatorSection{}
+			if err := s.unmarshalSystemd(section); err != nil {
+				return err
+			}
+			n.NetworkEmulator = s
+		case "TokenBucketFilter":
+			s := TokenBucketFilterSection{}
+			if err := s.unmarshalSystemd(section); err != nil {
+				return err
+			}
+			n.TokenBucketFilters = append(n.TokenBucketFilters, s)
+		case "PIE":
+			s := PIESection{}
+			if err := s.unmarshalSystemd(section); err != nil {
+				return err
+			}
+			n.PIEs = append(n.PIEs, s)
+		case "StochasticFairBlue":
+			s := StochasticFairBlueSection{}
+			if err := s.unmarshalSystemd(section); err != nil {
+				return err
+			}
+			n.StochasticFairBlue = append(n.StochasticFairBlue, s)
+		case "StochasticFairnessQueueing":
+			s := StochasticFairnessQueueingSection{}
+			if err := s.unmarshalSystemd(section); err != nil {
+				return err
+			}
+			n.StochasticFairnessQueueing = append(n.StochasticFairnessQueueing, s)
+		case "BFIFO":
+			s := BFIFOSection{}
+			if err := s.unmarshalSystemd(section); err != nil {
+				return err
+			}
+			n.BFIFO = append(n.BFIFO, s)
+		case "PFIFO":
+			s := PFIFOSection{}
+			if err := s.unmarshalSystemd(section); err != nil {
+				return err
+			}
+			n.PFIFO = append(n.PFIFO, s)
+		case "PFIFOHeadDrop":
+			s := PFIFOHeadDropSection{}
+			if err := s.unmarshalSystemd(section); err != nil {
+				return err
+			}
+			n.PFIFOHeadDrop = append(n.PFIFOHeadDrop, s)
+		case "PFIFOFast":
+			s := PFIFOFastSection{}
+			if err := s.unmarshalSystemd(section); err != nil {
+				return err
+			}
+			n.PFIFOFast = append(n.PFIFOFast, s)
+		case "CAKE":
+			s := CAKESection{}
+			if err := s.unmarshalSystemd(section); err != nil {
+				return err
+			}
+			n.CAKE = append(n.CAKE, s)
+		case "ControlledDelay":
+			s := ControlledDelaySection{}
+			if err := s.unmarshalSystemd(section); err != nil {
+				return err
+			}
+			n.ControlledDelay = append(n.ControlledDelay, s)
+		case "DeficitRoundRobinScheduler":
+			s := DeficitRoundRobinSchedulerSection{}
+			if err := s.unmarshalSystemd(section); err != nil {
+				return err
+			}
+			n.DeficitRoundRobinScheduler = append(n.DeficitRoundRobinScheduler, s)
+		case "DeficitRoundRobinSchedulerClass":
+			s := DeficitRoundRobinSchedulerClassSection{}
+			if err := s.unmarshalSystemd(section); err != nil {
+				return err
+			}
+			n.DeficitRoundRobinSchedulerClass = append(n.DeficitRoundRobinSchedulerClass, s)
+		case "EnhancedTransmissionSelection":
+			s := EnhancedTransmissionSelectionSection{}
+			if err := s.unmarshalSystemd(section); err != nil {
+				return err
+			}
+			n.EnhancedTransmissionSelection = append(n.EnhancedTransmissionSelection, s)
+		case "GenericRandomEarlyDetection":
+			s := GenericRandomEarlyDetectionSection{}
+			if err := s.unmarshalSystemd(section); err != nil {
+				return err
+			}
+			n.GenericRandomEarlyDetection = append(n.GenericRandomEarlyDetection, s)
+		case "FairQueueingControlledDelay":
+			s := FairQueueingControlledDelaySection{}
+			if err := s.unmarshalSystemd(section); err != nil {
+				return err
+			}
+			n.FairQueueingControlledDelay = append(n.FairQueueingControlledDelay, s)
+		case "FairQueueing":
+			s := FairQueueingSection{}
+			if err := s.unmarshalSystemd(section); err != nil {
+				return err
+			}
+			n.FairQueueing = append(n.FairQueueing, s)
+		case "TrivialLinkEqualizer":
+			s := TrivialLinkEqualizerSection{}
+			if err := s.unmarshalSystemd(section); err != nil {
+				return err
+			}
+			n.TrivialLinkEqualizer = append(n.TrivialLinkEqualizer, s)
+		case "HierarchyTokenBucket":
+			s := HierarchyTokenBucketSection{}
+			if err := s.unmarshalSystemd(section); err != nil {
+				return err
+			}
+			n.HierarchyTokenBucket = append(n.HierarchyTokenBucket, s)
+		case "HierarchyTokenBucketClass":
+			s := HierarchyTokenBucketClassSection{}
+			if err := s.unmarshalSystemd(section); err != nil {
+				return err
+			}
+			n.HierarchyTokenBucketClass = append(n.HierarchyTokenBucketClass, s)
+		case "HeavyHitterFilter":
+			s := HeavyHitterFilterSection{}
+			if err := s.unmarshalSystemd(section); err != nil {
+				return err
+			}
+			n.HeavyHitterFilter = append(n.HeavyHitterFilter, s)
+		case "QuickFairQueueing":
+			s := QuickFairQueueingSection{}
+			if err := s.unmarshalSystemd(section); err != nil {
+				return err
+			}
+			n.QuickFairQueueing = append(n.QuickFairQueueing, s)
+		case "QuickFairQueueingClass":
+			s := QuickFairQueueingClassSection{}
+			if err := s.unmarshalSystemd(section); err != nil {
+				return err
+			}
+			n.QuickFairQueueingClass = append(n.QuickFairQueueingClass, s)
+		case "BridgeVLAN":
+			s := BridgeVLANSection{}
+			if err := s.unmarshalSystemd(section); err != nil {
+				return err
+			}
+			n.BridgeVLAN = append(n.BridgeVLAN, s)
+		default:
+			n.AddSection(*section)
+		}
+	}
+	return nil
+}
+
+func (s *AddressSection) marshalSystemd(name string) (systemd.Section, error) {
+	e := systemd.NewSectionEncoder(name, s.Comment, s.GetKeyComment)
+	e.String("Address", s.Address, true)
+	e.String("Peer", s.Peer, true)
+	e.String("Broadcast", s.Broadcast, true)
+	e.String("Label", s.Label, true)
+	e.String("PreferredLifetime", s.PreferredLifetime, true)
+	e.String("Scope", s.Scope, true)
+	e.String("HomeAddress", s.HomeAddress, true)
+	e.String("DuplicateAddressDetection", s.DuplicateAddressDetection, true)
+	e.String("ManageTemporaryAddress", s.ManageTemporaryAddress, true)
+	e.String("AddPrefixRoute", s.AddPrefixRoute, true)
+	e.String("AutoJoin", s.AutoJoin, true)
+	return e.Section(s.KeyList), e.Err()
+}
+
+func (s *AddressSection) unmarshalSystemd(section *systemd.Section) error {
+	s.Comment = section.Comment
+	d := systemd.NewSectionDecoder(section, s.AddKeyComment)
+	d.String("Address", &s.Address)
+	d.String("Peer", &s.Peer)
+	d.String("Broadcast", &s.Broadcast)
+	d.String("Label", &s.Label)
+	d.String("PreferredLifetime", &s.PreferredLifetime)
+	d.String("Scope", &s.Scope)
+	d.String("HomeAddress", &s.HomeAddress)
+	d.String("DuplicateAddressDetection", &s.DuplicateAddressDetection)
+	d.String("ManageTemporaryAddress", &s.ManageTemporaryAddress)
+	d.String("AddPrefixRoute", &s.AddPrefixRoute)
+	d.String("AutoJoin", &s.AutoJoin)
+	d.Unknown(s.AddKey)
+	return d.Err()
+}
+
+func (s *BFIFOSection) marshalSystemd(name string) (systemd.Section, error) {
+	e := systemd.NewSectionEncoder(name, s.Comment, s.GetKeyComment)
+	e.String("Parent", s.Parent, true)
+	e.String("Handle", s.Handle, true)
+	e.String("LimitBytes", s.LimitBytes, true)
+	return e.Section(s.KeyList), e.Err()
+}
+
+func (s *BFIFOSection) unmarshalSystemd(section *systemd.Section) error {
+	s.Comment = section.Comment
+	d := systemd.NewSectionDecoder(section, s.AddKeyComment)
+	d.String("Parent", &s.Parent)
+	d.String("Handle", &s.Handle)
+	d.String("LimitBytes", &s.LimitBytes)
+	d.Unknown(s.AddKey)
+	return d.Err()
+}
+
+func (s *BridgeFDBSection) marshalSystemd(name string) (systemd.Section, error) {
+	e := systemd.NewSectionEncoder(name, s.Comment, s.GetKeyComment)
+	e.String("MACAddress", s.MACAddress, true)
+	e.String("Destination", s.Destination, true)
+	e.String("VLANId", s.VLANId, true)
+	e.String("VNI", s.VNI, true)
+	e.String("AssociatedWith", s.AssociatedWith, true)
+	return e.Section(s.KeyList), e.Err()
+}
+
+func (s *BridgeFDBSection) unmarshalSystemd(section *systemd.Section) error {
+	s.Comment = section.Comment
+	d := systemd.NewSectionDecoder(section, s.AddKeyComment)
+	d.String("MACAddress", &s.MACAddress)
+	d.String("Destination", &s.Destination)
+	d.String("VLANId", &s.VLANId)
+	d.String("VNI", &s.VNI)
+	d.String("AssociatedWith", &s.AssociatedWith)
+	d.Unknown(s.AddKey)
+	return d.Err()
+}
+
+func (s *BridgeSection) marshalSystemd(name string) (systemd.Section, error) {
+	e := systemd.NewSectionEncoder(name, s.Comment, s.GetKeyComment)
+	e.String("UnicastFlood", s.UnicastFlood, true)
+	e.String("MulticastFlood", s.MulticastFlood, true)
+	e.String("MulticastToUnicast", s.MulticastToUnicast, true)
+	e.String("NeighborSuppression", s.NeighborSuppression, true)
+	e.String("Learning", s.Learning, true)
+	e.String("HairPin", s.HairPin, true)
+	e.String("UseBPDU", s.UseBPDU, true)
+	e.String("FastLeave", s.FastLeave, true)
+	e.String("AllowPortToBeRoot", s.AllowPortToBeRoot, true)
+	e.String("ProxyARP", s.ProxyARP, true)
+	e.String("ProxyARPWiFi", s.ProxyARPWiFi, true)
+	e.String("MulticastRouter", s.MulticastRouter, true)
+	e.String("Cost", s.Cost, true)
+	e.String("Priority", s.Priority, true)
+	return e.Section(s.KeyList), e.Err()
+}
+
+func (s *BridgeSection) unmarshalSystemd(section *systemd.Section) error {
+	s.Comment = section.Comment
+	d := systemd.NewSectionDecoder(section, s.AddKeyComment)
+	d.String("UnicastFlood", &s.UnicastFlood)
+	d.String("MulticastFlood", &s.MulticastFlood)
+	d.String("MulticastToUnicast", &s.MulticastToUnicast)
+	d.String("NeighborSuppression", &s.NeighborSuppression)
+	d.String("Learning", &s.Learning)
+	d.String("HairPin", &s.HairPin)
+	d.String("UseBPDU", &s.UseBPDU)
+	d.String("FastLeave", &s.FastLeave)
+	d.String("AllowPortToBeRoot", &s.AllowPortToBeRoot)
+	d.String("ProxyARP", &s.ProxyARP)
+	d.String("ProxyARPWiFi", &s.ProxyARPWiFi)
+	d.String("MulticastRouter", &s.MulticastRouter)
+	d.String("Cost", &s.Cost)
+	d.String("Priority", &s.Priority)
+	d.Unknown(s.AddKey)
+	return d.Err()
+}
+
+func (s *BridgeVLANSection) marshalSystemd(name string) (systemd.Section, error) {
+	e := systemd.NewSectionEncoder(name, s.Comment, s.GetKeyComment)
+	e.String("VLAN", s.VLAN, true)
+	e.String("EgressUntagged", s.EgressUntagged, true)
+	e.String("PVID", s.PVID, true)
+	return e.Section(s.KeyList), e.Err()
+}
+
+func (s *BridgeVLANSection) unmarshalSystemd(section *systemd.Section) error {
+	s.Comment = section.Comment
+	d := systemd.NewSectionDecoder(section, s.AddKeyComment)
+	d.String("VLAN", &s.VLAN)
+	d.String("EgressUntagged", &s.EgressUntagged)
+	d.String("PVID", &s.PVID)
+	d.Unknown(s.AddKey)
+	return d.Err()
+}
+
+func (s *CAKESection) marshalSystemd(name string) (systemd.Section, error) {
+	e := systemd.NewSectionEncoder(name, s.Comment, s.GetKeyComment)
+	e.String("Parent", s.Parent, true)
+	e.String("Handle", s.Handle, true)
+	e.String("OverheadBytes", s.OverheadBytes, true)
+	e.String("Bandwidth", s.Bandwidth, true)
+	return e.Section(s.KeyList), e.Err()
+}
+
+func (s *CAKESection) unmarshalSystemd(section *systemd.Section) error {
+	s.Comment = section.Comment
+	d := systemd.NewSectionDecoder(section, s.AddKeyComment)
+	d.String("Parent", &s.Parent)
+	d.String("Handle", &s.Handle)
+	d.String("OverheadBytes", &s.OverheadBytes)
+	d.String("Bandwidth", &s.Bandwidth)
+	d.Unknown(s.AddKey)
+	return d.Err()
+}
+
+func (s *CANSection) marshalSystemd(name string) (systemd.Section, error) {
+	e := systemd.NewSectionEncoder(name, s.Comment, s.GetKeyComment)
+	e.String("BitRate", s.BitRate, true)
+	e.String("SamplePoint", s.SamplePoint, true)
+	e.String("DataBitRate", s.DataBitRate, true)
+	e.String("DataSamplePoint", s.DataSamplePoint, true)
+	e.String("FDMode", s.FDMode, true)
+	e.String("FDNonISO", s.FDNonISO, true)
+	e.String("RestartSec", s.RestartSec, true)
+	e.String("Termination", s.Termination, true)
+	e.String("TripleSampling", s.TripleSampling, true)
+	e.String("ListenOnly", s.ListenOnly, true)
+	return e.Section(s.KeyList), e.Err()
+}
+
+func (s *CANSection) unmarshalSystemd(section *systemd.Section) error {
+	s.Comment = section.Comment
+	d := systemd.NewSectionDecoder(section, s.AddKeyComment)
+	d.String("BitRate", &s.BitRate)
+	d.String("SamplePoint", &s.SamplePoint)
+	d.String("DataBitRate", &s.DataBitRate)
+	d.String("DataSamplePoint", &s.DataSamplePoint)
+	d.String("FDMode", &s.FDMode)
+	d.String("FDNonISO", &s.FDNonISO)
+	d.String("RestartSec", &s.RestartSec)
+	d.String("Termination", &s.Termination)
+	d.String("TripleSampling", &s.TripleSampling)
+	d.String("ListenOnly", &s.ListenOnly)
+	d.Unknown(s.AddKey)
+	return d.Err()
+}
+
+func (s *ControlledDelaySection) marshalSystemd(name string) (systemd.Section, error) {
+	e := systemd.NewSectionEncoder(name, s.Comment, s.GetKeyComment)
+	e.String("Parent", s.Parent, true)
+	e.String("Handle", s.Handle, true)
+	e.String("PacketLimit", s.PacketLimit, true)
+	e.String("TargetSec", s.TargetSec, true)
+	e.String("IntervalSec", s.IntervalSec, true)
+	e.String("ECN", s.ECN, true)
+	e.String("CEThresholdSec", s.CEThresholdSec, true)
+	return e.Section(s.KeyList), e.Err()
+}
+
+func (s *ControlledDelaySection) unmarshalSystemd(section *systemd.Section) error {
+	s.Comment = section.Comment
+	d := systemd.NewSectionDecoder(section, s.AddKeyComment)
+	d.String("Parent", &s.Parent)
+	d.String("Handle", &s.Handle)
+	d.String("PacketLimit", &s.PacketLimit)
+	d.String("TargetSec", &s.TargetSec)
+	d.String("IntervalSec", &s.IntervalSec)
+	d.String("ECN", &s.ECN)
+	d.String("CEThresholdSec", &s.CEThresholdSec)
+	d.Unknown(s.AddKey)
+	return d.Err()
+}
+
+func (s *DHCPPrefixDelegationSection) marshalSystemd(name string) (systemd.Section, error) {
+	e := systemd.NewSectionEncoder(name, s.Comment, s.GetKeyComment)
+	e.String("UplinkInterface", s.UplinkInterface, true)
+	e.String("SubnetId", s.SubnetId, true)
+	e.String("Announce", s.Announce, true)
+	e.String("Assign", s.Assign, true)
+	e.String("Token", s.Token, true)
+	e.String("ManageTemporaryAddress", s.ManageTemporaryAddress, true)
+	e.String("RouteMetric", s.RouteMetric, true)
+	return e.Section(s.KeyList), e.Err()
+}
+
+func (s *DHCPPrefixDelegationSection) unmarshalSystemd(section *systemd.Section) error {
+	s.Comment = section.Comment
+	d := systemd.NewSectionDecoder(section, s.AddKeyComment)
+	d.String("UplinkInterface", &s.UplinkInterface)
+	d.String("SubnetId", &s.SubnetId)
+	d.String("Announce", &s.Announce)
+	d.String("Assign", &s.Assign)
+	d.String("Token", &s.Token)
+	d.String("ManageTemporaryAddress", &s.ManageTemporaryAddress)
+	d.String("RouteMetric", &s.RouteMetric)
+	d.Unknown(s.AddKey)
+	return d.Err()
+}
+
+func (s *DHCPServerSection) marshalSystemd(name string) (systemd.Section, error) {
+	e := systemd.NewSectionEncoder(name, s.Comment, s.GetKeyComment)
+	e.String("PoolOffset", s.PoolOffset, true)
+	e.String("PoolSize", s.PoolSize, true)
+	e.String("DefaultLeaseTimeSec", s.DefaultLeaseTimeSec, true)
+	e.String("MaxLeaseTimeSec", s.MaxLeaseTimeSec, true)
+	e.String("EmitDNS", s.EmitDNS, true)
+	e.String("DNS", s.DNS, true)
+	e.String("EmitNTP", s.EmitNTP, true)
+	e.String("NTP", s.NTP, true)
+	e.String("EmitSIP", s.EmitSIP, true)
+	e.String("SIP", s.SIP, true)
+	e.String("EmitPOP3", s.EmitPOP3, true)
+	e.String("POP3", s.POP3, true)
+	e.String("EmitSMTP", s.EmitSMTP, true)
+	e.String("SMTP", s.SMTP, true)
+	e.String("EmitLPR", s.EmitLPR, true)
+	e.String("LPR", s.LPR, true)
+	e.String("EmitRouter", s.EmitRouter, true)
+	e.String("EmitTimezone", s.EmitTimezone, true)
+	e.String("Timezone", s.Timezone, true)
+	e.Strings("SendOption", s.SendOption, false)
+	e.Strings("SendVendorOption", s.SendVendorOption, false)
+	return e.Section(s.KeyList), e.Err()
+}
+
+func (s *DHCPServerSection) unmarshalSystemd(section *systemd.Section) error {
+	s.Comment = section.Comment
+	d := systemd.NewSectionDecoder(section, s.AddKeyComment)
+	d.String("PoolOffset", &s.PoolOffset)
+	d.String("PoolSize", &s.PoolSize)
+	d.String("DefaultLeaseTimeSec", &s.DefaultLeaseTimeSec)
+	d.String("MaxLeaseTimeSec", &s.MaxLeaseTimeSec)
+	d.String("EmitDNS", &s.EmitDNS)
+	d.String("DNS", &s.DNS)
+	d.String("EmitNTP", &s.EmitNTP)
+	d.String("NTP", &s.NTP)
+	d.String("EmitSIP", &s.EmitSIP)
+	d.String("SIP", &s.SIP)
+	d.String("EmitPOP3", &s.EmitPOP3)
+	d.String("POP3", &s.POP3)
+	d.String("EmitSMTP", &s.EmitSMTP)
+	d.String("SMTP", &s.SMTP)
+	d.String("EmitLPR", &s.EmitLPR)
+	d.String("LPR", &s.LPR)
+	d.String("EmitRouter", &s.EmitRouter)
+	d.String("EmitTimezone", &s.EmitTimezone)
+	d.String("Timezone", &s.Timezone)
+	d.Strings("SendOption", &s.SendOption, false)
+	d.Strings("SendVendorOption", &s.SendVendorOption, false)
+	d.Unknown(s.AddKey)
+	return d.Err()
+}
+
+func (s *DHCPServerStaticLeaseSection) marshalSystemd(name string) (systemd.Section, error) {
+	e := systemd.NewSectionEncoder(name, s.Comment, s.GetKeyComment)
+	e.String("MACAddress", s.MACAddress, true)
+	e.String("Address", s.Address, true)
+	return e.Section(s.KeyList), e.Err()
+}
+
+func (s *DHCPServerStaticLeaseSection) unmarshalSystemd(section *systemd.Section) error {
+	s.Comment = section.Comment
+	d := systemd.NewSectionDecoder(section, s.AddKeyComment)
+	d.String("MACAddress", &s.MACAddress)
+	d.String("Address", &s.Address)
+	d.Unknown(s.AddKey)
+	return d.Err()
+}
+
+func (s *DHCPv4Section) marshalSystemd(name string) (systemd.Section, error) {
+	e := systemd.NewSectionEncoder(name, s.Comment, s.GetKeyComment)
+	e.String("UseDNS", s.UseDNS, true)
+	e.String("RoutesToDNS", s.RoutesToDNS, true)
+	e.String("UseNTP", s.UseNTP, true)
+	e.String("UseSIP", s.UseSIP, true)
+	e.String("UseMTU", s.UseMTU, true)
+	e.String("Anonymize", s.Anonymize, true)
+	e.String("SendHostname", s.SendHostname, true)
+	e.String("MUDURL", s.MUDURL, true)
+	e.String("UseHostname", s.UseHostname, true)
+	e.String("Hostname", s.Hostname, true)
+	e.String("UseDomains", s.UseDomains, true)
+	e.String("UseRoutes", s.UseRoutes, true)
+	e.String("UseGateway", s.UseGateway, true)
+	e.String("UseTimezone", s.UseTimezone, true)
+	e.String("ClientIdentifier", s.ClientIdentifier, true)
+	e.String("VendorClassIdentifier", s.VendorClassIdentifier, true)
+	e.Strings("UserClass", s.UserClass, true)
+	e.String("MaxAttempts", s.MaxAttempts, true)
+	e.String("DUIDType", s.DUIDType, true)
+	e.String("DUIDRawData", s.DUIDRawData, true)
+	e.String("IAID", s.IAID, true)
+	e.String("RequestBroadcast", s.RequestBroadcast, true)
+	e.String("RouteMetric", s.RouteMetric, true)
+	e.String("RouteMTUBytes", s.RouteMTUBytes, true)
+	e.String("ListenPort", s.ListenPort, true)
+	e.String("FallbackLeaseLifetimeSec", s.FallbackLeaseLifetimeSec, true)
+	e.String("SendRelease", s.SendRelease, true)
+	e.String("SendDecline", s.SendDecline, true)
+	e.Strings("DenyList", s.DenyList, true)
+	e.Strings("AllowList", s.AllowList, true)
+	e.Strings("RequestOptions", s.RequestOptions, true)
+	e.Strings("SendOption", s.SendOption, false)
+	e.Strings("SendVendorOption", s.SendVendorOption, false)
+	return e.Section(s.KeyList), e.Err()
+}
+
+func (s *DHCPv4Section) unmarshalSystemd(section *systemd.Section) error {
+	s.Comment = section.Comment
+	d := systemd.NewSectionDecoder(section, s.AddKeyComment)
+	d.String("UseDNS", &s.UseDNS)
+	d.String("RoutesToDNS", &s.RoutesToDNS)
+	d.String("UseNTP", &s.UseNTP)
+	d.String("UseSIP", &s.UseSIP)
+	d.String("UseMTU", &s.UseMTU)
+	d.String("Anonymize", &s.Anonymize)
+	d.String("SendHostname", &s.SendHostname)
+	d.String("MUDURL", &s.MUDURL)
+	d.String("UseHostname", &s.UseHostname)
+	d.String("Hostname", &s.Hostname)
+	d.String("UseDomains", &s.UseDomains)
+	d.String("UseRoutes", &s.UseRoutes)
+	d.String("UseGateway", &s.UseGateway)
+	d.String("UseTimezone", &s.UseTimezone)
+	d.String("ClientIdentifier", &s.ClientIdentifier)
+	d.String("VendorClassIdentifier", &s.VendorClassIdentifier)
+	d.Strings("UserClass", &s.UserClass, true)
+	d.String("MaxAttempts", &s.MaxAttempts)
+	d.String("DUIDType", &s.DUIDType)
+	d.String("DUIDRawData", &s.DUIDRawData)
+	d.String("IAID", &s.IAID)
+	d.String("RequestBroadcast", &s.RequestBroadcast)
+	d.String("RouteMetric", &s.RouteMetric)
+	d.String("RouteMTUBytes", &s.RouteMTUBytes)
+	d.String("ListenPort", &s.ListenPort)
+	d.String("FallbackLeaseLifetimeSec", &s.FallbackLeaseLifetimeSec)
+	d.String("SendRelease", &s.SendRelease)
+	d.String("SendDecline", &s.SendDecline)
+	d.Strings("DenyList", &s.DenyList, true)
+	d.Strings("AllowList", &s.AllowList, true)
+	d.Strings("RequestOptions", &s.RequestOptions, true)
+	d.Strings("SendOption", &s.SendOption, false)
+	d.Strings("SendVendorOption", &s.SendVendorOption, false)
+	d.Unknown(s.AddKey)
+	return d.Err()
+}
+
+func (s *DHCPv6PrefixDelegationSection) marshalSystemd(name string) (systemd.Section, error) {
+	e := systemd.NewSectionEncoder(name, s.Comment, s.GetKeyComment)
+	e.String("SubnetId", s.SubnetId, true)
+	e.String("Assign", s.Assign, true)
+	e.String("Token", s.Token, true)
+	return e.Section(s.KeyList), e.Err()
+}
+
+func (s *DHCPv6PrefixDelegationSection) unmarshalSystemd(section *systemd.Section) error {
+	s.Comment = section.Comment
+	d := systemd.NewSectionDecoder(section, s.AddKeyComment)
+	d.String("SubnetId", &s.SubnetId)
+	d.String("Assign", &s.Assign)
+	d.String("Token", &s.Token)
+	d.Unknown(s.AddKey)
+	return d.Err()
+}
+
+func (s *DHCPv6Section) marshalSystemd(name string) (systemd.Section, error) {
+	e := systemd.NewSectionEncoder(name, s.Comment, s.GetKeyComment)
+	e.String("UseDNS", s.UseDNS, true)
+	e.String("UseNTP", s.UseNTP, true)
+	e.String("RouteMetric", s.RouteMetric, true)
+	e.String("RapidCommit", s.RapidCommit, true)
+	e.String("MUDURL", s.MUDURL, true)
+	e.Strings("RequestOptions", s.RequestOptions, true)
+	e.Strings("SendVendorOption", s.SendVendorOption, false)
+	e.String("ForceDHCPv6PDOtherInformation", s.ForceDHCPv6PDOtherInformation, true)
+	e.String("PrefixDelegationHint", s.PrefixDelegationHint, true)
+	e.String("WithoutRA", s.WithoutRA, true)
+	e.String("SendOption", s.SendOption, true)
+	e.Strings("UserClass", s.UserClass, true)
+	e.Strings("VendorClass", s.VendorClass, true)
+	return e.Section(s.KeyList), e.Err()
+}
+
+func (s *DHCPv6Section) unmarshalSystemd(section *systemd.Section) error {
+	s.Comment = section.Comment
+	d := systemd.NewSectionDecoder(section, s.AddKeyComment)
+	d.String("UseDNS", &s.UseDNS)
+	d.String("UseNTP", &s.UseNTP)
+	d.String("RouteMetric", &s.RouteMetric)
+	d.String("RapidCommit", &s.RapidCommit)
+	d.String("MUDURL", &s.MUDURL)
+	d.Strings("RequestOptions", &s.RequestOptions, true)
+	d.Strings("SendVendorOption", &s.SendVendorOption, false)
+	d.String("ForceDHCPv6PDOtherInformation", &s.ForceDHCPv6PDOtherInformation)
+	d.String("PrefixDelegationHint", &s.PrefixDelegationHint)
+	d.String("WithoutRA", &s.WithoutRA)
+	d.String("SendOption", &s.SendOption)
+	d.Strings("UserClass", &s.UserClass, true)
+	d.Strings("VendorClass", &s.VendorClass, true)
+	d.Unknown(s.AddKey)
+	return d.Err()
+}
+
+func (s *DeficitRoundRobinSchedulerClassSection) marshalSystemd(name string) (systemd.Section, error) {
+	e := systemd.NewSectionEncoder(name, s.Comment, s.GetKeyComment)
+	e.String("Parent", s.Parent, true)
+	e.String("ClassId", s.ClassId, true)
+	e.String("QuantumBytes", s.QuantumBytes, true)
+	return e.Section(s.KeyList), e.Err()
+}
+
+func (s *DeficitRoundRobinSchedulerClassSection) unmarshalSystemd(section *systemd.Section) error {
+	s.Comment = section.Comment
+	d := systemd.NewSectionDecoder(section, s.AddKeyComment)
+	d.String("Parent", &s.Parent)
+	d.String("ClassId", &s.ClassId)
+	d.String("QuantumBytes", &s.QuantumBytes)
+	d.Unknown(s.AddKey)
+	return d.Err()
+}
+
+func (s *DeficitRoundRobinSchedulerSection) marshalSystemd(name string) (systemd.Section, error) {
+	e := systemd.NewSectionEncoder(name, s.Comment, s.GetKeyComment)
+	e.String("Parent", s.Parent, true)
+	e.String("Handle", s.Handle, true)
+	return e.Section(s.KeyList), e.Err()
+}
+
+func (s *DeficitRoundRobinSchedulerSection) unmarshalSystemd(section *systemd.Section) error {
+	s.Comment = section.Comment
+	d := systemd.NewSectionDecoder(section, s.AddKeyComment)
+	d.String("Parent", &s.Parent)
+	d.String("Handle", &s.Handle)
+	d.Unknown(s.AddKey)
+	return d.Err()
+}
+
+func (s *EnhancedTransmissionSelectionSection) marshalSystemd(name string) (systemd.Section, error) {
+	e := systemd.NewSectionEncoder(name, s.Comment, s.GetKeyComment)
+	e.String("Parent", s.Parent, true)
+	e.String("Handle", s.Handle, true)
+	e.String("Bands", s.Bands, true)
+	e.String("StrictBands", s.StrictBands, true)
+	e.Strings("QuantumBytes", s.QuantumBytes, true)
+	e.Strings("PriorityMap", s.PriorityMap, true)
+	return e.Section(s.KeyList), e.Err()
+}
+
+func (s *EnhancedTransmissionSelectionSection) unmarshalSystemd(section *systemd.Section) error {
+	s.Comment = section.Comment
+	d := systemd.NewSectionDecoder(section, s.AddKeyComment)
+	d.String("Parent", &s.Parent)
+	d.String("Handle", &s.Handle)
+	d.String("Bands", &s.Bands)
+	d.String("StrictBands", &s.StrictBands)
+	d.Strings("QuantumBytes", &s.QuantumBytes, true)
+	d.Strings("PriorityMap", &s.PriorityMap, true)
+	d.Unknown(s.AddKey)
+	return d.Err()
+}
+
+func (s *FairQueueingControlledDelaySection) marshalSystemd(name string) (systemd.Section, error) {
+	e := systemd.NewSectionEncoder(name, s.Comment, s.GetKeyComment)
+	e.String("Parent", s.Parent, true)
+	e.String("Handle", s.Handle, true)
+	e.String("PacketLimit", s.PacketLimit, true)
+	e.String("MemoryLimitBytes", s.MemoryLimitBytes, true)
+	e.String("Flows", s.Flows, true)
+	e.String("TargetSec", s.TargetSec, true)
+	e.String("IntervalSec", s.IntervalSec, true)
+	e.String("QuantumBytes", s.QuantumBytes, true)
+	e.String("ECN", s.ECN, true)
+	e.String("CEThresholdSec", s.CEThresholdSec, true)
+	return e.Section(s.KeyList), e.Err()
+}
+
+func (s *FairQueueingControlledDelaySection) unmarshalSystemd(section *systemd.Section) error {
+	s.Comment = section.Comment
+	d := systemd.NewSectionDecoder(section, s.AddKeyComment)
+	d.String("Parent", &s.Parent)
+	d.String("Handle", &s.Handle)
+	d.String("PacketLimit", &s.PacketLimit)
+	d.String("MemoryLimitBytes", &s.MemoryLimitBytes)
+	d.String("Flows", &s.Flows)
+	d.String("TargetSec", &s.TargetSec)
+	d.String("IntervalSec", &s.IntervalSec)
+	d.String("QuantumBytes", &s.QuantumBytes)
+	d.String("ECN", &s.ECN)
+	d.String("CEThresholdSec", &s.CEThresholdSec)
+	d.Unknown(s.AddKey)
+	return d.Err()
+}
+
+func (s *FairQueueingSection) marshalSystemd(name string) (systemd.Section, error) {
+	e := systemd.NewSectionEncoder(name, s.Comment, s.GetKeyComment)
+	e.String("Parent", s.Parent, true)
+	e.String("Handle", s.Handle, true)
+	e.String("PacketLimit", s.PacketLimit, true)
+	e.String("FlowLimit", s.FlowLimit, true)
+	e.String("QuantumBytes", s.QuantumBytes, true)
+	e.String("InitialQuantumBytes", s.InitialQuantumBytes, true)
+	e.String("MaximumRate", s.MaximumRate, true)
+	e.String("Buckets", s.Buckets, true)
+	e.String("OrphanMask", s.OrphanMask, true)
+	e.String("Pacing", s.Pacing, true)
+	e.String("CEThresholdSec", s.CEThresholdSec, true)
+	return e.Section(s.KeyList), e.Err()
+}
+
+func (s *FairQueueingSection) unmarshalSystemd(section *systemd.Section) error {
+	s.Comment = section.Comment
+	d := systemd.NewSectionDecoder(section, s.AddKeyComment)
+	d.String("Parent", &s.Parent)
+	d.String("Handle", &s.Handle)
+	d.String("PacketLimit", &s.PacketLimit)
+	d.String("FlowLimit", &s.FlowLimit)
+	d.String("QuantumBytes", &s.QuantumBytes)
+	d.String("InitialQuantumBytes", &s.InitialQuantumBytes)
+	d.String("MaximumRate", &s.MaximumRate)
+	d.String("Buckets", &s.Buckets)
+	d.String("OrphanMask", &s.OrphanMask)
+	d.String("Pacing", &s.Pacing)
+	d.String("CEThresholdSec", &s.CEThresholdSec)
+	d.Unknown(s.AddKey)
+	return d.Err()
+}
+
+func (s *GenericRandomEarlyDetectionSection) marshalSystemd(name string) (systemd.Section, error) {
+	e := systemd.NewSectionEncoder(name, s.Comment, s.GetKeyComment)
+	e.String("Parent", s.Parent, true)
+	e.String("Handle", s.Handle, true)
+	e.String("VirtualQueues", s.VirtualQueues, true)
+	e.String("DefaultVirtualQueue", s.DefaultVirtualQueue, true)
+	e.String("GenericRIO", s.GenericRIO, true)
+	return e.Section(s.KeyList), e.Err()
+}
+
+func (s *GenericRandomEarlyDetectionSection) unmarshalSystemd(section *systemd.Section) error {
+	s.Comment = section.Comment
+	d := systemd.NewSectionDecoder(section, s.AddKeyComment)
+	d.String("Parent", &s.Parent)
+	d.String("Handle", &s.Handle)
+	d.String("VirtualQueues", &s.VirtualQueues)
+	d.String("DefaultVirtualQueue", &s.DefaultVirtualQueue)
+	d.String("GenericRIO", &s.GenericRIO)
+	d.Unknown(s.AddKey)
+	return d.Err()
+}
+
+func (s *HeavyHitterFilterSection) marshalSystemd(name string) (systemd.Section, error) {
+	e := systemd.NewSectionEncoder(name, s.Comment, s.GetKeyComment)
+	e.String("Parent", s.Parent, true)
+	e.String("Handle", s.Handle, true)
+	e.String("PacketLimit", s.PacketLimit, true)
+	return e.Section(s.KeyList), e.Err()
+}
+
+func (s *HeavyHitterFilterSection) unmarshalSystemd(section *systemd.Section) error {
+	s.Comment = section.Comment
+	d := systemd.NewSectionDecoder(section, s.AddKeyComment)
+	d.String("Parent", &s.Parent)
+	d.String("Handle", &s.Handle)
+	d.String("PacketLimit", &s.PacketLimit)
+	d.Unknown(s.AddKey)
+	return d.Err()
+}
+
+func (s *HierarchyTokenBucketClassSection) marshalSystemd(name string) (systemd.Section, error) {
+	e := systemd.NewSectionEncoder(name, s.Comment, s.GetKeyComment)
+	e.String("Parent", s.Parent, true)
+	e.String("ClassId", s.ClassId, true)
+	e.String("Priority", s.Priority, true)
+	e.String("QuantumBytes", s.QuantumBytes, true)
+	e.String("MTUBytes", s.MTUBytes, true)
+	e.String("OverheadBytes", s.OverheadBytes, true)
+	e.String("Rate", s.Rate, true)
+	e.String("CeilRate", s.CeilRate, true)
+	e.String("BufferBytes", s.BufferBytes, true)
+	e.String("CeilBufferBytes", s.CeilBufferBytes, true)
+	return e.Section(s.KeyList), e.Err()
+}
+
+func (s *HierarchyTokenBucketClassSection) unmarshalSystemd(section *systemd.Section) error {
+	s.Comment = section.Comment
+	d := systemd.NewSectionDecoder(section, s.AddKeyComment)
+	d.String("Parent", &s.Parent)
+	d.String("ClassId", &s.ClassId)
+	d.String("Priority", &s.Priority)
+	d.String("QuantumBytes", &s.QuantumBytes)
+	d.String("MTUBytes", &s.MTUBytes)
+	d.String("OverheadBytes", &s.OverheadBytes)
+	d.String("Rate", &s.Rate)
+	d.String("CeilRate", &s.CeilRate)
+	d.String("BufferBytes", &s.BufferBytes)
+	d.String("CeilBufferBytes", &s.CeilBufferBytes)
+	d.Unknown(s.AddKey)
+	return d.Err()
+}
+
+func (s *HierarchyTokenBucketSection) marshalSystemd(name string) (systemd.Section, error) {
+	e := systemd.NewSectionEncoder(name, s.Comment, s.GetKeyComment)
+	e.String("Parent", s.Parent, true)
+	e.String("Handle", s.Handle, true)
+	e.String("DefaultClass", s.DefaultClass, true)
+	e.String("RateToQuantum", s.RateToQuantum, true)
+	return e.Section(s.KeyList), e.Err()
+}
+
+func (s *HierarchyTokenBucketSection) unmarshalSystemd(section *systemd.Section) error {
+	s.Comment = section.Comment
+	d := systemd.NewSectionDecoder(section, s.AddKeyComment)
+	d.String("Parent", &s.Parent)
+	d.String("Handle", &s.Handle)
+	d.String("DefaultClass", &s.DefaultClass)
+	d.String("RateToQuantum", &s.RateToQuantum)
+	d.Unknown(s.AddKey)
+	return d.Err()
+}
+
+func (s *IPoIBSection) marshalSystemd(name string) (systemd.Section, error) {
+	e := systemd.NewSectionEncoder(name, s.Comment, s.GetKeyComment)
+	e.String("Mode", s.Mode, true)
+	e.String("IgnoreUserspaceMulticastGroups", s.IgnoreUserspaceMulticastGroups, true)
+	return e.Section(s.KeyList), e.Err()
+}
+
+func (s *IPoIBSection) unmarshalSystemd(section *systemd.Section) error {
+	s.Comment = section.Comment
+	d := systemd.NewSectionDecoder(section, s.AddKeyComment)
+	d.String("Mode", &s.Mode)
+	d.String("IgnoreUserspaceMulticastGroups", &s.IgnoreUserspaceMulticastGroups)
+	d.Unknown(s.AddKey)
+	return d.Err()
+}
+
+func (s *IPv6AcceptRASection) marshalSystemd(name string) (systemd.Section, error) {
+	e := systemd.NewSectionEncoder(name, s.Comment, s.GetKeyComment)
+	e.String("UseDNS", s.UseDNS, true)
+	e.String("UseDomains", s.UseDomains, true)
+	e.String("RouteTable", s.RouteTable, true)
+	e.String("UseAutonomousPrefix", s.UseAutonomousPrefix, true)
+	e.String("UseOnLinkPrefix", s.UseOnLinkPrefix, true)
+	e.Strings("DenyList", s.DenyList, true)
+	e.String("DHCPv6Client", s.DHCPv6Client, true)
+	return e.Section(s.KeyList), e.Err()
+}
+
+func (s *IPv6AcceptRASection) unmarshalSystemd(section *systemd.Section) error {
+	s.Comment = section.Comment
+	d := systemd.NewSectionDecoder(section, s.AddKeyComment)
+	d.String("UseDNS", &s.UseDNS)
+	d.String("UseDomains", &s.UseDomains)
+	d.String("RouteTable", &s.RouteTable)
+	d.String("UseAutonomousPrefix", &s.UseAutonomousPrefix)
+	d.String("UseOnLinkPrefix", &s.UseOnLinkPrefix)
+	d.Strings("DenyList", &s.DenyList, true)
+	d.String("DHCPv6Client", &s.DHCPv6Client)
+	d.Unknown(s.AddKey)
+	return d.Err()
+}
+
+func (s *IPv6AddressLabelSection) marshalSystemd(name string) (systemd.Section, error) {
+	e := systemd.NewSectionEncoder(name, s.Comment, s.GetKeyComment)
+	e.String("Label", s.Label, true)
+	e.String("Prefix", s.Prefix, true)
+	return e.Section(s.KeyList), e.Err()
+}
+
+func (s *IPv6AddressLabelSection) unmarshalSystemd(section *systemd.Section) error {
+	s.Comment = section.Comment
+	d := systemd.NewSectionDecoder(section, s.AddKeyComment)
+	d.String("Label", &s.Label)
+	d.String("Prefix", &s.Prefix)
+	d.Unknown(s.AddKey)
+	return d.Err()
+}
+
+func (s *IPv6PrefixDelegationSection) marshalSystemd(name string) (systemd.Section, error) {
+	e := systemd.NewSectionEncoder(name, s.Comment, s.GetKeyComment)
+	e.String("Managed", s.Managed, true)
+	e.String("OtherInformation", s.OtherInformation, true)
+	e.String("RouterLifetimeSec", s.RouterLifetimeSec, true)
+	e.String("RouterPreference", s.RouterPreference, true)
+	e.String("EmitDNS", s.EmitDNS, true)
+	e.String("DNS", s.DNS, true)
+	e.String("EmitDomains", s.EmitDomains, true)
+	e.String("Domains", s.Domains, true)
+	e.String("DNSLifetimeSec", s.DNSLifetimeSec, true)
+	return e.Section(s.KeyList), e.Err()
+}
+
+func (s *IPv6PrefixDelegationSection) unmarshalSystemd(section *systemd.Section) error {
+	s.Comment = section.Comment
+	d := systemd.NewSectionDecoder(section, s.AddKeyComment)
+	d.String("Managed", &s.Managed)
+	d.String("OtherInformation", &s.OtherInformation)
+	d.String("RouterLifetimeSec", &s.RouterLifetimeSec)
+	d.String("RouterPreference", &s.RouterPreference)
+	d.String("EmitDNS", &s.EmitDNS)
+	d.String("DNS", &s.DNS)
+	d.String("EmitDomains", &s.EmitDomains)
+	d.String("Domains", &s.Domains)
+	d.String("DNSLifetimeSec", &s.DNSLifetimeSec)
+	d.Unknown(s.AddKey)
+	return d.Err()
+}
+
+func (s *IPv6PrefixSection) marshalSystemd(name string) (systemd.Section, error) {
+	e := systemd.NewSectionEncoder(name, s.Comment, s.GetKeyComment)
+	e.String("AddressAutoconfiguration", s.AddressAutoconfiguration, true)
+	e.String("OnLink", s.OnLink, true)
+	e.String("Prefix", s.Prefix, true)
+	e.String("PreferredLifetimeSec", s.PreferredLifetimeSec, true)
+	e.String("ValidLifetimeSec", s.ValidLifetimeSec, true)
+	e.String("Assign", s.Assign, true)
+	return e.Section(s.KeyList), e.Err()
+}
+
+func (s *IPv6PrefixSection) unmarshalSystemd(section *systemd.Section) error {
+	s.Comment = section.Comment
+	d := systemd.NewSectionDecoder(section, s.AddKeyComment)
+	d.String("AddressAutoconfiguration", &s.AddressAutoconfiguration)
+	d.String("OnLink", &s.OnLink)
+	d.String("Prefix", &s.Prefix)
+	d.String("PreferredLifetimeSec", &s.PreferredLifetimeSec)
+	d.String("ValidLifetimeSec", &s.ValidLifetimeSec)
+	d.String("Assign", &s.Assign)
+	d.Unknown(s.AddKey)
+	return d.Err()
+}
+
+func (s *IPv6RoutePrefixSection) marshalSystemd(name string) (systemd.Section, error) {
+	e := systemd.NewSectionEncoder(name, s.Comment, s.GetKeyComment)
+	e.String("Route", s.Route, true)
+	e.String("LifetimeSec", s.LifetimeSec, true)
+	return e.Section(s.KeyList), e.Err()
+}
+
+func (s *IPv6RoutePrefixSection) unmarshalSystemd(section *systemd.Section) error {
+	s.Comment = section.Comment
+	d := systemd.NewSectionDecoder(section, s.AddKeyComment)
+	d.String("Route", &s.Route)
+	d.String("LifetimeSec", &s.LifetimeSec)
+	d.Unknown(s.AddKey)
+	return d.Err()
+}
+
+func (s *LLDPSection) marshalSystemd(name string) (systemd.Section, error) {
+	e := systemd.NewSectionEncoder(name, s.Comment, s.GetKeyComment)
+	e.String("MUDURL", s.MUDURL, true)
+	return e.Section(s.KeyList), e.Err()
+}
+
+func (s *LLDPSection) unmarshalSystemd(section *systemd.Section) error {
+	s.Comment = section.Comment
+	d := systemd.NewSectionDecoder(section, s.AddKeyComment)
+	d.String("MUDURL", &s.MUDURL)
+	d.Unknown(s.AddKey)
+	return d.Err()
+}
+
+func (s *LinkSection) marshalSystemd(name string) (systemd.Section, error) {
+	e := systemd.NewSectionEncoder(name, s.Comment, s.GetKeyComment)
+	e.String("MACAddress", s.MACAddress, true)
+	e.String("MTUBytes", s.MTUBytes, true)
+	e.String("ARP", s.ARP, true)
+	e.String("Multicast", s.Multicast, true)
+	e.String("AllMulticast", s.AllMulticast, true)
+	e.String("Unmanaged", s.Unmanaged, true)
+	e.String("Group", s.Group, true)
+	e.String("RequiredForOnline", s.RequiredForOnline, true)
+	return e.Section(s.KeyList), e.Err()
+}
+
+func (s *LinkSection) unmarshalSystemd(section *systemd.Section) error {
+	s.Comment = section.Comment
+	d := systemd.NewSectionDecoder(section, s.AddKeyComment)
+	d.String("MACAddress", &s.MACAddress)
+	d.String("MTUBytes", &s.MTUBytes)
+	d.String("ARP", &s.ARP)
+	d.String("Multicast", &s.Multicast)
+	d.String("AllMulticast", &s.AllMulticast)
+	d.String("Unmanaged", &s.Unmanaged)
+	d.String("Group", &s.Group)
+	d.String("RequiredForOnline", &s.RequiredForOnline)
+	d.Unknown(s.AddKey)
+	return d.Err()
+}
+
+func (s *MatchSection) marshalSystemd(name string) (systemd.Section, error) {
+	e := systemd.NewSectionEncoder(name, s.Comment, s.GetKeyComment)
+	e.Strings("MACAddress", s.MACAddress, true)
+	e.Strings("PermanentMACAddress", s.PermanentMACAddress, true)
+	e.Strings("Path", s.Path, true)
+	e.Strings("Driver", s.Driver, true)
+	e.Strings("Type", s.Type, true)
+	e.Strings("Property", s.Property, true)
+	e.Strings("Name", s.Name, true)
+	e.Strings("WLANInterfaceType", s.WLANInterfaceType, true)
+	e.Strings("SSID", s.SSID, true)
+	e.Strings("BSSID", s.BSSID, true)
+	e.Value("Host", &s.Host, ",omitempty,lastwins")
+	e.Value("Virtualization", &s.Virtualization, ",omitempty,lastwins")
+	e.Value("KernelCommandLine", &s.KernelCommandLine, ",omitempty,lastwins")
+	e.Value("KernelVersion", &s.KernelVersion, ",omitempty,lastwins")
+	e.Value("Architecture", &s.Architecture, ",omitempty,lastwins")
+	return e.Section(s.KeyList), e.Err()
+}
+
+func (s *MatchSection) unmarshalSystemd(section *systemd.Section) error {
+	s.Comment = section.Comment
+	d := systemd.NewSectionDecoder(section, s.AddKeyComment)
+	d.Strings("MACAddress", &s.MACAddress, true)
+	d.Strings("PermanentMACAddress", &s.PermanentMACAddress, true)
+	d.Strings("Path", &s.Path, true)
+	d.Strings("Driver", &s.Driver, true)
+	d.Strings("Type", &s.Type, true)
+	d.Strings("Property", &s.Property, true)
+	d.Strings("Name", &s.Name, true)
+	d.Strings("WLANInterfaceType", &s.WLANInterfaceType, true)
+	d.Strings("SSID", &s.SSID, true)
+	d.Strings("BSSID", &s.BSSID, true)
+	d.Value("Host", &s.Host, ",omitempty,lastwins")
+	d.Value("Virtualization", &s.Virtualization, ",omitempty,lastwins")
+	d.Value("KernelCommandLine", &s.KernelCommandLine, ",omitempty,lastwins")
+	d.Value("KernelVersion", &s.KernelVersion, ",omitempty,lastwins")
+	d.Value("Architecture", &s.Architecture, ",omitempty,lastwins")
+	d.Unknown(s.AddKey)
+	return d.Err()
+}
+
+func (s *NeighborSection) marshalSystemd(name string) (systemd.Section, error) {
+	e := systemd.NewSectionEncoder(name, s.Comment, s.GetKeyComment)
+	e.String("Address", s.Address, true)
+	e.String("LinkLayerAddress", s.LinkLayerAddress, true)
+	return e.Section(s.KeyList), e.Err()
+}
+
+func (s *NeighborSection) unmarshalSystemd(section *systemd.Section) error {
+	s.Comment = section.Comment
+	d := systemd.NewSectionDecoder(section, s.AddKeyComment)
+	d.String("Address", &s.Address)
+	d.String("LinkLayerAddress", &s.LinkLayerAddress)
+	d.Unknown(s.AddKey)
+	return d.Err()
+}
+
+func (s *NetworkEmulatorSection) marshalSystemd(name string) (systemd.Section, error) {
+	e := systemd.NewSectionEncoder(name, s.Comment, s.GetKeyComment)
+	e.String("Parent", s.Parent, true)
+	e.String("Handle", s.Handle, true)
+	e.String("DelaySec", s.DelaySec, true)
+	e.String("DelayJitterSec", s.DelayJitterSec, true)
+	e.String("PacketLimit", s.PacketLimit, true)
+	e.String("LossRate", s.LossRate, true)
+	e.String("DuplicateRate", s.DuplicateRate, true)
+	return e.Section(s.KeyList), e.Err()
+}
+
+func (s *NetworkEmulatorSection) unmarshalSystemd(section *systemd.Section) error {
+	s.Comment = section.Comment
+	d := systemd.NewSectionDecoder(section, s.AddKeyComment)
+	d.String("Parent", &s.Parent)
+	d.String("Handle", &s.Handle)
+	d.String("DelaySec", &s.DelaySec)
+	d.String("DelayJitterSec", &s.DelayJitterSec)
+	d.String("PacketLimit", &s.PacketLimit)
+	d.String("LossRate", &s.LossRate)
+	d.String("DuplicateRate", &s.DuplicateRate)
+	d.Unknown(s.AddKey)
+	return d.Err()
+}
+
+func (s *NetworkSection) marshalSystemd(name string) (systemd.Section, error) {
+	e := systemd.NewSectionEncoder(name, s.Comment, s.GetKeyComment)
+	e.String("Description", s.Description, true)
+	e.String("DHCP", s.DHCP, true)
+	e.String("DHCPServer", s.DHCPServer, true)
+	e.String("LinkLocalAddressing", s.LinkLocalAddressing, true)
+	e.String("IPv6LinkLocalAddressGenerationMode", s.IPv6LinkLocalAddressGenerationMode, true)
+	e.String("IPv4LLRoute", s.IPv4LLRoute, true)
+	e.String("DefaultRouteOnDevice", s.DefaultRouteOnDevice, true)
+	e.String("IPv6Token", s.IPv6Token, true)
+	e.String("LLMNR", s.LLMNR, true)
+	e.String("MulticastDNS", s.MulticastDNS, true)
+	e.String("DNSOverTLS", s.DNSOverTLS, true)
+	e.String("DNSSEC", s.DNSSEC, true)
+	e.Strings("DNSSECNegativeTrustAnchors", s.DNSSECNegativeTrustAnchors, true)
+	e.String("LLDP", s.LLDP, true)
+	e.String("EmitLLDP", s.EmitLLDP, true)
+	e.String("BindCarrier", s.BindCarrier, true)
+	e.Value("Address", &s.Address, ",omitempty,append")
+	e.Value("Gateway", &s.Gateway, ",omitempty,append")
+	e.Strings("DNS", s.DNS, false)
+	e.Strings("Domains", s.Domains, true)
+	e.String("DNSDefaultRoute", s.DNSDefaultRoute, true)
+	e.Strings("NTP", s.NTP, false)
+	e.String("IPForward", s.IPForward, true)
+	e.String("IPMasquerade", s.IPMasquerade, true)
+	e.String("IPv6PrivacyExtensions", s.IPv6PrivacyExtensions, true)
+	e.String("IPv6AcceptRA", s.IPv6AcceptRA, true)
+	e.String("IPv6DuplicateAddressDetection", s.IPv6DuplicateAddressDetection, true)
+	e.String("IPv6HopLimit", s.IPv6HopLimit, true)
+	e.String("IPv4AcceptLocal", s.IPv4AcceptLocal, true)
+	e.String("IPv4ProxyARP", s.IPv4ProxyARP, true)
+	e.String("IPv6ProxyNDP", s.IPv6ProxyNDP, true)
+	e.Strings("IPv6ProxyNDPAddress", s.IPv6ProxyNDPAddress, false)
+	e.String("IPv6PrefixDelegation", s.IPv6PrefixDelegation, true)
+	e.String("DHCPPrefixDelegation", s.DHCPPrefixDelegation, true)
+	e.String("IPv6MTUBytes", s.IPv6MTUBytes, true)
+	e.String("Bridge", s.Bridge, true)
+	e.String("Bond", s.Bond, true)
+	e.String("VRF", s.VRF, true)
+	e.String("BatmanAdvanced", s.BatmanAdvanced, true)
+	e.Value("VLAN", &s.VLAN, ",omitempty,append")
+	e.Value("IPVLAN", &s.IPVLAN, ",omitempty,append")
+	e.Value("MACVLAN", &s.MACVLAN, ",omitempty,append")
+	e.Value("VXLAN", &s.VXLAN, ",omitempty,append")
+	e.Value("Tunnel", &s.Tunnel, ",omitempty,append")
+	e.Value("MACsec", &s.MACsec, ",omitempty,append")
+	e.String("ActiveSlave", s.ActiveSlave, true)
+	e.String("PrimarySlave", s.PrimarySlave, true)
+	e.String("ConfigureWithoutCarrier", s.ConfigureWithoutCarrier, true)
+	e.String("IgnoreCarrierLoss", s.IgnoreCarrierLoss, true)
+	e.Value("Xfrm", &s.Xfrm, ",omitempty,append")
+	e.String("KeepConfiguration", s.KeepConfiguration, true)
+	return e.Section(s.KeyList), e.Err()
+}
+
+func (s *NetworkSection) unmarshalSystemd(section *systemd.Section) error {
+	s.Comment = section.Comment
+	d := systemd.NewSectionDecoder(section, s.AddKeyComment)
+	d.String("Description", &s.Description)
+	d.String("DHCP", &s.DHCP)
+	d.String("DHCPServer", &s.DHCPServer)
+	d.String("LinkLocalAddressing", &s.LinkLocalAddressing)
+	d.String("IPv6LinkLocalAddressGenerationMode", &s.IPv6LinkLocalAddressGenerationMode)
+	d.String("IPv4LLRoute", &s.IPv4LLRoute)
+	d.String("DefaultRouteOnDevice", &s.DefaultRouteOnDevice)
+	d.String("IPv6Token", &s.IPv6Token)
+	d.String("LLMNR", &s.LLMNR)
+	d.String("MulticastDNS", &s.MulticastDNS)
+	d.String("DNSOverTLS", &s.DNSOverTLS)
+	d.String("DNSSEC", &s.DNSSEC)
+	d.Strings("DNSSECNegativeTrustAnchors", &s.DNSSECNegativeTrustAnchors, true)
+	d.String("LLDP", &s.LLDP)
+	d.String("EmitLLDP", &s.EmitLLDP)
+	d.String("BindCarrier", &s.BindCarrier)
+	d.Value("Address", &s.Address, ",omitempty,append")
+	d.Value("Gateway", &s.Gateway, ",omitempty,append")
+	d.Strings("DNS", &s.DNS, false)
+	d.Strings("Domains", &s.Domains, true)
+	d.String("DNSDefaultRoute", &s.DNSDefaultRoute)
+	d.Strings("NTP", &s.NTP, false)
+	d.String("IPForward", &s.IPForward)
+	d.String("IPMasquerade", &s.IPMasquerade)
+	d.String("IPv6PrivacyExtensions", &s.IPv6PrivacyExtensions)
+	d.String("IPv6AcceptRA", &s.IPv6AcceptRA)
+	d.String("IPv6DuplicateAddressDetection", &s.IPv6DuplicateAddressDetection)
+	d.String("IPv6HopLimit", &s.IPv6HopLimit)
+	d.String("IPv4AcceptLocal", &s.IPv4AcceptLocal)
+	d.String("IPv4ProxyARP", &s.IPv4ProxyARP)
+	d.String("IPv6ProxyNDP", &s.IPv6ProxyNDP)
+	d.Strings("IPv6ProxyNDPAddress", &s.IPv6ProxyNDPAddress, false)
+	d.String("IPv6PrefixDelegation", &s.IPv6PrefixDelegation)
+	d.String("DHCPPrefixDelegation", &s.DHCPPrefixDelegation)
+	d.String("IPv6MTUBytes", &s.IPv6MTUBytes)
+	d.String("Bridge", &s.Bridge)
+	d.String("Bond", &s.Bond)
+	d.String("VRF", &s.VRF)
+	d.String("BatmanAdvanced", &s.BatmanAdvanced)
+	d.Value("VLAN", &s.VLAN, ",omitempty,append")
+	d.Value("IPVLAN", &s.IPVLAN, ",omitempty,append")
+	d.Value("MACVLAN", &s.MACVLAN, ",omitempty,append")
+	d.Value("VXLAN", &s.VXLAN, ",omitempty,append")
+	d.Value("Tunnel", &s.Tunnel, ",omitempty,append")
+	d.Value("MACsec", &s.MACsec, ",omitempty,append")
+	d.String("ActiveSlave", &s.ActiveSlave)
+	d.String("PrimarySlave", &s.PrimarySlave)
+	d.String("ConfigureWithoutCarrier", &s.ConfigureWithoutCarrier)
+	d.String("IgnoreCarrierLoss", &s.IgnoreCarrierLoss)
+	d.Value("Xfrm", &s.Xfrm, ",omitempty,append")
+	d.String("KeepConfiguration", &s.KeepConfiguration)
+	d.Unknown(s.AddKey)
+	return d.Err()
+}
+
+func (s *NextHopSection) marshalSystemd(name string) (systemd.Section, error) {
+	e := systemd.NewSectionEncoder(name, s.Comment, s.GetKeyComment)
+	e.String("Gateway", s.Gateway, true)
+	e.String("Id", s.Id, true)
+	return e.Section(s.KeyList), e.Err()
+}
+
+func (s *NextHopSection) unmarshalSystemd(section *systemd.Section) error {
+	s.Comment = section.Comment
+	d := systemd.NewSectionDecoder(section, s.AddKeyComment)
+	d.String("Gateway", &s.Gateway)
+	d.String("Id", &s.Id)
+	d.Unknown(s.AddKey)
+	return d.Err()
+}
+
+func (s *PFIFOFastSection) marshalSystemd(name string) (systemd.Section, error) {
+	e := systemd.NewSectionEncoder(name, s.Comment, s.GetKeyComment)
+	e.String("Parent", s.Parent, true)
+	e.String("Handle", s.Handle, true)
+	return e.Section(s.KeyList), e.Err()
+}
+
+func (s *PFIFOFastSection) unmarshalSystemd(section *systemd.Section) error {
+	s.Comment = section.Comment
+	d := systemd.NewSectionDecoder(section, s.AddKeyComment)
+	d.String("Parent", &s.Parent)
+	d.String("Handle", &s.Handle)
+	d.Unknown(s.AddKey)
+	return d.Err()
+}
+
+func (s *PFIFOHeadDropSection) marshalSystemd(name string) (systemd.Section, error) {
+	e := systemd.NewSectionEncoder(name, s.Comment, s.GetKeyComment)
+	e.String("Parent", s.Parent, true)
+	e.String("Handle", s.Handle, true)
+	e.String("PacketLimit", s.PacketLimit, true)
+	return e.Section(s.KeyList), e.Err()
+}
+
+func (s *PFIFOHeadDropSection) unmarshalSystemd(section *systemd.Section) error {
+	s.Comment = section.Comment
+	d := systemd.NewSectionDecoder(section, s.AddKeyComment)
+	d.String("Parent", &s.Parent)
+	d.String("Handle", &s.Handle)
+	d.String("PacketLimit", &s.PacketLimit)
+	d.Unknown(s.AddKey)
+	return d.Err()
+}
+
+func (s *PFIFOSection) marshalSystemd(name string) (systemd.Section, error) {
+	e := systemd.NewSectionEncoder(name, s.Comment, s.GetKeyComment)
+	e.String("Parent", s.Parent, true)
+	e.String("Handle", s.Handle, true)
+	e.String("PacketLimit", s.PacketLimit, true)
+	return e.Section(s.KeyList), e.Err()
+}
+
+func (s *PFIFOSection) unmarshalSystemd(section *systemd.Section) error {
+	s.Comment = section.Comment
+	d := systemd.NewSectionDecoder(section, s.AddKeyComment)
+	d.String("Parent", &s.Parent)
+	d.String("Handle", &s.Handle)
+	d.String("PacketLimit", &s.PacketLimit)
+	d.Unknown(s.AddKey)
+	return d.Err()
+}
+
+func (s *PIESection) marshalSystemd(name string) (systemd.Section, error) {
+	e := systemd.NewSectionEncoder(name, s.Comment, s.GetKeyComment)
+	e.String("Parent", s.Parent, true)
+	e.String("Handle", s.Handle, true)
+	e.String("PacketLimit", s.PacketLimit, true)
+	return e.Section(s.KeyList), e.Err()
+}
+
+func (s *PIESection) unmarshalSystemd(section *systemd.Section) error {
+	s.Comment = section.Comment
+	d := systemd.NewSectionDecoder(section, s.AddKeyComment)
+	d.String("Parent", &s.Parent)
+	d.String("Handle", &s.Handle)
+	d.String("PacketLimit", &s.PacketLimit)
+	d.Unknown(s.AddKey)
+	return d.Err()
+}
+
+func (s *QDiscSection) marshalSystemd(name string) (systemd.Section, error) {
+	e := systemd.NewSectionEncoder(name, s.Comment, s.GetKeyComment)
+	e.String("Parent", s.Parent, true)
+	e.String("Handle", s.Handle, true)
+	return e.Section(s.KeyList), e.Err()
+}
+
+func (s *QDiscSection) unmarshalSystemd(section *systemd.Section) error {
+	s.Comment = section.Comment
+	d := systemd.NewSectionDecoder(section, s.AddKeyComment)
+	d.String("Parent", &s.Parent)
+	d.String("Handle", &s.Handle)
+	d.Unknown(s.AddKey)
+	return d.Err()
+}
+
+func (s *QuickFairQueueingClassSection) marshalSystemd(name string) (systemd.Section, error) {
+	e := systemd.NewSectionEncoder(name, s.Comment, s.GetKeyComment)
+	e.String("Parent", s.Parent, true)
+	e.String("ClassId", s.ClassId, true)
+	e.String("Weight", s.Weight, true)
+	e.String("MaxPacketBytes", s.MaxPacketBytes, true)
+	return e.Section(s.KeyList), e.Err()
+}
+
+func (s *QuickFairQueueingClassSection) unmarshalSystemd(section *systemd.Section) error {
+	s.Comment = section.Comment
+	d := systemd.NewSectionDecoder(section, s.AddKeyComment)
+	d.String("Parent", &s.Parent)
+	d.String("ClassId", &s.ClassId)
+	d.String("Weight", &s.Weight)
+	d.String("MaxPacketBytes", &s.MaxPacketBytes)
+	d.Unknown(s.AddKey)
+	return d.Err()
+}
+
+func (s *QuickFairQueueingSection) marshalSystemd(name string) (systemd.Section, error) {
+	e := systemd.NewSectionEncoder(name, s.Comment, s.GetKeyComment)
+	e.String("Parent", s.Parent, true)
+	e.String("Handle", s.Handle, true)
+	return e.Section(s.KeyList), e.Err()
+}
+
+func (s *QuickFairQueueingSection) unmarshalSystemd(section *systemd.Section) error {
+	s.Comment = section.Comment
+	d := systemd.NewSectionDecoder(section, s.AddKeyComment)
+	d.String("Parent", &s.Parent)
+	d.String("Handle", &s.Handle)
+	d.Unknown(s.AddKey)
+	return d.Err()
+}
+
+func (s *RouteSection) marshalSystemd(name string) (systemd.Section, error) {
+	e := systemd.NewSectionEncoder(name, s.Comment, s.GetKeyComment)
+	e.String("Gateway", s.Gateway, true)
+	e.String("GatewayOnLink", s.GatewayOnLink, true)
+	e.String("Destination", s.Destination, true)
+	e.String("Source", s.Source, true)
+	e.String("Metric", s.Metric, true)
+	e.String("IPv6Preference", s.IPv6Preference, true)
+	e.String("Scope", s.Scope, true)
+	e.String("PreferredSource", s.PreferredSource, true)
+	e.String("Table", s.Table, true)
+	e.String("Protocol", s.Protocol, true)
+	e.String("Type", s.Type, true)
+	e.String("InitialCongestionWindow", s.InitialCongestionWindow, true)
+	e.String("InitialAdvertisedReceiveWindow", s.InitialAdvertisedReceiveWindow, true)
+	e.String("QuickAck", s.QuickAck, true)
+	e.String("FastOpenNoCookie", s.FastOpenNoCookie, true)
+	e.String("TTLPropagate", s.TTLPropagate, true)
+	e.String("MTUBytes", s.MTUBytes, true)
+	e.String("IPServiceType", s.IPServiceType, true)
+	e.Strings("MultiPathRoute", s.MultiPathRoute, false)
+	return e.Section(s.KeyList), e.Err()
+}
+
+func (s *RouteSection) unmarshalSystemd(section *systemd.Section) error {
+	s.Comment = section.Comment
+	d := systemd.NewSectionDecoder(section, s.AddKeyComment)
+	d.String("Gateway", &s.Gateway)
+	d.String("GatewayOnLink", &s.GatewayOnLink)
+	d.String("Destination", &s.Destination)
+	d.String("Source", &s.Source)
+	d.String("Metric", &s.Metric)
+	d.String("IPv6Preference", &s.IPv6Preference)
+	d.String("Scope", &s.Scope)
+	d.String("PreferredSource", &s.PreferredSource)
+	d.String("Table", &s.Table)
+	d.String("Protocol", &s.Protocol)
+	d.String("Type", &s.Type)
+	d.String("InitialCongestionWindow", &s.InitialCongestionWindow)
+	d.String("InitialAdvertisedReceiveWindow", &s.InitialAdvertisedReceiveWindow)
+	d.String("QuickAck", &s.QuickAck)
+	d.String("FastOpenNoCookie", &s.FastOpenNoCookie)
+	d.String("TTLPropagate", &s.TTLPropagate)
+	d.String("MTUBytes", &s.MTUBytes)
+	d.String("IPServiceType", &s.IPServiceType)
+	d.Strings("MultiPathRoute", &s.MultiPathRoute, false)
+	d.Unknown(s.AddKey)
+	return d.Err()
+}
+
+func (s *RoutingPolicyRuleSection) marshalSystemd(name string) (systemd.Section, error) {
+	e := systemd.NewSectionEncoder(name, s.Comment, s.GetKeyComment)
+	e.String("TypeOfService", s.TypeOfService, true)
+	e.String("From", s.From, true)
+	e.String("To", s.To, true)
+	e.String("FirewallMark", s.FirewallMark, true)
+	e.String("Table", s.Table, true)
+	e.String("Priority", s.Priority, true)
+	e.String("IncomingInterface", s.IncomingInterface, true)
+	e.String("OutgoingInterface", s.OutgoingInterface, true)
+	e.String("SourcePort", s.SourcePort, true)
+	e.String("DestinationPort", s.DestinationPort, true)
+	e.String("IPProtocol", s.IPProtocol, true)
+	e.String("InvertRule", s.InvertRule, true)
+	e.String("Family", s.Family, true)
+	e.String("User", s.User, true)
+	e.String("SuppressPrefixLength", s.SuppressPrefixLength, true)
+	return e.Section(s.KeyList), e.Err()
+}
+
+func (s *RoutingPolicyRuleSection) unmarshalSystemd(section *systemd.Section) error {
+	s.Comment = section.Comment
+	d := systemd.NewSectionDecoder(section, s.AddKeyComment)
+	d.String("TypeOfService", &s.TypeOfService)
+	d.String("From", &s.From)
+	d.String("To", &s.To)
+	d.String("FirewallMark", &s.FirewallMark)
+	d.String("Table", &s.Table)
+	d.String("Priority", &s.Priority)
+	d.String("IncomingInterface", &s.IncomingInterface)
+	d.String("OutgoingInterface", &s.OutgoingInterface)
+	d.String("SourcePort", &s.SourcePort)
+	d.String("DestinationPort", &s.DestinationPort)
+	d.String("IPProtocol", &s.IPProtocol)
+	d.String("InvertRule", &s.InvertRule)
+	d.String("Family", &s.Family)
+	d.String("User", &s.User)
+	d.String("SuppressPrefixLength", &s.SuppressPrefixLength)
+	d.Unknown(s.AddKey)
+	return d.Err()
+}
+
+func (s *SRIOVSection) marshalSystemd(name string) (systemd.Section, error) {
+	e := systemd.NewSectionEncoder(name, s.Comment, s.GetKeyComment)
+	e.String("VirtualFunction", s.VirtualFunction, true)
+	e.String("VLANId", s.VLANId, true)
+	e.String("QualityOfService", s.QualityOfService, true)
+	e.String("VLANProtocol", s.VLANProtocol, true)
+	e.String("MACSpoofCheck", s.MACSpoofCheck, true)
+	e.String("QueryReceiveSideScaling", s.QueryReceiveSideScaling, true)
+	e.String("Trust", s.Trust, true)
+	e.String("LinkState", s.LinkState, true)
+	e.String("MACAddress", s.MACAddress, true)
+	return e.Section(s.KeyList), e.Err()
+}
+
+func (s *SRIOVSection) unmarshalSystemd(section *systemd.Section) error {
+	s.Comment = section.Comment
+	d := systemd.NewSectionDecoder(section, s.AddKeyComment)
+	d.String("VirtualFunction", &s.VirtualFunction)
+	d.String("VLANId", &s.VLANId)
+	d.String("QualityOfService", &s.QualityOfService)
+	d.String("VLANProtocol", &s.VLANProtocol)
+	d.String("MACSpoofCheck", &s.MACSpoofCheck)
+	d.String("QueryReceiveSideScaling", &s.QueryReceiveSideScaling)
+	d.String("Trust", &s.Trust)
+	d.String("LinkState", &s.LinkState)
+	d.String("MACAddress", &s.MACAddress)
+	d.Unknown(s.AddKey)
+	return d.Err()
+}
+
+func (s *StochasticFairBlueSection) marshalSystemd(name string) (systemd.Section, error) {
+	e := systemd.NewSectionEncoder(name, s.Comment, s.GetKeyComment)
+	e.String("Parent", s.Parent, true)
+	e.String("Handle", s.Handle, true)
+	e.String("PacketLimit", s.PacketLimit, true)
+	return e.Section(s.KeyList), e.Err()
+}
+
+func (s *StochasticFairBlueSection) unmarshalSystemd(section *systemd.Section) error {
+	s.Comment = section.Comment
+	d := systemd.NewSectionDecoder(section, s.AddKeyComment)
+	d.String("Parent", &s.Parent)
+	d.String("Handle", &s.Handle)
+	d.String("PacketLimit", &s.PacketLimit)
+	d.Unknown(s.AddKey)
+	return d.Err()
+}
+
+func (s *StochasticFairnessQueueingSection) marshalSystemd(name string) (systemd.Section, error) {
+	e := systemd.NewSectionEncoder(name, s.Comment, s.GetKeyComment)
+	e.String("Parent", s.Parent, true)
+	e.String("Handle", s.Handle, true)
+	e.String("PerturbPeriodSec", s.PerturbPeriodSec, true)
+	return e.Section(s.KeyList), e.Err()
+}
+
+func (s *StochasticFairnessQueueingSection) unmarshalSystemd(section *systemd.Section) error {
+	s.Comment = section.Comment
+	d := systemd.NewSectionDecoder(section, s.AddKeyComment)
+	d.String("Parent", &s.Parent)
+	d.String("Handle", &s.Handle)
+	d.String("PerturbPeriodSec", &s.PerturbPeriodSec)
+	d.Unknown(s.AddKey)
+	return d.Err()
+}
+
+func (s *TokenBucketFilterSection) marshalSystemd(name string) (systemd.Section, error) {
+	e := systemd.NewSectionEncoder(name, s.Comment, s.GetKeyComment)
+	e.String("Parent", s.Parent, true)
+	e.String("Handle", s.Handle, true)
+	e.String("LatencySec", s.LatencySec, true)
+	e.String("LimitBytes", s.LimitBytes, true)
+	e.String("BurstBytes", s.BurstBytes, true)
+	e.String("Rate", s.Rate, true)
+	e.String("MPUBytes", s.MPUBytes, true)
+	e.String("PeakRate", s.PeakRate, true)
+	e.String("MTUBytes", s.MTUBytes, true)
+	return e.Section(s.KeyList), e.Err()
+}
+
+func (s *TokenBucketFilterSection) unmarshalSystemd(section *systemd.Section) error {
+	s.Comment = section.Comment
+	d := systemd.NewSectionDecoder(section, s.AddKeyComment)
+	d.String("Parent", &s.Parent)
+	d.String("Handle", &s.Handle)
+	d.String("LatencySec", &s.LatencySec)
+	d.String("LimitBytes", &s.LimitBytes)
+	d.String("BurstBytes", &s.BurstBytes)
+	d.String("Rate", &s.Rate)
+	d.String("MPUBytes", &s.MPUBytes)
+	d.String("PeakRate", &s.PeakRate)
+	d.String("MTUBytes", &s.MTUBytes)
+	d.Unknown(s.AddKey)
+	return d.Err()
+}
+
+func (s *TrivialLinkEqualizerSection) marshalSystemd(name string) (systemd.Section, error) {
+	e := systemd.NewSectionEncoder(name, s.Comment, s.GetKeyComment)
+	e.String("Parent", s.Parent, true)
+	e.String("Handle", s.Handle, true)
+	e.String("Id", s.Id, true)
+	return e.Section(s.KeyList), e.Err()
+}
+
+func (s *TrivialLinkEqualizerSection) unmarshalSystemd(section *systemd.Section) error {
+	s.Comment = section.Comment
+	d := systemd.NewSectionDecoder(section, s.AddKeyComment)
+	d.String("Parent", &s.Parent)
+	d.String("Handle", &s.Handle)
+	d.String("Id", &s.Id)
+	d.Unknown(s.AddKey)
+	return d.Err()
+}
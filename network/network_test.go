@@ -17,10 +17,12 @@ limitations under the License.
 package network
 
 import (
+	"encoding/json"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
 
 	"routerd.net/go-systemd"
 )
@@ -69,6 +71,54 @@ Name=eth0
 
 [Network]
 Xfrm=xfrm0
+`
+
+	// example 5 + comments, repeated and unknown sections and keys
+	example12 = `# match
+[Match]
+Name=enp2s0
+Name=enp3s0
+Foo=bar
+
+[Network]
+# bridge
+Bridge=bridge0
+Bridge=
+
+[BridgeVLAN]
+VLAN=1-32
+# egress
+EgressUntagged=42
+
+[X-Vendor]
+Mode=fast
+`
+
+	// sections added in later systemd versions
+	example13 = `[Match]
+Name=ib0
+
+[Network]
+DHCP=ipv6
+DHCPServer=yes
+DHCPPrefixDelegation=yes
+
+[DHCPPrefixDelegation]
+UplinkInterface=:auto
+SubnetId=1
+Announce=yes
+
+[DHCPServerStaticLease]
+MACAddress=12:34:56:78:9a:bc
+Address=192.168.10.10
+
+[DHCPServerStaticLease]
+MACAddress=12:34:56:78:9a:bd
+Address=192.168.10.11
+
+[IPoIB]
+Mode=connected
+IgnoreUserspaceMulticastGroups=yes
 `
 
 	// example 10 + comments
@@ -81,6 +131,23 @@ Name=eth0
 [Network]
 # this as well
 Xfrm=xfrm0
+`
+
+	// repeated keys
+	example14 = `[Match]
+Name=eth0 eth1
+Name=
+Name=en*
+Host=a
+Host=b
+
+[Network]
+Address=192.168.0.10/24
+Address=
+Address=fd00::10/64
+DNS=1.1.1.1
+DNS=8.8.8.8
+Domains=example.com ~.
 `
 )
 
@@ -96,6 +163,7 @@ func TestNetwork(t *testing.T) {
 			{Name: "Example 8", File: example8},
 			{Name: "Example 10", File: example10},
 			{Name: "Example 11", File: example11},
+			{Name: "Example 13", File: example13},
 		}
 
 		for _, test := range tests {
@@ -110,4 +178,169 @@ func TestNetwork(t *testing.T) {
 			})
 		}
 	})
+
+	t.Run("test JSON and YAML conversion", func(t *testing.T) {
+		tests := []struct {
+			Name string
+			File string
+		}{
+			{Name: "Example 1", File: example1},
+			{Name: "Example 2", File: example2},
+			{Name: "Example 5", File: example5},
+			{Name: "Example 8", File: example8},
+			{Name: "Example 10", File: example10},
+			{Name: "Example 11", File: example11},
+			{Name: "Example 13", File: example13},
+		}
+
+		for _, test := range tests {
+			t.Run(test.Name, func(t *testing.T) {
+				in := &Network{}
+				require.NoError(t, systemd.Unmarshal([]byte(test.File), in))
+
+				b, err := json.Marshal(in)
+				require.NoError(t, err, "error in JSON marshal")
+				fromJSON := &Network{}
+				require.NoError(t, json.Unmarshal(b, fromJSON), "error in JSON unmarshal")
+
+				b, err = yaml.Marshal(fromJSON)
+				require.NoError(t, err, "error in YAML marshal")
+				fromYAML := &Network{}
+				require.NoError(t, yaml.Unmarshal(b, fromYAML), "error in YAML unmarshal")
+
+				b, err = systemd.Marshal(fromYAML)
+				require.NoError(t, err, "error in marshal")
+				assert.Equal(t, test.File, string(b))
+			})
+		}
+	})
+
+	t.Run("test repeated keys", func(t *testing.T) {
+		in := &Network{}
+		require.NoError(t, systemd.Unmarshal([]byte(example14), in))
+		assert.Equal(t, []string{"en*"}, in.Match.Name)
+		assert.Equal(t, "b", in.Match.Host)
+		assert.Equal(t, []string{"192.168.0.10/24", "fd00::10/64"}, in.Network.Address)
+		assert.Equal(t, []string{"1.1.1.1", "8.8.8.8"}, in.Network.DNS)
+		assert.Equal(t, []string{"example.com", "~."}, in.Network.Domains)
+
+		b, err := systemd.Marshal(in)
+		require.NoError(t, err)
+		assert.Equal(t, `[Match]
+Name=en*
+Host=b
+
+[Network]
+Address=192.168.0.10/24
+Address=fd00::10/64
+DNS=1.1.1.1
+DNS=8.8.8.8
+Domains=example.com ~.
+`, string(b))
+	})
+
+	t.Run("test equal", func(t *testing.T) {
+		a := &Network{Match: &MatchSection{Name: []string{"eth0", "eth1"}}}
+		b := &Network{Match: &MatchSection{Name: []string{"eth1", "eth0"}}}
+		assert.True(t, systemd.Equal(a, b))
+
+		// the order of DNS servers matters
+		a.Network = &NetworkSection{DNS: []string{"1.1.1.1", "8.8.8.8"}}
+		b.Network = &NetworkSection{DNS: []string{"8.8.8.8", "1.1.1.1"}}
+		assert.False(t, systemd.Equal(a, b))
+	})
+
+	t.Run("test marshal for version", func(t *testing.T) {
+		in := &Network{DHCPPrefixDelegation: &DHCPPrefixDelegationSection{SubnetId: "1"}}
+		b, err := systemd.MarshalForVersion(in, 249)
+		require.NoError(t, err)
+		assert.Equal(t, "[DHCPv6PrefixDelegation]\nSubnetId=1\n", string(b))
+
+		b, err = systemd.MarshalForVersion(in, 250)
+		require.NoError(t, err)
+		assert.Equal(t, "[DHCPPrefixDelegation]\nSubnetId=1\n", string(b))
+
+		// renamed keys are written under their old name
+		b, err = systemd.MarshalForVersion(&Network{DHCPv4: &DHCPv4Section{DenyList: []string{"192.168.1.1"}}}, 245)
+		require.NoError(t, err)
+		assert.Equal(t, "[DHCPv4]\nBlackList=192.168.1.1\n", string(b))
+
+		_, err = systemd.MarshalForVersion(&Network{IPoIB: &IPoIBSection{}}, 249)
+		assert.Equal(t, &systemd.VersionError{Section: "IPoIB", Since: 250, Version: 249}, err)
+	})
+
+	t.Run("test generated code", func(t *testing.T) {
+		tests := []struct {
+			Name string
+			File string
+		}{
+			{Name: "Example 1", File: example1},
+			{Name: "Example 2", File: example2},
+			{Name: "Example 5", File: example5},
+			{Name: "Example 8", File: example8},
+			{Name: "Example 10", File: example10},
+			{Name: "Example 11", File: example11},
+			{Name: "Example 13", File: example13},
+			{Name: "Repeated keys", File: example14},
+			{Name: "Unknown sections and keys", File: example12},
+		}
+
+		for _, test := range tests {
+			t.Run(test.Name, func(t *testing.T) {
+				generated := &Network{}
+				require.NoError(t, systemd.Unmarshal([]byte(test.File), generated))
+				reflected := &reflectNetwork{}
+				require.NoError(t, systemd.Unmarshal([]byte(test.File), reflected))
+				assert.Equal(t, (Network)(*reflected), *generated)
+
+				b, err := systemd.Marshal(generated)
+				require.NoError(t, err)
+				expected, err := systemd.Marshal(reflected)
+				require.NoError(t, err)
+				assert.Equal(t, string(expected), string(b))
+			})
+		}
+	})
+}
+
+// reflectNetwork has the fields of Network without its generated methods,
+// so it is encoded by reflection.
+type reflectNetwork Network
+
+func BenchmarkNetwork(b *testing.B) {
+	file, err := systemd.Decode([]byte(example12))
+	require.NoError(b, err)
+
+	b.Run("unmarshal generated", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if err := file.Unmarshal(&Network{}); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("unmarshal reflection", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if err := file.Unmarshal(&reflectNetwork{}); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	generated := &Network{}
+	require.NoError(b, file.Unmarshal(generated))
+	reflected := (*reflectNetwork)(generated)
+	b.Run("marshal generated", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := systemd.Marshal(generated); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("marshal reflection", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := systemd.Marshal(reflected); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
 }
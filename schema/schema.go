@@ -0,0 +1,36 @@
+// Package schema provides the JSON Schemas of the JSON representation
+// of .network, .netdev and .link files, see MarshalJSON.
+//
+// The schemas are generated from the structs and their documentation
+// in the network, netdev and link packages by cmd/systemd-schema.
+package schema
+
+import (
+	_ "embed" // for go:embed
+)
+
+//go:generate go run ../cmd/systemd-schema -src .. -o network.schema.json network
+//go:generate go run ../cmd/systemd-schema -src .. -o netdev.schema.json netdev
+//go:generate go run ../cmd/systemd-schema -src .. -o link.schema.json link
+//go:generate go run ../cmd/systemd-schema -src .. -openapi -o openapi.json network netdev link
+
+// Network is the JSON Schema of .network files.
+//
+//go:embed network.schema.json
+var Network []byte
+
+// NetDev is the JSON Schema of .netdev files.
+//
+//go:embed netdev.schema.json
+var NetDev []byte
+
+// Link is the JSON Schema of .link files.
+//
+//go:embed link.schema.json
+var Link []byte
+
+// OpenAPI is an OpenAPI document with the schemas of all files in its components,
+// named after their structs, like "network.Network".
+//
+//go:embed openapi.json
+var OpenAPI []byte
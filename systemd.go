@@ -2,6 +2,8 @@ package systemd
 
 import (
 	"routerd.net/go-systemd/internal/encoding"
+	"routerd.net/go-systemd/internal/parser"
+	"routerd.net/go-systemd/internal/specifier"
 )
 
 type SectionList = encoding.SectionList
@@ -12,9 +14,31 @@ type KeyComments = encoding.KeyComments
 
 type InvalidUnmarshalError = encoding.InvalidUnmarshalError
 
+type UnmarshalTypeError = encoding.UnmarshalTypeError
+
+type SyntaxError = encoding.SyntaxError
+
+type MarshalerError = encoding.MarshalerError
+
+type UnknownFieldError = encoding.UnknownFieldError
+
+type UnknownFieldsError = encoding.UnknownFieldsError
+
+type VersionError = encoding.VersionError
+
+type ValueMarshaler = encoding.ValueMarshaler
+
+type ValueUnmarshaler = encoding.ValueUnmarshaler
+
+type Marshaler = encoding.Marshaler
+
+type Unmarshaler = encoding.Unmarshaler
+
 var (
-	Marshal   = encoding.Marshal
-	Unmarshal = encoding.Unmarshal
+	Marshal           = encoding.Marshal
+	MarshalForVersion = encoding.MarshalForVersion
+	Unmarshal         = encoding.Unmarshal
+	UnmarshalStrict   = encoding.UnmarshalStrict
 )
 
 // Generic stuff
@@ -25,9 +49,110 @@ type Section = encoding.Section
 
 type Key = encoding.Key
 
+type Position = encoding.Position
+
+type DecodeOptions = encoding.DecodeOptions
+
+type EncodeOptions = encoding.EncodeOptions
+
+type BlankLinePolicy = encoding.BlankLinePolicy
+
+const (
+	BlankLinesDefault         = encoding.BlankLinesDefault
+	BlankLinesBetweenSections = encoding.BlankLinesBetweenSections
+	BlankLinesNone            = encoding.BlankLinesNone
+)
+
+type Decoder = encoding.Decoder
+
+type Encoder = encoding.Encoder
+
+var (
+	Decode         = encoding.Decode
+	DecodeLossless = encoding.DecodeLossless
+	ReadFile       = encoding.ReadFile
+	Encode         = encoding.Encode
+	NewDecoder     = encoding.NewDecoder
+	NewEncoder     = encoding.NewEncoder
+)
+
+// generated code, see cmd/systemd-codegen
+
+type SectionDecoder = encoding.SectionDecoder
+
+type SectionEncoder = encoding.SectionEncoder
+
+var (
+	NewSectionDecoder = encoding.NewSectionDecoder
+	NewSectionEncoder = encoding.NewSectionEncoder
+)
+
+// drop-ins
+
+type DropInOptions = encoding.DropInOptions
+
+type MergeRules = encoding.MergeRules
+
+type MergeMode = encoding.MergeMode
+
+type DropInError = encoding.DropInError
+
+const (
+	MergeKeepAll      = encoding.MergeKeepAll
+	MergeLastWins     = encoding.MergeLastWins
+	MergeResetOnEmpty = encoding.MergeResetOnEmpty
+)
+
 var (
-	Decode = encoding.Decode
-	Encode = encoding.Encode
+	Load          = encoding.Load
+	MergeRulesFor = encoding.MergeRulesFor
+	ErrMasked     = encoding.ErrMasked
+)
+
+// diff and merge
+
+type DiffOptions = encoding.DiffOptions
+
+type Change = encoding.Change
+
+type ChangeType = encoding.ChangeType
+
+const (
+	SectionAdded   = encoding.SectionAdded
+	SectionRemoved = encoding.SectionRemoved
+	KeyAdded       = encoding.KeyAdded
+	KeyChanged     = encoding.KeyChanged
+	KeyRemoved     = encoding.KeyRemoved
+)
+
+type Conflict = encoding.Conflict
+
+var (
+	Diff          = encoding.Diff
+	ThreeWayMerge = encoding.ThreeWayMerge
+
+	DefaultSectionIdentity = encoding.DefaultSectionIdentity
+)
+
+// equality
+
+type EqualOptions = encoding.EqualOptions
+
+var (
+	Equal           = encoding.Equal
+	Fingerprint     = encoding.Fingerprint
+	EqualOptionsFor = encoding.EqualOptionsFor
+)
+
+// JSON and YAML
+
+var (
+	MarshalJSON       = encoding.MarshalJSON
+	UnmarshalJSON     = encoding.UnmarshalJSON
+	MarshalYAML       = encoding.MarshalYAML
+	UnmarshalYAML     = encoding.UnmarshalYAML
+	MarshalYAMLNode   = encoding.MarshalYAMLNode
+	UnmarshalYAMLNode = encoding.UnmarshalYAMLNode
 )
 
 // utils
@@ -35,4 +160,36 @@ var (
 var (
 	StringPtr = encoding.StringPtr
 	BoolPtr   = encoding.BoolPtr
+	StrToBool = encoding.StrToBool
+	BoolToStr = encoding.BoolToStr
+
+	ParseTimespan  = encoding.ParseTimespan
+	FormatTimespan = encoding.FormatTimespan
+)
+
+// quoting and escaping
+
+var (
+	SplitWords = parser.SplitWords
+	JoinWords  = parser.JoinWords
+	Quote      = parser.Quote
+	Unquote    = parser.Unquote
+	Escape     = parser.Escape
+	Unescape   = parser.Unescape
+)
+
+// specifiers
+
+type Specifiers = specifier.Specifiers
+
+type SpecifierResolver = specifier.Resolver
+
+type SpecifierResolverFunc = specifier.ResolverFunc
+
+type SpecifierError = specifier.Error
+
+var (
+	Expand = specifier.Expand
+
+	ErrUnknownSpecifier = specifier.ErrUnknown
 )